@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"sort"
+	"sync"
+
+	"config-engine/internal/models"
+)
+
+// AuditLogger defines the interface for recording and querying audit events
+type AuditLogger interface {
+	Record(event models.AuditEvent) error
+	Query(filter models.AuditFilter) ([]models.AuditEvent, int, error)
+}
+
+// InMemoryAuditLogger implements AuditLogger using in-memory storage
+type InMemoryAuditLogger struct {
+	mu     sync.RWMutex
+	events []models.AuditEvent
+}
+
+// NewInMemoryAuditLogger creates a new in-memory audit logger
+func NewInMemoryAuditLogger() *InMemoryAuditLogger {
+	return &InMemoryAuditLogger{}
+}
+
+// Record appends an event to the audit trail
+func (l *InMemoryAuditLogger) Record(event models.AuditEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = append(l.events, event)
+	return nil
+}
+
+// Query returns the events matching the filter, newest first, along with the
+// total number of matching events before pagination is applied
+func (l *InMemoryAuditLogger) Query(filter models.AuditFilter) ([]models.AuditEvent, int, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var matched []models.AuditEvent
+	for _, event := range l.events {
+		if filter.Matches(event) {
+			matched = append(matched, event)
+		}
+	}
+
+	// Newest first; stable so events with equal timestamps keep reverse insertion order
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Timestamp.After(matched[j].Timestamp)
+	})
+
+	total := len(matched)
+	if filter.Offset >= total {
+		return []models.AuditEvent{}, total, nil
+	}
+	end := total
+	// Compare against the remainder so a huge limit cannot overflow
+	if filter.Limit > 0 && filter.Limit < total-filter.Offset {
+		end = filter.Offset + filter.Limit
+	}
+
+	return matched[filter.Offset:end], total, nil
+}
+
+// Validate that InMemoryAuditLogger implements AuditLogger
+var _ AuditLogger = (*InMemoryAuditLogger)(nil)
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"config-engine/internal/models"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	logger := NewInMemoryAuditLogger()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	logger.Record(models.AuditEvent{Timestamp: base, Action: "create", Name: "a", Actor: "alice"})
+	logger.Record(models.AuditEvent{Timestamp: base.Add(time.Hour), Action: "update", Name: "a", Actor: "bob"})
+	logger.Record(models.AuditEvent{Timestamp: base.Add(2 * time.Hour), Action: "create", Name: "b", Actor: "alice"})
+	logger.Record(models.AuditEvent{Timestamp: base.Add(3 * time.Hour), Action: "update", Name: "a", Actor: "alice"})
+
+	tests := []struct {
+		name          string
+		filter        models.AuditFilter
+		expectTotal   int
+		expectActions []string
+	}{
+		{
+			name:          "all newest first",
+			filter:        models.AuditFilter{},
+			expectTotal:   4,
+			expectActions: []string{"update", "create", "update", "create"},
+		},
+		{
+			name:          "by name",
+			filter:        models.AuditFilter{Name: "a"},
+			expectTotal:   3,
+			expectActions: []string{"update", "update", "create"},
+		},
+		{
+			name:          "by actor and action",
+			filter:        models.AuditFilter{Actor: "alice", Action: "create"},
+			expectTotal:   2,
+			expectActions: []string{"create", "create"},
+		},
+		{
+			name:          "time range",
+			filter:        models.AuditFilter{Since: base.Add(30 * time.Minute), Until: base.Add(150 * time.Minute)},
+			expectTotal:   2,
+			expectActions: []string{"create", "update"},
+		},
+		{
+			name:          "paginated",
+			filter:        models.AuditFilter{Limit: 2, Offset: 1},
+			expectTotal:   4,
+			expectActions: []string{"create", "update"},
+		},
+		{
+			name:          "huge limit",
+			filter:        models.AuditFilter{Limit: math.MaxInt, Offset: 1},
+			expectTotal:   4,
+			expectActions: []string{"create", "update", "create"},
+		},
+		{
+			name:          "offset past end",
+			filter:        models.AuditFilter{Offset: 10},
+			expectTotal:   4,
+			expectActions: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, total, err := logger.Query(tt.filter)
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if total != tt.expectTotal {
+				t.Errorf("Expected total %d, got %d", tt.expectTotal, total)
+			}
+			if len(events) != len(tt.expectActions) {
+				t.Fatalf("Expected %d events, got %d", len(tt.expectActions), len(events))
+			}
+			for i, action := range tt.expectActions {
+				if events[i].Action != action {
+					t.Errorf("Event %d: expected action %s, got %s", i, action, events[i].Action)
+				}
+			}
+		})
+	}
+}
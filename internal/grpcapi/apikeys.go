@@ -0,0 +1,88 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+
+	"config-engine/internal/grpcapi/configpb"
+	"config-engine/internal/handlers"
+	"config-engine/internal/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyMetadata carries the API key of a call, like the X-API-Key header over HTTP
+const apiKeyMetadata = "x-api-key"
+
+// namedRequest is implemented by every request of the ConfigService
+type namedRequest interface {
+	GetName() string
+}
+
+// APIKeyInterceptor applies the API key scopes of the HTTP API to gRPC calls:
+// every call needs a known key in the x-api-key metadata, read-only keys may
+// only get configurations and list versions, and keys restricted to
+// namespaces or types may only touch configurations within them. The type of
+// a create comes from the request and otherwise from the stored config.
+func APIKeyInterceptor(svc *service.ConfigService, keys map[string]handlers.APIKeyScope) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var key string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(apiKeyMetadata); len(values) > 0 {
+				key = values[0]
+			}
+		}
+		scope, ok := handlers.LookupAPIKey(keys, key)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "a valid "+apiKeyMetadata+" metadata value is required")
+		}
+
+		switch info.FullMethod {
+		case configpb.ConfigService_GetConfig_FullMethodName, configpb.ConfigService_ListVersions_FullMethodName:
+		default:
+			if scope.ReadOnly {
+				return nil, status.Error(codes.PermissionDenied, "the API key is read-only")
+			}
+		}
+
+		if scope.Restricted() {
+			if details := outOfScope(ctx, svc, scope, req); details != "" {
+				return nil, status.Error(codes.PermissionDenied, details)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// outOfScope describes why a call touches a configuration outside scope, or
+// returns "" when it stays within it
+func outOfScope(ctx context.Context, svc *service.ConfigService, scope handlers.APIKeyScope, req interface{}) string {
+	named, ok := req.(namedRequest)
+	if !ok {
+		return "the API key is restricted to namespaces or types and cannot make this call"
+	}
+	name := named.GetName()
+	if !scope.AllowsName(name) {
+		return fmt.Sprintf("config %q is outside the API key's namespaces", name)
+	}
+	if len(scope.Types) == 0 {
+		return ""
+	}
+
+	var configType string
+	if create, isCreate := req.(*configpb.CreateConfigRequest); isCreate {
+		configType = create.GetType()
+	} else if config, err := svc.GetConfig(ctx, name, nil); err == nil {
+		configType = config.Type
+	} else {
+		// Let the call report the missing config
+		return ""
+	}
+	if !scope.AllowsType(configType) {
+		return fmt.Sprintf("config type %q is outside the API key's types", configType)
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+
+	"config-engine/internal/audit"
+	"config-engine/internal/grpcapi/configpb"
+	"config-engine/internal/handlers"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIKeyInterceptor(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	client := serve(t, svc, audit.NewInMemoryAuditLogger(), grpc.UnaryInterceptor(APIKeyInterceptor(svc, map[string]handlers.APIKeyScope{
+		"admin-key":  {},
+		"reader-key": {ReadOnly: true},
+		"team-a-key": {Namespaces: []string{"team_a"}},
+		"flags-key":  {Types: []string{"flag_config"}},
+	})))
+
+	withKey := func(key string) context.Context {
+		if key == "" {
+			return context.Background()
+		}
+		return metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, key)
+	}
+	create := func(key, name string) error {
+		_, err := client.CreateConfig(withKey(key), &configpb.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: mustStruct(t, map[string]interface{}{"max_limit": 100, "enabled": true}),
+		})
+		return err
+	}
+	get := func(key, name string) error {
+		_, err := client.GetConfig(withKey(key), &configpb.GetConfigRequest{Name: name})
+		return err
+	}
+
+	for _, name := range []string{"team_a_payments", "team_b_payments"} {
+		if err := create("admin-key", name); err != nil {
+			t.Fatalf("Expected an unrestricted key to create %s, got %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{"missing key", func() error { return get("", "team_a_payments") }, codes.Unauthenticated},
+		{"unknown key", func() error { return get("guess", "team_a_payments") }, codes.Unauthenticated},
+		{"read-only key reads", func() error { return get("reader-key", "team_b_payments") }, codes.OK},
+		{"read-only key creates", func() error { return create("reader-key", "team_c_payments") }, codes.PermissionDenied},
+		{"namespace key reads own", func() error { return get("team-a-key", "team_a_payments") }, codes.OK},
+		{"namespace key reads other", func() error { return get("team-a-key", "team_b_payments") }, codes.PermissionDenied},
+		{"namespace key creates other", func() error { return create("team-a-key", "team_b_cards") }, codes.PermissionDenied},
+		{"type key reads other type", func() error { return get("flags-key", "team_a_payments") }, codes.PermissionDenied},
+		{"type key creates other type", func() error { return create("flags-key", "flags") }, codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"config-engine/internal/audit"
+	"config-engine/internal/grpcapi/configpb"
+	"config-engine/internal/models"
+	"config-engine/internal/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// actorMetadata identifies who made a change, like the X-Actor header over HTTP
+	actorMetadata = "x-actor"
+	// defaultActor is recorded when no actor metadata is sent
+	defaultActor = "anonymous"
+)
+
+// Server implements the gRPC ConfigService on top of the shared ConfigService
+type Server struct {
+	configpb.UnimplementedConfigServiceServer
+	service *service.ConfigService
+	audit   audit.AuditLogger
+	logger  *log.Logger
+}
+
+// NewServer creates a new gRPC configuration server recording its writes to
+// auditLog, which is normally shared with the HTTP API
+func NewServer(service *service.ConfigService, auditLog audit.AuditLogger, logger *log.Logger) *Server {
+	return &Server{service: service, audit: auditLog, logger: logger}
+}
+
+// Register creates a gRPC server with the configuration service registered
+func Register(svc *service.ConfigService, auditLog audit.AuditLogger, logger *log.Logger, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	configpb.RegisterConfigServiceServer(server, NewServer(svc, auditLog, logger))
+	return server
+}
+
+// CreateConfig creates a new configuration
+func (s *Server) CreateConfig(ctx context.Context, req *configpb.CreateConfigRequest) (*configpb.Config, error) {
+	config, err := s.service.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name:      req.GetName(),
+		Type:      req.GetType(),
+		Data:      structToMap(req.GetData()),
+		DependsOn: req.GetDependsOn(),
+		Actor:     actorFrom(ctx),
+	})
+	if err := s.writeStatus(err); err != nil {
+		return nil, err
+	}
+	s.recordAudit(ctx, models.AuditActionCreate, config)
+	return toProtoConfig(config)
+}
+
+// GetConfig retrieves a configuration by name, optionally at a specific version
+func (s *Server) GetConfig(ctx context.Context, req *configpb.GetConfigRequest) (*configpb.Config, error) {
+	var version *int
+	if req.GetVersion() != 0 {
+		if req.GetVersion() < 1 {
+			return nil, status.Error(codes.InvalidArgument, "version must be a positive integer")
+		}
+		v := int(req.GetVersion())
+		version = &v
+	}
+
+	config, err := s.service.GetConfig(ctx, req.GetName(), version)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoConfig(config)
+}
+
+// UpdateConfig updates an existing configuration
+func (s *Server) UpdateConfig(ctx context.Context, req *configpb.UpdateConfigRequest) (*configpb.Config, error) {
+	update := &models.UpdateConfigRequest{Data: structToMap(req.GetData()), Actor: actorFrom(ctx)}
+	if len(req.GetDependsOn()) > 0 {
+		update.DependsOn = req.GetDependsOn()
+	}
+
+	config, err := s.service.UpdateConfig(ctx, req.GetName(), update)
+	if err := s.writeStatus(err); err != nil {
+		return nil, err
+	}
+	s.recordAudit(ctx, models.AuditActionUpdate, config)
+	return toProtoConfig(config)
+}
+
+// RollbackConfig rolls back a configuration to a previous version
+func (s *Server) RollbackConfig(ctx context.Context, req *configpb.RollbackConfigRequest) (*configpb.Config, error) {
+	config, err := s.service.RollbackConfig(ctx, req.GetName(), &models.RollbackRequest{
+		Version: int(req.GetVersion()),
+		Steps:   int(req.GetSteps()),
+		Tag:     req.GetTag(),
+		Actor:   actorFrom(ctx),
+	})
+	if err := s.writeStatus(err); err != nil {
+		return nil, err
+	}
+	s.recordAudit(ctx, models.AuditActionRollback, config)
+	return toProtoConfig(config)
+}
+
+// ListVersions lists all versions of a configuration
+func (s *Server) ListVersions(ctx context.Context, req *configpb.ListVersionsRequest) (*configpb.ListVersionsResponse, error) {
+	resp, err := s.service.ListVersions(ctx, req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	out := &configpb.ListVersionsResponse{Name: resp.Name}
+	for _, v := range resp.Versions {
+		data, err := dataStruct(v.Data)
+		if err != nil {
+			return nil, err
+		}
+		out.Versions = append(out.Versions, &configpb.ConfigVersion{
+			Version:   int64(v.Version),
+			Data:      data,
+			CreatedAt: timestamppb.New(v.CreatedAt),
+		})
+	}
+	return out, nil
+}
+
+// writeStatus returns the status of a failed write, or nil when the write was
+// stored and only a post-write hook failed, which is logged instead
+func (s *Server) writeStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if service.WriteCommitted(err) {
+		s.logger.Printf("Hook failed: %v", err)
+		return nil
+	}
+	return toStatus(err)
+}
+
+// recordAudit records a write that produced a config version, stamped with
+// the time and actor like the audit events of the HTTP API
+func (s *Server) recordAudit(ctx context.Context, action string, config *models.Config) {
+	event := models.AuditEvent{
+		Timestamp: time.Now(),
+		Action:    action,
+		Name:      config.Name,
+		Version:   config.Version,
+		Actor:     actorFrom(ctx),
+		Origin:    config.Origin,
+	}
+	if err := s.audit.Record(event); err != nil {
+		s.logger.Printf("Failed to record audit event: %v", err)
+	}
+}
+
+// actorFrom returns the actor making the call
+func actorFrom(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(actorMetadata); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return defaultActor
+}
+
+// toStatus maps service errors to gRPC status codes, mirroring the HTTP mapping
+func toStatus(err error) error {
+	var (
+		validationErr *models.ValidationError
+		schemaErr     *models.SchemaValidationError
+		notFoundErr   *models.ConfigNotFoundError
+		versionErr    *models.VersionNotFoundError
+		tagErr        *models.TagNotFoundError
+		existsErr     *models.ConfigExistsError
+		inUseErr      *models.ConfigInUseError
+		lockedErr     *models.ConfigLockedError
+		capacityErr   *models.CapacityExceededError
+	)
+
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.As(err, &validationErr), errors.As(err, &schemaErr):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &notFoundErr), errors.As(err, &versionErr), errors.As(err, &tagErr):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &existsErr):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.As(err, &inUseErr), errors.As(err, &lockedErr):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.As(err, &capacityErr):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
+
+// toProtoConfig converts a config model to its protobuf representation
+func toProtoConfig(config *models.Config) (*configpb.Config, error) {
+	data, err := dataStruct(config.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &configpb.Config{
+		Name:      config.Name,
+		Type:      config.Type,
+		Version:   int64(config.Version),
+		Data:      data,
+		DependsOn: config.DependsOn,
+		CreatedAt: timestamppb.New(config.CreatedAt),
+		UpdatedAt: timestamppb.New(config.UpdatedAt),
+	}, nil
+}
+
+// dataStruct converts config data to a protobuf Struct. A Struct cannot hold
+// the array data of types whose schema declares an array, so such configs
+// are only served over HTTP.
+func dataStruct(data map[string]interface{}) (*structpb.Struct, error) {
+	if _, isArray := models.DataItems(data); isArray {
+		return nil, status.Error(codes.Unimplemented, "array config data is not supported over gRPC")
+	}
+	encoded, err := structpb.NewStruct(data)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode config data: %v", err)
+	}
+	return encoded, nil
+}
+
+// structToMap converts a protobuf Struct to a data map (nil when absent)
+func structToMap(s *structpb.Struct) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	return s.AsMap()
+}
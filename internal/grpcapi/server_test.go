@@ -0,0 +1,185 @@
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"testing"
+
+	"config-engine/internal/audit"
+	"config-engine/internal/grpcapi/configpb"
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func setupClient(t *testing.T) configpb.ConfigServiceClient {
+	validator, err := validation.NewValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	return serve(t, service.NewConfigService(repository.NewInMemoryRepository(), validator), audit.NewInMemoryAuditLogger())
+}
+
+// serve starts a gRPC server for svc recording to auditLog and returns a
+// client connected to it
+func serve(t *testing.T, svc *service.ConfigService, auditLog audit.AuditLogger, opts ...grpc.ServerOption) configpb.ConfigServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := Register(svc, auditLog, log.New(io.Discard, "", 0), opts...)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return configpb.NewConfigServiceClient(conn)
+}
+
+func mustStruct(t *testing.T, data map[string]interface{}) *structpb.Struct {
+	s, err := structpb.NewStruct(data)
+	if err != nil {
+		t.Fatalf("Failed to build struct: %v", err)
+	}
+	return s
+}
+
+func TestGRPCWorkflow(t *testing.T) {
+	client := setupClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateConfig(ctx, &configpb.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: mustStruct(t, map[string]interface{}{"max_limit": 1000, "enabled": true}),
+	})
+	if err != nil {
+		t.Fatalf("CreateConfig failed: %v", err)
+	}
+	if created.GetVersion() != 1 {
+		t.Errorf("Expected version 1, got %d", created.GetVersion())
+	}
+
+	_, err = client.UpdateConfig(ctx, &configpb.UpdateConfigRequest{
+		Name: "payment_config",
+		Data: mustStruct(t, map[string]interface{}{"max_limit": 2000, "enabled": false}),
+	})
+	if err != nil {
+		t.Fatalf("UpdateConfig failed: %v", err)
+	}
+
+	v1, err := client.GetConfig(ctx, &configpb.GetConfigRequest{Name: "payment_config", Version: 1})
+	if err != nil {
+		t.Fatalf("GetConfig failed: %v", err)
+	}
+	if v1.GetData().AsMap()["max_limit"].(float64) != 1000 {
+		t.Errorf("Expected max_limit 1000, got %v", v1.GetData().AsMap()["max_limit"])
+	}
+
+	rolledBack, err := client.RollbackConfig(ctx, &configpb.RollbackConfigRequest{Name: "payment_config", Steps: 1})
+	if err != nil {
+		t.Fatalf("RollbackConfig failed: %v", err)
+	}
+	if rolledBack.GetVersion() != 3 {
+		t.Errorf("Expected version 3, got %d", rolledBack.GetVersion())
+	}
+
+	versions, err := client.ListVersions(ctx, &configpb.ListVersionsRequest{Name: "payment_config"})
+	if err != nil {
+		t.Fatalf("ListVersions failed: %v", err)
+	}
+	if len(versions.GetVersions()) != 3 {
+		t.Errorf("Expected 3 versions, got %d", len(versions.GetVersions()))
+	}
+}
+
+func TestGRPCAudit(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	auditLog := audit.NewInMemoryAuditLogger()
+	client := serve(t, svc, auditLog)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), actorMetadata, "deployer")
+
+	data := mustStruct(t, map[string]interface{}{"max_limit": 1000, "enabled": true})
+	if _, err := client.CreateConfig(ctx, &configpb.CreateConfigRequest{Name: "payment_config", Type: "payment_config", Data: data}); err != nil {
+		t.Fatalf("CreateConfig failed: %v", err)
+	}
+	if _, err := client.UpdateConfig(ctx, &configpb.UpdateConfigRequest{Name: "payment_config", Data: data}); err != nil {
+		t.Fatalf("UpdateConfig failed: %v", err)
+	}
+	if _, err := client.RollbackConfig(context.Background(), &configpb.RollbackConfigRequest{Name: "payment_config", Version: 1}); err != nil {
+		t.Fatalf("RollbackConfig failed: %v", err)
+	}
+	// Failed writes are not recorded
+	client.UpdateConfig(ctx, &configpb.UpdateConfigRequest{Name: "missing", Data: data})
+
+	events, total, _ := auditLog.Query(models.AuditFilter{})
+	if total != 3 {
+		t.Fatalf("Expected 3 audit events, got %+v", events)
+	}
+	want := []struct {
+		action, actor string
+		version       int
+	}{
+		{models.AuditActionRollback, defaultActor, 3},
+		{models.AuditActionUpdate, "deployer", 2},
+		{models.AuditActionCreate, "deployer", 1},
+	}
+	for i, w := range want {
+		if e := events[i]; e.Action != w.action || e.Actor != w.actor || e.Version != w.version || e.Name != "payment_config" {
+			t.Errorf("Expected %s by %s of version %d, got %+v", w.action, w.actor, w.version, e)
+		}
+	}
+
+	config, _ := svc.GetConfig(context.Background(), "payment_config", nil)
+	if config.UpdatedBy != defaultActor {
+		t.Errorf("Expected the rollback to be stamped with the default actor, got %q", config.UpdatedBy)
+	}
+}
+
+func TestGRPCErrorCodes(t *testing.T) {
+	client := setupClient(t)
+	ctx := context.Background()
+
+	_, err := client.GetConfig(ctx, &configpb.GetConfigRequest{Name: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+
+	_, err = client.CreateConfig(ctx, &configpb.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: mustStruct(t, map[string]interface{}{"max_limit": "invalid", "enabled": true}),
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+
+	req := &configpb.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: mustStruct(t, map[string]interface{}{"max_limit": 1000, "enabled": true}),
+	}
+	client.CreateConfig(ctx, req)
+	_, err = client.CreateConfig(ctx, req)
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected AlreadyExists, got %v", err)
+	}
+}
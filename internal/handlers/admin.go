@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuthMiddleware requires "Authorization: Bearer <token>" on admin routes.
+// With an empty token admin routes are disabled and always return 403.
+func AdminAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			abortJSON(c, http.StatusForbidden, models.ErrorResponse{
+				Error:   "Admin endpoints disabled",
+				Details: "start the server with an admin token to enable them",
+			})
+			return
+		}
+
+		provided, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			abortJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+				Error:   "Unauthorized",
+				Details: "a valid admin bearer token is required",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Compact handles POST /api/v1/admin/compact
+func (h *ConfigHandler) Compact(c *gin.Context) {
+	keepVersions := 0
+	if keepStr := c.Query("keep_versions"); keepStr != "" {
+		v, err := strconv.Atoi(keepStr)
+		if err != nil || v < 1 {
+			h.badQuery(c, "keep_versions", "keep_versions must be a positive integer")
+			return
+		}
+		keepVersions = v
+	}
+
+	stats, err := h.service.Compact(c.Request.Context(), keepVersions)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.logger.Printf("Compaction removed %d versions from %d configs", stats.VersionsRemoved, stats.ConfigsCompacted)
+	writeJSON(c, http.StatusOK, stats)
+}
+
+// ValidationStats handles GET /api/v1/admin/validation-stats
+func (h *ConfigHandler) ValidationStats(c *gin.Context) {
+	writeJSON(c, http.StatusOK, h.service.ValidationStats())
+}
+
+// ReloadSchemas handles POST /api/v1/admin/schemas/reload. The response
+// lists the stored configs the new schemas reject; with dry_run=true the
+// schemas are only checked.
+func (h *ConfigHandler) ReloadSchemas(c *gin.Context) {
+	dryRun, ok := h.parseDryRun(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ReloadSchemas(c.Request.Context(), dryRun)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	if len(resp.Incompatible) > 0 {
+		h.logger.Printf("Reloaded schemas reject %d stored configs", len(resp.Incompatible))
+	}
+	if !dryRun {
+		h.logger.Printf("Reloaded %d schemas", len(resp.Types))
+	}
+	writeJSON(c, http.StatusOK, resp)
+}
+
+// Verify handles POST /api/v1/admin/verify. The response is 200 whether or
+// not mismatches were found.
+func (h *ConfigHandler) Verify(c *gin.Context) {
+	resp, err := h.service.Verify(c.Request.Context())
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	if len(resp.Mismatches) > 0 {
+		h.logger.Printf("Checksum verification found %d corrupted versions", len(resp.Mismatches))
+	}
+	writeJSON(c, http.StatusOK, resp)
+}
+
+// Routes returns the handler of GET /api/v1/admin/routes, which lists the
+// routes registered on engine sorted by path and method. Routes are read on
+// each request, so those registered after this call are listed too.
+func (h *ConfigHandler) Routes(engine *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		registered := engine.Routes()
+		resp := models.RoutesResponse{Routes: make([]models.Route, len(registered))}
+		for i, route := range registered {
+			resp.Routes[i] = models.Route{Method: route.Method, Path: route.Path}
+		}
+		sort.Slice(resp.Routes, func(i, j int) bool {
+			a, b := resp.Routes[i], resp.Routes[j]
+			if a.Path != b.Path {
+				return a.Path < b.Path
+			}
+			return a.Method < b.Method
+		})
+		writeJSON(c, http.StatusOK, resp)
+	}
+}
+
+// Snapshot handles GET /api/v1/admin/snapshot. The snapshot is returned
+// verbatim, unenveloped, so it can be saved and later posted to restore.
+func (h *ConfigHandler) Snapshot(c *gin.Context) {
+	snapshot, err := h.service.Snapshot(c.Request.Context())
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", snapshot)
+}
+
+// Restore handles POST /api/v1/admin/restore
+func (h *ConfigHandler) Restore(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		h.logger.Printf("Failed to read request body: %v", err)
+		writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Error: "request body is required",
+		})
+		return
+	}
+
+	resp, err := h.service.Restore(c.Request.Context(), body)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.logger.Printf("Restored %d configs from snapshot", resp.ConfigsRestored)
+	writeJSON(c, http.StatusOK, resp)
+}
+
+// UnlockConfig handles POST /api/v1/configs/{name}/unlock
+func (h *ConfigHandler) UnlockConfig(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	config, err := h.service.UnlockConfig(c.Request.Context(), name)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.recordAudit(c, models.AuditActionUnlock, config.Name, config.Version)
+	h.writeConfig(c, http.StatusOK, config)
+}
+
+// DeprecateConfig handles PUT /api/v1/configs/{name}/deprecation
+func (h *ConfigHandler) DeprecateConfig(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.DeprecationRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	config, err := h.service.DeprecateConfig(c.Request.Context(), name, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.recordAuditEvent(c, models.AuditEvent{
+		Action:  models.AuditActionDeprecate,
+		Name:    config.Name,
+		Version: config.Version,
+		Details: deprecationDetails(&req),
+	})
+	h.writeConfig(c, http.StatusOK, config)
+}
+
+// RegisterSchema handles PUT /api/v1/schemas/{type}. The response lists the
+// stored configs of the type the schema rejects; with dry_run=true the
+// schema is only checked.
+func (h *ConfigHandler) RegisterSchema(c *gin.Context) {
+	dryRun, ok := h.parseDryRun(c)
+	if !ok {
+		return
+	}
+
+	var req models.RegisterSchemaRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	resp, err := h.service.RegisterSchema(c.Request.Context(), c.Param("type"), &req, dryRun)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	if len(resp.Incompatible) > 0 {
+		h.logger.Printf("Schema for %s rejects %d stored configs", resp.Type, len(resp.Incompatible))
+	}
+	if !dryRun {
+		h.logger.Printf("Registered schema for %s", resp.Type)
+	}
+	writeJSON(c, http.StatusOK, resp)
+}
+
+// DeprecateType handles PUT /api/v1/schemas/{type}/deprecation
+func (h *ConfigHandler) DeprecateType(c *gin.Context) {
+	var req models.DeprecationRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	resp, err := h.service.DeprecateType(c.Param("type"), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.recordAuditEvent(c, models.AuditEvent{
+		Action:  models.AuditActionDeprecate,
+		Details: "type " + resp.Type + ": " + deprecationDetails(&req),
+	})
+	writeJSON(c, http.StatusOK, resp)
+}
+
+// deprecationDetails describes a deprecation change for the audit log
+func deprecationDetails(req *models.DeprecationRequest) string {
+	if !req.Deprecated {
+		return "deprecation lifted"
+	}
+	if req.Message == "" {
+		return "deprecated"
+	}
+	return "deprecated: " + req.Message
+}
+
+// SetTypePolicy handles POST /api/v1/admin/policies/:type
+func (h *ConfigHandler) SetTypePolicy(c *gin.Context) {
+	var req models.TypePolicy
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	resp, err := h.service.SetTypePolicy(c.Request.Context(), c.Param("type"), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.recordAuditEvent(c, models.AuditEvent{
+		Action:  models.AuditActionPolicy,
+		Details: "type " + resp.Type + ": " + policyDetails(&resp.Policy),
+	})
+	writeJSON(c, http.StatusOK, resp)
+}
+
+// policyDetails describes a type policy for the audit log
+func policyDetails(policy *models.TypePolicy) string {
+	var parts []string
+	if policy.DefaultTTL != "" {
+		parts = append(parts, "default TTL "+policy.DefaultTTL)
+	}
+	if policy.MaxVersions > 0 {
+		parts = append(parts, "keeps "+strconv.Itoa(policy.MaxVersions)+" versions")
+	}
+	if policy.VersionLimit > 0 {
+		parts = append(parts, "at most "+strconv.Itoa(policy.VersionLimit)+" versions ever")
+	}
+	if policy.DisallowRollback {
+		parts = append(parts, "rollback disallowed")
+	}
+	if len(parts) == 0 {
+		return "policy removed"
+	}
+	return "policy set: " + strings.Join(parts, ", ")
+}
+
+// BulkRollback handles POST /api/v1/admin/rollback. Each named config is
+// rolled back to the version active at the given time; the response reports
+// every config's outcome, so it is 200 even when some of them failed.
+func (h *ConfigHandler) BulkRollback(c *gin.Context) {
+	var req models.BulkRollbackRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if forceStr := c.Query("force"); forceStr != "" {
+		v, err := strconv.ParseBool(forceStr)
+		if err != nil {
+			h.badQuery(c, "force", "force must be a boolean")
+			return
+		}
+		req.Force = v
+	}
+
+	req.Actor = actorFrom(c)
+	resp, err := h.service.BulkRollback(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	details := "bulk rollback to " + req.At.Format(time.RFC3339)
+	if req.Force {
+		details += "; schema validation skipped (force)"
+	}
+	rolledBack := 0
+	for _, result := range resp.Results {
+		if result.Status != models.BulkRollbackRolledBack {
+			continue
+		}
+		rolledBack++
+		h.recordAuditEvent(c, models.AuditEvent{
+			Action:  models.AuditActionRollback,
+			Name:    result.Name,
+			Version: result.Version,
+			Origin:  models.OriginRollback,
+			Details: details,
+		})
+	}
+
+	h.logger.Printf("Bulk rollback to %s rolled back %d of %d configs", req.At.Format(time.RFC3339), rolledBack, len(resp.Results))
+	writeJSON(c, http.StatusOK, resp)
+}
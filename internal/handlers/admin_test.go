@@ -0,0 +1,545 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCompactEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1, "enabled": true},
+	})
+	for i := 2; i <= 4; i++ {
+		svc.UpdateConfig(context.Background(), "payments", &models.UpdateConfigRequest{
+			Data: map[string]interface{}{"max_limit": i, "enabled": true},
+		})
+	}
+
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	opts.AdminToken = "secret"
+	router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+	tests := []struct {
+		name   string
+		auth   string
+		status int
+	}{
+		{name: "missing token", auth: "", status: http.StatusUnauthorized},
+		{name: "wrong token", auth: "Bearer nope", status: http.StatusUnauthorized},
+		{name: "valid token", auth: "Bearer secret", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/compact?keep_versions=1", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var stats models.CompactResponse
+			json.Unmarshal(w.Body.Bytes(), &stats)
+			if stats.VersionsRemoved != 3 || stats.KeepVersions != 1 {
+				t.Errorf("Expected 3 versions removed keeping 1, got %+v", stats)
+			}
+		})
+	}
+
+	// Admin routes are disabled without a token
+	disabled := SetupRouter(NewConfigHandler(svc, logger), logger)
+	w := httptest.NewRecorder()
+	disabled.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/compact", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 without an admin token, got %d", w.Code)
+	}
+}
+
+func TestValidationStatsEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	opts.AdminToken = "secret"
+	router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+	for _, body := range []string{
+		`{"name":"a","type":"payment_config","data":{"max_limit":"high","enabled":true}}`,
+		`{"name":"b","type":"payment_config","data":{"max_limit":0,"enabled":true}}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(body)))
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("Expected status 422, got %d", w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/validation-stats", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var stats models.ValidationStatsResponse
+	json.Unmarshal(w.Body.Bytes(), &stats)
+	payment := stats.Types["payment_config"]
+	if payment.Failures != 2 || payment.Fields["max_limit"] != 2 {
+		t.Errorf("Expected 2 max_limit failures, got %+v", payment)
+	}
+}
+
+func TestReloadSchemasEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "feature_flags.json"), []byte(`{"type": "object"}`), 0o600)
+	validator, err := validation.NewValidatorWithSchemaDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	opts.AdminToken = "secret"
+	router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+	reload := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/schemas/reload", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"type": `), 0o600)
+	if w := reload(); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422 for a broken schema, got %d", w.Code)
+	}
+
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"type": "object"}`), 0o600)
+	w := reload()
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var resp models.SchemaReloadResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Types) != 2 {
+		t.Errorf("Expected 2 reloaded types, got %v", resp.Types)
+	}
+}
+
+func TestUnlockEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name:   "payments",
+		Type:   "payment_config",
+		Data:   map[string]interface{}{"max_limit": 1, "enabled": true},
+		Locked: true,
+	})
+
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	opts.AdminToken = "secret"
+	router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+	update := func() int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/configs/payments",
+			strings.NewReader(`{"data": {"max_limit": 2, "enabled": true}}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := update(); code != http.StatusLocked {
+		t.Fatalf("Expected status 423 updating a locked config, got %d", code)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/configs/payments/unlock", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected status 401 unlocking without a token, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs/payments/unlock", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 unlocking, got %d", w.Code)
+	}
+
+	if code := update(); code != http.StatusOK {
+		t.Errorf("Expected status 200 updating an unlocked config, got %d", code)
+	}
+}
+
+func TestDeprecationWarnings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	if err := validator.RegisterSchema("feature_flag", map[string]interface{}{"type": "object"}); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	ctx := context.Background()
+	for _, name := range []string{"payments", "payments_v2"} {
+		svc.CreateConfig(ctx, &models.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": 1, "enabled": true},
+		})
+	}
+	svc.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name: "dark_mode",
+		Type: "feature_flag",
+		Data: map[string]interface{}{"on": true},
+	})
+
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	opts.AdminToken = "secret"
+	router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+	deprecate := func(path, body string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 from %s, got %d: %s", path, w.Code, w.Body.String())
+		}
+	}
+	get := func(name string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/configs/"+name, nil)
+		req.Header.Set("Accept", "application/json; envelope=true")
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 getting %s, got %d", name, w.Code)
+		}
+		return w
+	}
+
+	deprecate("/api/v1/configs/payments/deprecation", `{"deprecated": true, "message": "use \"payments_v2\""}`)
+
+	w := get("payments")
+	if got, want := w.Header().Get("Warning"), `299 - "use \"payments_v2\""`; got != want {
+		t.Errorf("Expected Warning %s, got %q", want, got)
+	}
+	var envelope struct {
+		Data     models.Config `json:"data"`
+		Warnings []string      `json:"warnings"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(envelope.Warnings) != 1 || envelope.Warnings[0] != `use "payments_v2"` {
+		t.Errorf("Expected the deprecation message in warnings, got %v", envelope.Warnings)
+	}
+	if !envelope.Data.Deprecated {
+		t.Error("Expected the config to be marked deprecated")
+	}
+	for _, name := range []string{"payments_v2", "dark_mode"} {
+		if warning := get(name).Header().Get("Warning"); warning != "" {
+			t.Errorf("Expected no Warning for %s, got %q", name, warning)
+		}
+	}
+
+	// Deprecating the type warns for every config of that type only
+	deprecate("/api/v1/schemas/feature_flag/deprecation", `{"deprecated": true}`)
+	if warning := get("dark_mode").Header().Get("Warning"); warning != `299 - "config type \"feature_flag\" is deprecated"` {
+		t.Errorf("Expected the default type deprecation warning, got %q", warning)
+	}
+	if warning := get("payments_v2").Header().Get("Warning"); warning != "" {
+		t.Errorf("Expected no Warning for payments_v2, got %q", warning)
+	}
+
+	// Lifting the deprecations removes the warnings
+	deprecate("/api/v1/configs/payments/deprecation", `{"deprecated": false}`)
+	deprecate("/api/v1/schemas/feature_flag/deprecation", `{"deprecated": false}`)
+	for _, name := range []string{"payments", "dark_mode"} {
+		if warning := get(name).Header().Get("Warning"); warning != "" {
+			t.Errorf("Expected no Warning for %s after lifting deprecation, got %q", name, warning)
+		}
+	}
+}
+
+func TestSnapshotRestoreEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	ctx := context.Background()
+	svc.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1, "enabled": true},
+	})
+	svc.UpdateConfig(ctx, "payments", &models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": 2, "enabled": true}})
+
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	opts.AdminToken = "secret"
+	router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+	admin := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := admin(http.MethodGet, "/api/v1/admin/snapshot", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	snapshot := w.Body.String()
+
+	svc.DeleteConfig(ctx, "payments", true)
+	if w := admin(http.MethodPost, "/api/v1/admin/restore", `{"format": 1, "configs": [{"config": {"name": "x", "version": 1}}]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an inconsistent snapshot, got %d", w.Code)
+	}
+
+	w = admin(http.MethodPost, "/api/v1/admin/restore", snapshot)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp models.RestoreResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.ConfigsRestored != 1 {
+		t.Errorf("Expected 1 config restored, got %d", resp.ConfigsRestored)
+	}
+
+	versions, err := svc.ListVersions(ctx, "payments")
+	if err != nil || len(versions.Versions) != 2 {
+		t.Errorf("Expected both versions back after restore, got %+v (%v)", versions, err)
+	}
+}
+
+func TestBulkRollbackEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	ctx := context.Background()
+	for _, name := range []string{"payments", "checkout"} {
+		svc.CreateConfig(ctx, &models.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+		})
+		svc.UpdateConfig(ctx, name, &models.UpdateConfigRequest{
+			Data: map[string]interface{}{"max_limit": 200, "enabled": true},
+		})
+	}
+
+	// Both configs change after the incident starts, and a new one appears
+	time.Sleep(time.Millisecond)
+	incident := time.Now()
+	time.Sleep(time.Millisecond)
+	for _, name := range []string{"payments", "checkout"} {
+		svc.UpdateConfig(ctx, name, &models.UpdateConfigRequest{
+			Data: map[string]interface{}{"max_limit": 999, "enabled": false},
+		})
+	}
+	svc.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name: "refunds",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 5, "enabled": true},
+	})
+
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	opts.AdminToken = "secret"
+	router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+	rollback := func(body string) (int, models.BulkRollbackResponse) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/rollback", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+		router.ServeHTTP(w, req)
+		var resp models.BulkRollbackResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	body := fmt.Sprintf(`{"names": ["payments", "checkout", "refunds", "missing"], "at": %q}`, incident.Format(time.RFC3339Nano))
+	code, resp := rollback(body)
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+	want := []models.BulkRollbackResult{
+		{Name: "payments", Status: models.BulkRollbackRolledBack, FromVersion: 3, TargetVersion: 2, Version: 4},
+		{Name: "checkout", Status: models.BulkRollbackRolledBack, FromVersion: 3, TargetVersion: 2, Version: 4},
+		{Name: "refunds", Status: models.BulkRollbackSkipped, FromVersion: 1},
+		{Name: "missing", Status: models.BulkRollbackFailed},
+	}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("Expected %d results, got %+v", len(want), resp.Results)
+	}
+	for i, result := range resp.Results {
+		if result.Note == "" && result.Status != models.BulkRollbackRolledBack {
+			t.Errorf("Expected a note for %s", result.Name)
+		}
+		result.Note = ""
+		if result != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], result)
+		}
+	}
+	for _, name := range []string{"payments", "checkout"} {
+		config, _ := svc.GetConfig(ctx, name, nil)
+		if config.Data["max_limit"] != 200 {
+			t.Errorf("Expected %s to have its data from before the incident, got %v", name, config.Data)
+		}
+	}
+
+	// Rerunning leaves the rolled back configs alone
+	if _, resp := rollback(body); resp.Results[0].Status != models.BulkRollbackUnchanged {
+		t.Errorf("Expected a rerun to leave payments unchanged, got %+v", resp.Results[0])
+	}
+
+	if code, _ := rollback(`{"names": ["payments"]}`); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without at, got %d", code)
+	}
+}
+
+func TestTypePolicyEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	validator.RegisterSchema("temporary_flag", map[string]interface{}{"type": "object"})
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	opts.AdminToken = "secret"
+	router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send(http.MethodPost, "/api/v1/admin/policies/temporary_flag", `{"default_ttl": "soon"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid TTL, got %d", w.Code)
+	}
+	if w := send(http.MethodPost, "/api/v1/admin/policies/unknown_type", `{"max_versions": 2}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown type, got %d", w.Code)
+	}
+	w := send(http.MethodPost, "/api/v1/admin/policies/temporary_flag", `{"default_ttl": "1h", "max_versions": 2, "disallow_rollback": true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	before := time.Now()
+	w = send(http.MethodPost, "/api/v1/configs", `{"name": "banner", "type": "temporary_flag", "data": {"on": true}}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var config models.Config
+	json.Unmarshal(w.Body.Bytes(), &config)
+	if config.ExpiresAt == nil || config.ExpiresAt.Before(before.Add(time.Hour)) || config.ExpiresAt.After(time.Now().Add(time.Hour)) {
+		t.Errorf("Expected the config to expire in an hour, got %v", config.ExpiresAt)
+	}
+
+	for i := 0; i < 3; i++ {
+		if w := send(http.MethodPut, "/api/v1/configs/banner", fmt.Sprintf(`{"data": {"on": true, "n": %d}}`, i)); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	w = send(http.MethodGet, "/api/v1/configs/banner/versions", "")
+	var versions models.VersionsResponse
+	json.Unmarshal(w.Body.Bytes(), &versions)
+	if len(versions.Versions) != 2 || versions.Versions[len(versions.Versions)-1].Version != 4 {
+		t.Errorf("Expected the newest 2 of 4 versions to be kept, got %+v", versions.Versions)
+	}
+
+	if w := send(http.MethodPost, "/api/v1/configs/banner/rollback", `{"version": 3}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for a disallowed rollback, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Other types are unaffected
+	w = send(http.MethodPost, "/api/v1/configs", `{"name": "payments", "type": "payment_config", "data": {"max_limit": 100, "enabled": true}}`)
+	var unexpiring models.Config
+	json.Unmarshal(w.Body.Bytes(), &unexpiring)
+	if unexpiring.ExpiresAt != nil {
+		t.Errorf("Expected a config without a policy never to expire, got %v", unexpiring.ExpiresAt)
+	}
+}
+
+func TestRoutesEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	opts.AdminToken = "secret"
+	router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/routes", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var resp models.RoutesResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	listed := make(map[models.Route]bool, len(resp.Routes))
+	for _, route := range resp.Routes {
+		listed[route] = true
+	}
+	for _, route := range []models.Route{
+		{Method: http.MethodGet, Path: "/health"},
+		{Method: http.MethodGet, Path: "/api/v1/configs"},
+		{Method: http.MethodPost, Path: "/api/v1/configs"},
+		{Method: http.MethodGet, Path: "/api/v1/configs/:name"},
+		{Method: http.MethodPut, Path: "/api/v1/configs/:name"},
+		{Method: http.MethodDelete, Path: "/api/v1/configs/:name"},
+		{Method: http.MethodPost, Path: "/api/v1/configs/:name/rollback"},
+		{Method: http.MethodGet, Path: "/api/v1/admin/routes"},
+	} {
+		if !listed[route] {
+			t.Errorf("Expected %s %s to be listed", route.Method, route.Path)
+		}
+	}
+	if len(resp.Routes) != len(router.Routes()) {
+		t.Errorf("Expected all %d routes, got %d", len(router.Routes()), len(resp.Routes))
+	}
+}
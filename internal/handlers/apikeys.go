@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiKeyHeader carries the API key of a request
+const apiKeyHeader = "X-API-Key"
+
+// apiKeyScopeKey holds the scope of a restricted API key in the gin context
+const apiKeyScopeKey = "api_key_scope"
+
+// APIKeyScope limits what requests made with an API key may do. Empty
+// Namespaces or Types leave that dimension unrestricted.
+type APIKeyScope struct {
+	// ReadOnly keys may read but not write
+	ReadOnly bool `json:"read_only"`
+	// Namespaces are config name prefixes: a key scoped to "team_a" may touch
+	// the config named "team_a" and those whose names start with "team_a_"
+	// or "team_a-". Names are compared as sent, before any normalization.
+	Namespaces []string `json:"namespaces"`
+	// Types are the config types the key may touch
+	Types []string `json:"types"`
+}
+
+// Restricted reports whether the scope limits which configs the key may touch
+func (s APIKeyScope) Restricted() bool {
+	return len(s.Namespaces) > 0 || len(s.Types) > 0
+}
+
+// AllowsName reports whether name lies in one of the scope's namespaces
+func (s APIKeyScope) AllowsName(name string) bool {
+	if len(s.Namespaces) == 0 {
+		return true
+	}
+	for _, namespace := range s.Namespaces {
+		rest, found := strings.CutPrefix(name, namespace)
+		if found && (rest == "" || rest[0] == '_' || rest[0] == '-') {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsType reports whether configType is one of the scope's types
+func (s APIKeyScope) AllowsType(configType string) bool {
+	return len(s.Types) == 0 || slices.Contains(s.Types, configType)
+}
+
+// LoadAPIKeys reads a JSON file mapping each API key to its scope, e.g.
+// {"k1": {"read_only": true}, "k2": {"namespaces": ["team_a"], "types": ["feature_flags"]}}
+func LoadAPIKeys(path string) (map[string]APIKeyScope, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys map[string]APIKeyScope
+	if err := json.Unmarshal(raw, &keys); err != nil {
+		return nil, fmt.Errorf("invalid API key file %s: %v", path, err)
+	}
+	for key, scope := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("invalid API key file %s: empty key", path)
+		}
+		if slices.Contains(scope.Namespaces, "") || slices.Contains(scope.Types, "") {
+			return nil, fmt.Errorf("invalid API key file %s: empty namespace or type", path)
+		}
+	}
+	return keys, nil
+}
+
+// APIKeyMiddleware requires a known X-API-Key on every request and rejects
+// with 403 those outside the key's scope. Read-only keys may only GET, fetch
+// in batches and validate. Keys restricted to namespaces or types may only
+// touch configs within them: names come from the path, query or body, and
+// types from the request or the stored configuration. Listing requests must
+// filter to the scope, and requests that span every config, such as exports
+// and admin routes, need an unrestricted key. References resolved by a read
+// may only reach configs within the scope. With no keys the API is open.
+func (h *ConfigHandler) APIKeyMiddleware(keys map[string]APIKeyScope) gin.HandlerFunc {
+	if len(keys) == 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		scope, ok := LookupAPIKey(keys, c.GetHeader(apiKeyHeader))
+		if !ok {
+			abortJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+				Error:   "Unauthorized",
+				Details: "a valid " + apiKeyHeader + " header is required",
+			})
+			return
+		}
+
+		if scope.ReadOnly && !isReadRequest(c) {
+			abortJSON(c, http.StatusForbidden, models.ErrorResponse{
+				Error:   "Forbidden",
+				Details: "the API key is read-only",
+			})
+			return
+		}
+
+		if scope.Restricted() {
+			if details := h.outOfScope(c, scope); details != "" {
+				abortJSON(c, http.StatusForbidden, models.ErrorResponse{
+					Error:   "Forbidden",
+					Details: details,
+				})
+				return
+			}
+			c.Set(apiKeyScopeKey, scope)
+		}
+
+		c.Next()
+	}
+}
+
+// LookupAPIKey finds the scope of key, comparing in constant time
+func LookupAPIKey(keys map[string]APIKeyScope, key string) (APIKeyScope, bool) {
+	var match APIKeyScope
+	found := false
+	for candidate, scope := range keys {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			match, found = scope, true
+		}
+	}
+	return match, found && key != ""
+}
+
+// scopeFilter returns a filter admitting the configs within the scope of the
+// request's API key, or nil when the key is unrestricted. Reads that follow
+// references, such as resolve, use it to stay within the scope.
+func scopeFilter(c *gin.Context) func(*models.Config) bool {
+	value, exists := c.Get(apiKeyScopeKey)
+	if !exists {
+		return nil
+	}
+	scope := value.(APIKeyScope)
+	return func(config *models.Config) bool {
+		return scope.AllowsName(config.Name) && scope.AllowsType(config.Type)
+	}
+}
+
+// isReadRequest reports whether a request leaves stored configs unchanged.
+// Batch gets and validations are POSTs only to carry a body.
+func isReadRequest(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	}
+	switch c.FullPath() {
+	case "/api/v1/configs:method", "/api/v1/validate:method":
+		return true
+	}
+	return false
+}
+
+// outOfScope describes why a request touches configs outside scope, or
+// returns "" when it stays within it
+func (h *ConfigHandler) outOfScope(c *gin.Context, scope APIKeyScope) string {
+	var names, types []string
+	addName := func(name string) {
+		names = append(names, name)
+		if len(scope.Types) > 0 {
+			if config, err := h.service.GetConfig(c.Request.Context(), name, nil); err == nil {
+				types = append(types, config.Type)
+			}
+		}
+	}
+
+	path := c.FullPath()
+	switch {
+	case path == "/api/v1/configs" && c.Request.Method == http.MethodGet:
+		prefix, configType := c.Query("name_prefix"), c.Query("type")
+		if len(scope.Namespaces) > 0 && (prefix == "" || !scope.AllowsName(prefix)) {
+			return "the API key requires name_prefix to start with one of its namespaces"
+		}
+		if len(scope.Types) > 0 && (configType == "" || !scope.AllowsType(configType)) {
+			return "the API key requires type to be one of its config types"
+		}
+		return ""
+	case path == "/api/v1/configs":
+		var req struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		}
+		if peekJSON(c, &req) {
+			names, types = append(names, req.Name), append(types, req.Type)
+		}
+	case path == "/api/v1/configs:method":
+		var req models.BatchGetRequest
+		if peekJSON(c, &req) {
+			for _, name := range req.Names {
+				addName(name)
+			}
+		}
+	case path == "/api/v1/validate:method":
+		var req models.BatchValidateRequest
+		if peekJSON(c, &req) {
+			for _, item := range req.Items {
+				types = append(types, item.Type)
+			}
+		}
+	case path == "/api/v1/transactions":
+		var req models.TransactionRequest
+		if peekJSON(c, &req) {
+			for _, op := range req.Operations {
+				if op.Op == models.OperationCreate {
+					names, types = append(names, op.Name), append(types, op.Type)
+				} else {
+					addName(op.Name)
+				}
+			}
+		}
+	case path == "/api/v1/import":
+		var req models.ImportRequest
+		if peekJSON(c, &req) {
+			for _, record := range req.Configs {
+				if record.Config != nil {
+					names, types = append(names, record.Config.Name), append(types, record.Config.Type)
+					addName(record.Config.Name)
+				}
+			}
+		}
+	case path == "/api/v1/compare":
+		addName(c.Query("a"))
+		addName(c.Query("b"))
+	case path == "/api/v1/configs/:name/rename":
+		addName(c.Param("name"))
+		var req models.RenameRequest
+		if peekJSON(c, &req) {
+			names = append(names, req.NewName)
+		}
+	case path == "/api/v1/audit":
+		name := c.Query("name")
+		if name == "" {
+			return "the API key requires name to filter the audit log to its scope"
+		}
+		addName(name)
+	case c.Param("name") != "":
+		addName(c.Param("name"))
+	case c.Param("type") != "":
+		types = append(types, c.Param("type"))
+	default:
+		return "the API key is restricted to namespaces or types and cannot make requests spanning every config"
+	}
+
+	for _, name := range names {
+		if !scope.AllowsName(name) {
+			return fmt.Sprintf("config %q is outside the API key's namespaces", name)
+		}
+	}
+	for _, configType := range types {
+		if !scope.AllowsType(configType) {
+			return fmt.Sprintf("config type %q is outside the API key's types", configType)
+		}
+	}
+	return ""
+}
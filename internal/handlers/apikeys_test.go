@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	validator.RegisterSchema("flag_config", map[string]interface{}{"type": "object"})
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	opts.APIKeys = map[string]APIKeyScope{
+		"admin-key":  {},
+		"reader-key": {ReadOnly: true},
+		"team-a-key": {Namespaces: []string{"team_a"}},
+		"flags-key":  {Types: []string{"flag_config"}},
+	}
+	router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+	send := func(key, method, path string, body interface{}) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != nil {
+			encoded, _ := json.Marshal(body)
+			reader = bytes.NewReader(encoded)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		if key != "" {
+			req.Header.Set(apiKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	create := func(key, name, configType string) *httptest.ResponseRecorder {
+		return send(key, http.MethodPost, "/api/v1/configs", models.CreateConfigRequest{
+			Name: name,
+			Type: configType,
+			Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+		})
+	}
+
+	for _, name := range []string{"team_a_payments", "team_b_payments"} {
+		if w := create("admin-key", name, "payment_config"); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201 for an unrestricted key, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	// References resolved on read stay within the key's namespaces
+	for name, ref := range map[string]string{"team_a_own": "team_a_payments", "team_a_other": "team_b_payments"} {
+		w := send("team-a-key", http.MethodPost, "/api/v1/configs", models.CreateConfigRequest{
+			Name: name, Type: "flag_config", Data: map[string]interface{}{"limit": "${" + ref + ".max_limit}"},
+		})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201 creating %s, got %d: %s", name, w.Code, w.Body.String())
+		}
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		method     string
+		path       string
+		body       interface{}
+		wantStatus int
+	}{
+		{"missing key", "", http.MethodGet, "/api/v1/configs/team_a_payments", nil, http.StatusUnauthorized},
+		{"unknown key", "guess", http.MethodGet, "/api/v1/configs/team_a_payments", nil, http.StatusUnauthorized},
+		{"read-only key reads", "reader-key", http.MethodGet, "/api/v1/configs/team_b_payments", nil, http.StatusOK},
+		{"read-only key batch gets", "reader-key", http.MethodPost, "/api/v1/configs:batchGet",
+			models.BatchGetRequest{Names: []string{"team_a_payments"}}, http.StatusOK},
+		{"read-only key creates", "reader-key", http.MethodPost, "/api/v1/configs", models.CreateConfigRequest{
+			Name: "team_c_payments", Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+		}, http.StatusForbidden},
+		{"read-only key deletes", "reader-key", http.MethodDelete, "/api/v1/configs/team_a_payments", nil, http.StatusForbidden},
+		{"namespace key reads own", "team-a-key", http.MethodGet, "/api/v1/configs/team_a_payments", nil, http.StatusOK},
+		{"namespace key reads other", "team-a-key", http.MethodGet, "/api/v1/configs/team_b_payments", nil, http.StatusForbidden},
+		{"namespace key does not match a longer prefix", "team-a-key", http.MethodGet, "/api/v1/configs/team_ab", nil, http.StatusForbidden},
+		{"namespace key updates own", "team-a-key", http.MethodPatch, "/api/v1/configs/team_a_payments",
+			models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": 200}}, http.StatusOK},
+		{"namespace key batch gets other", "team-a-key", http.MethodPost, "/api/v1/configs:batchGet",
+			models.BatchGetRequest{Names: []string{"team_a_payments", "team_b_payments"}}, http.StatusForbidden},
+		{"namespace key compares across namespaces", "team-a-key", http.MethodGet, "/api/v1/compare?a=team_a_payments&b=team_b_payments", nil, http.StatusForbidden},
+		{"namespace key lists own", "team-a-key", http.MethodGet, "/api/v1/configs?name_prefix=team_a_", nil, http.StatusOK},
+		{"namespace key lists everything", "team-a-key", http.MethodGet, "/api/v1/configs", nil, http.StatusForbidden},
+		{"namespace key exports", "team-a-key", http.MethodGet, "/api/v1/export", nil, http.StatusForbidden},
+		{"namespace key transacts on other", "team-a-key", http.MethodPost, "/api/v1/transactions", models.TransactionRequest{
+			Operations: []models.TransactionOperation{{Op: models.OperationUpdate, Name: "team_b_payments", Data: map[string]interface{}{"max_limit": 1}}},
+		}, http.StatusForbidden},
+		{"namespace key renames out of its namespace", "team-a-key", http.MethodPost, "/api/v1/configs/team_a_payments/rename",
+			models.RenameRequest{NewName: "team_b_cards"}, http.StatusForbidden},
+		{"namespace key resolves own reference", "team-a-key", http.MethodGet, "/api/v1/configs/team_a_own?resolve=true", nil, http.StatusOK},
+		{"namespace key resolves other reference", "team-a-key", http.MethodGet, "/api/v1/configs/team_a_other?resolve=true", nil, http.StatusUnprocessableEntity},
+		{"unrestricted key resolves any reference", "admin-key", http.MethodGet, "/api/v1/configs/team_a_other?resolve=true", nil, http.StatusOK},
+		{"type key reads other type", "flags-key", http.MethodGet, "/api/v1/configs/team_a_payments", nil, http.StatusForbidden},
+		{"type key creates own type", "flags-key", http.MethodPost, "/api/v1/configs", models.CreateConfigRequest{
+			Name: "dark_mode", Type: "flag_config", Data: map[string]interface{}{"on": true},
+		}, http.StatusCreated},
+		{"type key lists own type", "flags-key", http.MethodGet, "/api/v1/configs?type=flag_config", nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := send(tt.key, tt.method, tt.path, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		return path
+	}
+
+	keys, err := LoadAPIKeys(write("keys.json", `{"k1": {"read_only": true}, "k2": {"namespaces": ["team_a"], "types": ["flag_config"]}}`))
+	if err != nil {
+		t.Fatalf("Failed to load API keys: %v", err)
+	}
+	if !keys["k1"].ReadOnly || keys["k1"].Restricted() {
+		t.Errorf("Expected k1 to be read-only and unrestricted, got %+v", keys["k1"])
+	}
+	if keys["k2"].ReadOnly || !keys["k2"].AllowsName("team_a-cache") || !keys["k2"].AllowsType("flag_config") {
+		t.Errorf("Unexpected k2 scope %+v", keys["k2"])
+	}
+
+	for name, content := range map[string]string{
+		"malformed.json":       `["k1"]`,
+		"empty-key.json":       `{"": {}}`,
+		"empty-namespace.json": `{"k1": {"namespaces": [""]}}`,
+	} {
+		if _, err := LoadAPIKeys(write(name, content)); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+	if _, err := LoadAPIKeys(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected a missing file to be rejected")
+	}
+}
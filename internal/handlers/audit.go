@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// actorHeader identifies who made a change
+	actorHeader = "X-Actor"
+	// defaultActor is recorded when no actor header is sent
+	defaultActor = "anonymous"
+	// defaultAuditLimit is the page size used when no limit is given
+	defaultAuditLimit = 50
+)
+
+// QueryAudit handles GET /api/v1/audit
+func (h *ConfigHandler) QueryAudit(c *gin.Context) {
+	filter := models.AuditFilter{
+		Name:   c.Query("name"),
+		Action: c.Query("action"),
+		Actor:  c.Query("actor"),
+	}
+
+	var err error
+	if filter.Since, err = parseTimeQuery(c, "since"); err != nil {
+		h.badQuery(c, "since", "since must be an RFC3339 timestamp")
+		return
+	}
+	if filter.Until, err = parseTimeQuery(c, "until"); err != nil {
+		h.badQuery(c, "until", "until must be an RFC3339 timestamp")
+		return
+	}
+	var ok bool
+	if filter.Limit, filter.Offset, ok = h.parsePagination(c, defaultAuditLimit); !ok {
+		return
+	}
+
+	events, total, err := h.audit.Query(filter)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, models.AuditResponse{
+		Events: events,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	})
+}
+
+// recordAudit records a successful change in the audit trail
+func (h *ConfigHandler) recordAudit(c *gin.Context, action, name string, version int) {
+	h.recordAuditEvent(c, models.AuditEvent{
+		Action:  action,
+		Name:    name,
+		Version: version,
+	})
+}
+
+// recordConfigAudit records a change that produced a config version, including
+// its origin and an optional free-form note
+func (h *ConfigHandler) recordConfigAudit(c *gin.Context, action string, config *models.Config, details string) {
+	h.recordAuditEvent(c, models.AuditEvent{
+		Action:  action,
+		Name:    config.Name,
+		Version: config.Version,
+		Origin:  config.Origin,
+		Details: details,
+	})
+}
+
+// recordAuditEvent stamps an event with the time and actor and records it
+func (h *ConfigHandler) recordAuditEvent(c *gin.Context, event models.AuditEvent) {
+	event.Timestamp = time.Now()
+	event.Actor = actorFrom(c)
+	if err := h.audit.Record(event); err != nil {
+		h.logger.Printf("Failed to record audit event: %v", err)
+	}
+}
+
+// actorFrom returns the actor making the request
+func actorFrom(c *gin.Context) string {
+	if actor := c.GetHeader(actorHeader); actor != "" {
+		return actor
+	}
+	return defaultActor
+}
+
+// badQuery writes a 400 response for an invalid query parameter
+func (h *ConfigHandler) badQuery(c *gin.Context, param, details string) {
+	writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+		Error:   "Invalid " + param + " parameter",
+		Details: details,
+	})
+}
+
+// parseTimeQuery parses an optional RFC3339 query parameter
+func parseTimeQuery(c *gin.Context, param string) (time.Time, error) {
+	value := c.Query(param)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// bindJSON decodes the request body into obj and runs struct validation.
+// It distinguishes an empty body, malformed JSON and a body that fails
+// validation, writing a 400 response and returning false for each. Failures
+// tied to a field list it under violations, in the shape of schema errors. In
+// strict mode bodies that repeat a key within an object are malformed too,
+// and unknown fields can optionally be rejected.
+func (h *ConfigHandler) bindJSON(c *gin.Context, obj interface{}) bool {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		h.logger.Printf("Failed to read request body: %v", err)
+		writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request format",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Error: "request body is required",
+		})
+		return false
+	}
+
+	if err := h.decodeJSON(body, obj); err != nil {
+		// A field of the wrong JSON type, such as a string for data, gets a plain message
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			h.handleServiceError(c, fieldTypeError(typeErr))
+			return false
+		}
+		h.logger.Printf("Failed to bind request: %v", err)
+		resp := models.ErrorResponse{
+			Error:   "Invalid request format",
+			Details: err.Error(),
+		}
+		if violation, ok := unknownFieldViolation(err); ok {
+			resp.Details, resp.Violations = violation.Message, []models.FieldViolation{violation}
+		}
+		writeJSON(c, http.StatusBadRequest, resp)
+		return false
+	}
+
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		h.logger.Printf("Request validation failed: %v", err)
+		violations := structViolations(reflect.TypeOf(obj), err)
+		details := make([]string, len(violations))
+		for i, violation := range violations {
+			details[i] = violation.Message
+		}
+		writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Error:      "Request validation failed",
+			Details:    strings.Join(details, "; "),
+			Violations: violations,
+		})
+		return false
+	}
+
+	return true
+}
+
+// decodeJSON decodes a single JSON value into obj, applying the strictness settings
+func (h *ConfigHandler) decodeJSON(body []byte, obj interface{}) error {
+	if h.strictJSON {
+		if err := checkDuplicateKeys(body); err != nil {
+			return err
+		}
+	}
+
+	// Keep numbers as json.Number, like models.DecodeJSON
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if h.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(obj); err != nil {
+		return err
+	}
+	// Like json.Unmarshal, reject anything after the value
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
+
+// unknownFieldViolation reports the field named by the decoder's error for
+// an unknown field, which it returns as a plain error
+func unknownFieldViolation(err error) (models.FieldViolation, bool) {
+	field, found := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !found {
+		return models.FieldViolation{}, false
+	}
+	name, unquoteErr := strconv.Unquote(field)
+	if unquoteErr != nil {
+		name = field
+	}
+	return models.FieldViolation{Field: name, Message: fmt.Sprintf("unknown field %q", name)}, true
+}
+
+// configDataType is the type of config data in request bodies
+var configDataType = reflect.TypeOf(models.ConfigData{})
+
+// fieldTypeError describes a JSON value of the wrong type in terms of the
+// JSON type the field expects, e.g. "name must be a string". A wrongly
+// typed body as a whole is reported against the field "body".
+func fieldTypeError(err *json.UnmarshalTypeError) *models.ValidationError {
+	field := err.Field
+	switch {
+	case field == "" && err.Type == configDataType:
+		// The decoder adds no field to errors from ConfigData's own decoding
+		field = "data"
+	case field == "":
+		field = "body"
+	}
+	return &models.ValidationError{
+		Field:   field,
+		Message: fmt.Sprintf("%s must be %s", field, jsonTypeName(err.Type)),
+	}
+}
+
+// jsonTypeName names the JSON type a Go value of type t decodes from
+func jsonTypeName(t reflect.Type) string {
+	if t == configDataType {
+		return "an object or array"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+	return "a valid value"
+}
+
+// structViolations lists the failed binding tags of a struct validation
+// error, naming each field by its JSON path within obj, of type t
+func structViolations(t reflect.Type, err error) []models.FieldViolation {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []models.FieldViolation{{Field: "body", Message: err.Error()}}
+	}
+
+	violations := make([]models.FieldViolation, len(fieldErrs))
+	for i, fieldErr := range fieldErrs {
+		field := jsonFieldPath(t, fieldErr.StructNamespace())
+		message := fmt.Sprintf("%s failed the %s check", field, fieldErr.Tag())
+		if fieldErr.Tag() == "required" {
+			message = field + " is required"
+		}
+		violations[i] = models.FieldViolation{Field: field, Message: message}
+	}
+	return violations
+}
+
+// jsonFieldPath maps a validator namespace such as "Request.Items[0].Name"
+// onto the JSON keys of t, giving "items[0].name"
+func jsonFieldPath(t reflect.Type, namespace string) string {
+	parts := strings.Split(namespace, ".")[1:]
+	for i, part := range parts {
+		name, index, _ := strings.Cut(part, "[")
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			continue
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			continue
+		}
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" && tag != "-" {
+			name = tag
+		}
+		if index != "" {
+			name += "[" + index
+		}
+		parts[i] = name
+		t = field.Type
+	}
+	return strings.Join(parts, ".")
+}
+
+// checkDuplicateKeys walks a JSON document and reports the first key that
+// appears twice in the same object. The standard decoder silently keeps the
+// last value, which can hide mistakes in config data.
+func checkDuplicateKeys(body []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	return checkDuplicateKeysIn(decoder, "")
+}
+
+// checkDuplicateKeysIn checks the next value from the decoder; path locates it in the document
+func checkDuplicateKeysIn(decoder *json.Decoder, path string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		seen := make(map[string]struct{})
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key := token.(string)
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			if _, exists := seen[key]; exists {
+				return fmt.Errorf("duplicate key %q", keyPath)
+			}
+			seen[key] = struct{}{}
+			if err := checkDuplicateKeysIn(decoder, keyPath); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for i := 0; decoder.More(); i++ {
+			if err := checkDuplicateKeysIn(decoder, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+
+	// Consume the closing delimiter
+	_, err = decoder.Token()
+	return err
+}
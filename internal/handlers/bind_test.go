@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type bindTestRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func TestBindJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &ConfigHandler{logger: log.New(io.Discard, "", 0)}
+
+	tests := []struct {
+		name        string
+		body        string
+		expectOK    bool
+		expectError string
+	}{
+		{name: "empty body", body: "", expectError: "request body is required"},
+		{name: "whitespace body", body: "  \n\t ", expectError: "request body is required"},
+		{name: "malformed JSON", body: `{"name":`, expectError: "Invalid request format"},
+		{name: "failing struct validation", body: `{}`, expectError: "Request validation failed"},
+		{name: "valid body", body: `{"name":"test"}`, expectOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			var req bindTestRequest
+			ok := h.bindJSON(c, &req)
+			if ok != tt.expectOK {
+				t.Fatalf("Expected ok=%v, got %v", tt.expectOK, ok)
+			}
+			if tt.expectOK {
+				if req.Name != "test" {
+					t.Errorf("Expected name 'test', got '%s'", req.Name)
+				}
+				return
+			}
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", w.Code)
+			}
+			var errResp models.ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &errResp)
+			if errResp.Error != tt.expectError {
+				t.Errorf("Expected error '%s', got '%s'", tt.expectError, errResp.Error)
+			}
+		})
+	}
+}
+
+func TestBindJSONStrict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		handler       *ConfigHandler
+		body          string
+		expectOK      bool
+		expectDetails string
+	}{
+		{name: "lenient keeps last duplicate", handler: &ConfigHandler{}, body: `{"name":"first","name":"test"}`, expectOK: true},
+		{name: "strict duplicate key", handler: &ConfigHandler{strictJSON: true}, body: `{"name":"test","name":"test"}`, expectDetails: `duplicate key "name"`},
+		{name: "strict nested duplicate key", handler: &ConfigHandler{strictJSON: true}, body: `{"name":"test","data":{"a":[{"b":1,"b":2}]}}`, expectDetails: `duplicate key "data.a[0].b"`},
+		{name: "strict same key in sibling objects", handler: &ConfigHandler{strictJSON: true}, body: `{"name":"test","x":{"b":1},"y":{"b":2}}`, expectOK: true},
+		{name: "unknown field allowed", handler: &ConfigHandler{}, body: `{"name":"test","extra":1}`, expectOK: true},
+		{name: "unknown field rejected", handler: &ConfigHandler{disallowUnknownFields: true}, body: `{"name":"test","extra":1}`, expectDetails: `unknown field "extra"`},
+		{name: "trailing data", handler: &ConfigHandler{}, body: `{"name":"test"} {}`, expectDetails: "unexpected data after JSON value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.handler.logger = log.New(io.Discard, "", 0)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			var req bindTestRequest
+			ok := tt.handler.bindJSON(c, &req)
+			if ok != tt.expectOK {
+				t.Fatalf("Expected ok=%v, got %v (%s)", tt.expectOK, ok, w.Body.String())
+			}
+			if tt.expectOK {
+				if req.Name != "test" {
+					t.Errorf("Expected name 'test', got '%s'", req.Name)
+				}
+				return
+			}
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", w.Code)
+			}
+			var errResp models.ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &errResp)
+			if !strings.Contains(errResp.Details, tt.expectDetails) {
+				t.Errorf("Expected details containing %q, got %q", tt.expectDetails, errResp.Details)
+			}
+		})
+	}
+}
+
+func TestBindJSONFieldTypes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &ConfigHandler{logger: log.New(io.Discard, "", 0)}
+
+	tests := []struct {
+		name        string
+		body        string
+		expectError string
+	}{
+		{name: "data number", body: `{"name": "x", "type": "t", "data": 5}`, expectError: "data must be an object or array"},
+		{name: "data string", body: `{"name": "x", "type": "t", "data": "{}"}`, expectError: "data must be an object or array"},
+		{name: "data reserved key", body: `{"name": "x", "type": "t", "data": {"\u0000items": []}}`, expectError: "data must be an object or array"},
+		{name: "name number", body: `{"name": 5, "type": "t", "data": {}}`, expectError: "name must be a string"},
+		{name: "depends_on object", body: `{"name": "x", "type": "t", "data": {}, "depends_on": {}}`, expectError: "depends_on must be an array"},
+		{name: "rollout array", body: `{"name": "x", "type": "t", "data": {}, "rollout": []}`, expectError: "rollout must be an object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			var req models.CreateConfigRequest
+			if h.bindJSON(c, &req) {
+				t.Fatal("Expected binding to fail")
+			}
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", w.Code)
+			}
+			var errResp models.ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &errResp)
+			if errResp.Error != tt.expectError {
+				t.Errorf("Expected error '%s', got '%s'", tt.expectError, errResp.Error)
+			}
+			if len(errResp.Violations) != 1 || errResp.Violations[0].Message != tt.expectError {
+				t.Errorf("Expected a single violation '%s', got %+v", tt.expectError, errResp.Violations)
+			}
+		})
+	}
+}
+
+func TestBindJSONViolations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type item struct {
+		Key string `json:"key" binding:"required"`
+	}
+	type request struct {
+		Name  string `json:"name" binding:"required"`
+		Items []item `json:"items" binding:"dive"`
+	}
+
+	tests := []struct {
+		name            string
+		handler         *ConfigHandler
+		body            string
+		expectFields    []string
+		expectNoDetails string
+	}{
+		{name: "missing required fields", handler: &ConfigHandler{}, body: `{"items": [{"key": "a"}, {}]}`, expectFields: []string{"name", "items[1].key"}, expectNoDetails: "request"},
+		{name: "unknown field", handler: &ConfigHandler{disallowUnknownFields: true}, body: `{"name": "x", "extra": 1}`, expectFields: []string{"extra"}, expectNoDetails: "json:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.handler.logger = log.New(io.Discard, "", 0)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			var req request
+			if tt.handler.bindJSON(c, &req) {
+				t.Fatal("Expected binding to fail")
+			}
+			var errResp models.ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &errResp)
+			var fields []string
+			for _, violation := range errResp.Violations {
+				fields = append(fields, violation.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.expectFields, ",") {
+				t.Errorf("Expected violations of %v, got %+v", tt.expectFields, errResp.Violations)
+			}
+			if strings.Contains(errResp.Details, tt.expectNoDetails) {
+				t.Errorf("Expected details without %q, got %q", tt.expectNoDetails, errResp.Details)
+			}
+		})
+	}
+}
+
+func TestLargeIntegersKeepPrecision(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	validator.RegisterSchema("ledger_config", map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"account_id": map[string]interface{}{"type": "integer"}},
+	})
+	logger := log.New(io.Discard, "", 0)
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	router := SetupRouter(NewConfigHandler(svc, logger), logger)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// 2^53 + 1 is the first integer float64 cannot represent
+	w := send(http.MethodPost, "/api/v1/configs", `{"name": "ledger", "type": "ledger_config", "data": {"account_id": 9007199254740993}}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w = send(http.MethodGet, "/api/v1/configs/ledger", ""); !strings.Contains(w.Body.String(), `"account_id":9007199254740993`) {
+		t.Errorf("Expected the integer to round-trip exactly, got %s", w.Body.String())
+	}
+
+	// Changing only the last digit is a real change, not a float64 rounding artefact
+	if w = send(http.MethodPut, "/api/v1/configs/ledger", `{"data": {"account_id": 9007199254740992}}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	w = send(http.MethodGet, "/api/v1/configs/ledger/diff?from=1&to=2", "")
+	if !strings.Contains(w.Body.String(), "9007199254740993") || !strings.Contains(w.Body.String(), "9007199254740992") {
+		t.Errorf("Expected a diff between the two integers, got %s", w.Body.String())
+	}
+
+	// Fractions still fail the integer schema
+	if w = send(http.MethodPut, "/api/v1/configs/ledger", `{"data": {"account_id": 1.5}}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for a fraction, got %d", w.Code)
+	}
+}
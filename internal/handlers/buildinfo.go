@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"config-engine/internal/models"
+)
+
+// NewBuildInfo describes the running build from the values injected with
+// -ldflags "-X main.version=... -X main.commit=... -X main.buildTime=...".
+// Values left empty fall back to what the Go toolchain embedded in the binary:
+// the module version and, for builds from a VCS checkout, the revision and
+// commit time.
+func NewBuildInfo(version, commit, buildTime string) models.BuildInfo {
+	info := models.BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	embedded, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if info.Version == "" && embedded.Main.Version != "(devel)" {
+		info.Version = embedded.Main.Version
+	}
+	for _, setting := range embedded.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && info.Commit == "":
+			info.Commit = setting.Value
+		case setting.Key == "vcs.time" && info.BuildTime == "":
+			info.BuildTime = setting.Value
+		}
+	}
+	return info
+}
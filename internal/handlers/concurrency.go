@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// retryAfterSeconds is the Retry-After hint sent when the in-flight limit is reached
+const retryAfterSeconds = "1"
+
+// ConcurrencyLimitMiddleware caps the number of requests served at once.
+// A buffered channel acts as a semaphore; when it is full the request is
+// rejected immediately with 503 instead of queueing. A limit of 0 disables it.
+func ConcurrencyLimitMiddleware(limit int) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	semaphore := make(chan struct{}, limit)
+	return func(c *gin.Context) {
+		select {
+		case semaphore <- struct{}{}:
+			defer func() { <-semaphore }()
+			c.Next()
+		default:
+			c.Header("Retry-After", retryAfterSeconds)
+			abortJSON(c, http.StatusServiceUnavailable, models.ErrorResponse{
+				Error:   "Server is busy",
+				Details: "too many requests in flight, retry later",
+			})
+		}
+	}
+}
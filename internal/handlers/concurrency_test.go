@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConcurrencyLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const limit = 2
+	started := make(chan struct{}, limit)
+	release := make(chan struct{})
+
+	r := gin.New()
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	api := r.Group("/api")
+	api.Use(ConcurrencyLimitMiddleware(limit))
+	api.GET("/slow", func(c *gin.Context) {
+		started <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	// Saturate the semaphore with slow requests
+	var wg sync.WaitGroup
+	codes := make([]int, limit)
+	for i := 0; i < limit; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+			codes[i] = w.Code
+		}(i)
+	}
+	for i := 0; i < limit; i++ {
+		<-started
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 when saturated, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header on 503")
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected health to bypass the limit, got %d", w.Code)
+	}
+
+	close(release)
+	wg.Wait()
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("Expected slow request %d to succeed, got %d", i, code)
+		}
+	}
+
+	// Slots are released once requests finish
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected request to succeed after release, got %d", w.Code)
+	}
+}
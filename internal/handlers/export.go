@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ndjsonContentType is the media type of newline-delimited JSON
+const ndjsonContentType = "application/x-ndjson"
+
+// ExportConfigs handles GET /api/v1/export.
+// format=json (the default) returns every configuration with its history in
+// one document built in memory; format=ndjson streams one record per line.
+func (h *ConfigHandler) ExportConfigs(c *gin.Context) {
+	redact, ok := h.parseRedact(c)
+	if !ok {
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		resp, err := h.service.ExportConfigs(c.Request.Context(), redact)
+		if err != nil {
+			h.handleServiceError(c, err)
+			return
+		}
+		writeJSON(c, http.StatusOK, resp)
+	case "ndjson":
+		h.streamExport(c, redact)
+	default:
+		h.badQuery(c, "format", "format must be json or ndjson")
+	}
+}
+
+// ImportConfigs handles POST /api/v1/import. The body is a buffered export
+// with an optional mode for configurations that already exist.
+func (h *ConfigHandler) ImportConfigs(c *gin.Context) {
+	var req models.ImportRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	req.Actor = actorFrom(c)
+	resp, err := h.service.ImportConfigs(c.Request.Context(), &req)
+	if !h.handleWriteError(c, err) {
+		return
+	}
+
+	for _, result := range resp.Results {
+		if result.Result == models.ImportSkipped {
+			continue
+		}
+		h.recordAuditEvent(c, models.AuditEvent{
+			Action:  models.AuditActionImport,
+			Name:    result.Name,
+			Version: result.Version,
+			Origin:  models.OriginImport,
+			Details: result.Result,
+		})
+	}
+
+	writeJSON(c, http.StatusOK, resp)
+}
+
+// streamExport writes one ExportRecord per line, reading each configuration
+// only when its line is due so memory stays flat however many there are.
+// Once the first line is flushed the status can no longer change, so a
+// failure part way through is reported as a final ErrorResponse line.
+func (h *ConfigHandler) streamExport(c *gin.Context, redact bool) {
+	ctx := c.Request.Context()
+	names, err := h.service.ListConfigNames(ctx)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+
+	next := 0
+	c.Stream(func(w io.Writer) bool {
+		if next >= len(names) {
+			return false
+		}
+		name := names[next]
+		next++
+
+		record, err := h.service.ExportConfig(ctx, name, redact)
+		var notFound *models.ConfigNotFoundError
+		if errors.As(err, &notFound) {
+			// Deleted since the names were listed
+			return true
+		}
+
+		var line interface{} = record
+		if err != nil {
+			h.logger.Printf("Export failed at %s: %v", name, err)
+			line = models.ErrorResponse{
+				Error:   "Export failed",
+				Details: "export stopped at " + name + ": " + err.Error(),
+			}
+		}
+		if err := json.NewEncoder(w).Encode(line); err != nil {
+			h.logger.Printf("Failed to write export line: %v", err)
+			return false
+		}
+		return err == nil
+	})
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// failingVersionsRepo fails to list the history of one configuration
+type failingVersionsRepo struct {
+	*repository.InMemoryRepository
+	failOn string
+}
+
+func (r *failingVersionsRepo) ListVersions(ctx context.Context, name string) ([]models.ConfigVersion, error) {
+	if name == r.failOn {
+		return nil, errors.New("storage unavailable")
+	}
+	return r.InMemoryRepository.ListVersions(ctx, name)
+}
+
+func setupExportServer(tb testing.TB, repo repository.ConfigRepository, configs int) *httptest.Server {
+	tb.Helper()
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repo, validator)
+	for i := 0; i < configs; i++ {
+		name := fmt.Sprintf("config_%04d", i)
+		if _, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": i + 1, "enabled": true},
+		}); err != nil {
+			tb.Fatalf("Failed to create config: %v", err)
+		}
+		if _, err := svc.UpdateConfig(context.Background(), name, &models.UpdateConfigRequest{
+			Data: map[string]interface{}{"max_limit": i + 2, "enabled": false},
+		}); err != nil {
+			tb.Fatalf("Failed to update config: %v", err)
+		}
+	}
+	logger := log.New(io.Discard, "", 0)
+	// c.Stream needs a real connection; the recorder has no CloseNotify
+	return httptest.NewServer(SetupRouter(NewConfigHandler(svc, logger), logger))
+}
+
+func readNDJSON(t *testing.T, body io.Reader) []json.RawMessage {
+	t.Helper()
+	var lines []json.RawMessage
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		lines = append(lines, json.RawMessage(append([]byte(nil), scanner.Bytes()...)))
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Failed to read stream: %v", err)
+	}
+	return lines
+}
+
+func TestExportConfigs(t *testing.T) {
+	server := setupExportServer(t, repository.NewInMemoryRepository(), 3)
+	defer server.Close()
+
+	t.Run("ndjson", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v1/export?format=ndjson")
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != ndjsonContentType {
+			t.Errorf("Expected Content-Type %s, got %q", ndjsonContentType, ct)
+		}
+
+		lines := readNDJSON(t, resp.Body)
+		if len(lines) != 3 {
+			t.Fatalf("Expected 3 lines, got %d", len(lines))
+		}
+		for i, line := range lines {
+			var record models.ExportRecord
+			if err := json.Unmarshal(line, &record); err != nil {
+				t.Fatalf("Line %d is not a record: %v", i, err)
+			}
+			if want := fmt.Sprintf("config_%04d", i); record.Config == nil || record.Config.Name != want {
+				t.Errorf("Line %d: expected %s, got %s", i, want, line)
+			}
+			if len(record.Versions) != 2 {
+				t.Errorf("Line %d: expected 2 versions, got %d", i, len(record.Versions))
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v1/export")
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		var export models.ExportResponse
+		if err := json.NewDecoder(resp.Body).Decode(&export); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(export.Configs) != 3 || len(export.Configs[0].Versions) != 2 {
+			t.Errorf("Expected 3 configs with 2 versions, got %+v", export)
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v1/export?format=csv")
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestStreamExportTrailingError(t *testing.T) {
+	repo := &failingVersionsRepo{InMemoryRepository: repository.NewInMemoryRepository(), failOn: "config_0001"}
+	server := setupExportServer(t, repo, 3)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/export?format=ndjson")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	lines := readNDJSON(t, resp.Body)
+	if len(lines) != 2 {
+		t.Fatalf("Expected one record and an error line, got %d lines", len(lines))
+	}
+	var record models.ExportRecord
+	if err := json.Unmarshal(lines[0], &record); err != nil || record.Config == nil {
+		t.Errorf("Expected a record first, got %s", lines[0])
+	}
+	var errResp models.ErrorResponse
+	if err := json.Unmarshal(lines[1], &errResp); err != nil || errResp.Error == "" {
+		t.Errorf("Expected a trailing error line, got %s", lines[1])
+	}
+}
+
+func BenchmarkExport(b *testing.B) {
+	server := setupExportServer(b, repository.NewInMemoryRepository(), 2000)
+	defer server.Close()
+
+	for _, format := range []string{"json", "ndjson"} {
+		b.Run(format, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				resp, err := http.Get(server.URL + "/api/v1/export?format=" + format)
+				if err != nil {
+					b.Fatalf("Failed to make request: %v", err)
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+		})
+	}
+}
@@ -1,27 +1,63 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"config-engine/internal/audit"
 	"config-engine/internal/models"
 	"config-engine/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
+// maxVersionParam bounds version query parameters; no config accumulates
+// anywhere near this many versions, so larger values are client mistakes
+const maxVersionParam = math.MaxInt32
+
+// defaultListLimit is the page size of the configs list when no limit is given
+const defaultListLimit = 50
+
 // ConfigHandler handles HTTP requests for configuration management
 type ConfigHandler struct {
 	service *service.ConfigService
+	audit   audit.AuditLogger
 	logger  *log.Logger
+	// sortedJSON sorts the keys of every object in config responses
+	sortedJSON bool
+	// strictJSON rejects request bodies that repeat a key within an object
+	strictJSON bool
+	// disallowUnknownFields rejects request bodies with fields the request type does not define
+	disallowUnknownFields bool
+	// idempotency replays creates retried with the same Idempotency-Key (nil disables it)
+	idempotency *idempotencyCache
+	// outcomes counts recent requests and server errors for the health score
+	outcomes *requestOutcomes
+	// healthWeights weighs the signals of the health score
+	healthWeights models.HealthScoreWeights
+	// startedAt and build describe the process in health responses
+	startedAt time.Time
+	build     models.BuildInfo
 }
 
-// NewConfigHandler creates a new configuration handler
+// NewConfigHandler creates a new configuration handler with its own audit log
 func NewConfigHandler(service *service.ConfigService, logger *log.Logger) *ConfigHandler {
+	return NewConfigHandlerWithAudit(service, audit.NewInMemoryAuditLogger(), logger)
+}
+
+// NewConfigHandlerWithAudit creates a new configuration handler recording to
+// auditLog, which other transports such as gRPC may share
+func NewConfigHandlerWithAudit(service *service.ConfigService, auditLog audit.AuditLogger, logger *log.Logger) *ConfigHandler {
 	return &ConfigHandler{
 		service: service,
+		audit:   auditLog,
 		logger:  logger,
 	}
 }
@@ -29,152 +65,971 @@ func NewConfigHandler(service *service.ConfigService, logger *log.Logger) *Confi
 // CreateConfig handles POST /api/v1/configs
 func (h *ConfigHandler) CreateConfig(c *gin.Context) {
 	var req models.CreateConfigRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Printf("Failed to bind request: %v", err)
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request format",
-			Details: err.Error(),
-		})
+	if !h.bindJSON(c, &req) {
 		return
 	}
 
-	config, err := h.service.CreateConfig(&req)
-	if err != nil {
-		h.handleServiceError(c, err)
+	req.Actor = actorFrom(c)
+	idempotencyKey, ok := h.beginIdempotent(c, &req)
+	if !ok {
+		return
+	}
+
+	config, err := h.service.CreateConfig(c.Request.Context(), &req)
+	if !h.handleWriteError(c, err) {
+		if idempotencyKey != "" {
+			h.idempotency.release(idempotencyKey)
+		}
 		return
 	}
+	if idempotencyKey != "" {
+		h.idempotency.complete(idempotencyKey, config)
+	}
 
-	c.JSON(http.StatusCreated, config)
+	h.recordConfigAudit(c, models.AuditActionCreate, config, "")
+
+	c.Header("Location", configLocation(config.Name))
+	h.writeConfig(c, http.StatusCreated, config)
 }
 
 // GetConfig handles GET /api/v1/configs/{name}
 func (h *ConfigHandler) GetConfig(c *gin.Context) {
-	name := c.Param("name")
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
 
 	// Check for version query parameter
-	var version *int
-	if versionStr := c.Query("version"); versionStr != "" {
-		v, err := strconv.Atoi(versionStr)
-		if err != nil || v < 1 {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Error:   "Invalid version parameter",
-				Details: "version must be a positive integer",
+	version, ok := h.parseVersionQuery(c, "version", 1)
+	if !ok {
+		return
+	}
+
+	// A missing config falls back to the default of the named type; history
+	// has no default
+	defaultType := c.Query("default")
+	if defaultType != "" && (version != nil || c.Query("tag") != "" || c.Query("as_of") != "") {
+		writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: "default cannot be used with version, tag or as_of",
+		})
+		return
+	}
+
+	// Resolve a point in time to the version current then
+	asOf, err := parseTimeQuery(c, "as_of")
+	if err != nil {
+		h.badQuery(c, "as_of", "as_of must be an RFC3339 timestamp")
+		return
+	}
+	if !asOf.IsZero() {
+		if version != nil || c.Query("tag") != "" {
+			writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid query parameters",
+				Details: "as_of cannot be used with version or tag",
 			})
 			return
 		}
+		v, err := h.service.ResolveVersionAt(c.Request.Context(), name, asOf)
+		if err != nil {
+			h.handleServiceError(c, err)
+			return
+		}
 		version = &v
 	}
 
-	config, err := h.service.GetConfig(name, version)
+	// Resolve a tag to its version
+	if tag := c.Query("tag"); tag != "" {
+		if version != nil {
+			writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid query parameters",
+				Details: "version and tag cannot be used together",
+			})
+			return
+		}
+		v, err := h.service.ResolveTag(c.Request.Context(), name, tag)
+		if err != nil {
+			h.handleServiceError(c, err)
+			return
+		}
+		version = &v
+	}
+
+	redact, ok := h.parseRedact(c)
+	if !ok {
+		return
+	}
+
+	resolve := false
+	if resolveStr := c.Query("resolve"); resolveStr != "" {
+		v, err := strconv.ParseBool(resolveStr)
+		if err != nil {
+			h.badQuery(c, "resolve", "resolve must be a boolean")
+			return
+		}
+		resolve = v
+	}
+
+	var config *models.Config
+	if defaultType != "" {
+		config, err = h.service.GetConfigOrDefault(c.Request.Context(), name, defaultType)
+	} else {
+		config, err = h.service.GetConfig(c.Request.Context(), name, version)
+	}
 	if err != nil {
 		h.handleServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, config)
+	// Substitute ${config.field} references; the stored data keeps the
+	// templates. Redacted reads resolve against redacted data so references
+	// cannot copy secrets into other fields.
+	if resolve {
+		config, err = h.service.ResolveConfig(c.Request.Context(), config, service.ResolveOptions{
+			Allow:  scopeFilter(c),
+			Redact: redact,
+		})
+		if err != nil {
+			h.handleServiceError(c, err)
+			return
+		}
+	} else if redact {
+		config = h.service.RedactConfig(config)
+	}
+
+	for _, warning := range h.service.DeprecationWarnings(config) {
+		addWarning(c, warning)
+	}
+	h.writeConfig(c, http.StatusOK, config)
+}
+
+// ListConfigs handles GET /api/v1/configs
+func (h *ConfigHandler) ListConfigs(c *gin.Context) {
+	filter := models.ConfigFilter{
+		Type:       c.Query("type"),
+		NamePrefix: c.Query("name_prefix"),
+	}
+	var ok bool
+	if filter.Limit, filter.Offset, ok = h.parsePagination(c, defaultListLimit); !ok {
+		return
+	}
+
+	redact, ok := h.parseRedact(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ListConfigs(c.Request.Context(), filter)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	if redact {
+		for i, config := range resp.Configs {
+			resp.Configs[i] = h.service.RedactConfig(config)
+		}
+	}
+
+	h.writeConfig(c, http.StatusOK, resp)
+}
+
+// configsMethods maps custom collection methods (POST /api/v1/configs:<method>)
+// to their handlers
+func (h *ConfigHandler) configsMethods() map[string]gin.HandlerFunc {
+	return map[string]gin.HandlerFunc{
+		"batchGet": h.BatchGetConfigs,
+	}
+}
+
+// ConfigsMethod dispatches POST /api/v1/configs:<method>. Gin only routes a
+// literal colon when started with Run, so the method is matched as a wildcard.
+func (h *ConfigHandler) ConfigsMethod(c *gin.Context) {
+	dispatchMethod(c, "configs", h.configsMethods())
+}
+
+// validateMethods maps custom validation methods (POST /api/v1/validate:<method>)
+// to their handlers
+func (h *ConfigHandler) validateMethods() map[string]gin.HandlerFunc {
+	return map[string]gin.HandlerFunc{
+		"batch": h.BatchValidate,
+	}
+}
+
+// ValidateMethod dispatches POST /api/v1/validate:<method>
+func (h *ConfigHandler) ValidateMethod(c *gin.Context) {
+	dispatchMethod(c, "validate", h.validateMethods())
+}
+
+// dispatchMethod runs the handler of the custom method named by the :method
+// wildcard, which includes the leading colon, or writes a 404
+func dispatchMethod(c *gin.Context, collection string, methods map[string]gin.HandlerFunc) {
+	method, ok := strings.CutPrefix(c.Param("method"), ":")
+	if handler := methods[method]; ok && handler != nil {
+		handler(c)
+		return
+	}
+
+	writeJSON(c, http.StatusNotFound, models.ErrorResponse{
+		Error:   "Unknown method",
+		Details: "no " + collection + " method named " + strconv.Quote(method),
+	})
+}
+
+// BatchValidate handles POST /api/v1/validate:batch. The response is 200
+// whether or not the items are valid.
+func (h *ConfigHandler) BatchValidate(c *gin.Context) {
+	var req models.BatchValidateRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	resp, err := h.service.BatchValidate(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, resp)
+}
+
+// BatchGetConfigs handles POST /api/v1/configs:batchGet
+func (h *ConfigHandler) BatchGetConfigs(c *gin.Context) {
+	var req models.BatchGetRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	redact, ok := h.parseRedact(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.BatchGetConfigs(c.Request.Context(), &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	if redact {
+		for name, config := range resp.Configs {
+			resp.Configs[name] = h.service.RedactConfig(config)
+		}
+	}
+
+	h.writeConfig(c, http.StatusOK, resp)
 }
 
 // UpdateConfig handles PUT /api/v1/configs/{name}
 func (h *ConfigHandler) UpdateConfig(c *gin.Context) {
-	name := c.Param("name")
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
 
 	var req models.UpdateConfigRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Printf("Failed to bind request: %v", err)
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request format",
-			Details: err.Error(),
-		})
+	if !h.bindJSON(c, &req) {
 		return
 	}
 
-	config, err := h.service.UpdateConfig(name, &req)
-	if err != nil {
-		h.handleServiceError(c, err)
+	req.Actor = actorFrom(c)
+	config, err := h.service.UpdateConfig(c.Request.Context(), name, &req)
+	if !h.handleWriteError(c, err) {
+		return
+	}
+
+	h.recordConfigAudit(c, models.AuditActionUpdate, config, "")
+
+	h.writeConfig(c, http.StatusOK, config)
+}
+
+// PatchConfig handles PATCH /api/v1/configs/{name}
+func (h *ConfigHandler) PatchConfig(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	if c.ContentType() == models.JSONPatchContentType {
+		h.jsonPatchConfig(c, name)
+		return
+	}
+
+	var req models.UpdateConfigRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	req.Actor = actorFrom(c)
+	config, err := h.service.PatchConfig(c.Request.Context(), name, &req)
+	if !h.handleWriteError(c, err) {
 		return
 	}
 
-	c.JSON(http.StatusOK, config)
+	h.recordConfigAudit(c, models.AuditActionUpdate, config, "")
+
+	h.writeConfig(c, http.StatusOK, config)
+}
+
+// jsonPatchConfig handles PATCH /api/v1/configs/{name} with an RFC 6902 JSON Patch body
+func (h *ConfigHandler) jsonPatchConfig(c *gin.Context, name string) {
+	var ops []models.PatchOperation
+	if !h.bindJSON(c, &ops) {
+		return
+	}
+
+	config, err := h.service.JSONPatchConfig(c.Request.Context(), name, ops, actorFrom(c))
+	if !h.handleWriteError(c, err) {
+		return
+	}
+
+	h.recordConfigAudit(c, models.AuditActionUpdate, config, "json patch")
+
+	h.writeConfig(c, http.StatusOK, config)
 }
 
 // RollbackConfig handles POST /api/v1/configs/{name}/rollback
 func (h *ConfigHandler) RollbackConfig(c *gin.Context) {
-	name := c.Param("name")
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
 
 	var req models.RollbackRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Printf("Failed to bind request: %v", err)
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request format",
-			Details: err.Error(),
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if forceStr := c.Query("force"); forceStr != "" {
+		v, err := strconv.ParseBool(forceStr)
+		if err != nil {
+			h.badQuery(c, "force", "force must be a boolean")
+			return
+		}
+		req.Force = v
+	}
+
+	req.Actor = actorFrom(c)
+	config, err := h.service.RollbackConfig(c.Request.Context(), name, &req)
+	if !h.handleWriteError(c, err) {
+		return
+	}
+
+	details := ""
+	if req.Force {
+		details = "schema validation skipped (force)"
+	}
+	h.recordConfigAudit(c, models.AuditActionRollback, config, details)
+
+	h.writeConfig(c, http.StatusOK, config)
+}
+
+// GetConfigValue handles GET /api/v1/configs/{name}/data/{pointer}.
+// It responds with the single value a JSON Pointer selects from the config's data.
+func (h *ConfigHandler) GetConfigValue(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+	redact, ok := h.parseRedact(c)
+	if !ok {
+		return
+	}
+
+	value, err := h.service.GetConfigValue(c.Request.Context(), name, c.Param("pointer"), redact)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, value)
+}
+
+// EvaluateRollout handles GET /api/v1/configs/{name}/evaluate.
+// It decides whether the user query parameter is in the config's rollout;
+// repeated cohort parameters name groups the user belongs to.
+func (h *ConfigHandler) EvaluateRollout(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	user := c.Query("user")
+	if user == "" {
+		h.badQuery(c, "user", "user is required")
+		return
+	}
+
+	evaluation, err := h.service.EvaluateRollout(c.Request.Context(), name, user, c.QueryArray("cohort"))
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, evaluation)
+}
+
+// CompareConfigs handles GET /api/v1/compare?a={name}&b={name}
+func (h *ConfigHandler) CompareConfigs(c *gin.Context) {
+	a, b := c.Query("a"), c.Query("b")
+	if a == "" || b == "" {
+		writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: "a and b must both name a configuration",
 		})
 		return
 	}
 
-	config, err := h.service.RollbackConfig(name, &req)
+	redact, ok := h.parseRedact(c)
+	if !ok {
+		return
+	}
+
+	diff, err := h.service.CompareConfigs(c.Request.Context(), a, b, redact)
 	if err != nil {
 		h.handleServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, config)
+	writeJSON(c, http.StatusOK, diff)
+}
+
+// DiffVersions handles GET /api/v1/configs/{name}/diff?from={version}&to={version}.
+// format=json (the default) returns the structured VersionDiff; format=text
+// returns a unified diff for pasting into reviews.
+func (h *ConfigHandler) DiffVersions(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	var versions [2]int
+	for i, param := range []string{"from", "to"} {
+		version, ok := h.parseVersionQuery(c, param, 1)
+		if !ok {
+			return
+		}
+		if version == nil {
+			h.badQuery(c, param, param+" is required")
+			return
+		}
+		versions[i] = *version
+	}
+
+	redact, ok := h.parseRedact(c)
+	if !ok {
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		diff, err := h.service.DiffVersions(c.Request.Context(), name, versions[0], versions[1], redact)
+		if err != nil {
+			h.handleServiceError(c, err)
+			return
+		}
+		writeJSON(c, http.StatusOK, diff)
+	case "text":
+		diff, err := h.service.DiffVersionsText(c.Request.Context(), name, versions[0], versions[1], redact)
+		if err != nil {
+			h.handleServiceError(c, err)
+			return
+		}
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(diff))
+	default:
+		h.badQuery(c, "format", "format must be json or text")
+	}
 }
 
 // ListVersions handles GET /api/v1/configs/{name}/versions
 func (h *ConfigHandler) ListVersions(c *gin.Context) {
-	name := c.Param("name")
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
 
-	versions, err := h.service.ListVersions(name)
+	redact, ok := h.parseRedact(c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.service.ListVersions(c.Request.Context(), name)
 	if err != nil {
 		h.handleServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, versions)
+	if redact {
+		versions, err = h.service.RedactVersions(c.Request.Context(), versions)
+		if err != nil {
+			h.handleServiceError(c, err)
+			return
+		}
+	}
+
+	h.writeConfig(c, http.StatusOK, versions)
+}
+
+// SquashVersions handles POST /api/v1/configs/{name}/versions/squash
+func (h *ConfigHandler) SquashVersions(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.SquashRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.service.SquashVersions(c.Request.Context(), name, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.recordAudit(c, models.AuditActionSquash, result.Name, result.To)
+
+	writeJSON(c, http.StatusOK, result)
+}
+
+// TagVersion handles POST /api/v1/configs/{name}/tags
+func (h *ConfigHandler) TagVersion(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.TagRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	tag, err := h.service.TagVersion(c.Request.Context(), name, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.recordAudit(c, models.AuditActionTag, tag.Name, tag.Version)
+
+	writeJSON(c, http.StatusOK, tag)
+}
+
+// DeleteConfig handles DELETE /api/v1/configs/{name}
+func (h *ConfigHandler) DeleteConfig(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	force := false
+	if forceStr := c.Query("force"); forceStr != "" {
+		v, err := strconv.ParseBool(forceStr)
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid force parameter",
+				Details: "force must be a boolean",
+			})
+			return
+		}
+		force = v
+	}
+
+	if err := h.service.DeleteConfig(c.Request.Context(), name, force); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.recordAudit(c, models.AuditActionDelete, name, 0)
+
+	c.Status(http.StatusNoContent)
+}
+
+// RenameConfig handles POST /api/v1/configs/{name}/rename
+func (h *ConfigHandler) RenameConfig(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.RenameRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	config, err := h.service.RenameConfig(c.Request.Context(), name, &req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.recordAuditEvent(c, models.AuditEvent{
+		Action:  models.AuditActionRename,
+		Name:    config.Name,
+		Version: config.Version,
+		Details: "renamed from " + name,
+	})
+
+	c.Header("Location", configLocation(config.Name))
+	h.writeConfig(c, http.StatusOK, config)
+}
+
+// ListDependents handles GET /api/v1/configs/{name}/dependents
+func (h *ConfigHandler) ListDependents(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	dependents, err := h.service.ListDependents(c.Request.Context(), name)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dependents)
+}
+
+// ValidateConfig handles GET /api/v1/configs/{name}/validate. The result is
+// 200 whether or not the stored data is still valid.
+func (h *ConfigHandler) ValidateConfig(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ValidateStoredConfig(c.Request.Context(), name)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, resp)
+}
+
+// GetConfigMeta handles GET /api/v1/configs/{name}/meta
+func (h *ConfigHandler) GetConfigMeta(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	meta, err := h.service.GetConfigMeta(c.Request.Context(), name)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, meta)
+}
+
+// ConfigStats handles GET /api/v1/configs/{name}/stats
+func (h *ConfigHandler) ConfigStats(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.service.ConfigStats(c.Request.Context(), name)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, stats)
+}
+
+// ExecuteTransaction handles POST /api/v1/transactions
+func (h *ConfigHandler) ExecuteTransaction(c *gin.Context) {
+	var req models.TransactionRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	req.Actor = actorFrom(c)
+	result, err := h.service.ExecuteTransaction(c.Request.Context(), &req)
+	if !h.handleWriteError(c, err) {
+		return
+	}
+
+	for _, config := range result.Configs {
+		h.recordConfigAudit(c, models.AuditActionTransaction, config, "")
+	}
+
+	h.writeConfig(c, http.StatusOK, result)
 }
 
 // HealthCheck handles GET /health
 func (h *ConfigHandler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, map[string]string{"status": "running"})
+	health := h.service.Health(c.Request.Context())
+	health.Uptime = time.Since(h.startedAt).Round(time.Second).String()
+	health.StartedAt = h.startedAt
+	health.Build = h.build
+	if health.Status != "running" {
+		h.logger.Printf("Health check failed: %+v", health.Components)
+		writeJSON(c, http.StatusServiceUnavailable, health)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, health)
+}
+
+// parseRedact parses the optional redact query parameter.
+// It writes a 400 response and returns false when the value is invalid.
+func (h *ConfigHandler) parseRedact(c *gin.Context) (bool, bool) {
+	redactStr := c.Query("redact")
+	if redactStr == "" {
+		return false, true
+	}
+
+	redact, err := strconv.ParseBool(redactStr)
+	if err != nil {
+		writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid redact parameter",
+			Details: "redact must be a boolean",
+		})
+		return false, false
+	}
+	return redact, true
 }
 
-// handleServiceError maps service errors to appropriate HTTP responses
+// parseVersionQuery parses an optional version-number query parameter that
+// must be at least min. It returns nil when the parameter is absent, and
+// writes a 400 response and returns false when the value is invalid.
+func (h *ConfigHandler) parseVersionQuery(c *gin.Context, param string, min int) (*int, bool) {
+	versionStr := c.Query(param)
+	if versionStr == "" {
+		return nil, true
+	}
+
+	details := param + " must be a positive integer"
+	if min == 0 {
+		details = param + " must be a non-negative integer"
+	}
+	v, err := strconv.ParseInt(versionStr, 10, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		h.badQuery(c, param, details)
+		return nil, false
+	}
+	if v < int64(min) {
+		// Also catches negative values too large to parse
+		h.badQuery(c, param, details)
+		return nil, false
+	}
+	if err != nil || v > maxVersionParam {
+		h.badQuery(c, param, fmt.Sprintf("%s must be at most %d", param, maxVersionParam))
+		return nil, false
+	}
+	version := int(v)
+	return &version, true
+}
+
+// handleWriteError handles the error of a write, reporting whether the
+// handler should go on to audit and return the write. A write stored despite
+// a failed post-write hook succeeds with the failure as a warning, so the
+// response and the audit log match the stored state; anything else is
+// reported by handleServiceError.
+func (h *ConfigHandler) handleWriteError(c *gin.Context, err error) bool {
+	if err == nil {
+		return true
+	}
+	if service.WriteCommitted(err) {
+		h.logger.Printf("Hook failed: %v", err)
+		addWarning(c, "the change was saved but a post-write hook failed: "+err.Error())
+		return true
+	}
+	h.handleServiceError(c, err)
+	return false
+}
+
+// handleServiceError maps service errors to appropriate HTTP responses.
+// Syntactic problems with the request (malformed body, missing or invalid
+// fields, bad parameters) are 400; data that is well-formed but violates the
+// type's schema or custom rules is 422 Unprocessable Entity.
 func (h *ConfigHandler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		// The client went away; there is no one to respond to
+		h.logger.Printf("Request cancelled: %v", err)
+		c.Abort()
+		return
+	case errors.Is(err, context.DeadlineExceeded) && isRequestTimeout(c.Request.Context()):
+		h.logger.Printf("Request exceeded the time limit: %v", err)
+		abortRequestTimeout(c)
+		return
+	case errors.Is(err, context.DeadlineExceeded):
+		h.logger.Printf("Request timed out: %v", err)
+		writeJSON(c, http.StatusGatewayTimeout, models.ErrorResponse{
+			Error:   "Request timed out",
+			Details: err.Error(),
+		})
+		return
+	}
+
 	switch e := err.(type) {
 	case *models.ValidationError:
 		h.logger.Printf("Validation error: %v", err)
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		resp := models.ErrorResponse{
 			Error:   err.Error(),
 			Details: "",
-		})
+		}
+		if e.Field != "" {
+			resp.Violations = []models.FieldViolation{{Field: e.Field, Message: e.Message}}
+		}
+		writeJSON(c, http.StatusBadRequest, resp)
 	case *models.ConfigNotFoundError:
 		h.logger.Printf("Config not found: %v", err)
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
+		writeJSON(c, http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: "",
+		})
+	case *models.SchemaNotFoundError:
+		h.logger.Printf("Schema not found: %v", err)
+		writeJSON(c, http.StatusNotFound, models.ErrorResponse{
 			Error:   err.Error(),
 			Details: "",
 		})
+	case *models.SchemaLoadError:
+		h.logger.Printf("Schema reload failed: %v", err)
+		writeJSON(c, http.StatusUnprocessableEntity, models.ErrorResponse{
+			Error:   "Schema reload failed",
+			Details: e.Err.Error() + "; the previous schemas remain active",
+		})
 	case *models.ConfigExistsError:
 		h.logger.Printf("Config already exists: %v", err)
-		c.JSON(http.StatusConflict, models.ErrorResponse{
+		writeJSON(c, http.StatusConflict, models.ErrorResponse{
 			Error:   err.Error(),
 			Details: "",
 		})
+	case *models.ConfigInUseError:
+		h.logger.Printf("Config in use: %v", err)
+		writeJSON(c, http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: "pass force=true to delete anyway",
+		})
+	case *models.ConfigTypeMismatchError:
+		h.logger.Printf("Config type mismatch: %v", err)
+		writeJSON(c, http.StatusUnprocessableEntity, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: "only configurations of the same type can be compared",
+		})
+	case *models.VersionLimitError:
+		h.logger.Printf("Version limit reached: %v", err)
+		writeJSON(c, http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: "the history is kept complete, so no further versions can be written",
+		})
+	case *models.RollbackDisallowedError:
+		h.logger.Printf("Rollback disallowed: %v", err)
+		writeJSON(c, http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: "an admin must change the type's policy to allow rollbacks",
+		})
+	case *models.ConfigLockedError:
+		h.logger.Printf("Config locked: %v", err)
+		writeJSON(c, http.StatusLocked, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: "an admin must unlock the config before it can be changed",
+		})
+	case *models.CapacityExceededError:
+		h.logger.Printf("Capacity exceeded: %v", err)
+		writeJSON(c, http.StatusInsufficientStorage, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: "delete unused configurations or raise -max-configs",
+		})
 	case *models.VersionNotFoundError:
 		h.logger.Printf("Version not found: %v", err)
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
+		writeJSON(c, http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: "",
+		})
+	case *models.NoVersionAtError:
+		h.logger.Printf("No version at time: %v", err)
+		writeJSON(c, http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: "",
+		})
+	case *models.PointerNotFoundError:
+		h.logger.Printf("Pointer not found: %v", err)
+		writeJSON(c, http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: "",
+		})
+	case *models.TagNotFoundError:
+		h.logger.Printf("Tag not found: %v", err)
+		writeJSON(c, http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: "",
+		})
+	case *models.ProposalNotFoundError:
+		h.logger.Printf("Proposal not found: %v", err)
+		writeJSON(c, http.StatusNotFound, models.ErrorResponse{
 			Error:   err.Error(),
 			Details: "",
 		})
+	case *models.JSONPatchError:
+		h.logger.Printf("JSON patch failed: %v", err)
+		status, details := http.StatusUnprocessableEntity, "no operation of the patch was applied"
+		if e.TestFailed {
+			status, details = http.StatusConflict, "a test operation failed, so no operation of the patch was applied"
+		}
+		writeJSON(c, status, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: details,
+		})
+	case *models.ProposalConflictError:
+		h.logger.Printf("Proposal conflict: %v", err)
+		writeJSON(c, http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: "pass revalidate=true to apply against the current version",
+		})
 	case *models.SchemaValidationError:
 		h.logger.Printf("Schema validation error: %v", err)
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Schema validation failed",
-			Details: e.Details,
+		writeJSON(c, http.StatusUnprocessableEntity, models.ErrorResponse{
+			Error:       "Schema validation failed",
+			Details:     e.Details,
+			Suggestions: e.Suggestions,
+			Violations:  e.Violations,
+		})
+	case *models.ReferenceError:
+		h.logger.Printf("Reference error: %v", err)
+		writeJSON(c, http.StatusUnprocessableEntity, models.ErrorResponse{
+			Error:   "Reference resolution failed",
+			Details: e.Error(),
+		})
+	case *models.TransactionError:
+		h.logger.Printf("Transaction failed: %v", err)
+		status := http.StatusConflict
+		switch e.Err.(type) {
+		case *models.ValidationError:
+			status = http.StatusBadRequest
+		case *models.SchemaValidationError:
+			status = http.StatusUnprocessableEntity
+		case *models.ConfigLockedError:
+			status = http.StatusLocked
+		case *models.CapacityExceededError:
+			status = http.StatusInsufficientStorage
+		}
+		writeJSON(c, status, models.TransactionErrorResponse{
+			Error:          "Transaction failed",
+			Details:        e.Err.Error(),
+			OperationIndex: e.Index,
+		})
+	case *models.HookError:
+		h.logger.Printf("Hook failed: %v", err)
+		details := "the change was saved but a post-write hook failed"
+		if e.Reverted {
+			details = "a critical post-write hook failed and the change was reverted"
+		}
+		writeJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Details: details,
 		})
 	default:
 		// TODO: Ideally not exposing internal error details to the client side
 		h.logger.Printf("Internal error: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		writeJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Internal server error",
 			Details: err.Error(),
 		})
@@ -195,7 +1050,7 @@ func RecoveryMiddleware(logger *log.Logger) gin.HandlerFunc {
 		defer func() {
 			if err := recover(); err != nil {
 				logger.Printf("Panic recovered: %v", err)
-				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				writeJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 					Error:   "Internal server error",
 					Details: fmt.Sprintf("%v", err),
 				})
@@ -206,26 +1061,111 @@ func RecoveryMiddleware(logger *log.Logger) gin.HandlerFunc {
 	}
 }
 
-// SetupRouter configures and returns the HTTP router
+// SetupRouter configures and returns the HTTP router with default options
 func SetupRouter(handler *ConfigHandler, logger *log.Logger) *gin.Engine {
+	return SetupRouterWithOptions(handler, logger, DefaultRouterOptions())
+}
+
+// SetupRouterWithOptions configures and returns the HTTP router
+func SetupRouterWithOptions(handler *ConfigHandler, logger *log.Logger, opts RouterOptions) *gin.Engine {
 	r := gin.New()
+	// Match routes on the escaped path so an encoded slash stays inside :name
+	// and is rejected by nameParam instead of falling through to a 404
+	r.UseRawPath = true
+	// Gin trusts every proxy by default, letting any client spoof its IP
+	// with a forwarded header; only the configured proxies are believed
+	if err := r.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		logger.Printf("Ignoring invalid trusted proxies: %v", err)
+		r.SetTrustedProxies(nil)
+	}
+	if opts.ClientIPHeader != "" {
+		r.RemoteIPHeaders = []string{opts.ClientIPHeader}
+	}
+	handler.sortedJSON = opts.SortedJSON
+	handler.strictJSON = opts.StrictJSON
+	handler.disallowUnknownFields = opts.DisallowUnknownFields
+	handler.idempotency = newIdempotencyCache(opts.IdempotencyTTL, opts.MaxIdempotencyKeys)
+	handler.outcomes = newRequestOutcomes()
+	handler.healthWeights = opts.HealthScoreWeights
+	handler.startedAt = opts.StartedAt
+	if handler.startedAt.IsZero() {
+		handler.startedAt = time.Now()
+	}
+	handler.build = opts.Build
+	if handler.build == (models.BuildInfo{}) {
+		handler.build = NewBuildInfo("", "", "")
+	}
 
 	// Apply middleware
+	r.Use(EnvelopeMiddleware(opts.Envelope))
+	r.Use(PrettyMiddleware(opts.PrettyJSON))
 	r.Use(LoggingMiddleware(logger))
 	r.Use(RecoveryMiddleware(logger))
 
-	// Health check
+	// Health check (not subject to the in-flight limit so probes keep working under load)
 	r.GET("/health", handler.HealthCheck)
+	r.GET("/openapi.json", handler.OpenAPI)
 
 	// API routes
 	api := r.Group("/api/v1")
+	api.Use(handler.ErrorRateMiddleware())
+	api.Use(ConcurrencyLimitMiddleware(opts.MaxInFlight))
+	api.Use(RequestTimeoutMiddleware(opts.RequestTimeout))
+	api.Use(handler.APIKeyMiddleware(opts.APIKeys))
+	api.Use(handler.TypeRateLimitMiddleware(opts.TypeRateLimits))
 	{
 		api.POST("/configs", handler.CreateConfig)
+		api.GET("/configs", handler.ListConfigs)
+		api.POST("/configs:method", handler.ConfigsMethod)
+		api.POST("/validate:method", handler.ValidateMethod)
 		api.GET("/configs/:name", handler.GetConfig)
 		api.PUT("/configs/:name", handler.UpdateConfig)
+		api.PATCH("/configs/:name", handler.PatchConfig)
+		api.DELETE("/configs/:name", handler.DeleteConfig)
+		api.GET("/configs/:name/data/*pointer", handler.GetConfigValue)
+		api.GET("/configs/:name/evaluate", handler.EvaluateRollout)
 		api.GET("/configs/:name/versions", handler.ListVersions)
+		api.GET("/configs/:name/diff", handler.DiffVersions)
+		api.POST("/configs/:name/versions/squash", handler.SquashVersions)
+		api.GET("/configs/:name/dependents", handler.ListDependents)
+		api.GET("/configs/:name/stats", handler.ConfigStats)
+		api.GET("/configs/:name/meta", handler.GetConfigMeta)
+		api.GET("/configs/:name/validate", handler.ValidateConfig)
+		api.GET("/configs/:name/wait", handler.WaitForChange)
 		api.POST("/configs/:name/rollback", handler.RollbackConfig)
+		api.POST("/configs/:name/rename", handler.RenameConfig)
+		api.POST("/configs/:name/unlock", AdminAuthMiddleware(opts.AdminToken), handler.UnlockConfig)
+		api.PUT("/configs/:name/deprecation", AdminAuthMiddleware(opts.AdminToken), handler.DeprecateConfig)
+		api.POST("/configs/:name/tags", handler.TagVersion)
+		api.POST("/configs/:name/proposals", handler.ProposeChange)
+		api.GET("/configs/:name/proposals", handler.ListProposals)
+		api.POST("/configs/:name/proposals/:id/approve", handler.ApproveProposal)
+		api.POST("/configs/:name/proposals/:id/reject", handler.RejectProposal)
+		api.GET("/schemas/:type/example", handler.GetSchemaExample)
+		api.PUT("/schemas/:type", AdminAuthMiddleware(opts.AdminToken), handler.RegisterSchema)
+		api.PUT("/schemas/:type/deprecation", AdminAuthMiddleware(opts.AdminToken), handler.DeprecateType)
+		api.POST("/transactions", handler.ExecuteTransaction)
+		api.GET("/compare", handler.CompareConfigs)
+		api.GET("/export", handler.ExportConfigs)
+		api.POST("/import", handler.ImportConfigs)
+		api.GET("/audit", handler.QueryAudit)
+	}
+
+	// Admin routes
+	admin := api.Group("/admin")
+	admin.Use(AdminAuthMiddleware(opts.AdminToken))
+	{
+		admin.POST("/compact", handler.Compact)
+		admin.GET("/validation-stats", handler.ValidationStats)
+		admin.POST("/schemas/reload", handler.ReloadSchemas)
+		admin.GET("/snapshot", handler.Snapshot)
+		admin.POST("/restore", handler.Restore)
+		admin.GET("/health-score", handler.HealthScore)
+		admin.POST("/rollback", handler.BulkRollback)
+		admin.POST("/verify", handler.Verify)
+		admin.POST("/policies/:type", handler.SetTypePolicy)
+		admin.GET("/routes", handler.Routes(r))
 	}
 
 	return r
-}
\ No newline at end of file
+}
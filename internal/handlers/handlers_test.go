@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestContextDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	})
+	logger := log.New(io.Discard, "", 0)
+	router := SetupRouter(NewConfigHandler(svc, logger), logger)
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/configs/payments", nil).WithContext(ctx))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status 504 once the request deadline passed, got %d", w.Code)
+	}
+}
+
+func TestVersionQueryBounds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	})
+	logger := log.New(io.Discard, "", 0)
+	router := SetupRouter(NewConfigHandler(svc, logger), logger)
+
+	tests := []struct {
+		name        string
+		query       string
+		status      int
+		wantDetails string
+	}{
+		{name: "existing version", query: "/api/v1/configs/payments?version=1", status: http.StatusOK},
+		{name: "largest allowed", query: "/api/v1/configs/payments?version=2147483647", status: http.StatusNotFound},
+		{name: "above bound", query: "/api/v1/configs/payments?version=2147483648", status: http.StatusBadRequest, wantDetails: "at most"},
+		{name: "overflow", query: "/api/v1/configs/payments?version=99999999999999999999999", status: http.StatusBadRequest, wantDetails: "at most"},
+		{name: "negative", query: "/api/v1/configs/payments?version=-5", status: http.StatusBadRequest, wantDetails: "positive"},
+		{name: "negative overflow", query: "/api/v1/configs/payments?version=-99999999999999999999999", status: http.StatusBadRequest, wantDetails: "positive"},
+		{name: "not a number", query: "/api/v1/configs/payments?version=latest", status: http.StatusBadRequest, wantDetails: "positive"},
+		{name: "wait overflow", query: "/api/v1/configs/payments/wait?since_version=99999999999999999999999", status: http.StatusBadRequest, wantDetails: "at most"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.query, nil))
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.wantDetails == "" {
+				return
+			}
+			var errResp models.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+				t.Fatalf("Failed to parse error response: %v", err)
+			}
+			if !strings.Contains(errResp.Details, tt.wantDetails) {
+				t.Errorf("Expected details mentioning %q, got %q", tt.wantDetails, errResp.Details)
+			}
+		})
+	}
+}
+
+func TestHealthCheckProcessInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	opts.StartedAt = time.Now().Add(-90 * time.Second)
+	opts.Build = NewBuildInfo("v1.2.3", "abc123", "2026-10-16T09:00:00Z")
+	router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to parse body: %v", err)
+	}
+	for _, key := range []string{"status", "uptime", "started_at", "build", "components"} {
+		if _, ok := body[key]; !ok {
+			t.Errorf("Expected key %q in %s", key, w.Body.String())
+		}
+	}
+
+	var health models.HealthResponse
+	json.Unmarshal(w.Body.Bytes(), &health)
+	if health.Uptime != "1m30s" {
+		t.Errorf("Expected uptime 1m30s, got %q", health.Uptime)
+	}
+	want := models.BuildInfo{Version: "v1.2.3", Commit: "abc123", BuildTime: "2026-10-16T09:00:00Z", GoVersion: runtime.Version()}
+	if health.Build != want {
+		t.Errorf("Expected build %+v, got %+v", want, health.Build)
+	}
+
+	// Without injected values the uptime still counts from router setup
+	router = SetupRouter(NewConfigHandler(svc, logger), logger)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	json.Unmarshal(w.Body.Bytes(), &health)
+	if health.Uptime == "" || health.StartedAt.IsZero() || health.Build.GoVersion == "" {
+		t.Errorf("Expected uptime, start time and Go version, got %+v", health)
+	}
+}
+
+func TestWriteWithFailedHook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	logger := log.New(io.Discard, "", 0)
+	opts := service.DefaultOptions()
+	opts.FailOnHookError = true
+	opts.Logger = logger
+	svc := service.NewConfigServiceWithOptions(repository.NewInMemoryRepository(), validator, opts)
+	svc.RegisterHook("payment_config", service.Hook{OnCreate: func(*models.Config) error { return errors.New("cache unavailable") }})
+	router := SetupRouter(NewConfigHandler(svc, logger), logger)
+
+	body := `{"name": "payments", "type": "payment_config", "data": {"max_limit": 100, "enabled": true}}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected the stored write to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if warning := w.Header().Get("Warning"); !strings.Contains(warning, "cache unavailable") {
+		t.Errorf("Expected the hook failure as a warning, got %q", warning)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit?name=payments", nil))
+	var audit models.AuditResponse
+	json.Unmarshal(w.Body.Bytes(), &audit)
+	if audit.Total != 1 || audit.Events[0].Action != models.AuditActionCreate {
+		t.Errorf("Expected the write to be audited, got %s", w.Body.String())
+	}
+}
+
+func TestGetConfigValueRedacted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	validator.RegisterSchema("gateway_config", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"api_key": map[string]interface{}{"type": "string", "secret": true},
+		},
+	})
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "gateway",
+		Type: "gateway_config",
+		Data: map[string]interface{}{"api_key": "sk_live_123"},
+	})
+	logger := log.New(io.Discard, "", 0)
+	router := SetupRouter(NewConfigHandler(svc, logger), logger)
+
+	tests := []struct {
+		query  string
+		status int
+		want   string
+	}{
+		{query: "", status: http.StatusOK, want: `"sk_live_123"`},
+		{query: "?redact=true", status: http.StatusOK, want: `"` + validation.RedactedValue + `"`},
+		{query: "?redact=maybe", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/configs/gateway/data/api_key"+tt.query, nil))
+		if w.Code != tt.status {
+			t.Fatalf("%q: expected status %d, got %d: %s", tt.query, tt.status, w.Code, w.Body.String())
+		}
+		if tt.want != "" && strings.TrimSpace(w.Body.String()) != tt.want {
+			t.Errorf("%q: expected %s, got %s", tt.query, tt.want, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorRateWindow is the span the health score's error rate is computed over,
+// kept as one bucket per second
+const errorRateWindow = 60 * time.Second
+
+// DefaultHealthScoreWeights weighs errors most: they are what clients notice
+func DefaultHealthScoreWeights() models.HealthScoreWeights {
+	return models.HealthScoreWeights{ErrorRate: 0.6, Capacity: 0.2, Memory: 0.2}
+}
+
+// ParseHealthScoreWeights parses a comma-separated list of signal=weight
+// entries, e.g. "error_rate=0.8,memory=0.2". Signals not listed weigh 0.
+func ParseHealthScoreWeights(list string) (models.HealthScoreWeights, error) {
+	var weights models.HealthScoreWeights
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		signal, weightStr, _ := strings.Cut(entry, "=")
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+		if err != nil || weight < 0 || math.IsInf(weight, 0) {
+			return weights, fmt.Errorf("invalid weight %q: want signal=weight with a non-negative weight", entry)
+		}
+		switch strings.TrimSpace(signal) {
+		case "error_rate":
+			weights.ErrorRate = weight
+		case "capacity":
+			weights.Capacity = weight
+		case "memory":
+			weights.Memory = weight
+		default:
+			return weights, fmt.Errorf("unknown signal %q (want error_rate, capacity or memory)", signal)
+		}
+	}
+	if weights.ErrorRate+weights.Capacity+weights.Memory == 0 {
+		return weights, fmt.Errorf("at least one weight must be positive")
+	}
+	return weights, nil
+}
+
+// healthScore combines the signals into a score from 0 to 100:
+//
+//	score = 100 × (1 − Σ wᵢ·sᵢ / Σ wᵢ)
+//
+// Each signal sᵢ is clamped to [0, 1], so the score is the weighted share of
+// headroom left, rounded to one decimal.
+func healthScore(signals models.HealthSignals, weights models.HealthScoreWeights) float64 {
+	total := weights.ErrorRate + weights.Capacity + weights.Memory
+	if total <= 0 {
+		return 100
+	}
+	clamp := func(v float64) float64 { return math.Max(0, math.Min(1, v)) }
+	load := weights.ErrorRate*clamp(signals.ErrorRate) +
+		weights.Capacity*clamp(signals.Capacity) +
+		weights.Memory*clamp(signals.Memory)
+	return math.Round(1000*(1-load/total)) / 10
+}
+
+// outcomeBucket counts the requests finished within one second
+type outcomeBucket struct {
+	second int64
+	total  int
+	errors int
+}
+
+// requestOutcomes counts requests and server errors over a sliding window of
+// one-second buckets; a bucket is reset when its second comes round again
+type requestOutcomes struct {
+	mu      sync.Mutex
+	buckets [int(errorRateWindow / time.Second)]outcomeBucket
+	now     func() time.Time
+}
+
+func newRequestOutcomes() *requestOutcomes {
+	return &requestOutcomes{now: time.Now}
+}
+
+// record counts one finished request
+func (o *requestOutcomes) record(failed bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	second := o.now().Unix()
+	bucket := &o.buckets[second%int64(len(o.buckets))]
+	if bucket.second != second {
+		*bucket = outcomeBucket{second: second}
+	}
+	bucket.total++
+	if failed {
+		bucket.errors++
+	}
+}
+
+// counts returns the requests and errors recorded within the window
+func (o *requestOutcomes) counts() (total, errors int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	oldest := o.now().Unix() - int64(len(o.buckets)) + 1
+	for _, bucket := range o.buckets {
+		if bucket.second >= oldest {
+			total += bucket.total
+			errors += bucket.errors
+		}
+	}
+	return total, errors
+}
+
+// ErrorRateMiddleware records the outcome of each request for the health
+// score; responses with a 5xx status count as errors
+func (h *ConfigHandler) ErrorRateMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		h.outcomes.record(c.Writer.Status() >= http.StatusInternalServerError)
+	}
+}
+
+// HealthScore handles GET /api/v1/admin/health-score
+func (h *ConfigHandler) HealthScore(c *gin.Context) {
+	used, limit, err := h.service.Capacity(c.Request.Context())
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	var signals models.HealthSignals
+	total, errors := h.outcomes.counts()
+	if total > 0 {
+		signals.ErrorRate = float64(errors) / float64(total)
+	}
+	if limit > 0 {
+		signals.Capacity = float64(used) / float64(limit)
+	}
+	signals.Memory = memoryPressure()
+
+	writeJSON(c, http.StatusOK, models.HealthScoreResponse{
+		Score:    healthScore(signals, h.healthWeights),
+		Signals:  signals,
+		Weights:  h.healthWeights,
+		Requests: total,
+		Window:   errorRateWindow.String(),
+	})
+}
+
+// memoryPressure is the share of the Go memory limit (GOMEMLIMIT) in use,
+// measured the way the runtime enforces the limit. Without a limit there
+// is nothing to compare against and it is 0.
+func memoryPressure() float64 {
+	limit := debug.SetMemoryLimit(-1)
+	if limit <= 0 || limit == math.MaxInt64 {
+		return 0
+	}
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return float64(stats.Sys-stats.HeapReleased) / float64(limit)
+}
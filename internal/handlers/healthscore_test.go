@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHealthScoreFormula(t *testing.T) {
+	weights := models.HealthScoreWeights{ErrorRate: 0.6, Capacity: 0.2, Memory: 0.2}
+
+	tests := []struct {
+		name    string
+		signals models.HealthSignals
+		want    float64
+	}{
+		{"healthy", models.HealthSignals{}, 100},
+		{"saturated", models.HealthSignals{ErrorRate: 1, Capacity: 1, Memory: 1}, 0},
+		{"errors weigh most", models.HealthSignals{ErrorRate: 0.5}, 70},
+		{"capacity", models.HealthSignals{Capacity: 0.5}, 90},
+		{"clamped", models.HealthSignals{Memory: 1.7}, 80},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := healthScore(tt.signals, weights); got != tt.want {
+				t.Errorf("Expected score %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRequestOutcomesWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	outcomes := newRequestOutcomes()
+	outcomes.now = func() time.Time { return now }
+
+	outcomes.record(true)
+	outcomes.record(false)
+	now = now.Add(30 * time.Second)
+	outcomes.record(false)
+	if total, errors := outcomes.counts(); total != 3 || errors != 1 {
+		t.Fatalf("Expected 3 requests and 1 error, got %d and %d", total, errors)
+	}
+
+	// The first second slides out of the window
+	now = now.Add(30 * time.Second)
+	if total, errors := outcomes.counts(); total != 1 || errors != 0 {
+		t.Errorf("Expected 1 request and no errors, got %d and %d", total, errors)
+	}
+
+	// A bucket is reused once its second comes round again
+	outcomes.record(true)
+	if total, errors := outcomes.counts(); total != 2 || errors != 1 {
+		t.Errorf("Expected 2 requests and 1 error, got %d and %d", total, errors)
+	}
+}
+
+func TestHealthScoreEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	repo := repository.NewInMemoryRepository()
+	repo.SetMaxConfigs(4)
+	svc := service.NewConfigService(repo, validator)
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	opts.AdminToken = "secret"
+	handler := NewConfigHandler(svc, logger)
+	router := SetupRouterWithOptions(handler, logger, opts)
+
+	score := func() models.HealthScoreResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/health-score", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var resp models.HealthScoreResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+
+	baseline := score()
+	if baseline.Signals.ErrorRate != 0 || baseline.Signals.Capacity != 0 {
+		t.Fatalf("Expected no load at startup, got %+v", baseline.Signals)
+	}
+
+	// Successful requests, including the score requests themselves, keep the error rate at 0
+	for i := 0; i < 8; i++ {
+		handler.outcomes.record(false)
+	}
+	if resp := score(); resp.Score != baseline.Score || resp.Requests != 9 {
+		t.Errorf("Expected the score to hold at %v over 9 requests, got %+v", baseline.Score, resp)
+	}
+
+	for i := 0; i < 10; i++ {
+		handler.outcomes.record(true)
+	}
+	withErrors := score()
+	if withErrors.Signals.ErrorRate != 0.5 || withErrors.Score >= baseline.Score {
+		t.Errorf("Expected errors to lower the score from %v, got %+v", baseline.Score, withErrors)
+	}
+
+	for i := 0; i < 2; i++ {
+		svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+			Name: fmt.Sprintf("payments_%d", i),
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+		})
+	}
+	withCapacity := score()
+	if withCapacity.Signals.Capacity != 0.5 || withCapacity.Score >= withErrors.Score {
+		t.Errorf("Expected capacity use to lower the score from %v, got %+v", withErrors.Score, withCapacity)
+	}
+}
+
+func TestParseHealthScoreWeights(t *testing.T) {
+	weights, err := ParseHealthScoreWeights("error_rate=0.8, memory=0.2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if weights != (models.HealthScoreWeights{ErrorRate: 0.8, Memory: 0.2}) {
+		t.Errorf("Unexpected weights %+v", weights)
+	}
+
+	for _, invalid := range []string{"latency=1", "error_rate=-1", "error_rate", "error_rate=0", ""} {
+		if _, err := ParseHealthScoreWeights(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// idempotencyKeyHeader lets clients retry a create without risking a 409
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayHeader marks a response replayed from an earlier request
+	idempotentReplayHeader = "Idempotent-Replayed"
+)
+
+// idempotencyEntry is the outcome of the first request made with a key.
+// The config is nil while that request is still being served.
+type idempotencyEntry struct {
+	fingerprint [sha256.Size]byte
+	config      *models.Config
+	expires     time.Time
+}
+
+// idempotencyCache remembers successful creates by Idempotency-Key so a
+// retried request gets the original response. It holds at most max keys,
+// each for ttl; when full the entry closest to expiry is evicted.
+type idempotencyCache struct {
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+	ttl     time.Duration
+	max     int
+	now     func() time.Time
+}
+
+// newIdempotencyCache returns a cache, or nil (idempotency disabled) when
+// ttl or max is not positive
+func newIdempotencyCache(ttl time.Duration, max int) *idempotencyCache {
+	if ttl <= 0 || max <= 0 {
+		return nil
+	}
+	return &idempotencyCache{
+		entries: make(map[string]*idempotencyEntry),
+		ttl:     ttl,
+		max:     max,
+		now:     time.Now,
+	}
+}
+
+// begin looks up key. If it is unknown the key is reserved for the caller,
+// who must later call complete or release, and begin returns nil.
+// Otherwise it returns the existing entry, whose config is nil while the
+// original request is still in flight.
+func (ic *idempotencyCache) begin(key string, fingerprint [sha256.Size]byte) *idempotencyEntry {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	now := ic.now()
+	if entry, exists := ic.entries[key]; exists {
+		if now.Before(entry.expires) {
+			return entry
+		}
+		delete(ic.entries, key)
+	}
+
+	if len(ic.entries) >= ic.max {
+		ic.evict(now)
+	}
+	ic.entries[key] = &idempotencyEntry{fingerprint: fingerprint, expires: now.Add(ic.ttl)}
+	return nil
+}
+
+// evict drops expired entries, or the one closest to expiry if none has expired
+func (ic *idempotencyCache) evict(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range ic.entries {
+		if !now.Before(entry.expires) {
+			delete(ic.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expires.Before(oldest) {
+			oldestKey, oldest = key, entry.expires
+		}
+	}
+	if len(ic.entries) >= ic.max {
+		delete(ic.entries, oldestKey)
+	}
+}
+
+// complete records the config created for a reserved key
+func (ic *idempotencyCache) complete(key string, config *models.Config) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	if entry, exists := ic.entries[key]; exists {
+		entry.config = config
+	}
+}
+
+// release frees a reserved key after a failed request so a retry runs again
+func (ic *idempotencyCache) release(key string) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	if entry, exists := ic.entries[key]; exists && entry.config == nil {
+		delete(ic.entries, key)
+	}
+}
+
+// beginIdempotent handles the Idempotency-Key header of a create request.
+// It returns the cache key the caller must complete or release, or "" when
+// the request carries no key. When it returns false the response has been
+// written: a replay of the original result, or an error for a key reused
+// with a different body or whose first request is still in flight.
+func (h *ConfigHandler) beginIdempotent(c *gin.Context, req *models.CreateConfigRequest) (string, bool) {
+	idempotencyKey := c.GetHeader(idempotencyKeyHeader)
+	if idempotencyKey == "" || h.idempotency == nil {
+		return "", true
+	}
+
+	// Scope keys to the caller so two clients picking the same key never collide
+	key := req.Actor + "\x00" + idempotencyKey
+	body, err := json.Marshal(req)
+	if err != nil {
+		h.logger.Printf("Failed to fingerprint request: %v", err)
+		writeJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal server error",
+			Details: "An unexpected error occurred",
+		})
+		return "", false
+	}
+	fingerprint := sha256.Sum256(body)
+
+	entry := h.idempotency.begin(key, fingerprint)
+	switch {
+	case entry == nil:
+		return key, true
+	case entry.fingerprint != fingerprint:
+		writeJSON(c, http.StatusUnprocessableEntity, models.ErrorResponse{
+			Error:   "Idempotency-Key reused",
+			Details: "the key was already used with a different request body",
+		})
+	case entry.config == nil:
+		c.Header("Retry-After", retryAfterSeconds)
+		writeJSON(c, http.StatusConflict, models.ErrorResponse{
+			Error:   "Request in progress",
+			Details: "a request with this Idempotency-Key is still being processed",
+		})
+	default:
+		c.Header(idempotentReplayHeader, "true")
+		c.Header("Location", configLocation(entry.config.Name))
+		h.writeConfig(c, http.StatusCreated, entry.config)
+	}
+	return "", false
+}
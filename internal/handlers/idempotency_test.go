@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIdempotentCreate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	router := SetupRouter(NewConfigHandler(svc, logger), logger)
+
+	const body = `{"name": "payments", "type": "payment_config", "data": {"max_limit": 100, "enabled": true}}`
+	create := func(key, actor, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(idempotencyKeyHeader, key)
+		}
+		if actor != "" {
+			req.Header.Set(actorHeader, actor)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := create("key-1", "", body)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", first.Code, first.Body.String())
+	}
+	if first.Header().Get(idempotentReplayHeader) != "" {
+		t.Error("Expected the first response not to be marked as a replay")
+	}
+
+	replay := create("key-1", "", body)
+	if replay.Code != http.StatusCreated {
+		t.Fatalf("Expected replay status 201, got %d: %s", replay.Code, replay.Body.String())
+	}
+	if replay.Header().Get(idempotentReplayHeader) != "true" {
+		t.Error("Expected the replay to be marked with Idempotent-Replayed")
+	}
+	var original, replayed models.Config
+	json.Unmarshal(first.Body.Bytes(), &original)
+	json.Unmarshal(replay.Body.Bytes(), &replayed)
+	if replayed.Version != original.Version || !replayed.CreatedAt.Equal(original.CreatedAt) {
+		t.Errorf("Expected the original config, got %+v", replayed)
+	}
+
+	tests := []struct {
+		name   string
+		key    string
+		actor  string
+		body   string
+		status int
+	}{
+		{"different body", "key-1", "", `{"name": "payments", "type": "payment_config", "data": {"max_limit": 200, "enabled": true}}`, http.StatusUnprocessableEntity},
+		{"no key", "", "", body, http.StatusConflict},
+		{"new key", "key-2", "", body, http.StatusConflict},
+		{"other actor", "key-1", "someone-else", body, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := create(tt.key, tt.actor, tt.body); w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestIdempotencyCacheEviction(t *testing.T) {
+	now := time.Now()
+	cache := newIdempotencyCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+	fingerprint := sha256.Sum256([]byte("body"))
+
+	// A failed request releases its key so a retry runs again
+	if cache.begin("a", fingerprint) != nil {
+		t.Fatal("Expected an unknown key to be reserved")
+	}
+	if entry := cache.begin("a", fingerprint); entry == nil || entry.config != nil {
+		t.Fatal("Expected an in-flight entry for a reserved key")
+	}
+	cache.release("a")
+	if cache.begin("a", fingerprint) != nil {
+		t.Fatal("Expected a released key to be reserved again")
+	}
+	cache.complete("a", &models.Config{Name: "a"})
+
+	// Filling the cache evicts the entry closest to expiry
+	now = now.Add(time.Second)
+	cache.begin("b", fingerprint)
+	cache.begin("c", fingerprint)
+	if len(cache.entries) != 2 {
+		t.Fatalf("Expected the cache to stay bounded at 2 keys, got %d", len(cache.entries))
+	}
+	if _, exists := cache.entries["a"]; exists {
+		t.Error("Expected the oldest key to be evicted")
+	}
+
+	// Keys are forgotten once their TTL passes
+	cache.complete("b", &models.Config{Name: "b"})
+	now = now.Add(time.Minute)
+	if cache.begin("b", fingerprint) != nil {
+		t.Error("Expected an expired key to be reserved afresh")
+	}
+
+	if newIdempotencyCache(0, 10) != nil {
+		t.Error("Expected a zero TTL to disable idempotency")
+	}
+}
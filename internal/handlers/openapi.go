@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-yaml"
+)
+
+// openAPIYAML is the hand-written OpenAPI 3 document describing every route
+//
+//go:embed openapi.yml
+var openAPIYAML []byte
+
+var (
+	openAPIOnce sync.Once
+	openAPIJSON []byte
+	openAPIErr  error
+)
+
+// OpenAPISpec returns the OpenAPI document as JSON, converting it on first use
+func OpenAPISpec() ([]byte, error) {
+	openAPIOnce.Do(func() {
+		openAPIJSON, openAPIErr = yaml.YAMLToJSON(openAPIYAML)
+		if openAPIErr != nil {
+			openAPIErr = fmt.Errorf("failed to convert OpenAPI spec: %w", openAPIErr)
+		}
+	})
+	return openAPIJSON, openAPIErr
+}
+
+// OpenAPI handles GET /openapi.json
+func (h *ConfigHandler) OpenAPI(c *gin.Context) {
+	spec, err := OpenAPISpec()
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+}
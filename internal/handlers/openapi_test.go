@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ginParam matches gin path parameters such as :name and catch-alls such as *pointer
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z_]+)`)
+
+func TestOpenAPIDocumentsAllRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	handler := NewConfigHandler(svc, logger)
+	router := SetupRouter(handler, logger)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Errorf("Expected an OpenAPI 3 document, got version %q", spec.OpenAPI)
+	}
+
+	customMethods := map[string]map[string]gin.HandlerFunc{
+		"/api/v1/configs":  handler.configsMethods(),
+		"/api/v1/validate": handler.validateMethods(),
+	}
+	for _, route := range router.Routes() {
+		paths := []string{ginParam.ReplaceAllString(route.Path, "{$1}")}
+		// Custom methods share one wildcard route; each is documented separately
+		if prefix, ok := strings.CutSuffix(route.Path, ":method"); ok {
+			methods, known := customMethods[prefix]
+			if !known {
+				t.Errorf("Custom method route %s has no method map in this test", route.Path)
+			}
+			paths = paths[:0]
+			for method := range methods {
+				paths = append(paths, prefix+":"+method)
+			}
+		}
+		for _, path := range paths {
+			if _, ok := spec.Paths[path][strings.ToLower(route.Method)]; !ok {
+				t.Errorf("Route %s %s is not documented in openapi.yml", route.Method, path)
+			}
+		}
+	}
+}
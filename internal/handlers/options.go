@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"config-engine/internal/models"
+)
+
+const (
+	// DefaultMaxInFlight is the default limit on concurrently served API requests
+	DefaultMaxInFlight = 1024
+	// DefaultIdempotencyTTL is how long a create's Idempotency-Key is remembered
+	DefaultIdempotencyTTL = 24 * time.Hour
+	// DefaultMaxIdempotencyKeys bounds the number of remembered Idempotency-Keys
+	DefaultMaxIdempotencyKeys = 10000
+	// DefaultClientIPHeader is the forwarded header read from trusted proxies
+	DefaultClientIPHeader = "X-Forwarded-For"
+)
+
+// RouterOptions configures optional router behaviour
+type RouterOptions struct {
+	// MaxInFlight is the maximum number of API requests served concurrently (0 disables the limit)
+	MaxInFlight int
+	// SortedJSON sorts the keys of every object in config responses, struct fields
+	// included, so bodies are canonical
+	SortedJSON bool
+	// StrictJSON rejects request bodies with duplicate keys instead of keeping the last value
+	StrictJSON bool
+	// DisallowUnknownFields rejects request bodies with fields the request type does not define
+	DisallowUnknownFields bool
+	// AdminToken is the bearer token required by admin routes (empty disables them)
+	AdminToken string
+	// APIKeys maps each accepted X-API-Key to its scope (empty leaves the API open)
+	APIKeys map[string]APIKeyScope
+	// Envelope wraps every JSON response in {"data": ..., "error": ...}; when
+	// disabled clients can still opt in per request via the Accept header
+	Envelope bool
+	// PrettyJSON indents JSON responses by default; clients can still choose
+	// per request with the pretty query parameter
+	PrettyJSON bool
+	// IdempotencyTTL is how long creates are remembered by Idempotency-Key (0 disables replay)
+	IdempotencyTTL time.Duration
+	// MaxIdempotencyKeys bounds the remembered keys; the oldest are evicted first
+	MaxIdempotencyKeys int
+	// TrustedProxies lists the IPs and CIDRs of proxies whose ClientIPHeader is
+	// believed. Any client can set forwarded headers, so only list proxies that
+	// overwrite them; with none the peer address is always the client IP.
+	TrustedProxies []string
+	// ClientIPHeader is the header carrying the client IP set by a trusted
+	// proxy: X-Forwarded-For or X-Real-IP
+	ClientIPHeader string
+	// RequestTimeout is the deadline for serving an API request; long-polls
+	// and streams are exempt (0 disables it)
+	RequestTimeout time.Duration
+	// TypeRateLimits caps the write rate per config type (types not listed are unlimited)
+	TypeRateLimits map[string]RateLimit
+	// HealthScoreWeights weighs the signals of the admin health score
+	HealthScoreWeights models.HealthScoreWeights
+	// StartedAt is when the process started, for the uptime in health
+	// responses (zero means when the router is set up)
+	StartedAt time.Time
+	// Build identifies the running build in health responses (zero means
+	// NewBuildInfo without injected values)
+	Build models.BuildInfo
+}
+
+// DefaultRouterOptions returns the options used by SetupRouter
+func DefaultRouterOptions() RouterOptions {
+	return RouterOptions{
+		MaxInFlight:        DefaultMaxInFlight,
+		IdempotencyTTL:     DefaultIdempotencyTTL,
+		MaxIdempotencyKeys: DefaultMaxIdempotencyKeys,
+		ClientIPHeader:     DefaultClientIPHeader,
+		HealthScoreWeights: DefaultHealthScoreWeights(),
+	}
+}
+
+// ParseTrustedProxies splits a comma-separated list of proxy IPs and CIDRs,
+// rejecting entries that are neither
+func ParseTrustedProxies(list string) ([]string, error) {
+	var proxies []string
+	for _, proxy := range strings.Split(list, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: want an IP or CIDR", proxy)
+			}
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
+
+// ParseClientIPHeader canonicalizes the client IP header name, accepting
+// only X-Forwarded-For and X-Real-IP
+func ParseClientIPHeader(header string) (string, error) {
+	switch canonical := http.CanonicalHeaderKey(strings.TrimSpace(header)); canonical {
+	case "X-Forwarded-For", "X-Real-Ip":
+		return canonical, nil
+	default:
+		return "", fmt.Errorf("unsupported client IP header %q (want X-Forwarded-For or X-Real-IP)", header)
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTrustedProxyClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+
+	tests := []struct {
+		name    string
+		proxies []string
+		header  string
+		set     map[string]string
+		want    string
+	}{
+		{"untrusted forwarded header", nil, "", map[string]string{"X-Forwarded-For": "203.0.113.7"}, "192.0.2.1"},
+		{"trusted proxy", []string{"192.0.2.0/24"}, "", map[string]string{"X-Forwarded-For": "203.0.113.7"}, "203.0.113.7"},
+		{"trusted proxy chain", []string{"192.0.2.1", "10.0.0.0/8"}, "", map[string]string{"X-Forwarded-For": "203.0.113.7, 10.1.2.3"}, "203.0.113.7"},
+		{"other proxy", []string{"198.51.100.1"}, "", map[string]string{"X-Forwarded-For": "203.0.113.7"}, "192.0.2.1"},
+		{"real ip header", []string{"192.0.2.1"}, "X-Real-Ip", map[string]string{"X-Real-IP": "203.0.113.9", "X-Forwarded-For": "203.0.113.7"}, "203.0.113.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := log.New(&logs, "", 0)
+			opts := DefaultRouterOptions()
+			opts.TrustedProxies = tt.proxies
+			if tt.header != "" {
+				opts.ClientIPHeader = tt.header
+			}
+			router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+			// httptest requests come from 192.0.2.1
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			for k, v := range tt.set {
+				req.Header.Set(k, v)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if !strings.HasPrefix(logs.String(), tt.want+" GET /health") {
+				t.Errorf("Expected client IP %s, got log %q", tt.want, logs.String())
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies(" 10.0.0.1, 192.168.0.0/16,,::1 ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(proxies, " ") != "10.0.0.1 192.168.0.0/16 ::1" {
+		t.Errorf("Unexpected proxies %v", proxies)
+	}
+	if proxies, _ := ParseTrustedProxies(""); len(proxies) != 0 {
+		t.Errorf("Expected no proxies, got %v", proxies)
+	}
+	if _, err := ParseTrustedProxies("10.0.0.1,loadbalancer"); err == nil {
+		t.Error("Expected an error for a hostname")
+	}
+
+	if header, err := ParseClientIPHeader("x-real-ip"); err != nil || header != "X-Real-Ip" {
+		t.Errorf("Expected X-Real-Ip, got %q (%v)", header, err)
+	}
+	if _, err := ParseClientIPHeader("Forwarded"); err == nil {
+		t.Error("Expected an error for an unsupported header")
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// configLocation returns the URL path of a configuration for Location headers.
+// The name is escaped so it stays a single path segment.
+func configLocation(name string) string {
+	return "/api/v1/configs/" + url.PathEscape(name)
+}
+
+// nameParam returns the :name path parameter, writing a 400 response and
+// returning false when it is empty or contains a slash or control characters.
+func (h *ConfigHandler) nameParam(c *gin.Context) (string, bool) {
+	name := c.Param("name")
+
+	var details string
+	switch {
+	case strings.TrimSpace(name) == "":
+		details = "name must not be empty"
+	case strings.Contains(name, "/"):
+		details = "name must not contain '/'"
+	case strings.IndexFunc(name, unicode.IsControl) >= 0:
+		details = "name must not contain control characters"
+	default:
+		return name, true
+	}
+
+	writeJSON(c, http.StatusBadRequest, models.ErrorResponse{
+		Error:   "Invalid config name",
+		Details: details,
+	})
+	return "", false
+}
+
+// maxPageLimit caps the page size of paginated lists
+const maxPageLimit = 1000
+
+// parsePagination parses the optional limit and offset query parameters,
+// writing a 400 response and returning false when either is invalid. Limits
+// above maxPageLimit are lowered to it.
+func (h *ConfigHandler) parsePagination(c *gin.Context, defaultLimit int) (limit, offset int, ok bool) {
+	limit = defaultLimit
+	var err error
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit < 1 {
+			h.badQuery(c, "limit", "limit must be a positive integer")
+			return 0, 0, false
+		}
+		limit = min(limit, maxPageLimit)
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if offset, err = strconv.Atoi(offsetStr); err != nil || offset < 0 {
+			h.badQuery(c, "offset", "offset must be a non-negative integer")
+			return 0, 0, false
+		}
+	}
+	return limit, offset, true
+}
+
+// parseDryRun parses the optional dry_run query parameter, writing a 400
+// response and returning false when it is not a boolean
+func (h *ConfigHandler) parseDryRun(c *gin.Context) (dryRun, ok bool) {
+	dryRunStr := c.Query("dry_run")
+	if dryRunStr == "" {
+		return false, true
+	}
+
+	dryRun, err := strconv.ParseBool(dryRunStr)
+	if err != nil {
+		h.badQuery(c, "dry_run", "dry_run must be a boolean")
+		return false, false
+	}
+	return dryRun, true
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestInvalidNameParam(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	router := SetupRouter(NewConfigHandler(svc, logger), logger)
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		body          string
+		expectDetails string
+	}{
+		{name: "encoded slash", method: http.MethodGet, path: "/api/v1/configs/a%2Fb", expectDetails: "name must not contain '/'"},
+		{name: "control character", method: http.MethodGet, path: "/api/v1/configs/a%00b/versions", expectDetails: "name must not contain control characters"},
+		{name: "blank name", method: http.MethodPut, path: "/api/v1/configs/%20", body: `{"data":{}}`, expectDetails: "name must not be empty"},
+		{name: "rollback with newline", method: http.MethodPost, path: "/api/v1/configs/a%0Ab/rollback", body: `{"version":1}`, expectDetails: "name must not contain control characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+			var errResp models.ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &errResp)
+			if errResp.Error != "Invalid config name" {
+				t.Errorf("Expected error 'Invalid config name', got '%s'", errResp.Error)
+			}
+			if errResp.Details != tt.expectDetails {
+				t.Errorf("Expected details '%s', got '%s'", tt.expectDetails, errResp.Details)
+			}
+		})
+	}
+}
+
+func TestCreateLocationHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	router := SetupRouter(NewConfigHandler(svc, logger), logger)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs",
+		strings.NewReader(`{"name": " Payments ", "type": "payment_config", "data": {"max_limit": 100, "enabled": true}}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The header points at the normalized name, which the config is served under
+	location := w.Header().Get("Location")
+	if location != "/api/v1/configs/payments" {
+		t.Fatalf("Expected Location /api/v1/configs/payments, got %q", location)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, location, nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the Location to resolve, got status %d", w.Code)
+	}
+
+	tests := map[string]string{
+		"payment_config": "/api/v1/configs/payment_config",
+		"a b":            "/api/v1/configs/a%20b",
+		"a/b?c#d":        "/api/v1/configs/a%2Fb%3Fc%23d",
+		"café":           "/api/v1/configs/caf%C3%A9",
+	}
+	for name, want := range tests {
+		if got := configLocation(name); got != want {
+			t.Errorf("configLocation(%q): expected %q, got %q", name, want, got)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProposeChange handles POST /api/v1/configs/{name}/proposals
+func (h *ConfigHandler) ProposeChange(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateConfigRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	proposal, err := h.service.ProposeChange(c.Request.Context(), name, &req, actorFrom(c))
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.recordAudit(c, models.AuditActionPropose, proposal.Name, proposal.BaseVersion)
+	h.writeConfig(c, http.StatusCreated, proposal)
+}
+
+// ListProposals handles GET /api/v1/configs/{name}/proposals
+func (h *ConfigHandler) ListProposals(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	proposals, err := h.service.ListProposals(c.Request.Context(), name)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.writeConfig(c, http.StatusOK, proposals)
+}
+
+// ApproveProposal handles POST /api/v1/configs/{name}/proposals/{id}/approve
+func (h *ConfigHandler) ApproveProposal(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+
+	revalidate := false
+	if revalidateStr := c.Query("revalidate"); revalidateStr != "" {
+		v, err := strconv.ParseBool(revalidateStr)
+		if err != nil {
+			h.badQuery(c, "revalidate", "revalidate must be a boolean")
+			return
+		}
+		revalidate = v
+	}
+
+	config, err := h.service.ApproveProposal(c.Request.Context(), name, id, revalidate)
+	if !h.handleWriteError(c, err) {
+		return
+	}
+
+	h.recordConfigAudit(c, models.AuditActionApprove, config, "")
+	h.writeConfig(c, http.StatusOK, config)
+}
+
+// RejectProposal handles POST /api/v1/configs/{name}/proposals/{id}/reject
+func (h *ConfigHandler) RejectProposal(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+
+	if err := h.service.RejectProposal(c.Request.Context(), name, id); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.recordAudit(c, models.AuditActionReject, name, 0)
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitScopeHeader names the limit that rejected a request
+const rateLimitScopeHeader = "X-RateLimit-Scope"
+
+// RateLimit is a token bucket allowing Rate requests per second on average
+// and bursts of up to Burst requests
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// ParseTypeRateLimits parses a comma-separated list of type=rate[:burst]
+// entries, e.g. "payment_config=5:10,feature_flags=20". Rates are writes per
+// second; the burst defaults to the rate rounded up.
+func ParseTypeRateLimits(list string) (map[string]RateLimit, error) {
+	limits := make(map[string]RateLimit)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		configType, spec, ok := strings.Cut(entry, "=")
+		configType = strings.TrimSpace(configType)
+		if !ok || configType == "" {
+			return nil, fmt.Errorf("invalid rate limit %q: want type=rate[:burst]", entry)
+		}
+		if _, exists := limits[configType]; exists {
+			return nil, fmt.Errorf("duplicate rate limit for type %q", configType)
+		}
+
+		rateStr, burstStr, hasBurst := strings.Cut(spec, ":")
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+		if err != nil || rate <= 0 || math.IsInf(rate, 0) {
+			return nil, fmt.Errorf("invalid rate limit %q: rate must be a positive number", entry)
+		}
+		burst := int(math.Ceil(rate))
+		if hasBurst {
+			burst, err = strconv.Atoi(strings.TrimSpace(burstStr))
+			if err != nil || burst <= 0 {
+				return nil, fmt.Errorf("invalid rate limit %q: burst must be a positive integer", entry)
+			}
+		}
+		limits[configType] = RateLimit{Rate: rate, Burst: burst}
+	}
+	return limits, nil
+}
+
+// tokenBucket holds the tokens left for one config type
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// typeRateLimiter keeps one token bucket per limited config type
+type typeRateLimiter struct {
+	mu      sync.Mutex
+	limits  map[string]RateLimit
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+func newTypeRateLimiter(limits map[string]RateLimit) *typeRateLimiter {
+	return &typeRateLimiter{
+		limits:  limits,
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// allow takes a token from the bucket of every limited type, or from none.
+// When a bucket is empty it returns that type and how long until it refills.
+func (l *typeRateLimiter) allow(types []string) (string, time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var take []*tokenBucket
+	for _, configType := range types {
+		limit, limited := l.limits[configType]
+		if !limited {
+			continue
+		}
+		bucket, exists := l.buckets[configType]
+		if !exists {
+			bucket = &tokenBucket{tokens: float64(limit.Burst), last: now}
+			l.buckets[configType] = bucket
+		}
+		bucket.tokens = math.Min(float64(limit.Burst), bucket.tokens+now.Sub(bucket.last).Seconds()*limit.Rate)
+		bucket.last = now
+		if bucket.tokens < 1 {
+			wait := time.Duration((1 - bucket.tokens) / limit.Rate * float64(time.Second))
+			return configType, wait, false
+		}
+		take = append(take, bucket)
+	}
+
+	for _, bucket := range take {
+		bucket.tokens--
+	}
+	return "", 0, true
+}
+
+// TypeRateLimitMiddleware caps the rate of writes to configurations of each
+// type in limits, so one busy type cannot crowd out the others. Reads are
+// never limited. The type comes from the request body for creates,
+// transactions and imports, and from the stored configuration otherwise; requests whose
+// type cannot be resolved are passed on for the handler to reject.
+func (h *ConfigHandler) TypeRateLimitMiddleware(limits map[string]RateLimit) gin.HandlerFunc {
+	if len(limits) == 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	limiter := newTypeRateLimiter(limits)
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		configType, wait, ok := limiter.allow(h.writeTypes(c))
+		if ok {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		c.Header(rateLimitScopeHeader, "type="+configType)
+		abortJSON(c, http.StatusTooManyRequests, models.ErrorResponse{
+			Error:   "Rate limit exceeded",
+			Details: "too many writes to configurations of type " + configType + ", retry later",
+		})
+	}
+}
+
+// writeTypes returns the distinct config types a write request touches
+func (h *ConfigHandler) writeTypes(c *gin.Context) []string {
+	seen := make(map[string]bool)
+	addName := func(name string) {
+		if config, err := h.service.GetConfig(c.Request.Context(), name, nil); err == nil {
+			seen[config.Type] = true
+		}
+	}
+
+	switch c.FullPath() {
+	case "/api/v1/configs":
+		var req struct {
+			Type string `json:"type"`
+		}
+		if peekJSON(c, &req) {
+			seen[req.Type] = true
+		}
+	case "/api/v1/transactions":
+		var req models.TransactionRequest
+		if peekJSON(c, &req) {
+			for _, op := range req.Operations {
+				if op.Op == models.OperationCreate {
+					seen[op.Type] = true
+				} else {
+					addName(op.Name)
+				}
+			}
+		}
+	case "/api/v1/import":
+		var req models.ImportRequest
+		if peekJSON(c, &req) {
+			for _, record := range req.Configs {
+				if record.Config != nil {
+					// An import may also replace a stored config of another type
+					seen[record.Config.Type] = true
+					addName(record.Config.Name)
+				}
+			}
+		}
+	default:
+		if name := c.Param("name"); name != "" {
+			addName(name)
+		}
+	}
+
+	types := make([]string, 0, len(seen))
+	for configType := range seen {
+		types = append(types, configType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// peekJSON decodes the request body into v and puts the body back for the
+// handler. A body that cannot be decoded is left for the handler to report.
+func peekJSON(c *gin.Context, v interface{}) bool {
+	if c.Request.Body == nil {
+		return false
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return err == nil && json.Unmarshal(body, v) == nil
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTypeRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	validator.RegisterSchema("flag_config", map[string]interface{}{"type": "object"})
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	opts := DefaultRouterOptions()
+	// A rate this low never refills within the test
+	opts.TypeRateLimits = map[string]RateLimit{"payment_config": {Rate: 0.001, Burst: 2}}
+	router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+	send := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != nil {
+			encoded, _ := json.Marshal(body)
+			reader = bytes.NewReader(encoded)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, reader))
+		return w
+	}
+	create := func(name, configType string) *httptest.ResponseRecorder {
+		return send(http.MethodPost, "/api/v1/configs", models.CreateConfigRequest{
+			Name: name,
+			Type: configType,
+			Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+		})
+	}
+
+	for _, name := range []string{"payments_a", "payments_b"} {
+		if w := create(name, "payment_config"); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201 within the burst, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	w := create("payments_c", "payment_config")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429 over the limit, got %d", w.Code)
+	}
+	if scope := w.Header().Get(rateLimitScopeHeader); scope != "type=payment_config" {
+		t.Errorf("Expected scope type=payment_config, got %q", scope)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("Expected a Retry-After header")
+	}
+
+	// Updates resolve the type from the stored config
+	w = send(http.MethodPut, "/api/v1/configs/payments_a", models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 200, "enabled": true},
+	})
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected update to be limited, got %d", w.Code)
+	}
+	w = send(http.MethodPost, "/api/v1/transactions", models.TransactionRequest{Operations: []models.TransactionOperation{
+		{Op: models.OperationCreate, Name: "flags_tx", Type: "flag_config", Data: map[string]interface{}{}},
+		{Op: models.OperationRollback, Name: "payments_b", Version: 1},
+	}})
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected a transaction touching a limited type to be limited, got %d", w.Code)
+	}
+	w = send(http.MethodPost, "/api/v1/import", models.ImportRequest{Configs: []models.ExportRecord{
+		{Config: &models.Config{Name: "payments_import", Type: "payment_config", Data: map[string]interface{}{"max_limit": 100, "enabled": true}}},
+	}})
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected an import of a limited type to be limited, got %d", w.Code)
+	}
+
+	// Other types and reads are unaffected
+	for i, name := range []string{"flags_a", "flags_b", "flags_c"} {
+		w := send(http.MethodPost, "/api/v1/configs", models.CreateConfigRequest{Name: name, Type: "flag_config", Data: map[string]interface{}{"on": i > 0}})
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected other types to be unlimited, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	if w := send(http.MethodGet, "/api/v1/configs/payments_a", nil); w.Code != http.StatusOK {
+		t.Errorf("Expected reads to be unlimited, got %d", w.Code)
+	}
+}
+
+func TestTypeRateLimiterRefills(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newTypeRateLimiter(map[string]RateLimit{"payment_config": {Rate: 2, Burst: 1}})
+	limiter.now = func() time.Time { return now }
+
+	if _, _, ok := limiter.allow([]string{"payment_config"}); !ok {
+		t.Fatal("Expected the first write to be allowed")
+	}
+	configType, wait, ok := limiter.allow([]string{"payment_config", "flag_config"})
+	if ok || configType != "payment_config" || wait != 500*time.Millisecond {
+		t.Fatalf("Expected payment_config to wait 500ms, got %q %v %v", configType, wait, ok)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if _, _, ok := limiter.allow([]string{"payment_config"}); !ok {
+		t.Error("Expected the bucket to refill")
+	}
+}
+
+func TestParseTypeRateLimits(t *testing.T) {
+	limits, err := ParseTypeRateLimits(" payment_config=5:10, flag_config=0.5 ,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if limits["payment_config"] != (RateLimit{Rate: 5, Burst: 10}) || limits["flag_config"] != (RateLimit{Rate: 0.5, Burst: 1}) || len(limits) != 2 {
+		t.Errorf("Unexpected limits %+v", limits)
+	}
+
+	for _, invalid := range []string{"payment_config", "=5", "payment_config=0", "payment_config=fast", "payment_config=5:0", "a=1,a=2"} {
+		if _, err := ParseTypeRateLimits(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// envelopeKey marks requests whose responses are wrapped in models.Envelope
+	envelopeKey = "envelope"
+	// prettyKey marks requests whose responses are indented for humans
+	prettyKey = "pretty"
+	// warningsKey holds the warnings added to a response
+	warningsKey = "warnings"
+)
+
+// EnvelopeMiddleware decides per request whether responses are enveloped:
+// always when enabled at startup, otherwise only when the client sends an
+// Accept media type with an envelope parameter, e.g.
+// "Accept: application/json; envelope=true".
+func EnvelopeMiddleware(always bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if always || acceptsEnvelope(c.GetHeader("Accept")) {
+			c.Set(envelopeKey, true)
+		}
+		c.Next()
+	}
+}
+
+// acceptsEnvelope reports whether any media range in an Accept header asks for an envelope
+func acceptsEnvelope(accept string) bool {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		if enabled, err := strconv.ParseBool(params["envelope"]); err == nil && enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// PrettyMiddleware decides per request whether JSON responses are indented:
+// by default when enabled at startup, overridden by a "pretty" query
+// parameter. Indentation only changes the bytes on the wire; anything derived
+// from a response body, such as a checksum, must use the compact encoding.
+func PrettyMiddleware(byDefault bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		pretty := byDefault
+		if prettyStr := c.Query("pretty"); prettyStr != "" {
+			v, err := strconv.ParseBool(prettyStr)
+			if err != nil {
+				abortJSON(c, http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid pretty parameter",
+					Details: "pretty must be a boolean",
+				})
+				return
+			}
+			pretty = v
+		}
+		if pretty {
+			c.Set(prettyKey, true)
+		}
+		c.Next()
+	}
+}
+
+// enveloped wraps a response body when the request asked for an envelope.
+// Bodies of 4xx and 5xx responses go into the error field.
+func enveloped(c *gin.Context, status int, obj interface{}) interface{} {
+	if !c.GetBool(envelopeKey) {
+		return obj
+	}
+	if status >= http.StatusBadRequest {
+		return models.Envelope{Error: obj}
+	}
+	return models.Envelope{Data: obj, Warnings: c.GetStringSlice(warningsKey)}
+}
+
+// addWarning adds an RFC 7234 Warning header (code 299, miscellaneous
+// persistent warning) to the response; enveloped responses also list the
+// message in their warnings field. Call it before writing the body.
+func addWarning(c *gin.Context, message string) {
+	quoted := strings.ReplaceAll(strings.ReplaceAll(message, `\`, `\\`), `"`, `\"`)
+	c.Writer.Header().Add("Warning", `299 - "`+quoted+`"`)
+	c.Set(warningsKey, append(c.GetStringSlice(warningsKey), message))
+}
+
+// writeJSON writes a JSON response; every handler writes its body through it
+// or writeConfig so the envelope and pretty settings apply uniformly
+func writeJSON(c *gin.Context, status int, obj interface{}) {
+	if c.GetBool(prettyKey) {
+		c.IndentedJSON(status, enveloped(c, status, obj))
+		return
+	}
+	c.JSON(status, enveloped(c, status, obj))
+}
+
+// abortJSON writes a JSON response and stops the handler chain
+func abortJSON(c *gin.Context, status int, obj interface{}) {
+	c.Abort()
+	writeJSON(c, status, obj)
+}
+
+// writeConfig writes a response that carries config data. When sorted JSON is
+// enabled the body is canonical: every object's keys are in sorted order,
+// including the fields of the response structs, which encoding/json otherwise
+// emits in declaration order. Clients can then hash or diff bodies, e.g. for
+// ETags and golden files, without knowing how a response type is declared.
+func (h *ConfigHandler) writeConfig(c *gin.Context, status int, obj interface{}) {
+	if !h.sortedJSON {
+		writeJSON(c, status, obj)
+		return
+	}
+
+	body, err := sortedJSON(enveloped(c, status, obj), c.GetBool(prettyKey))
+	if err != nil {
+		h.logger.Printf("Failed to encode response: %v", err)
+		writeJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal server error",
+			Details: "An unexpected error occurred",
+		})
+		return
+	}
+	c.Data(status, "application/json; charset=utf-8", body)
+}
+
+// sortedJSON encodes obj with the keys of every object sorted. Re-encoding
+// the generic decoding does it, as encoding/json sorts map keys; numbers are
+// decoded as json.Number so they keep their exact text.
+func sortedJSON(obj interface{}, pretty bool) ([]byte, error) {
+	encoded, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := models.DecodeJSON(encoded, &doc); err != nil {
+		return nil, err
+	}
+	if pretty {
+		return json.MarshalIndent(doc, "", "    ")
+	}
+	return json.Marshal(doc)
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSortedJSONResponsesAreStable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	validator.RegisterSchema("open_config", map[string]interface{}{"type": "object"})
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+
+	data := make(map[string]interface{})
+	for i := 0; i < 50; i++ {
+		data[fmt.Sprintf("key_%02d", 49-i)] = map[string]interface{}{"z": i, "a": i}
+	}
+	if _, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{Name: "stable", Type: "open_config", Data: data}); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	logger := log.New(io.Discard, "", 0)
+	get := func(sorted bool) []byte {
+		opts := DefaultRouterOptions()
+		opts.SortedJSON = sorted
+		router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/configs/stable", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		return w.Body.Bytes()
+	}
+
+	first := get(true)
+	for i := 0; i < 10; i++ {
+		if body := get(true); !bytes.Equal(first, body) {
+			t.Fatalf("Expected byte-identical bodies, got:\n%s\n%s", first, body)
+		}
+	}
+
+	body := string(first)
+	if strings.Index(body, `"key_00"`) > strings.Index(body, `"key_49"`) {
+		t.Error("Expected data keys in sorted order")
+	}
+	if strings.Index(body, `"a":`) > strings.Index(body, `"z":`) {
+		t.Error("Expected nested keys in sorted order")
+	}
+
+	// Without the flag the fields of the config keep their declaration order
+	unsorted := get(false)
+	if bytes.Equal(first, unsorted) {
+		t.Fatal("Expected sorting to change the body")
+	}
+	for _, body := range [][]byte{first, unsorted} {
+		var config models.Config
+		if err := json.Unmarshal(body, &config); err != nil || config.Name != "stable" {
+			t.Fatalf("Expected both bodies to decode to the config, got %s", body)
+		}
+	}
+	if strings.Index(body, `"data":`) > strings.Index(body, `"name":`) {
+		t.Error("Expected the config fields in sorted order")
+	}
+	if text := string(unsorted); strings.Index(text, `"name":`) > strings.Index(text, `"data":`) {
+		t.Error("Expected the unsorted body to keep the declaration order")
+	}
+}
+
+func TestEnvelopeResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	})
+	logger := log.New(io.Discard, "", 0)
+
+	tests := []struct {
+		name         string
+		always       bool
+		accept       string
+		path         string
+		wantStatus   int
+		wantEnvelope bool
+	}{
+		{"default bare success", false, "", "/api/v1/configs/payments", http.StatusOK, false},
+		{"default bare error", false, "application/json", "/api/v1/configs/missing", http.StatusNotFound, false},
+		{"accept param success", false, "application/json; envelope=true", "/api/v1/configs/payments", http.StatusOK, true},
+		{"accept param error", false, "text/html, application/json;envelope=1", "/api/v1/configs/missing", http.StatusNotFound, true},
+		{"startup flag success", true, "", "/api/v1/configs/payments", http.StatusOK, true},
+		{"startup flag error", true, "", "/api/v1/configs/missing", http.StatusNotFound, true},
+		{"startup flag health", true, "", "/health", http.StatusOK, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultRouterOptions()
+			opts.Envelope = tt.always
+			router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to parse body: %v", err)
+			}
+			_, hasData := body["data"]
+			_, hasError := body["error"]
+			if !tt.wantEnvelope {
+				// Bare config responses have a "data" field of their own, but never both
+				if hasData && hasError {
+					t.Errorf("Expected a bare body, got %s", w.Body.String())
+				}
+				return
+			}
+
+			if !hasData || !hasError {
+				t.Fatalf("Expected an envelope, got %s", w.Body.String())
+			}
+			if w.Code >= http.StatusBadRequest {
+				var errResp models.ErrorResponse
+				if string(body["data"]) != "null" || json.Unmarshal(body["error"], &errResp) != nil || errResp.Error == "" {
+					t.Errorf("Expected data null and an ErrorResponse, got %s", w.Body.String())
+				}
+				return
+			}
+			if string(body["error"]) != "null" || string(body["data"]) == "null" {
+				t.Errorf("Expected error null and a payload, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPrettyResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	})
+	logger := log.New(io.Discard, "", 0)
+
+	tests := []struct {
+		name       string
+		byDefault  bool
+		sorted     bool
+		path       string
+		wantStatus int
+		wantPretty bool
+	}{
+		{"default compact", false, false, "/api/v1/configs/payments", http.StatusOK, false},
+		{"query success", false, false, "/api/v1/configs/payments?pretty=true", http.StatusOK, true},
+		{"query error", false, false, "/api/v1/configs/missing?pretty=1", http.StatusNotFound, true},
+		{"query sorted", false, true, "/api/v1/configs/payments?pretty=true", http.StatusOK, true},
+		{"startup flag", true, false, "/api/v1/configs/payments", http.StatusOK, true},
+		{"startup flag opt out", true, false, "/api/v1/configs/payments?pretty=false", http.StatusOK, false},
+		{"invalid value", false, false, "/api/v1/configs/payments?pretty=maybe", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultRouterOptions()
+			opts.PrettyJSON = tt.byDefault
+			opts.SortedJSON = tt.sorted
+			router := SetupRouterWithOptions(NewConfigHandler(svc, logger), logger, opts)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if !json.Valid(w.Body.Bytes()) {
+				t.Fatalf("Expected valid JSON, got %s", w.Body.String())
+			}
+			if pretty := strings.Contains(w.Body.String(), "\n    \""); pretty != tt.wantPretty {
+				t.Errorf("Expected indented=%v, got %s", tt.wantPretty, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSchemaExample handles GET /api/v1/schemas/{type}/example
+func (h *ConfigHandler) GetSchemaExample(c *gin.Context) {
+	example, err := h.service.SchemaExample(c.Param("type"))
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.writeConfig(c, http.StatusOK, example)
+}
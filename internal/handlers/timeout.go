@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"config-engine/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errRequestTimeout is the cause of a request context cancelled by
+// RequestTimeoutMiddleware, telling it apart from a client's own deadline
+var errRequestTimeout = errors.New("the request exceeded the server's time limit")
+
+// RequestTimeoutMiddleware puts a deadline of timeout on the request context.
+// Handlers that honor the context are cancelled when it expires and answer
+// 503; a handler that overruns without responding gets a 503 once it returns,
+// while one that finished its work late keeps its own response.
+// Long-polls and streams run as long as they need and are exempt. A timeout
+// of 0 disables the deadline.
+func RequestTimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	if timeout <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		if isStreaming(c) {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeoutCause(c.Request.Context(), timeout, errRequestTimeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		if !responded(c) && isRequestTimeout(ctx) {
+			abortRequestTimeout(c)
+		}
+	}
+}
+
+// responded reports whether the handler answered the request. A status set
+// with c.Status, such as the 204 of a delete, is not written until gin
+// flushes the response, so a status other than the default counts too.
+func responded(c *gin.Context) bool {
+	return c.Writer.Written() || c.Writer.Status() != http.StatusOK
+}
+
+// isStreaming reports whether the request holds its connection open by
+// design: the long-poll wait endpoint and the NDJSON export
+func isStreaming(c *gin.Context) bool {
+	switch c.FullPath() {
+	case "/api/v1/configs/:name/wait":
+		return true
+	case "/api/v1/export":
+		return c.Query("format") == "ndjson"
+	}
+	return false
+}
+
+// isRequestTimeout reports whether ctx was cancelled by RequestTimeoutMiddleware
+func isRequestTimeout(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errRequestTimeout)
+}
+
+// abortRequestTimeout responds 503 for a request that ran out of time
+func abortRequestTimeout(c *gin.Context) {
+	abortJSON(c, http.StatusServiceUnavailable, models.ErrorResponse{
+		Error:   "Request timed out",
+		Details: errRequestTimeout.Error(),
+	})
+}
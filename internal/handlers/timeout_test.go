@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestTimeoutMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewConfigHandler(nil, log.New(io.Discard, "", 0))
+
+	r := gin.New()
+	api := r.Group("/api/v1")
+	api.Use(RequestTimeoutMiddleware(20 * time.Millisecond))
+	// Honors the context, as handlers calling the service do
+	api.GET("/cancellable", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		h.handleServiceError(c, c.Request.Context().Err())
+	})
+	// Ignores the context and overruns without responding
+	api.GET("/slow", func(c *gin.Context) {
+		time.Sleep(50 * time.Millisecond)
+	})
+	// Overruns but completes, answering with a status gin has not flushed yet
+	api.GET("/late", func(c *gin.Context) {
+		time.Sleep(50 * time.Millisecond)
+		c.Status(http.StatusNoContent)
+	})
+	api.GET("/fast", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	api.GET("/configs/:name/wait", func(c *gin.Context) {
+		if _, hasDeadline := c.Request.Context().Deadline(); hasDeadline {
+			t.Error("Expected the long-poll to have no deadline")
+		}
+		time.Sleep(50 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{"/api/v1/cancellable", http.StatusServiceUnavailable},
+		{"/api/v1/slow", http.StatusServiceUnavailable},
+		{"/api/v1/late", http.StatusNoContent},
+		{"/api/v1/fast", http.StatusOK},
+		{"/api/v1/configs/payments/wait", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultWaitTimeout is used when a wait request has no timeout parameter
+	defaultWaitTimeout = 30 * time.Second
+	// waitWriteMargin is added to the wait timeout when extending the write deadline
+	waitWriteMargin = 5 * time.Second
+)
+
+// WaitForChange handles GET /api/v1/configs/{name}/wait.
+// It long-polls until the config's version differs from since_version, returning
+// the new config (redacted with ?redact=true), or responds 304 Not Modified once
+// the timeout elapses.
+func (h *ConfigHandler) WaitForChange(c *gin.Context) {
+	name, ok := h.nameParam(c)
+	if !ok {
+		return
+	}
+
+	sinceVersion := 0
+	if v, ok := h.parseVersionQuery(c, "since_version", 0); !ok {
+		return
+	} else if v != nil {
+		sinceVersion = *v
+	}
+
+	timeout := defaultWaitTimeout
+	if timeoutStr := c.Query("timeout"); timeoutStr != "" {
+		d, err := time.ParseDuration(timeoutStr)
+		if err != nil || d <= 0 {
+			h.badQuery(c, "timeout", "timeout must be a positive duration such as 30s")
+			return
+		}
+		timeout = d
+	}
+	redact, ok := h.parseRedact(c)
+	if !ok {
+		return
+	}
+
+	// Let the response outlive the server's write timeout while waiting;
+	// writers that cannot extend their deadline are fine to ignore
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(timeout + waitWriteMargin))
+
+	config, changed, err := h.service.WaitForChange(c.Request.Context(), name, sinceVersion, timeout)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	if !changed {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	if redact {
+		config = h.service.RedactConfig(config)
+	}
+	h.writeConfig(c, http.StatusOK, config)
+}
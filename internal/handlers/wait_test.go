@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/service"
+	"config-engine/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestWaitForChangeEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	})
+	logger := log.New(io.Discard, "", 0)
+	router := SetupRouter(NewConfigHandler(svc, logger), logger)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{name: "already newer", query: "?since_version=0", status: http.StatusOK},
+		{name: "timeout", query: "?since_version=1&timeout=10ms", status: http.StatusNotModified},
+		{name: "invalid timeout", query: "?timeout=soon", status: http.StatusBadRequest},
+		{name: "negative since_version", query: "?since_version=-1", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/configs/payments/wait"+tt.query, nil))
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWaitForChangeRedacted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, _ := validation.NewValidator()
+	validator.RegisterSchema("gateway_config", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"api_key": map[string]interface{}{"type": "string", "secret": true},
+		},
+	})
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "gateway",
+		Type: "gateway_config",
+		Data: map[string]interface{}{"api_key": "sk_live_123"},
+	})
+	logger := log.New(io.Discard, "", 0)
+	router := SetupRouter(NewConfigHandler(svc, logger), logger)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/configs/gateway/wait?since_version=0&redact=true", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var config models.Config
+	if err := json.Unmarshal(w.Body.Bytes(), &config); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if config.Data["api_key"] != validation.RedactedValue {
+		t.Errorf("Expected api_key to be redacted, got %v", config.Data["api_key"])
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/configs/gateway/wait?redact=maybe", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid redact, got %d", w.Code)
+	}
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Lookup returns the value at a dot-separated path such as "limits.max",
+// descending through nested objects. It reports false if any segment is missing.
+// Keys that themselves contain a dot cannot be addressed; use LookupPointer.
+func Lookup(data map[string]interface{}, path string) (interface{}, bool) {
+	current := data
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		value, exists := current[segment]
+		if !exists {
+			return nil, false
+		}
+		if i == len(segments)-1 {
+			return value, true
+		}
+
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = nested
+	}
+	return nil, false
+}
+
+// GetInt returns the integer at path. Whole numbers of any type, including
+// floats and json.Number literals such as 1000.0, are accepted; fractional
+// values are not.
+func GetInt(data map[string]interface{}, path string) (int, bool) {
+	value, exists := Lookup(data, path)
+	if !exists {
+		return 0, false
+	}
+
+	switch n := value.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case json.Number:
+		// Exact, so large integers written as 1e18 or 123.0 keep every digit
+		r, ok := toRat(n)
+		if !ok || !r.IsInt() || !r.Num().IsInt64() {
+			return 0, false
+		}
+		return int(r.Num().Int64()), true
+	}
+
+	f, ok := GetFloat(data, path)
+	if !ok || f != math.Trunc(f) || f < math.MinInt || f >= math.MaxInt {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// GetFloat returns the number at path as a float64
+func GetFloat(data map[string]interface{}, path string) (float64, bool) {
+	value, exists := Lookup(data, path)
+	if !exists {
+		return 0, false
+	}
+	return toFloat(value)
+}
+
+// toFloat converts a number of any Go type to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// toRat converts a number of any Go type to an exact rational, so numbers too
+// large for float64 precision still compare correctly
+func toRat(value interface{}) (*big.Rat, bool) {
+	switch n := value.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int64:
+		return new(big.Rat).SetInt64(n), true
+	case json.Number:
+		return new(big.Rat).SetString(string(n))
+	}
+	if f, ok := toFloat(value); ok && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return new(big.Rat).SetFloat64(f), true
+	}
+	return nil, false
+}
+
+// GetBool returns the boolean at path
+func GetBool(data map[string]interface{}, path string) (bool, bool) {
+	value, exists := Lookup(data, path)
+	if !exists {
+		return false, false
+	}
+	b, ok := value.(bool)
+	return b, ok
+}
+
+// GetString returns the string at path
+func GetString(data map[string]interface{}, path string) (string, bool) {
+	value, exists := Lookup(data, path)
+	if !exists {
+		return "", false
+	}
+	s, ok := value.(string)
+	return s, ok
+}
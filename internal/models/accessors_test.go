@@ -0,0 +1,168 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func accessorTestData() map[string]interface{} {
+	var decoded map[string]interface{}
+	json.Unmarshal([]byte(`{"count": 3, "ratio": 0.5, "enabled": true, "label": "x", "limits": {"max": 100, "inner": {"name": "deep"}}}`), &decoded)
+	decoded["native"] = 7
+	decoded["number"] = json.Number("12")
+	decoded["large"] = json.Number("9007199254740993.0")
+	return decoded
+}
+
+func TestLookup(t *testing.T) {
+	data := accessorTestData()
+
+	tests := []struct {
+		path   string
+		expect bool
+	}{
+		{path: "count", expect: true},
+		{path: "limits.max", expect: true},
+		{path: "limits.inner.name", expect: true},
+		{path: "missing", expect: false},
+		{path: "limits.missing", expect: false},
+		{path: "count.nested", expect: false},
+		{path: "", expect: false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := Lookup(data, tt.path); ok != tt.expect {
+			t.Errorf("Lookup(%q): expected ok=%v, got %v", tt.path, tt.expect, ok)
+		}
+	}
+
+	if _, ok := Lookup(nil, "count"); ok {
+		t.Error("Expected lookup in nil data to fail")
+	}
+}
+
+func TestGetInt(t *testing.T) {
+	data := accessorTestData()
+
+	tests := []struct {
+		path     string
+		expected int
+		ok       bool
+	}{
+		{path: "count", expected: 3, ok: true},         // float64 from JSON
+		{path: "native", expected: 7, ok: true},        // Go int
+		{path: "number", expected: 12, ok: true},       // json.Number
+		{path: "large", expected: 1<<53 + 1, ok: true}, // beyond float64 precision
+		{path: "limits.max", expected: 100, ok: true},  // nested
+		{path: "ratio", ok: false},                     // fractional
+		{path: "label", ok: false},                     // wrong type
+		{path: "missing", ok: false},                   // absent
+		{path: "limits.inner.name", ok: false},         // nested wrong type
+	}
+
+	for _, tt := range tests {
+		value, ok := GetInt(data, tt.path)
+		if ok != tt.ok || value != tt.expected {
+			t.Errorf("GetInt(%q): expected (%d, %v), got (%d, %v)", tt.path, tt.expected, tt.ok, value, ok)
+		}
+	}
+}
+
+func TestGetFloat(t *testing.T) {
+	data := accessorTestData()
+
+	tests := []struct {
+		path     string
+		expected float64
+		ok       bool
+	}{
+		{path: "ratio", expected: 0.5, ok: true},
+		{path: "native", expected: 7, ok: true},
+		{path: "number", expected: 12, ok: true},
+		{path: "limits.max", expected: 100, ok: true},
+		{path: "enabled", ok: false},
+		{path: "missing", ok: false},
+	}
+
+	for _, tt := range tests {
+		value, ok := GetFloat(data, tt.path)
+		if ok != tt.ok || value != tt.expected {
+			t.Errorf("GetFloat(%q): expected (%v, %v), got (%v, %v)", tt.path, tt.expected, tt.ok, value, ok)
+		}
+	}
+}
+
+func TestGetBool(t *testing.T) {
+	data := accessorTestData()
+
+	if value, ok := GetBool(data, "enabled"); !ok || !value {
+		t.Errorf("Expected (true, true), got (%v, %v)", value, ok)
+	}
+	if _, ok := GetBool(data, "count"); ok {
+		t.Error("Expected wrong type to fail")
+	}
+	if _, ok := GetBool(data, "missing"); ok {
+		t.Error("Expected absent key to fail")
+	}
+}
+
+func TestGetString(t *testing.T) {
+	data := accessorTestData()
+
+	if value, ok := GetString(data, "label"); !ok || value != "x" {
+		t.Errorf("Expected (x, true), got (%v, %v)", value, ok)
+	}
+	if value, ok := GetString(data, "limits.inner.name"); !ok || value != "deep" {
+		t.Errorf("Expected (deep, true), got (%v, %v)", value, ok)
+	}
+	if _, ok := GetString(data, "count"); ok {
+		t.Error("Expected wrong type to fail")
+	}
+	if _, ok := GetString(data, "limits.missing"); ok {
+		t.Error("Expected absent nested key to fail")
+	}
+}
+
+func TestLookupPointer(t *testing.T) {
+	var data map[string]interface{}
+	json.Unmarshal([]byte(`{"max_limit": 1000, "limits": {"max": 100}, "regions": ["us", {"name": "eu"}], "a/b": 1, "m~n": 2, "": 3}`), &data)
+
+	tests := []struct {
+		pointer string
+		want    interface{}
+		ok      bool
+	}{
+		{pointer: "/max_limit", want: float64(1000), ok: true},
+		{pointer: "/limits/max", want: float64(100), ok: true},
+		{pointer: "/regions/0", want: "us", ok: true},
+		{pointer: "/regions/1/name", want: "eu", ok: true},
+		{pointer: "/a~1b", want: float64(1), ok: true},
+		{pointer: "/m~0n", want: float64(2), ok: true},
+		{pointer: "/", want: float64(3), ok: true},
+		{pointer: "/missing", ok: false},
+		{pointer: "/limits/max/deeper", ok: false},
+		{pointer: "/regions/2", ok: false},
+		{pointer: "/regions/-", ok: false},
+		{pointer: "/regions/01", ok: false},
+		{pointer: "max_limit", ok: false},
+		{pointer: "/a~2b", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := LookupPointer(data, tt.pointer)
+		if ok != tt.ok {
+			t.Errorf("LookupPointer(%q): expected ok=%v, got %v", tt.pointer, tt.ok, ok)
+			continue
+		}
+		if ok && got != tt.want {
+			t.Errorf("LookupPointer(%q): expected %v, got %v", tt.pointer, tt.want, got)
+		}
+	}
+
+	if whole, ok := LookupPointer(data, ""); !ok || len(whole.(map[string]interface{})) != len(data) {
+		t.Error("Expected the empty pointer to return the whole document")
+	}
+	if _, err := ParsePointer("/trailing~"); err == nil {
+		t.Error("Expected a dangling ~ to be rejected")
+	}
+}
@@ -0,0 +1,143 @@
+package models
+
+import "time"
+
+// CompactResponse reports what a repository compaction reclaimed
+type CompactResponse struct {
+	KeepVersions     int `json:"keep_versions"`
+	ConfigsCompacted int `json:"configs_compacted"`
+	VersionsRemoved  int `json:"versions_removed"`
+	// BytesReclaimed is the approximate serialized size of the removed version data
+	BytesReclaimed int64 `json:"bytes_reclaimed"`
+}
+
+// ValidationStatsResponse reports validation failures by config type
+type ValidationStatsResponse struct {
+	Types map[string]ValidationTypeStats `json:"types"`
+}
+
+// ValidationTypeStats counts the failed validations of one config type
+type ValidationTypeStats struct {
+	Failures int `json:"failures"`
+	// Fields counts failures per field; one validation can fail several fields.
+	// Disallowed properties share one key, as do fields beyond a per-type cap
+	Fields map[string]int `json:"fields"`
+}
+
+// RestoreResponse reports the outcome of restoring a repository snapshot
+type RestoreResponse struct {
+	ConfigsRestored int `json:"configs_restored"`
+}
+
+// HealthSignals are the inputs of the health score, each from 0 (healthy)
+// to 1 (saturated)
+type HealthSignals struct {
+	// ErrorRate is the share of API requests in the window that failed with a 5xx
+	ErrorRate float64 `json:"error_rate"`
+	// Capacity is the share of the -max-configs cap in use (0 when unlimited)
+	Capacity float64 `json:"capacity"`
+	// Memory is the share of the Go memory limit in use (0 when no limit is set)
+	Memory float64 `json:"memory"`
+}
+
+// HealthScoreWeights sets how much each signal counts towards the health score
+type HealthScoreWeights struct {
+	ErrorRate float64 `json:"error_rate"`
+	Capacity  float64 `json:"capacity"`
+	Memory    float64 `json:"memory"`
+}
+
+// HealthScoreResponse is the composite health score for dashboards
+type HealthScoreResponse struct {
+	// Score runs from 0 (every signal saturated) to 100 (all healthy)
+	Score   float64            `json:"score"`
+	Signals HealthSignals      `json:"signals"`
+	Weights HealthScoreWeights `json:"weights"`
+	// Requests is the number of API requests the error rate is computed over
+	Requests int `json:"requests"`
+	// Window is the span of the error rate, e.g. "1m0s"
+	Window string `json:"window"`
+}
+
+// BulkRollbackRequest rolls several configurations back to the versions that
+// were active at a point in time, e.g. just before an incident
+type BulkRollbackRequest struct {
+	Names []string  `json:"names"`
+	At    time.Time `json:"at"`
+	// Actor is set by the transport layer from the caller identity, never from the body
+	Actor string `json:"-"`
+	// Force restores historical data even if it fails the current schema (set from ?force)
+	Force bool `json:"-"`
+}
+
+// Validate validates the BulkRollbackRequest
+func (r *BulkRollbackRequest) Validate() error {
+	if len(r.Names) == 0 {
+		return &ValidationError{Field: "names", Message: "at least one name is required"}
+	}
+	if r.At.IsZero() {
+		return &ValidationError{Field: "at", Message: "at is required"}
+	}
+	return nil
+}
+
+// Bulk rollback result statuses
+const (
+	BulkRollbackRolledBack = "rolled_back"
+	// BulkRollbackUnchanged means the config is already at its version from then
+	BulkRollbackUnchanged = "unchanged"
+	// BulkRollbackSkipped means the config has no version from then, e.g. it
+	// was created later
+	BulkRollbackSkipped = "skipped"
+	BulkRollbackFailed  = "failed"
+)
+
+// BulkRollbackResult is the outcome of a bulk rollback for one configuration
+type BulkRollbackResult struct {
+	// Name is the stored (normalized) name, or the requested one if invalid
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// FromVersion is the version that was current before the rollback
+	FromVersion int `json:"from_version,omitempty"`
+	// TargetVersion is the version that was active at the requested time
+	TargetVersion int `json:"target_version,omitempty"`
+	// Version is the new current version after a rollback
+	Version int `json:"version,omitempty"`
+	// Note explains a skipped or failed config
+	Note string `json:"note,omitempty"`
+}
+
+// BulkRollbackResponse reports a bulk rollback per configuration, in request order
+type BulkRollbackResponse struct {
+	At      time.Time            `json:"at"`
+	Results []BulkRollbackResult `json:"results"`
+}
+
+// VerifyResponse reports the result of recomputing every version's checksum
+type VerifyResponse struct {
+	ConfigsChecked  int `json:"configs_checked"`
+	VersionsChecked int `json:"versions_checked"`
+	// Unverified counts versions stored before checksums were recorded
+	Unverified int                `json:"unverified"`
+	Mismatches []ChecksumMismatch `json:"mismatches"`
+}
+
+// ChecksumMismatch is a version whose data no longer matches its checksum
+type ChecksumMismatch struct {
+	Name     string `json:"name"`
+	Version  int    `json:"version"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// RoutesResponse lists the routes a server exposes
+type RoutesResponse struct {
+	Routes []Route `json:"routes"`
+}
+
+// Route is a registered method and path; path parameters keep their
+// :name form
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Audit actions
+const (
+	AuditActionCreate      = "create"
+	AuditActionUpdate      = "update"
+	AuditActionRollback    = "rollback"
+	AuditActionDelete      = "delete"
+	AuditActionTag         = "tag"
+	AuditActionTransaction = "transaction"
+	AuditActionPropose     = "propose"
+	AuditActionApprove     = "approve"
+	AuditActionReject      = "reject"
+	AuditActionSquash      = "squash"
+	AuditActionUnlock      = "unlock"
+	AuditActionDeprecate   = "deprecate"
+	AuditActionImport      = "import"
+	AuditActionRename      = "rename"
+	AuditActionPolicy      = "policy"
+)
+
+// AuditEvent represents a single change recorded in the audit trail
+type AuditEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Action    string    `json:"action"`
+	Name      string    `json:"name"`
+	Actor     string    `json:"actor"`
+	Version   int       `json:"version,omitempty"`
+	Origin    string    `json:"origin,omitempty"`
+	Details   string    `json:"details,omitempty"`
+}
+
+// AuditFilter represents the criteria for querying the audit trail.
+// Zero values mean "no constraint".
+type AuditFilter struct {
+	Name   string
+	Action string
+	Actor  string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+	Offset int
+}
+
+// Matches reports whether an event satisfies the filter (pagination is not considered)
+func (f *AuditFilter) Matches(event AuditEvent) bool {
+	if f.Name != "" && event.Name != f.Name {
+		return false
+	}
+	if f.Action != "" && event.Action != f.Action {
+		return false
+	}
+	if f.Actor != "" && event.Actor != f.Actor {
+		return false
+	}
+	if !f.Since.IsZero() && event.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && event.Timestamp.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// AuditResponse represents a page of audit events
+type AuditResponse struct {
+	Events []AuditEvent `json:"events"`
+	Total  int          `json:"total"`
+	Limit  int          `json:"limit"`
+	Offset int          `json:"offset"`
+}
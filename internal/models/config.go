@@ -1,42 +1,174 @@
 package models
 
 import (
-	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
 // Config represents a configuration with versioning support
 type Config struct {
-	Name      string                 `json:"name"`
-	Type      string                 `json:"type"`
-	Version   int                    `json:"version"`
-	Data      map[string]interface{} `json:"data"`
-	CreatedAt time.Time              `json:"created_at"`
-	UpdatedAt time.Time              `json:"updated_at"`
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	Version   int        `json:"version"`
+	Data      ConfigData `json:"data"`
+	DependsOn []string   `json:"depends_on,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// UpdatedBy is the actor that wrote the latest version
+	UpdatedBy string `json:"updated_by,omitempty"`
+	// Origin is the entry point that produced the latest version (see Origin* constants)
+	Origin string `json:"origin,omitempty"`
+	// Locked rejects updates, rollbacks and deletes until an admin unlocks the config
+	Locked bool `json:"locked,omitempty"`
+	// Rollout stages the configuration to a subset of users; nil means everyone
+	Rollout *Rollout `json:"rollout,omitempty"`
+	// Deprecated warns readers that the configuration is going away; it is set
+	// by admins and kept across new versions
+	Deprecated         bool   `json:"deprecated,omitempty"`
+	DeprecationMessage string `json:"deprecation_message,omitempty"`
+	// Checksum is the checksum of the version's data (see ConfigVersion)
+	Checksum string `json:"checksum,omitempty"`
+	// Message describes the change that produced the current version
+	Message string `json:"message,omitempty"`
+	// SchemaHash identifies the schema the current version was validated against (see ConfigVersion)
+	SchemaHash string `json:"schema_hash,omitempty"`
+	// ExpiresAt is when the configuration is deleted, set from the default
+	// TTL of its type's policy; nil means it never expires
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
+// Config origins, recording which entry point produced a version.
+// OriginClone is reserved for a clone entry point.
+const (
+	OriginAPI      = "api"
+	OriginImport   = "import"
+	OriginClone    = "clone"
+	OriginRollback = "rollback"
+)
+
 // ConfigVersion represents a specific version of a configuration
 type ConfigVersion struct {
-	Version   int                    `json:"version"`
-	Data      map[string]interface{} `json:"data"`
-	CreatedAt time.Time              `json:"created_at"`
+	Version   int        `json:"version"`
+	Data      ConfigData `json:"data"`
+	CreatedAt time.Time  `json:"created_at"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	Origin    string     `json:"origin,omitempty"`
+	// IsCurrent marks the version the configuration currently points at
+	IsCurrent bool `json:"is_current"`
+	// Checksum is the DataChecksum of Data computed when the version was
+	// stored; empty for versions stored before checksums were recorded
+	Checksum string `json:"checksum,omitempty"`
+	// Message describes the change that produced the version
+	Message string `json:"message,omitempty"`
+	// SchemaHash is the content hash of the schema the data was validated
+	// against when the version was written; empty if validation was skipped
+	SchemaHash string `json:"schema_hash,omitempty"`
 }
 
 // CreateConfigRequest represents the request to create a new configuration
 type CreateConfigRequest struct {
-	Name string                 `json:"name"`
-	Type string                 `json:"type"`
-	Data map[string]interface{} `json:"data"`
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	Data      ConfigData `json:"data"`
+	DependsOn []string   `json:"depends_on,omitempty"`
+	// Locked makes the configuration immutable once created
+	Locked bool `json:"locked,omitempty"`
+	// Rollout stages the configuration to a subset of users
+	Rollout *Rollout `json:"rollout,omitempty"`
+	// Message describes the change; it is shown in the versions list
+	Message string `json:"message,omitempty"`
+	// Actor is set by the transport layer from the caller identity, never from the body
+	Actor string `json:"-"`
+	// Origin is set by bulk entry points such as import; empty means OriginAPI
+	Origin string `json:"-"`
 }
 
 // UpdateConfigRequest represents the request to update a configuration
 type UpdateConfigRequest struct {
-	Data map[string]interface{} `json:"data"`
+	Data ConfigData `json:"data"`
+	// DependsOn replaces the existing dependencies when set; omit it to keep them unchanged
+	DependsOn []string `json:"depends_on,omitempty"`
+	// Locked locks the configuration again once this update is applied
+	Locked bool `json:"locked,omitempty"`
+	// Rollout replaces the existing rollout when set; omit it to keep it unchanged
+	Rollout *Rollout `json:"rollout,omitempty"`
+	// Message describes the change, e.g. several related edits landing as one
+	// version; it is shown in the versions list
+	Message string `json:"message,omitempty"`
+	// Actor is set by the transport layer from the caller identity, never from the body
+	Actor string `json:"-"`
+	// Origin is set by bulk entry points such as import; empty means OriginAPI
+	Origin string `json:"-"`
 }
 
-// RollbackRequest represents the request to rollback to a specific version
+// RollbackRequest represents the request to rollback to a specific version.
+// Exactly one of Version (absolute), Steps (relative to current) or Tag must be set.
 type RollbackRequest struct {
-	Version int `json:"version"`
+	Version int    `json:"version,omitempty"`
+	Steps   int    `json:"steps,omitempty"`
+	Tag     string `json:"tag,omitempty"`
+	// Actor is set by the transport layer from the caller identity, never from the body
+	Actor string `json:"-"`
+	// Force restores the historical data even if it fails the current schema (set from ?force)
+	Force bool `json:"-"`
+}
+
+// RenameRequest represents the request to move a configuration to a new name
+type RenameRequest struct {
+	NewName string `json:"new_name"`
+}
+
+// Validate checks the request
+func (r *RenameRequest) Validate() error {
+	if strings.TrimSpace(r.NewName) == "" {
+		return &ValidationError{Field: "new_name", Message: "new_name is required"}
+	}
+	return nil
+}
+
+// TagRequest represents the request to assign a tag to a version
+type TagRequest struct {
+	Tag     string `json:"tag"`
+	Version int    `json:"version"`
+}
+
+// TagResponse represents a tag assigned to a version
+type TagResponse struct {
+	Name    string `json:"name"`
+	Tag     string `json:"tag"`
+	Version int    `json:"version"`
+}
+
+// Transaction operation types
+const (
+	OperationCreate   = "create"
+	OperationUpdate   = "update"
+	OperationRollback = "rollback"
+)
+
+// TransactionOperation represents a single operation within a transaction
+type TransactionOperation struct {
+	Op        string     `json:"op"`
+	Name      string     `json:"name"`
+	Type      string     `json:"type,omitempty"`
+	Data      ConfigData `json:"data,omitempty"`
+	DependsOn []string   `json:"depends_on,omitempty"`
+	Version   int        `json:"version,omitempty"`
+	// Message describes a create or update; rollbacks are described automatically
+	Message string `json:"message,omitempty"`
+}
+
+// TransactionRequest represents a set of operations applied atomically
+type TransactionRequest struct {
+	Operations []TransactionOperation `json:"operations"`
+	// Actor is set by the transport layer from the caller identity, never from the body
+	Actor string `json:"-"`
+}
+
+// TransactionResponse represents the result of a committed transaction
+type TransactionResponse struct {
+	Configs []*Config `json:"configs"`
 }
 
 // VersionsResponse represents the response containing all versions
@@ -45,10 +177,91 @@ type VersionsResponse struct {
 	Versions []ConfigVersion `json:"versions"`
 }
 
+// BatchGetRequest represents the request to fetch several configurations at once
+type BatchGetRequest struct {
+	Names []string `json:"names"`
+}
+
+// BatchGetResponse maps each requested name to its configuration, or to an
+// error when the configuration could not be returned
+type BatchGetResponse struct {
+	Configs map[string]*Config       `json:"configs"`
+	Errors  map[string]ErrorResponse `json:"errors"`
+}
+
+// ComponentHealth represents the health of a single service dependency
+type ComponentHealth struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// HealthResponse represents the structured health check response
+type HealthResponse struct {
+	Status     string                     `json:"status"`
+	Uptime     string                     `json:"uptime"`
+	StartedAt  time.Time                  `json:"started_at"`
+	Build      BuildInfo                  `json:"build"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+// BuildInfo identifies the running build. Fields the build did not record
+// are empty.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// ConfigFilter represents the criteria for listing configurations. Filters
+// compose: a configuration must match all of them. Zero values mean "no
+// constraint"; a zero Limit returns every match.
+type ConfigFilter struct {
+	Type       string
+	NamePrefix string
+	Limit      int
+	Offset     int
+}
+
+// Matches reports whether a configuration satisfies the filter (pagination is not considered)
+func (f *ConfigFilter) Matches(name, configType string) bool {
+	if f.Type != "" && configType != f.Type {
+		return false
+	}
+	return strings.HasPrefix(name, f.NamePrefix)
+}
+
+// ConfigListResponse represents a page of configurations sorted by name.
+// Total counts every configuration matching the filters, not just this page.
+type ConfigListResponse struct {
+	Configs []*Config `json:"configs"`
+	Total   int       `json:"total"`
+	Limit   int       `json:"limit"`
+	Offset  int       `json:"offset"`
+}
+
+// DependentsResponse represents the configs that depend on a configuration
+type DependentsResponse struct {
+	Name       string   `json:"name"`
+	Dependents []string `json:"dependents"`
+}
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Details string `json:"details,omitempty"`
+	// Suggestions accompanies schema validation failures caused by wrongly typed values
+	Suggestions []CoercionSuggestion `json:"suggestions,omitempty"`
+	// Violations lists the failed fields of schema validation, binding and
+	// other field-level request errors
+	Violations []FieldViolation `json:"violations,omitempty"`
+}
+
+// TransactionErrorResponse represents a failed transaction response
+type TransactionErrorResponse struct {
+	Error          string `json:"error"`
+	Details        string `json:"details,omitempty"`
+	OperationIndex int    `json:"operation_index"`
 }
 
 // Validate validates the CreateConfigRequest
@@ -62,7 +275,7 @@ func (r *CreateConfigRequest) Validate() error {
 	if r.Data == nil {
 		return &ValidationError{Field: "data", Message: "data is required"}
 	}
-	return nil
+	return validateMessage(r.Message)
 }
 
 // Validate validates the UpdateConfigRequest
@@ -70,17 +283,114 @@ func (r *UpdateConfigRequest) Validate() error {
 	if r.Data == nil {
 		return &ValidationError{Field: "data", Message: "data is required"}
 	}
+	return validateMessage(r.Message)
+}
+
+// MaxMessageLength is the maximum length in bytes of a version message
+const MaxMessageLength = 1024
+
+// validateMessage checks the length of a version message
+func validateMessage(message string) error {
+	if len(message) > MaxMessageLength {
+		return &ValidationError{
+			Field:   "message",
+			Message: fmt.Sprintf("message must be at most %d bytes", MaxMessageLength),
+		}
+	}
 	return nil
 }
 
-// Validate validates the RollbackRequest
+// Validate checks that the request selects its target with exactly one of
+// version, steps or tag and that the selector is in range. Conflicting
+// selectors are reported against the second of them, in that order.
 func (r *RollbackRequest) Validate() error {
+	var selectors []string
+	if r.Version != 0 {
+		selectors = append(selectors, "version")
+	}
+	if r.Steps != 0 {
+		selectors = append(selectors, "steps")
+	}
+	if r.Tag != "" {
+		selectors = append(selectors, "tag")
+	}
+
+	switch len(selectors) {
+	case 0:
+		return &ValidationError{Field: "version", Message: "one of version, steps or tag is required"}
+	case 1:
+	case 2:
+		return &ValidationError{
+			Field:   selectors[1],
+			Message: fmt.Sprintf("%s and %s cannot be combined; provide only one of version, steps or tag", selectors[0], selectors[1]),
+		}
+	default:
+		return &ValidationError{Field: selectors[1], Message: "version, steps and tag cannot be combined; provide only one of them"}
+	}
+
+	switch {
+	case r.Version < 0:
+		return &ValidationError{Field: "version", Message: "version must be >= 1"}
+	case r.Steps < 0:
+		return &ValidationError{Field: "steps", Message: "steps must be >= 1"}
+	case r.Tag != "" && strings.TrimSpace(r.Tag) == "":
+		return &ValidationError{Field: "tag", Message: "tag must not be blank"}
+	}
+	return nil
+}
+
+// Validate validates the TransactionRequest
+func (r *TransactionRequest) Validate() error {
+	if len(r.Operations) == 0 {
+		return &ValidationError{Field: "operations", Message: "at least one operation is required"}
+	}
+	return nil
+}
+
+// Validate validates the BatchGetRequest
+func (r *BatchGetRequest) Validate() error {
+	if len(r.Names) == 0 {
+		return &ValidationError{Field: "names", Message: "at least one name is required"}
+	}
+	return nil
+}
+
+// Validate validates the TagRequest
+func (r *TagRequest) Validate() error {
+	if r.Tag == "" {
+		return &ValidationError{Field: "tag", Message: "tag is required"}
+	}
 	if r.Version < 1 {
 		return &ValidationError{Field: "version", Message: "version must be >= 1"}
 	}
 	return nil
 }
 
+// SquashRequest represents the request to collapse a range of versions into one
+type SquashRequest struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// Validate validates the squash request
+func (r *SquashRequest) Validate() error {
+	if r.From < 1 {
+		return &ValidationError{Field: "from", Message: "from must be >= 1"}
+	}
+	if r.To <= r.From {
+		return &ValidationError{Field: "to", Message: "to must be greater than from"}
+	}
+	return nil
+}
+
+// SquashResponse represents the result of squashing a range of versions
+type SquashResponse struct {
+	Name            string `json:"name"`
+	From            int    `json:"from"`
+	To              int    `json:"to"`
+	VersionsRemoved int    `json:"versions_removed"`
+}
+
 // ValidationError represents a validation error
 type ValidationError struct {
 	Field   string
@@ -109,6 +419,46 @@ func (e *ConfigExistsError) Error() string {
 	return "configuration already exists: " + e.Name
 }
 
+// ConfigInUseError represents an attempt to delete a configuration others depend on
+type ConfigInUseError struct {
+	Name       string
+	Dependents []string
+}
+
+func (e *ConfigInUseError) Error() string {
+	return fmt.Sprintf("configuration %s is referenced by: %s", e.Name, strings.Join(e.Dependents, ", "))
+}
+
+// ConfigLockedError represents an attempt to change a locked configuration
+type ConfigLockedError struct {
+	Name string
+}
+
+func (e *ConfigLockedError) Error() string {
+	return "configuration is locked: " + e.Name
+}
+
+// CapacityExceededError represents a create rejected because the store holds
+// the maximum number of configurations
+type CapacityExceededError struct {
+	Limit int
+}
+
+func (e *CapacityExceededError) Error() string {
+	return fmt.Sprintf("configuration limit reached: at most %d configurations can be stored", e.Limit)
+}
+
+// VersionLimitError represents a write rejected because the configuration
+// already has the maximum number of versions it may ever have
+type VersionLimitError struct {
+	Name  string
+	Limit int
+}
+
+func (e *VersionLimitError) Error() string {
+	return fmt.Sprintf("version limit reached: %s may have at most %d versions", e.Name, e.Limit)
+}
+
 // VersionNotFoundError represents a version not found error
 type VersionNotFoundError struct {
 	Name    string
@@ -119,19 +469,102 @@ func (e *VersionNotFoundError) Error() string {
 	return "version not found"
 }
 
+// NoVersionAtError reports that a configuration has no version created at or
+// before a point in time
+type NoVersionAtError struct {
+	Name string
+	At   time.Time
+	// Compacted is set when older history existed but was compacted away
+	Compacted bool
+}
+
+func (e *NoVersionAtError) Error() string {
+	if e.Compacted {
+		return fmt.Sprintf("no version of %s at or before %s remains after compaction", e.Name, e.At.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s did not exist at %s", e.Name, e.At.Format(time.RFC3339))
+}
+
+// WriteNotRevertibleError reports that the write producing a version can no
+// longer be reverted, because a later change superseded it
+type WriteNotRevertibleError struct {
+	Name    string
+	Version int
+}
+
+func (e *WriteNotRevertibleError) Error() string {
+	return fmt.Sprintf("version %d of %s is not the latest write and cannot be reverted", e.Version, e.Name)
+}
+
+// TagNotFoundError represents a tag not found error
+type TagNotFoundError struct {
+	Name string
+	Tag  string
+}
+
+func (e *TagNotFoundError) Error() string {
+	return fmt.Sprintf("tag not found: %s", e.Tag)
+}
+
+// CoercionSuggestion reports that a value rejected for its type converts
+// cleanly to the type the schema expects. It is advisory: the data is
+// never changed, the client must resend it with the suggested value.
+type CoercionSuggestion struct {
+	Field string      `json:"field"`
+	From  string      `json:"from"`
+	To    string      `json:"to"`
+	Value interface{} `json:"value"`
+}
+
 // SchemaValidationError represents a schema validation error
 type SchemaValidationError struct {
 	Details string
+	// Suggestions lists wrongly typed values that would convert cleanly
+	Suggestions []CoercionSuggestion
+	// Violations lists the failed checks field by field
+	Violations []FieldViolation
 }
 
 func (e *SchemaValidationError) Error() string {
 	return "schema validation failed: " + e.Details
 }
 
+// TransactionError represents a failure of a single operation within a transaction
+type TransactionError struct {
+	Index int
+	Err   error
+}
+
+func (e *TransactionError) Error() string {
+	return fmt.Sprintf("operation %d failed: %v", e.Index, e.Err)
+}
+
+func (e *TransactionError) Unwrap() error {
+	return e.Err
+}
+
+// HookError represents a failed post-write hook. The write itself was stored
+// unless Reverted is set.
+type HookError struct {
+	Name string
+	Type string
+	Err  error
+	// Reverted is set when a critical hook had the write undone
+	Reverted bool
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("hook for %s failed on %s: %v", e.Type, e.Name, e.Err)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
 // UnmarshalCreateConfigRequest unmarshals JSON into CreateConfigRequest
 func UnmarshalCreateConfigRequest(data []byte) (*CreateConfigRequest, error) {
 	var req CreateConfigRequest
-	if err := json.Unmarshal(data, &req); err != nil {
+	if err := DecodeJSON(data, &req); err != nil {
 		return nil, err
 	}
 	return &req, nil
@@ -140,7 +573,7 @@ func UnmarshalCreateConfigRequest(data []byte) (*CreateConfigRequest, error) {
 // UnmarshalUpdateConfigRequest unmarshals JSON into UpdateConfigRequest
 func UnmarshalUpdateConfigRequest(data []byte) (*UpdateConfigRequest, error) {
 	var req UpdateConfigRequest
-	if err := json.Unmarshal(data, &req); err != nil {
+	if err := DecodeJSON(data, &req); err != nil {
 		return nil, err
 	}
 	return &req, nil
@@ -149,8 +582,31 @@ func UnmarshalUpdateConfigRequest(data []byte) (*UpdateConfigRequest, error) {
 // UnmarshalRollbackRequest unmarshals JSON into RollbackRequest
 func UnmarshalRollbackRequest(data []byte) (*RollbackRequest, error) {
 	var req RollbackRequest
-	if err := json.Unmarshal(data, &req); err != nil {
+	if err := DecodeJSON(data, &req); err != nil {
 		return nil, err
 	}
 	return &req, nil
-}
\ No newline at end of file
+}
+
+// ConfigStatsResponse reports how often a configuration is read
+type ConfigStatsResponse struct {
+	Name string `json:"name"`
+	// Reads counts reads of the configuration or any of its versions since it was created
+	Reads int64 `json:"reads"`
+	// LastReadAt is omitted if the configuration was never read
+	LastReadAt   *time.Time `json:"last_read_at,omitempty"`
+	VersionCount int        `json:"version_count"`
+}
+
+// ConfigMetaResponse describes the latest version of a configuration without its data
+type ConfigMetaResponse struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// SizeBytes is the size of the data serialized as JSON, as limited by the max data size
+	SizeBytes int `json:"size_bytes"`
+	// FieldCount counts the top-level fields of the data, or the items of array data
+	FieldCount int `json:"field_count"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// ArrayItemsKey holds the items of array config data. Config data is stored
+// as an object throughout; a config whose schema declares "type": "array"
+// keeps its items under this key, which no decoded object can contain.
+const ArrayItemsKey = "\x00items"
+
+// ConfigData is config data as it travels over JSON: an object, or an array
+// for types whose schema declares one. It marshals array data back to the
+// bare array and decodes numbers as json.Number (see DecodeJSON).
+type ConfigData map[string]interface{}
+
+// ArrayData wraps items as config data
+func ArrayData(items []interface{}) map[string]interface{} {
+	if items == nil {
+		items = []interface{}{}
+	}
+	return map[string]interface{}{ArrayItemsKey: items}
+}
+
+// DataItems returns the items of array config data; ok is false for object data
+func DataItems(data map[string]interface{}) ([]interface{}, bool) {
+	if len(data) != 1 {
+		return nil, false
+	}
+	items, ok := data[ArrayItemsKey].([]interface{})
+	return items, ok
+}
+
+// DataDocument returns config data as the JSON document it represents: the
+// items of array data, or the data itself
+func DataDocument(data map[string]interface{}) interface{} {
+	if items, ok := DataItems(data); ok {
+		return items
+	}
+	return data
+}
+
+// MarshalJSON encodes array data as the bare array
+func (d ConfigData) MarshalJSON() ([]byte, error) {
+	if items, ok := DataItems(d); ok {
+		return json.Marshal(items)
+	}
+	return json.Marshal(map[string]interface{}(d))
+}
+
+// UnmarshalJSON decodes an object or an array; anything else is rejected
+func (d *ConfigData) UnmarshalJSON(raw []byte) error {
+	var value interface{}
+	if err := DecodeJSON(raw, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case nil:
+		*d = nil
+	case map[string]interface{}:
+		if _, reserved := v[ArrayItemsKey]; reserved {
+			return &json.UnmarshalTypeError{Value: "object with a reserved key", Type: reflect.TypeOf(*d)}
+		}
+		*d = v
+	case []interface{}:
+		*d = ArrayData(v)
+	default:
+		return &json.UnmarshalTypeError{Value: jsonValueKind(raw), Type: reflect.TypeOf(*d)}
+	}
+	return nil
+}
+
+// jsonValueKind names the kind of a JSON value the way encoding/json does in
+// type errors
+func jsonValueKind(raw []byte) string {
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case len(raw) == 0:
+		return "value"
+	case raw[0] == '"':
+		return "string"
+	case raw[0] == 't' || raw[0] == 'f':
+		return "bool"
+	default:
+		return "number"
+	}
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConfigData(t *testing.T) {
+	for _, raw := range []string{`{"enabled":true,"max_limit":1000}`, `["10.0.0.1","10.0.0.2"]`, `[]`} {
+		var data ConfigData
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			t.Fatalf("Failed to decode %s: %v", raw, err)
+		}
+		if encoded, _ := json.Marshal(data); string(encoded) != raw {
+			t.Errorf("Expected %s to round trip, got %s", raw, encoded)
+		}
+	}
+
+	var data ConfigData
+	json.Unmarshal([]byte(`[1, {"a": 2}]`), &data)
+	items, ok := DataItems(data)
+	if !ok || len(items) != 2 || items[0] != json.Number("1") {
+		t.Errorf("Expected array items with numbers kept as json.Number, got %v", data)
+	}
+	if value, found := LookupPointer(data, "/1/a"); !found || value != json.Number("2") {
+		t.Errorf("Expected a pointer to index the array items, got %v", value)
+	}
+
+	for _, raw := range []string{`5`, `"x"`, `true`, `{"\u0000items": []}`} {
+		var typeErr *json.UnmarshalTypeError
+		if err := json.Unmarshal([]byte(raw), &data); !errors.As(err, &typeErr) {
+			t.Errorf("Expected a type error decoding %s, got %v", raw, err)
+		}
+	}
+}
+
+func TestArrayDataHelpers(t *testing.T) {
+	from := ArrayData([]interface{}{"us"})
+	to := ArrayData([]interface{}{"us", "eu"})
+
+	want := []DiffChange{{Path: "", Op: DiffChanged, From: []interface{}{"us"}, To: []interface{}{"us", "eu"}}}
+	if got := DiffData(from, to); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the whole array to change, got %+v", got)
+	}
+	if changes := DiffData(from, map[string]interface{}{"regions": []interface{}{"us"}}); len(changes) != 1 || changes[0].Path != "" {
+		t.Errorf("Expected array and object data to differ as a whole, got %+v", changes)
+	}
+
+	patched, err := ApplyJSONPatch(from, []PatchOperation{{Op: PatchOpAdd, Path: "/-", Value: json.RawMessage(`"eu"`)}})
+	if err != nil {
+		t.Fatalf("Failed to patch array data: %v", err)
+	}
+	if !reflect.DeepEqual(patched, to) {
+		t.Errorf("Expected %v, got %v", to, patched)
+	}
+	if _, err := ApplyJSONPatch(from, []PatchOperation{{Op: PatchOpReplace, Path: "", Value: json.RawMessage(`5`)}}); err == nil {
+		t.Error("Expected patching the data into a scalar to fail")
+	}
+
+	text, err := UnifiedDiff("a", "b", from, to)
+	if err != nil {
+		t.Fatalf("Failed to diff array data: %v", err)
+	}
+	if want := "--- a\n+++ b\n@@ -1,3 +1,4 @@\n [\n-    \"us\"\n+    \"us\",\n+    \"eu\"\n ]\n"; text != want {
+		t.Errorf("Expected diff:\n%s\ngot:\n%s", want, text)
+	}
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Diff operations
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// DiffChange is a single difference between two data documents, located by
+// an RFC 6901 JSON Pointer. From is omitted for additions and To for removals.
+type DiffChange struct {
+	Path string      `json:"path"`
+	Op   string      `json:"op"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// VersionDiff lists the differences between the data of two configurations
+// or two versions of one configuration
+type VersionDiff struct {
+	From      string       `json:"from"`
+	To        string       `json:"to"`
+	Type      string       `json:"type"`
+	Identical bool         `json:"identical"`
+	Changes   []DiffChange `json:"changes"`
+}
+
+// DiffData compares two data documents. Nested objects are compared field by
+// field; arrays and scalars are compared as whole values. Changes are ordered
+// by path, and numbers are equal if they have the same value whatever their
+// Go type, so data decoded from JSON matches data built in code. Array data
+// is compared as a whole, as a change at the root path "".
+func DiffData(from, to map[string]interface{}) []DiffChange {
+	changes := []DiffChange{}
+	_, fromArray := DataItems(from)
+	_, toArray := DataItems(to)
+	if fromArray || toArray {
+		if fromDoc, toDoc := DataDocument(from), DataDocument(to); !valuesEqual(fromDoc, toDoc) {
+			changes = append(changes, DiffChange{Path: "", Op: DiffChanged, From: fromDoc, To: toDoc})
+		}
+		return changes
+	}
+	diffObjects("", from, to, &changes)
+	return changes
+}
+
+// diffObjects appends the differences between two objects located at path
+func diffObjects(path string, from, to map[string]interface{}, changes *[]DiffChange) {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, exists := from[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := path + "/" + EscapePointerToken(key)
+		fromValue, inFrom := from[key]
+		toValue, inTo := to[key]
+		switch {
+		case !inFrom:
+			*changes = append(*changes, DiffChange{Path: keyPath, Op: DiffAdded, To: toValue})
+		case !inTo:
+			*changes = append(*changes, DiffChange{Path: keyPath, Op: DiffRemoved, From: fromValue})
+		default:
+			fromObject, fromIsObject := fromValue.(map[string]interface{})
+			toObject, toIsObject := toValue.(map[string]interface{})
+			if fromIsObject && toIsObject {
+				diffObjects(keyPath, fromObject, toObject, changes)
+			} else if !valuesEqual(fromValue, toValue) {
+				*changes = append(*changes, DiffChange{Path: keyPath, Op: DiffChanged, From: fromValue, To: toValue})
+			}
+		}
+	}
+}
+
+// valuesEqual compares JSON values, treating numbers of any Go type by value
+func valuesEqual(a, b interface{}) bool {
+	if ar, ok := toRat(a); ok {
+		br, ok := toRat(b)
+		return ok && ar.Cmp(br) == 0
+	}
+
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, value := range av {
+			other, exists := bv[key]
+			if !exists || !valuesEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !valuesEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+// EscapePointerToken escapes a key for use as a JSON Pointer reference token
+func EscapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// ConfigTypeMismatchError represents a comparison of configurations of different types
+type ConfigTypeMismatchError struct {
+	A, B         string
+	TypeA, TypeB string
+}
+
+func (e *ConfigTypeMismatchError) Error() string {
+	return fmt.Sprintf("cannot compare %s (%s) with %s (%s): types differ", e.A, e.TypeA, e.B, e.TypeB)
+}
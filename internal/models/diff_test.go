@@ -0,0 +1,159 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDiffData(t *testing.T) {
+	var from map[string]interface{}
+	json.Unmarshal([]byte(`{"max_limit": 1000, "enabled": true, "limits": {"daily": 5, "a/b": 1}, "regions": ["us"], "legacy": "x"}`), &from)
+	to := map[string]interface{}{
+		"max_limit": 1000, // an int equals the decoded float64
+		"enabled":   false,
+		"limits":    map[string]interface{}{"daily": 5, "a/b": 2, "monthly": 50},
+		"regions":   []interface{}{"us", "eu"},
+	}
+
+	want := []DiffChange{
+		{Path: "/enabled", Op: DiffChanged, From: true, To: false},
+		{Path: "/legacy", Op: DiffRemoved, From: "x"},
+		{Path: "/limits/a~1b", Op: DiffChanged, From: float64(1), To: 2},
+		{Path: "/limits/monthly", Op: DiffAdded, To: 50},
+		{Path: "/regions", Op: DiffChanged, From: []interface{}{"us"}, To: []interface{}{"us", "eu"}},
+	}
+	if got := DiffData(from, to); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected changes:\n%+v\ngot:\n%+v", want, got)
+	}
+
+	if changes := DiffData(from, from); len(changes) != 0 {
+		t.Errorf("Expected no changes diffing data with itself, got %+v", changes)
+	}
+
+	// Numbers compare exactly, even where float64 would round them together
+	large := map[string]interface{}{"id": json.Number("9007199254740993")}
+	if changes := DiffData(large, map[string]interface{}{"id": json.Number("9007199254740992")}); len(changes) != 1 {
+		t.Errorf("Expected integers beyond float64 precision to differ, got %+v", changes)
+	}
+	if changes := DiffData(large, map[string]interface{}{"id": json.Number("9007199254740993.0")}); len(changes) != 0 {
+		t.Errorf("Expected equal numbers written differently to match, got %+v", changes)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	from := map[string]interface{}{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8, "i": 9, "j": 10}
+	to := map[string]interface{}{"a": 1, "b": 20, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8, "i": 9, "j": 10, "k": 11}
+
+	got, err := UnifiedDiff("cfg@1", "cfg@2", from, to)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `--- cfg@1
++++ cfg@2
+@@ -1,6 +1,6 @@
+ {
+     "a": 1,
+-    "b": 2,
++    "b": 20,
+     "c": 3,
+     "d": 4,
+     "e": 5,
+@@ -8,5 +8,6 @@
+     "g": 7,
+     "h": 8,
+     "i": 9,
+-    "j": 10
++    "j": 10,
++    "k": 11
+ }
+`
+	if got != want {
+		t.Errorf("Unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got, _ := UnifiedDiff("cfg@1", "cfg@1", from, from); got != "" {
+		t.Errorf("Expected no output for identical data, got:\n%s", got)
+	}
+}
+
+func TestDiffLinesIsShortest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(12))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return lines
+	}
+
+	for round := 0; round < 500; round++ {
+		a, b := randomLines(), randomLines()
+		script := diffLines(a, b)
+
+		var gotA, gotB []string
+		changes := 0
+		for _, line := range script {
+			if line.op != '+' {
+				gotA = append(gotA, line.text)
+			}
+			if line.op != '-' {
+				gotB = append(gotB, line.text)
+			}
+			if line.op != ' ' {
+				changes++
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("Script %v does not turn %v into %v", script, a, b)
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); changes != want {
+			t.Fatalf("Expected %d changed lines from %v to %v, got %d: %v", want, a, b, changes, script)
+		}
+	}
+}
+
+// lcsLength is the reference longest common subsequence length
+func lcsLength(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	return lcs[0][0]
+}
+
+func TestUnifiedDiffLargeDocumentsStayBounded(t *testing.T) {
+	from := make(map[string]interface{}, 8000)
+	to := make(map[string]interface{}, 8000)
+	for i := 0; i < 8000; i++ {
+		key := fmt.Sprintf("key_%05d", i)
+		from[key], to[key] = i, -i
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	text, err := UnifiedDiff("cfg@1", "cfg@2", from, to)
+	runtime.ReadMemStats(&after)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(text, `-    "key_00001": 1,`) || !strings.Contains(text, `+    "key_00001": -1,`) {
+		t.Error("Expected every changed key in the diff")
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64<<20 {
+		t.Errorf("Expected a bounded diff, allocated %d MiB", allocated>>20)
+	}
+}
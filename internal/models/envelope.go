@@ -0,0 +1,11 @@
+package models
+
+// Envelope is the uniform response shape used when a client opts in to
+// enveloped responses. Exactly one of Data and Error is set; Error holds an
+// ErrorResponse (or TransactionErrorResponse for failed transactions).
+// Warnings repeats the Warning headers of the response, such as deprecations.
+type Envelope struct {
+	Data     interface{} `json:"data"`
+	Error    interface{} `json:"error"`
+	Warnings []string    `json:"warnings,omitempty"`
+}
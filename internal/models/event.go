@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ChangeType identifies the kind of change a ChangeEvent describes
+type ChangeType string
+
+// Change types emitted by the repository
+const (
+	ChangeCreated ChangeType = "created"
+	ChangeUpdated ChangeType = "updated"
+	ChangeDeleted ChangeType = "deleted"
+)
+
+// ChangeEvent describes a committed change to a configuration
+type ChangeEvent struct {
+	Type      ChangeType `json:"type"`
+	Name      string     `json:"name"`
+	Version   int        `json:"version,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import "fmt"
+
+// ExportRecord is a configuration with its full version history, as written
+// by the export endpoint: one per line in NDJSON format
+type ExportRecord struct {
+	Config   *Config         `json:"config"`
+	Versions []ConfigVersion `json:"versions"`
+}
+
+// ExportResponse is the buffered export of every configuration
+type ExportResponse struct {
+	Configs []ExportRecord `json:"configs"`
+}
+
+// ImportMode selects what an import does with a configuration that already exists
+type ImportMode string
+
+const (
+	// ImportFail aborts the whole import; it is the default
+	ImportFail ImportMode = "fail"
+	// ImportSkip keeps the existing configuration unchanged
+	ImportSkip ImportMode = "skip"
+	// ImportOverwrite replaces the existing data with the imported data
+	ImportOverwrite ImportMode = "overwrite"
+	// ImportMerge deep merges the imported data into the existing data
+	ImportMerge ImportMode = "merge"
+)
+
+// Import results, recording what happened to each imported configuration
+const (
+	ImportCreated     = "created"
+	ImportSkipped     = "skipped"
+	ImportOverwritten = "overwritten"
+	ImportMerged      = "merged"
+)
+
+// ImportRequest imports configurations in the format of the buffered export.
+// Only the latest data of each record is imported; version history is not
+// replayed.
+type ImportRequest struct {
+	// Mode defaults to ImportFail
+	Mode    ImportMode     `json:"mode,omitempty"`
+	Configs []ExportRecord `json:"configs"`
+	// Actor is set by the transport layer from the caller identity, never from the body
+	Actor string `json:"-"`
+}
+
+// Validate validates the ImportRequest
+func (r *ImportRequest) Validate() error {
+	switch r.Mode {
+	case "", ImportFail, ImportSkip, ImportOverwrite, ImportMerge:
+	default:
+		return &ValidationError{Field: "mode", Message: "mode must be fail, skip, overwrite or merge"}
+	}
+	if len(r.Configs) == 0 {
+		return &ValidationError{Field: "configs", Message: "at least one config is required"}
+	}
+	for i, record := range r.Configs {
+		if record.Config == nil {
+			return &ValidationError{Field: fmt.Sprintf("configs[%d].config", i), Message: "config is required"}
+		}
+	}
+	return nil
+}
+
+// ImportResult reports what an import did with one configuration
+type ImportResult struct {
+	Name string `json:"name"`
+	// Result is one of the Import* results
+	Result string `json:"result"`
+	// Version is the version written; omitted for skipped configurations
+	Version int `json:"version,omitempty"`
+}
+
+// ImportResponse lists the results of an import in request order
+type ImportResponse struct {
+	Results []ImportResult `json:"results"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// DecodeJSON unmarshals data into v, keeping numbers in untyped values such
+// as config data as json.Number. The default float64 would round integers
+// beyond 2^53 and blur the line between 1000 and 1000.0 that integer schemas
+// draw; json.Number keeps the literal and re-encodes to it unchanged.
+func DecodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	// Like json.Unmarshal, reject anything after the value
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
+
+// CopyData deep copies decoded config data
+func CopyData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	return copyValue(data).(map[string]interface{})
+}
+
+// checksumPrefix names the algorithm of a data checksum
+const checksumPrefix = "sha256:"
+
+// DataChecksum returns the SHA-256 of the canonical JSON encoding of config
+// data, which sorts object keys, as "sha256:<hex>". Numbers decoded as
+// json.Number re-encode to their literal, so data read back from a file or
+// snapshot has the checksum it was stored with. Array data is encoded as the
+// bare array. Data that cannot be encoded has no checksum.
+func DataChecksum(data map[string]interface{}) string {
+	encoded, err := json.Marshal(ConfigData(data))
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(encoded)
+	return checksumPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,292 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JSONPatchContentType is the media type of an RFC 6902 JSON Patch document
+const JSONPatchContentType = "application/json-patch+json"
+
+// JSON Patch operation names (RFC 6902)
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+	PatchOpMove    = "move"
+	PatchOpCopy    = "copy"
+	PatchOpTest    = "test"
+)
+
+// PatchOperation is a single RFC 6902 JSON Patch operation. Value is kept
+// raw so an explicit null can be told apart from a missing value.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatchError reports a patch operation that could not be applied. The
+// whole patch is rejected; TestFailed is set when a test operation did not match.
+type JSONPatchError struct {
+	Index      int
+	Op         string
+	Path       string
+	Message    string
+	TestFailed bool
+}
+
+func (e *JSONPatchError) Error() string {
+	return fmt.Sprintf("patch operation %d (%s %s): %s", e.Index, e.Op, e.Path, e.Message)
+}
+
+// ApplyJSONPatch applies ops in order to a copy of data and returns the
+// result; data itself is not modified. Malformed operations yield a
+// ValidationError and operations that do not apply a JSONPatchError. Array
+// data is patched as the array it represents, so "/0" is its first item.
+// The patched document must still be an object or an array.
+func ApplyJSONPatch(data map[string]interface{}, ops []PatchOperation) (map[string]interface{}, error) {
+	doc := copyValue(DataDocument(data))
+	if data == nil {
+		doc = map[string]interface{}{}
+	}
+
+	for i, op := range ops {
+		var err error
+		doc, err = applyPatchOperation(doc, op)
+		if err == nil {
+			continue
+		}
+		if patchErr, ok := err.(*JSONPatchError); ok {
+			patchErr.Index, patchErr.Op, patchErr.Path = i, op.Op, op.Path
+			return nil, patchErr
+		}
+		return nil, &ValidationError{Field: fmt.Sprintf("operations[%d]", i), Message: err.Error()}
+	}
+
+	switch result := doc.(type) {
+	case map[string]interface{}:
+		if _, reserved := result[ArrayItemsKey]; !reserved {
+			return result, nil
+		}
+	case []interface{}:
+		return ArrayData(result), nil
+	}
+	last := ops[len(ops)-1]
+	return nil, &JSONPatchError{Index: len(ops) - 1, Op: last.Op, Path: last.Path, Message: "the patched data must be an object or an array"}
+}
+
+// applyPatchOperation applies one operation to doc. A malformed operation
+// yields a plain error and one that does not apply a JSONPatchError.
+func applyPatchOperation(doc interface{}, op PatchOperation) (interface{}, error) {
+	path, err := ParsePointer(op.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %v", err)
+	}
+
+	var value interface{}
+	switch op.Op {
+	case PatchOpAdd, PatchOpReplace, PatchOpTest:
+		if len(op.Value) == 0 {
+			return nil, fmt.Errorf("%s requires a value", op.Op)
+		}
+		if err := DecodeJSON(op.Value, &value); err != nil {
+			return nil, fmt.Errorf("invalid value: %v", err)
+		}
+	case PatchOpMove, PatchOpCopy:
+		from, err := ParsePointer(op.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from: %v", err)
+		}
+		if op.Op == PatchOpMove && len(from) < len(path) && reflect.DeepEqual(from, path[:len(from)]) {
+			return nil, fmt.Errorf("cannot move a value into itself")
+		}
+		source, found := lookupTokens(doc, from)
+		if !found {
+			return nil, &JSONPatchError{Message: fmt.Sprintf("from %q not found", op.From)}
+		}
+		value = copyValue(source)
+		if op.Op == PatchOpMove {
+			if doc, err = patchParent(doc, from, removeChild); err != nil {
+				return nil, err
+			}
+		}
+	case PatchOpRemove:
+	default:
+		return nil, fmt.Errorf("unknown op %q", op.Op)
+	}
+
+	switch op.Op {
+	case PatchOpAdd, PatchOpMove, PatchOpCopy:
+		return patchParent(doc, path, func(parent interface{}, token string) (interface{}, error) {
+			return addChild(parent, token, value)
+		})
+	case PatchOpReplace:
+		return patchParent(doc, path, func(parent interface{}, token string) (interface{}, error) {
+			return replaceChild(parent, token, value)
+		})
+	case PatchOpRemove:
+		return patchParent(doc, path, removeChild)
+	default: // PatchOpTest
+		current, found := lookupTokens(doc, path)
+		if !found {
+			return nil, &JSONPatchError{Message: "path not found", TestFailed: true}
+		}
+		if !valuesEqual(current, value) {
+			return nil, &JSONPatchError{Message: fmt.Sprintf("value is %s, not %s", encodeValue(current), op.Value), TestFailed: true}
+		}
+		return doc, nil
+	}
+}
+
+// patchParent applies fn to the container holding the last token of path
+// and returns doc with the result in place. An empty path addresses the
+// whole document, which only add, replace and move may do.
+func patchParent(doc interface{}, path []string, fn func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 0 {
+		// Replace the root by treating it as the only child of a wrapper
+		wrapper := map[string]interface{}{"": doc}
+		result, err := fn(wrapper, "")
+		if err != nil {
+			return nil, err
+		}
+		root, exists := result.(map[string]interface{})[""]
+		if !exists {
+			return nil, &JSONPatchError{Message: "cannot remove the whole document"}
+		}
+		return root, nil
+	}
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	child, found := lookupTokens(doc, path[:1])
+	if !found {
+		return nil, &JSONPatchError{Message: "path not found"}
+	}
+	updated, err := patchParent(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	return replaceChild(doc, path[0], updated)
+}
+
+// addChild adds value under token: it sets an object member or inserts into
+// an array, where "-" appends
+func addChild(parent interface{}, token string, value interface{}) (interface{}, error) {
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[token] = value
+		return node, nil
+	case []interface{}:
+		index := len(node)
+		if token != "-" {
+			var ok bool
+			if index, ok = arrayIndex(token, len(node)+1); !ok {
+				return nil, &JSONPatchError{Message: fmt.Sprintf("index %s is out of range", token)}
+			}
+		}
+		node = append(node, nil)
+		copy(node[index+1:], node[index:])
+		node[index] = value
+		return node, nil
+	default:
+		return nil, &JSONPatchError{Message: "path not found"}
+	}
+}
+
+// replaceChild replaces the existing value under token
+func replaceChild(parent interface{}, token string, value interface{}) (interface{}, error) {
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		if _, exists := node[token]; !exists {
+			return nil, &JSONPatchError{Message: "path not found"}
+		}
+		node[token] = value
+		return node, nil
+	case []interface{}:
+		index, ok := arrayIndex(token, len(node))
+		if !ok {
+			return nil, &JSONPatchError{Message: "path not found"}
+		}
+		node[index] = value
+		return node, nil
+	default:
+		return nil, &JSONPatchError{Message: "path not found"}
+	}
+}
+
+// removeChild removes the existing value under token
+func removeChild(parent interface{}, token string) (interface{}, error) {
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		if _, exists := node[token]; !exists {
+			return nil, &JSONPatchError{Message: "path not found"}
+		}
+		delete(node, token)
+		return node, nil
+	case []interface{}:
+		index, ok := arrayIndex(token, len(node))
+		if !ok {
+			return nil, &JSONPatchError{Message: "path not found"}
+		}
+		return append(node[:index:index], node[index+1:]...), nil
+	default:
+		return nil, &JSONPatchError{Message: "path not found"}
+	}
+}
+
+// lookupTokens resolves parsed pointer tokens within doc
+func lookupTokens(doc interface{}, tokens []string) (interface{}, bool) {
+	current := doc
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, exists := node[token]
+			if !exists {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, ok := arrayIndex(token, len(node))
+			if !ok {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// copyValue deep copies a decoded JSON value
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			copied[key] = copyValue(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, child := range v {
+			copied[i] = copyValue(child)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// encodeValue renders a value for an error message
+func encodeValue(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
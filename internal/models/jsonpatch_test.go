@@ -0,0 +1,95 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	var data map[string]interface{}
+	DecodeJSON([]byte(`{"max_limit": 1000, "enabled": true, "regions": ["us", "eu"], "limits": {"daily": 5}}`), &data)
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "replace",
+			patch: `[{"op": "replace", "path": "/max_limit", "value": 2000}, {"op": "replace", "path": "/limits/daily", "value": 10}]`,
+			want:  `{"max_limit": 2000, "enabled": true, "regions": ["us", "eu"], "limits": {"daily": 10}}`,
+		},
+		{
+			name:  "remove",
+			patch: `[{"op": "remove", "path": "/limits"}, {"op": "remove", "path": "/regions/0"}]`,
+			want:  `{"max_limit": 1000, "enabled": true, "regions": ["eu"]}`,
+		},
+		{
+			name:  "add, move and copy",
+			patch: `[{"op": "add", "path": "/regions/1", "value": "ap"}, {"op": "add", "path": "/regions/-", "value": "sa"}, {"op": "move", "from": "/limits/daily", "path": "/daily"}, {"op": "copy", "from": "/daily", "path": "/limits/weekly"}]`,
+			want:  `{"max_limit": 1000, "enabled": true, "regions": ["us", "ap", "eu", "sa"], "limits": {"weekly": 5}, "daily": 5}`,
+		},
+		{
+			name:  "passing test",
+			patch: `[{"op": "test", "path": "/max_limit", "value": 1000.0}, {"op": "test", "path": "/limits", "value": {"daily": 5}}, {"op": "replace", "path": "/enabled", "value": false}]`,
+			want:  `{"max_limit": 1000, "enabled": false, "regions": ["us", "eu"], "limits": {"daily": 5}}`,
+		},
+		{
+			name:    "failing test",
+			patch:   `[{"op": "replace", "path": "/enabled", "value": false}, {"op": "test", "path": "/max_limit", "value": 500}]`,
+			wantErr: &JSONPatchError{Index: 1, Op: PatchOpTest, Path: "/max_limit", Message: "value is 1000, not 500", TestFailed: true},
+		},
+		{
+			name:    "missing path",
+			patch:   `[{"op": "replace", "path": "/limits/monthly", "value": 1}]`,
+			wantErr: &JSONPatchError{Index: 0, Op: PatchOpReplace, Path: "/limits/monthly", Message: "path not found"},
+		},
+		{
+			name:    "missing value",
+			patch:   `[{"op": "add", "path": "/owner"}]`,
+			wantErr: &ValidationError{Field: "operations[0]", Message: "add requires a value"},
+		},
+		{
+			name:    "unknown op",
+			patch:   `[{"op": "increment", "path": "/max_limit"}]`,
+			wantErr: &ValidationError{Field: "operations[0]", Message: `unknown op "increment"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ops []PatchOperation
+			if err := json.Unmarshal([]byte(tt.patch), &ops); err != nil {
+				t.Fatalf("Failed to parse patch: %v", err)
+			}
+
+			got, err := ApplyJSONPatch(data, ops)
+			if tt.wantErr != nil {
+				if !reflect.DeepEqual(err, tt.wantErr) {
+					t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to apply patch: %v", err)
+			}
+			var want map[string]interface{}
+			DecodeJSON([]byte(tt.want), &want)
+			if !valuesEqual(got, want) {
+				gotJSON, _ := json.Marshal(got)
+				t.Errorf("Expected %s, got %s", tt.want, gotJSON)
+			}
+		})
+	}
+
+	// The input is never modified, even by a patch that fails part way
+	if data["enabled"] != true || len(data["regions"].([]interface{})) != 2 {
+		t.Errorf("Expected the input data to be unchanged, got %v", data)
+	}
+	var patchErr *JSONPatchError
+	if _, err := ApplyJSONPatch(data, []PatchOperation{{Op: PatchOpRemove, Path: ""}}); !errors.As(err, &patchErr) {
+		t.Errorf("Expected removing the whole document to fail, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsePointer splits an RFC 6901 JSON Pointer such as "/limits/max" into its
+// unescaped reference tokens. The empty pointer refers to the whole document.
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("pointer must be empty or start with /")
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("invalid escape in pointer token %q", token)
+			}
+		}
+		// ~1 must be decoded before ~0 so "~01" becomes "~1", not "/"
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// LookupPointer returns the value an RFC 6901 JSON Pointer refers to within
+// data, descending through nested objects and array indices. It reports false
+// if the pointer is malformed or any token does not resolve. Pointers into
+// array data index its items, so "/0" is the first.
+func LookupPointer(data map[string]interface{}, pointer string) (interface{}, bool) {
+	tokens, err := ParsePointer(pointer)
+	if err != nil {
+		return nil, false
+	}
+
+	current := DataDocument(data)
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, exists := node[token]
+			if !exists {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, ok := arrayIndex(token, len(node))
+			if !ok {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// arrayIndex parses a pointer token as an index into an array of length n.
+// Leading zeros and "-" (the element past the end) never resolve.
+func arrayIndex(token string, n int) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index >= n {
+		return 0, false
+	}
+	return index, true
+}
+
+// PointerNotFoundError represents a JSON Pointer that does not resolve within
+// a configuration's data
+type PointerNotFoundError struct {
+	Name    string
+	Pointer string
+}
+
+func (e *PointerNotFoundError) Error() string {
+	return fmt.Sprintf("pointer %q not found in configuration %s", e.Pointer, e.Name)
+}
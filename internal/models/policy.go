@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// TypePolicy sets what the configurations of a config type get unless a
+// request says otherwise. The zero policy changes nothing.
+type TypePolicy struct {
+	// DefaultTTL is how long, as a duration such as "1h", a configuration
+	// lives after each create, update or rollback before it expires; empty
+	// keeps configurations until they are deleted
+	DefaultTTL string `json:"default_ttl,omitempty"`
+	// MaxVersions is the number of versions kept per configuration after
+	// each write; older ones are compacted away (0 keeps all history)
+	MaxVersions int `json:"max_versions,omitempty"`
+	// VersionLimit caps the number of versions a configuration may ever
+	// have; writes beyond it are rejected rather than pruned, keeping the
+	// history complete. It overrides the service-wide limit (0 applies that).
+	VersionLimit int `json:"version_limit,omitempty"`
+	// DisallowRollback rejects rollbacks of the type's configurations
+	DisallowRollback bool `json:"disallow_rollback,omitempty"`
+}
+
+// Validate validates the TypePolicy
+func (p *TypePolicy) Validate() error {
+	if p.DefaultTTL != "" {
+		ttl, err := time.ParseDuration(p.DefaultTTL)
+		if err != nil || ttl <= 0 {
+			return &ValidationError{Field: "default_ttl", Message: "default_ttl must be a positive duration such as 1h"}
+		}
+	}
+	if p.MaxVersions < 0 {
+		return &ValidationError{Field: "max_versions", Message: "max_versions must not be negative"}
+	}
+	if p.VersionLimit < 0 {
+		return &ValidationError{Field: "version_limit", Message: "version_limit must not be negative"}
+	}
+	return nil
+}
+
+// TTL returns DefaultTTL as a duration, or 0 when it is not set or invalid
+func (p TypePolicy) TTL() time.Duration {
+	ttl, err := time.ParseDuration(p.DefaultTTL)
+	if err != nil {
+		return 0
+	}
+	return ttl
+}
+
+// TypePolicyResponse reports the policy of a config type
+type TypePolicyResponse struct {
+	Type   string     `json:"type"`
+	Policy TypePolicy `json:"policy"`
+}
+
+// RollbackDisallowedError represents a rollback rejected by the policy of
+// the configuration's type
+type RollbackDisallowedError struct {
+	Name string
+	Type string
+}
+
+func (e *RollbackDisallowedError) Error() string {
+	return fmt.Sprintf("rollback of %s is disallowed by the policy of type %s", e.Name, e.Type)
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Proposal represents a pending configuration change awaiting approval
+type Proposal struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Data        ConfigData `json:"data"`
+	DependsOn   []string   `json:"depends_on,omitempty"`
+	BaseVersion int        `json:"base_version"`
+	Proposer    string     `json:"proposer"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ProposalsResponse represents the pending proposals of a configuration
+type ProposalsResponse struct {
+	Name      string     `json:"name"`
+	Proposals []Proposal `json:"proposals"`
+}
+
+// ProposalNotFoundError represents a proposal not found error
+type ProposalNotFoundError struct {
+	Name string
+	ID   string
+}
+
+func (e *ProposalNotFoundError) Error() string {
+	return fmt.Sprintf("proposal not found: %s", e.ID)
+}
+
+// ProposalConflictError represents an approval of a proposal whose base version is stale
+type ProposalConflictError struct {
+	ID             string
+	BaseVersion    int
+	CurrentVersion int
+}
+
+func (e *ProposalConflictError) Error() string {
+	return fmt.Sprintf("proposal %s was created against version %d but the current version is %d",
+		e.ID, e.BaseVersion, e.CurrentVersion)
+}
@@ -0,0 +1,30 @@
+package models
+
+// Rollout stages a configuration to a subset of users. A user is included if
+// they belong to one of the cohorts, or if their stable hash bucket falls
+// below the percentage, so raising the percentage only ever adds users.
+type Rollout struct {
+	// Percentage of users included, from 0 to 100
+	Percentage float64 `json:"percentage"`
+	// Cohorts lists user ids or group names that are always included
+	Cohorts []string `json:"cohorts,omitempty"`
+}
+
+// Rollout decision reasons
+const (
+	RolloutReasonNoRollout  = "no_rollout"
+	RolloutReasonCohort     = "cohort"
+	RolloutReasonPercentage = "percentage"
+)
+
+// RolloutEvaluation is the decision whether a user is in a config's rollout
+type RolloutEvaluation struct {
+	Name      string `json:"name"`
+	Version   int    `json:"version"`
+	User      string `json:"user"`
+	InRollout bool   `json:"in_rollout"`
+	// Reason is the rule that decided (see RolloutReason* constants)
+	Reason string `json:"reason"`
+	// Percentage is the configured rollout percentage; omitted without a rollout
+	Percentage *float64 `json:"percentage,omitempty"`
+}
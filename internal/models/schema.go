@@ -0,0 +1,136 @@
+package models
+
+// SchemaExampleResponse represents a sample config data object for a type
+type SchemaExampleResponse struct {
+	Type string     `json:"type"`
+	Data ConfigData `json:"data"`
+}
+
+// SchemaNotFoundError represents a config type without a registered schema
+type SchemaNotFoundError struct {
+	Type string
+}
+
+func (e *SchemaNotFoundError) Error() string {
+	return "schema not found: " + e.Type
+}
+
+// SchemaReloadResponse lists the config types loaded from the schema
+// directory and the stored configurations the new schemas reject. On a dry
+// run the schemas are only checked, not swapped in.
+type SchemaReloadResponse struct {
+	Types        []string             `json:"types"`
+	DryRun       bool                 `json:"dry_run"`
+	Incompatible []IncompatibleConfig `json:"incompatible"`
+}
+
+// RegisterSchemaRequest registers the schema of a config type, replacing
+// any schema or schema pair it had
+type RegisterSchemaRequest struct {
+	Schema map[string]interface{} `json:"schema"`
+}
+
+// Validate checks the request
+func (r *RegisterSchemaRequest) Validate() error {
+	if r.Schema == nil {
+		return &ValidationError{Field: "schema", Message: "schema is required"}
+	}
+	return nil
+}
+
+// SchemaRegisterResponse reports a registered schema and the stored
+// configurations it rejects. On a dry run the schema is only checked.
+type SchemaRegisterResponse struct {
+	Type         string               `json:"type"`
+	DryRun       bool                 `json:"dry_run"`
+	Incompatible []IncompatibleConfig `json:"incompatible"`
+}
+
+// IncompatibleConfig is a stored configuration whose latest data a new
+// schema rejects; it keeps its data but can no longer be updated unchanged
+type IncompatibleConfig struct {
+	Name    string           `json:"name"`
+	Type    string           `json:"type"`
+	Version int              `json:"version"`
+	Errors  []FieldViolation `json:"errors"`
+}
+
+// SchemaLoadError represents a failed schema reload; the previous schemas stay active
+type SchemaLoadError struct {
+	Err error
+}
+
+func (e *SchemaLoadError) Error() string {
+	return "schema reload failed: " + e.Err.Error()
+}
+
+func (e *SchemaLoadError) Unwrap() error {
+	return e.Err
+}
+
+// DeprecationRequest marks a configuration or config type as deprecated, or
+// lifts the deprecation when Deprecated is false
+type DeprecationRequest struct {
+	Deprecated bool `json:"deprecated"`
+	// Message tells readers what to do instead, e.g. "use payments_v2"
+	Message string `json:"message,omitempty"`
+}
+
+// TypeDeprecationResponse reports the deprecation of a config type
+type TypeDeprecationResponse struct {
+	Type       string `json:"type"`
+	Deprecated bool   `json:"deprecated"`
+	Message    string `json:"message,omitempty"`
+}
+
+// ConfigValidationResponse reports whether the stored latest data of a
+// configuration still satisfies its type's current schema
+type ConfigValidationResponse struct {
+	Name    string           `json:"name"`
+	Type    string           `json:"type"`
+	Version int              `json:"version"`
+	Valid   bool             `json:"valid"`
+	Errors  []FieldViolation `json:"errors"`
+}
+
+// FieldViolation is a single failed schema check or custom rule on one field
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	// Description is the field's schema description, if it has one
+	Description string `json:"description,omitempty"`
+}
+
+// BatchValidateRequest represents config data to validate without storing it
+type BatchValidateRequest struct {
+	Items []BatchValidateItem `json:"items"`
+}
+
+// BatchValidateItem is one config to validate against its type's schema
+type BatchValidateItem struct {
+	Type string     `json:"type"`
+	Data ConfigData `json:"data"`
+}
+
+// Validate validates the BatchValidateRequest
+func (r *BatchValidateRequest) Validate() error {
+	if len(r.Items) == 0 {
+		return &ValidationError{Field: "items", Message: "at least one item is required"}
+	}
+	return nil
+}
+
+// BatchValidateResponse reports the validity of each item, in request order
+type BatchValidateResponse struct {
+	// Valid is true when every item is valid
+	Valid   bool                  `json:"valid"`
+	Results []BatchValidateResult `json:"results"`
+}
+
+// BatchValidateResult is the outcome of validating one item
+type BatchValidateResult struct {
+	Index  int              `json:"index"`
+	Type   string           `json:"type"`
+	Valid  bool             `json:"valid"`
+	Errors []FieldViolation `json:"errors"`
+}
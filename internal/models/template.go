@@ -0,0 +1,13 @@
+package models
+
+import "fmt"
+
+// ReferenceError represents a ${config.field} template that cannot be resolved
+type ReferenceError struct {
+	Reference string
+	Reason    string
+}
+
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("cannot resolve ${%s}: %s", e.Reference, e.Reason)
+}
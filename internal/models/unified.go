@@ -0,0 +1,302 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// unifiedContext is the number of unchanged lines shown around each change
+const unifiedContext = 3
+
+// diffLine is one line of a line-based edit script: ' ' kept, '-' removed
+// from the old text, '+' added in the new one
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// UnifiedDiff renders the difference between two data documents as a
+// unified diff of their indented JSON. Object keys are sorted by the
+// encoder, so the same data always renders the same lines. Identical
+// documents produce an empty string.
+func UnifiedDiff(fromLabel, toLabel string, from, to map[string]interface{}) (string, error) {
+	fromLines, err := indentedLines(from)
+	if err != nil {
+		return "", err
+	}
+	toLines, err := indentedLines(to)
+	if err != nil {
+		return "", err
+	}
+
+	script := diffLines(fromLines, toLines)
+	var out strings.Builder
+	for _, hunk := range hunks(script) {
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromLabel, toLabel)
+		}
+		writeHunk(&out, script, hunk)
+	}
+	return out.String(), nil
+}
+
+// indentedLines encodes data, or the items of array data, as indented JSON
+// split into lines
+func indentedLines(data map[string]interface{}) ([]string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(DataDocument(data)); err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
+
+// maxEditDistance bounds the search for a shortest edit script. A changed
+// region needing more removed and added lines than this is rendered as
+// removed and re-added wholesale, which keeps the time of a diff linear in
+// the size of the documents beyond the bound.
+const maxEditDistance = 1000
+
+// diffLines computes an edit script from a to b: a shortest one when the
+// documents differ by at most maxEditDistance lines. The common prefix and
+// suffix are stripped first; the region between them is compared with
+// Myers' linear-space algorithm, so memory stays linear in the line count.
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	script := make([]diffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		script = append(script, diffLine{' ', line})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if editDistanceWithin(midA, midB, maxEditDistance) {
+		script = editScript(midA, midB, script)
+	} else {
+		script = replaceLines(midA, midB, script)
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		script = append(script, diffLine{' ', line})
+	}
+	groupChanges(script)
+	return script
+}
+
+// groupChanges reorders each run of changed lines so its removals come
+// before its additions, as diff tools print them
+func groupChanges(script []diffLine) {
+	for start := 0; start < len(script); {
+		if script[start].op == ' ' {
+			start++
+			continue
+		}
+		end := start
+		for end < len(script) && script[end].op != ' ' {
+			end++
+		}
+		sort.SliceStable(script[start:end], func(i, j int) bool {
+			return script[start+i].op == '-' && script[start+j].op == '+'
+		})
+		start = end
+	}
+}
+
+// editDistanceWithin reports whether a shortest edit script from a to b
+// removes and adds at most limit lines, using the greedy forward pass of
+// Myers' algorithm without keeping its trace
+func editDistanceWithin(a, b []string, limit int) bool {
+	n, m := len(a), len(b)
+	limit = min(limit, n+m)
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := v[offset+k-1] + 1
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// editScript appends a shortest edit script from a to b to script, splitting
+// the problem at the middle snake of Myers' linear-space algorithm
+func editScript(a, b []string, script []diffLine) []diffLine {
+	switch {
+	case len(a) == 0 || len(b) == 0:
+		return replaceLines(a, b, script)
+	}
+
+	d, x, y, u, v := middleSnake(a, b)
+	if d > 1 {
+		script = editScript(a[:x], b[:y], script)
+		for _, line := range a[x:u] {
+			script = append(script, diffLine{' ', line})
+		}
+		return editScript(a[u:], b[v:], script)
+	}
+
+	// At most one line was removed or added: keep the rest in order
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			script = append(script, diffLine{' ', a[i]})
+			i++
+			j++
+		case len(a)-i > len(b)-j:
+			script = append(script, diffLine{'-', a[i]})
+			i++
+		default:
+			script = append(script, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	return script
+}
+
+// middleSnake finds the middle snake of a shortest edit script from a to b,
+// searching forward from the start and backward from the end at once. It
+// returns the length d of the script and the snake from (x, y) to (u, v).
+func middleSnake(a, b []string) (d, x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+	// forward[k] is the furthest x reached on diagonal k from the start;
+	// backward[k] the furthest distance reached from the end on diagonal k
+	// of the reversed texts, which is diagonal delta-k of the forward ones
+	forward := make([]int, 2*limit+3)
+	backward := make([]int, 2*limit+3)
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := forward[offset+k-1] + 1
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			if odd && delta-k >= -(d-1) && delta-k <= d-1 && x+backward[offset+delta-k] >= n {
+				return 2*d - 1, startX, startY, x, y
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			x := backward[offset+k-1] + 1
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			if !odd && delta-k >= -d && delta-k <= d && x+forward[offset+delta-k] >= n {
+				return 2 * d, n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+	// Unreachable: a script of length n+m always exists
+	return n + m, 0, 0, 0, 0
+}
+
+// replaceLines appends the removal of every line of a and the addition of
+// every line of b to script
+func replaceLines(a, b []string, script []diffLine) []diffLine {
+	for _, line := range a {
+		script = append(script, diffLine{'-', line})
+	}
+	for _, line := range b {
+		script = append(script, diffLine{'+', line})
+	}
+	return script
+}
+
+// hunks groups the changes of an edit script into [start, end) ranges of
+// the script, each padded with unifiedContext unchanged lines. Changes
+// separated by no more than twice the context share a hunk.
+func hunks(script []diffLine) [][2]int {
+	var ranges [][2]int
+	for i, line := range script {
+		if line.op == ' ' {
+			continue
+		}
+		start := max(i-unifiedContext, 0)
+		end := min(i+unifiedContext+1, len(script))
+		if n := len(ranges); n > 0 && start <= ranges[n-1][1] {
+			ranges[n-1][1] = end
+			continue
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges
+}
+
+// writeHunk writes one hunk with its @@ -l,s +l,s @@ header
+func writeHunk(out *strings.Builder, script []diffLine, hunk [2]int) {
+	// Line numbers of the hunk's first line in the old and new text
+	fromLine, toLine := 1, 1
+	for _, line := range script[:hunk[0]] {
+		if line.op != '+' {
+			fromLine++
+		}
+		if line.op != '-' {
+			toLine++
+		}
+	}
+	fromCount, toCount := 0, 0
+	for _, line := range script[hunk[0]:hunk[1]] {
+		if line.op != '+' {
+			fromCount++
+		}
+		if line.op != '-' {
+			toCount++
+		}
+	}
+	// An empty range is numbered by the line before it
+	if fromCount == 0 {
+		fromLine--
+	}
+	if toCount == 0 {
+		toLine--
+	}
+
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", fromLine, fromCount, toLine, toCount)
+	for _, line := range script[hunk[0]:hunk[1]] {
+		out.WriteByte(line.op)
+		out.WriteString(line.text)
+		out.WriteByte('\n')
+	}
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// WebhookSignatureHeader carries the signature of a webhook POST body
+const WebhookSignatureHeader = "X-Signature"
+
+// webhookSignaturePrefix names the algorithm of a webhook signature
+const webhookSignaturePrefix = "sha256="
+
+// Webhook events, naming the write a payload reports
+const (
+	WebhookEventCreate = "create"
+	WebhookEventUpdate = "update"
+)
+
+// WebhookPayload is the body POSTed to a webhook after a write of a config
+// of its type. Rollbacks and approved proposals are updates.
+type WebhookPayload struct {
+	Event  string    `json:"event"`
+	Config *Config   `json:"config"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+// SignWebhookPayload signs a webhook POST body with secret, returning the
+// X-Signature value "sha256=<hex>" of its HMAC-SHA256. The signature covers
+// the body exactly as sent, the compact JSON encoding of a WebhookPayload,
+// so receivers must verify the raw bytes before decoding them: re-encoding
+// the decoded payload need not reproduce the same bytes.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature reports whether signature is the signature of body
+// under secret, comparing in constant time
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhookPayload(secret, body)), []byte(signature))
+}
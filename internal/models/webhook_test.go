@@ -0,0 +1,22 @@
+package models
+
+import "testing"
+
+func TestWebhookSignature(t *testing.T) {
+	secret := "whsec_test"
+	body := []byte(`{"event":"create"}`)
+
+	signature := SignWebhookPayload(secret, body)
+	if want := "sha256=cb3254324b4b2d2b20b480705cf9b34269d5339ffe8c4c05764047ddd0e7912a"; signature != want {
+		t.Errorf("Expected signature %s, got %s", want, signature)
+	}
+	if !VerifyWebhookSignature(secret, body, signature) {
+		t.Error("Expected the signature to verify")
+	}
+	if VerifyWebhookSignature(secret, []byte(`{"event":"update"}`), signature) {
+		t.Error("Expected a tampered body to fail verification")
+	}
+	if VerifyWebhookSignature("other", body, signature) {
+		t.Error("Expected another secret to fail verification")
+	}
+}
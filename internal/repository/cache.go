@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+
+	"config-engine/internal/models"
+)
+
+// CachingRepository is a read-through cache in front of another
+// ConfigRepository. Get results are kept in a bounded LRU cache; every other
+// method passes through to the inner repository.
+//
+// Writes made through the cache invalidate the written configuration before
+// returning. Writes made any other way, such as inside a transaction or
+// directly on the inner repository, are picked up from its change events when
+// it is a Subscriber; events are delivered asynchronously, so such a write
+// may be read stale for a moment, and an event dropped by a full subscriber
+// buffer leaves the entry stale until it is evicted or written again.
+// Transactions clear the whole cache when they commit. Subscribers of the
+// cache receive the inner events only once the cache has dropped their
+// configuration, so a read prompted by an event never sees the old state.
+//
+// Compactor, Snapshotter, CapacityReporter, UsageReporter and PolicyStore are
+// forwarded to the inner repository, failing when it does not implement them. Cache hits
+// do not reach the inner repository, so they are not counted in its read
+// statistics.
+type CachingRepository struct {
+	ConfigRepository
+
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	items    map[string]*list.Element
+	// generation is bumped on every invalidation so a Get racing a write does
+	// not cache what it read before the write
+	generation uint64
+
+	// events relays the inner change events once they are invalidated
+	events      eventBus
+	subscribed  bool
+	unsubscribe func()
+}
+
+// cachedConfig is a cached Get result
+type cachedConfig struct {
+	name   string
+	config *models.Config
+}
+
+// NewCachingRepository wraps inner with a cache of at most size
+// configurations; a size below 1 disables caching. Close stops following the
+// inner repository's change events.
+func NewCachingRepository(inner ConfigRepository, size int) *CachingRepository {
+	r := &CachingRepository{
+		ConfigRepository: inner,
+		capacity:         size,
+		order:            list.New(),
+		items:            make(map[string]*list.Element),
+		unsubscribe:      func() {},
+	}
+
+	if subscriber, ok := inner.(Subscriber); ok {
+		events, unsubscribe := subscriber.Subscribe()
+		r.subscribed = true
+		r.unsubscribe = unsubscribe
+		go func() {
+			for event := range events {
+				r.invalidate(event.Name)
+				r.events.publish(event)
+			}
+		}()
+	}
+	return r
+}
+
+// Close stops following the inner repository's change events
+func (r *CachingRepository) Close() {
+	r.unsubscribe()
+}
+
+// Get returns the latest version of a configuration, from the cache when present
+func (r *CachingRepository) Get(ctx context.Context, name string) (*models.Config, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	if elem, ok := r.items[name]; ok {
+		r.order.MoveToFront(elem)
+		config := cloneConfig(elem.Value.(*cachedConfig).config)
+		r.mu.Unlock()
+		return config, nil
+	}
+	generation := r.generation
+	r.mu.Unlock()
+
+	config, err := r.ConfigRepository.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	r.put(name, cloneConfig(config), generation)
+	return config, nil
+}
+
+// put caches a configuration read at generation, evicting the least recently
+// used entry when full. Nothing is cached if an invalidation happened since.
+func (r *CachingRepository) put(name string, config *models.Config, generation uint64) {
+	if r.capacity <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.generation != generation {
+		return
+	}
+	if elem, ok := r.items[name]; ok {
+		elem.Value.(*cachedConfig).config = config
+		r.order.MoveToFront(elem)
+		return
+	}
+
+	r.items[name] = r.order.PushFront(&cachedConfig{name: name, config: config})
+	if r.order.Len() > r.capacity {
+		oldest := r.order.Back()
+		r.order.Remove(oldest)
+		delete(r.items, oldest.Value.(*cachedConfig).name)
+	}
+}
+
+// invalidate drops the cached configuration name
+func (r *CachingRepository) invalidate(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	if elem, ok := r.items[name]; ok {
+		r.order.Remove(elem)
+		delete(r.items, name)
+	}
+}
+
+// invalidateAll empties the cache
+func (r *CachingRepository) invalidateAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	r.order.Init()
+	r.items = make(map[string]*list.Element)
+}
+
+// Create creates a configuration and invalidates its cache entry
+func (r *CachingRepository) Create(ctx context.Context, config *models.Config) error {
+	defer r.invalidate(config.Name)
+	return r.ConfigRepository.Create(ctx, config)
+}
+
+// Update stores a new version and invalidates its cache entry
+func (r *CachingRepository) Update(ctx context.Context, config *models.Config) error {
+	defer r.invalidate(config.Name)
+	return r.ConfigRepository.Update(ctx, config)
+}
+
+// Rollback makes a previous version the head and invalidates its cache entry
+func (r *CachingRepository) Rollback(ctx context.Context, config *models.Config, version int) error {
+	defer r.invalidate(config.Name)
+	return r.ConfigRepository.Rollback(ctx, config, version)
+}
+
+// RevertWrite undoes the latest write and invalidates its cache entry
+func (r *CachingRepository) RevertWrite(ctx context.Context, name string, version int) error {
+	defer r.invalidate(name)
+	return r.ConfigRepository.RevertWrite(ctx, name, version)
+}
+
+// Delete removes a configuration and invalidates its cache entry
+func (r *CachingRepository) Delete(ctx context.Context, name string) error {
+	defer r.invalidate(name)
+	return r.ConfigRepository.Delete(ctx, name)
+}
+
+// Rename moves a configuration and empties the cache, since the
+// configurations depending on it change too
+func (r *CachingRepository) Rename(ctx context.Context, oldName, newName string) error {
+	defer r.invalidateAll()
+	return r.ConfigRepository.Rename(ctx, oldName, newName)
+}
+
+// SetLocked sets the lock flag and invalidates the cache entry
+func (r *CachingRepository) SetLocked(ctx context.Context, name string, locked bool) error {
+	defer r.invalidate(name)
+	return r.ConfigRepository.SetLocked(ctx, name, locked)
+}
+
+// SetDeprecation sets the deprecation and invalidates the cache entry
+func (r *CachingRepository) SetDeprecation(ctx context.Context, name string, deprecated bool, message string) error {
+	defer r.invalidate(name)
+	return r.ConfigRepository.SetDeprecation(ctx, name, deprecated, message)
+}
+
+// WithTransaction runs fn against the inner repository's transactional view
+// and empties the cache afterwards, since fn may have written anything
+func (r *CachingRepository) WithTransaction(ctx context.Context, fn func(tx ConfigRepository) error) error {
+	defer r.invalidateAll()
+	return r.ConfigRepository.WithTransaction(ctx, fn)
+}
+
+// Subscribe relays the inner repository's change events, each delivered
+// after its configuration is invalidated. Without an inner repository that
+// publishes events the channel is closed immediately.
+func (r *CachingRepository) Subscribe() (<-chan models.ChangeEvent, func()) {
+	if r.subscribed {
+		return r.events.subscribe()
+	}
+	ch := make(chan models.ChangeEvent)
+	close(ch)
+	return ch, func() {}
+}
+
+// Compact forwards to the inner repository and empties the cache
+func (r *CachingRepository) Compact(ctx context.Context, keepVersions int) (*models.CompactResponse, error) {
+	compactor, ok := r.ConfigRepository.(Compactor)
+	if !ok {
+		return nil, errors.New("repository does not support compaction")
+	}
+	defer r.invalidateAll()
+	return compactor.Compact(ctx, keepVersions)
+}
+
+// CompactConfig forwards to the inner repository and invalidates the cache entry
+func (r *CachingRepository) CompactConfig(ctx context.Context, name string, keepVersions int) (*models.CompactResponse, error) {
+	compactor, ok := r.ConfigRepository.(Compactor)
+	if !ok {
+		return nil, errors.New("repository does not support compaction")
+	}
+	defer r.invalidate(name)
+	return compactor.CompactConfig(ctx, name, keepVersions)
+}
+
+// Snapshot forwards to the inner repository
+func (r *CachingRepository) Snapshot(ctx context.Context) ([]byte, error) {
+	snapshotter, ok := r.ConfigRepository.(Snapshotter)
+	if !ok {
+		return nil, errors.New("repository does not support snapshots")
+	}
+	return snapshotter.Snapshot(ctx)
+}
+
+// Restore forwards to the inner repository and empties the cache
+func (r *CachingRepository) Restore(ctx context.Context, data []byte) (int, error) {
+	snapshotter, ok := r.ConfigRepository.(Snapshotter)
+	if !ok {
+		return 0, errors.New("repository does not support snapshots")
+	}
+	defer r.invalidateAll()
+	return snapshotter.Restore(ctx, data)
+}
+
+// Capacity forwards to the inner repository, counting its configurations
+// as unlimited when it does not cap them
+func (r *CachingRepository) Capacity(ctx context.Context) (int, int, error) {
+	if reporter, ok := r.ConfigRepository.(CapacityReporter); ok {
+		return reporter.Capacity(ctx)
+	}
+	names, err := r.ConfigRepository.ListNames(ctx)
+	return len(names), 0, err
+}
+
+// Usage forwards to the inner repository; reads served by the cache are not counted
+func (r *CachingRepository) Usage(ctx context.Context, name string) (*models.ConfigStatsResponse, error) {
+	reporter, ok := r.ConfigRepository.(UsageReporter)
+	if !ok {
+		return nil, errors.New("repository does not track usage")
+	}
+	return reporter.Usage(ctx, name)
+}
+
+// Stats forwards to the inner repository, adding the number of cached
+// configurations
+func (r *CachingRepository) Stats() map[string]interface{} {
+	stats := make(map[string]interface{})
+	if reporter, ok := r.ConfigRepository.(interface{ Stats() map[string]interface{} }); ok {
+		for key, value := range reporter.Stats() {
+			stats[key] = value
+		}
+	}
+
+	r.mu.Lock()
+	stats["cached_configs"] = r.order.Len()
+	r.mu.Unlock()
+	return stats
+}
+
+// cloneConfig returns a deep copy of a configuration so cached entries cannot
+// be modified by callers
+func cloneConfig(config *models.Config) *models.Config {
+	configCopy := *config
+	configCopy.Data = copyData(config.Data)
+	configCopy.DependsOn = copyStrings(config.DependsOn)
+	configCopy.Rollout = copyRollout(config.Rollout)
+	return &configCopy
+}
+
+// Validate that CachingRepository implements ConfigRepository and forwards
+// the optional capabilities
+var (
+	_ ConfigRepository = (*CachingRepository)(nil)
+	_ Subscriber       = (*CachingRepository)(nil)
+	_ Compactor        = (*CachingRepository)(nil)
+	_ Snapshotter      = (*CachingRepository)(nil)
+	_ CapacityReporter = (*CachingRepository)(nil)
+	_ UsageReporter    = (*CachingRepository)(nil)
+)
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"config-engine/internal/models"
+)
+
+// countingRepository counts the Get calls that reach the wrapped repository.
+// It does not publish change events, so only the cache's own writes
+// invalidate entries.
+type countingRepository struct {
+	ConfigRepository
+	gets atomic.Int64
+}
+
+func (r *countingRepository) Get(ctx context.Context, name string) (*models.Config, error) {
+	r.gets.Add(1)
+	return r.ConfigRepository.Get(ctx, name)
+}
+
+func newCachedConfig(t *testing.T, repo ConfigRepository, name string, limit int) {
+	t.Helper()
+	if err := repo.Create(context.Background(), &models.Config{
+		Name: name,
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": limit},
+	}); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+}
+
+func TestCachingRepositoryServesHits(t *testing.T) {
+	inner := &countingRepository{ConfigRepository: NewInMemoryRepository()}
+	cache := NewCachingRepository(inner, 10)
+	defer cache.Close()
+	ctx := context.Background()
+	newCachedConfig(t, cache, "payments", 100)
+
+	for i := 0; i < 3; i++ {
+		config, err := cache.Get(ctx, "payments")
+		if err != nil {
+			t.Fatalf("Failed to get config: %v", err)
+		}
+		if config.Data["max_limit"] != 100 {
+			t.Fatalf("Expected max_limit 100, got %v", config.Data["max_limit"])
+		}
+		// Callers get copies, so this must not leak into the cache
+		config.Data["max_limit"] = 0
+	}
+	if gets := inner.gets.Load(); gets != 1 {
+		t.Errorf("Expected 1 read of the inner repository, got %d", gets)
+	}
+
+	// Misses are not cached
+	if _, err := cache.Get(ctx, "missing"); err == nil {
+		t.Error("Expected an error for a missing config")
+	}
+}
+
+func TestCachingRepositoryInvalidatesOnWrite(t *testing.T) {
+	inner := &countingRepository{ConfigRepository: NewInMemoryRepository()}
+	cache := NewCachingRepository(inner, 10)
+	defer cache.Close()
+	ctx := context.Background()
+	newCachedConfig(t, cache, "payments", 100)
+	cache.Get(ctx, "payments")
+
+	if err := cache.Update(ctx, &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": 200}}); err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+	config, _ := cache.Get(ctx, "payments")
+	if config.Version != 2 || config.Data["max_limit"] != 200 {
+		t.Errorf("Expected version 2 with max_limit 200, got version %d with %v", config.Version, config.Data["max_limit"])
+	}
+	if gets := inner.gets.Load(); gets != 2 {
+		t.Errorf("Expected the update to force a second inner read, got %d reads", gets)
+	}
+
+	if err := cache.Delete(ctx, "payments"); err != nil {
+		t.Fatalf("Failed to delete config: %v", err)
+	}
+	if _, err := cache.Get(ctx, "payments"); err == nil {
+		t.Error("Expected a deleted config not to be served from the cache")
+	}
+}
+
+func TestCachingRepositoryFollowsInnerEvents(t *testing.T) {
+	inner := NewInMemoryRepository()
+	cache := NewCachingRepository(inner, 10)
+	defer cache.Close()
+	ctx := context.Background()
+	newCachedConfig(t, inner, "payments", 100)
+	cache.Get(ctx, "payments")
+
+	// A write that bypasses the cache is picked up from the change event
+	inner.Update(ctx, &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": 200}})
+	deadline := time.Now().Add(time.Second)
+	for {
+		config, _ := cache.Get(ctx, "payments")
+		if config.Version == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the cache to drop the entry after the change event")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCachingRepositoryEvictsLeastRecentlyUsed(t *testing.T) {
+	inner := &countingRepository{ConfigRepository: NewInMemoryRepository()}
+	cache := NewCachingRepository(inner, 2)
+	defer cache.Close()
+	ctx := context.Background()
+	for _, name := range []string{"a", "b", "c"} {
+		newCachedConfig(t, cache, name, 1)
+	}
+
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "b")
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "c") // evicts b
+	inner.gets.Store(0)
+
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "b")
+	if gets := inner.gets.Load(); gets != 1 {
+		t.Errorf("Expected only the evicted config to be read again, got %d reads", gets)
+	}
+}
+
+func TestCachingRepositoryEventsFollowInvalidation(t *testing.T) {
+	inner := NewInMemoryRepository()
+	cache := NewCachingRepository(inner, 10)
+	defer cache.Close()
+	ctx := context.Background()
+
+	events, unsubscribe := cache.Subscribe()
+	defer unsubscribe()
+
+	for i := 1; i <= 20; i++ {
+		// Writes bypassing the cache are only seen through the inner events
+		if i == 1 {
+			newCachedConfig(t, inner, "payments", i)
+		} else if err := inner.Update(ctx, &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": i}}); err != nil {
+			t.Fatalf("Failed to update config: %v", err)
+		}
+		select {
+		case event := <-events:
+			// A read prompted by the event must see the write it reports
+			config, _ := cache.Get(ctx, "payments")
+			if config.Version != event.Version {
+				t.Fatalf("Expected version %d after its event, got %d", event.Version, config.Version)
+			}
+			cache.Get(ctx, "payments")
+		case <-time.After(time.Second):
+			t.Fatal("Expected a change event")
+		}
+	}
+}
+
+func TestCachingRepositoryForwardsCapabilities(t *testing.T) {
+	inner := NewInMemoryRepository()
+	inner.SetMaxConfigs(5)
+	cache := NewCachingRepository(inner, 10)
+	defer cache.Close()
+	ctx := context.Background()
+	newCachedConfig(t, cache, "payments", 100)
+	cache.Update(ctx, &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": 200}})
+
+	if used, limit, err := cache.Capacity(ctx); err != nil || used != 1 || limit != 5 {
+		t.Errorf("Expected 1 of 5 configs used, got %d of %d (%v)", used, limit, err)
+	}
+	cache.Get(ctx, "payments")
+	if usage, err := cache.Usage(ctx, "payments"); err != nil || usage.Reads != 1 {
+		t.Errorf("Expected the inner read to be counted, got %+v (%v)", usage, err)
+	}
+
+	snapshot, err := cache.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Failed to snapshot: %v", err)
+	}
+	if _, err := cache.Compact(ctx, 1); err != nil {
+		t.Fatalf("Failed to compact: %v", err)
+	}
+	if versions, _ := cache.ListVersions(ctx, "payments"); len(versions) != 1 {
+		t.Errorf("Expected compaction to keep 1 version, got %d", len(versions))
+	}
+
+	cache.Update(ctx, &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": 300}})
+	cache.Get(ctx, "payments")
+	if _, err := cache.Restore(ctx, snapshot); err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+	if config, _ := cache.Get(ctx, "payments"); config.Version != 2 {
+		t.Errorf("Expected the restored version 2 instead of a cached one, got %d", config.Version)
+	}
+	if stats := cache.Stats(); stats["total_configs"] != 1 {
+		t.Errorf("Expected the inner statistics, got %v", stats)
+	}
+
+	bare := NewCachingRepository(&countingRepository{ConfigRepository: inner}, 10)
+	defer bare.Close()
+	if _, err := bare.Compact(ctx, 1); err == nil {
+		t.Error("Expected compaction to fail without an inner Compactor")
+	}
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"config-engine/internal/models"
+)
+
+// Compactor is implemented by repositories that can reclaim old version history
+type Compactor interface {
+	Compact(ctx context.Context, keepVersions int) (*models.CompactResponse, error)
+	CompactConfig(ctx context.Context, name string, keepVersions int) (*models.CompactResponse, error)
+}
+
+// Compact drops version history beyond the newest keepVersions versions of
+// each configuration. The current version and any tagged version are always
+// kept so tags keep resolving. A keepVersions of 0 or less keeps everything.
+// Deleted configurations are removed immediately rather than tombstoned, so
+// there is no deleted data left for compaction to reclaim.
+//
+// Compaction runs as a transaction; readers see either the full or the
+// compacted history, never a partial one, and a cancelled compaction changes
+// nothing.
+func (r *InMemoryRepository) Compact(ctx context.Context, keepVersions int) (*models.CompactResponse, error) {
+	return r.compactAll(ctx, keepVersions, nil)
+}
+
+// compactAll compacts every configuration like Compact, passing commit to transact
+func (r *InMemoryRepository) compactAll(ctx context.Context, keepVersions int, commit func(names []string) error) (*models.CompactResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	stats := &models.CompactResponse{KeepVersions: keepVersions}
+	if keepVersions <= 0 {
+		return stats, nil
+	}
+
+	err := r.transact(func(tx *inMemoryTx) error {
+		for name := range r.entries {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			tx.compact(name, keepVersions, stats)
+		}
+		return nil
+	}, commit)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// CompactConfig drops version history beyond the newest keepVersions
+// versions of one configuration, keeping the same versions as Compact
+func (r *InMemoryRepository) CompactConfig(ctx context.Context, name string, keepVersions int) (*models.CompactResponse, error) {
+	return r.compactConfig(ctx, name, keepVersions, nil)
+}
+
+// compactConfig compacts one configuration like CompactConfig, passing commit to transact
+func (r *InMemoryRepository) compactConfig(ctx context.Context, name string, keepVersions int, commit func(names []string) error) (*models.CompactResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	stats := &models.CompactResponse{KeepVersions: keepVersions}
+	err := r.transact(func(tx *inMemoryTx) error {
+		if _, err := r.entry(name); err != nil {
+			return err
+		}
+		if keepVersions > 0 {
+			tx.compact(name, keepVersions, stats)
+		}
+		return nil
+	}, commit)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// compact drops the entry's versions beyond the newest keepVersions, except
+// the head and tagged ones, and adds what it removed to stats; callers must
+// hold the repository write lock
+func (e *configEntry) compact(keepVersions int, stats *models.CompactResponse) {
+	versions := e.versions
+	if len(versions) <= keepVersions {
+		return
+	}
+
+	tagged := make(map[int]struct{}, len(e.tags))
+	for _, version := range e.tags {
+		tagged[version] = struct{}{}
+	}
+
+	// Build a new slice so transaction snapshots sharing the old one are unaffected
+	cutoff := len(versions) - keepVersions
+	kept := make([]models.ConfigVersion, 0, keepVersions+len(tagged))
+	for i, version := range versions {
+		// The head is usually the newest version, but not after a branching rollback
+		isHead := version.Version == e.config.Version
+		if _, isTagged := tagged[version.Version]; i >= cutoff || isTagged || isHead {
+			kept = append(kept, version)
+			continue
+		}
+		stats.VersionsRemoved++
+		stats.BytesReclaimed += dataSize(version.Data)
+	}
+
+	if len(kept) < len(versions) {
+		e.versions = kept
+		e.undo = nil
+		stats.ConfigsCompacted++
+	}
+}
+
+// dataSize returns the approximate serialized size of config data
+func dataSize(data map[string]interface{}) int64 {
+	encoded, err := json.Marshal(models.ConfigData(data))
+	if err != nil {
+		return 0
+	}
+	return int64(len(encoded))
+}
+
+// Validate that InMemoryRepository implements Compactor
+var _ Compactor = (*InMemoryRepository)(nil)
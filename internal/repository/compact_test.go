@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"config-engine/internal/models"
+)
+
+func seedVersions(t *testing.T, repo *InMemoryRepository, name string, count int) {
+	t.Helper()
+	if err := repo.Create(context.Background(), &models.Config{Name: name, Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}}); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	for i := 2; i <= count; i++ {
+		if err := repo.Update(context.Background(), &models.Config{Name: name, Type: "payment_config", Data: map[string]interface{}{"max_limit": i}}); err != nil {
+			t.Fatalf("Failed to update config: %v", err)
+		}
+	}
+}
+
+func TestCompact(t *testing.T) {
+	repo := NewInMemoryRepository()
+	seedVersions(t, repo, "a", 5)
+	seedVersions(t, repo, "b", 2)
+	repo.SetTag(context.Background(), "a", "stable", 1)
+
+	stats, err := repo.Compact(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	if stats.VersionsRemoved != 2 || stats.ConfigsCompacted != 1 {
+		t.Errorf("Expected 2 versions removed from 1 config, got %+v", stats)
+	}
+	if stats.BytesReclaimed == 0 {
+		t.Error("Expected reclaimed bytes to be reported")
+	}
+
+	versions, _ := repo.ListVersions(context.Background(), "a")
+	got := make([]int, len(versions))
+	for i, v := range versions {
+		got[i] = v.Version
+	}
+	if len(got) != 3 || got[0] != 1 || got[1] != 4 || got[2] != 5 {
+		t.Errorf("Expected versions [1 4 5] (tagged version kept), got %v", got)
+	}
+
+	if _, err := repo.GetVersion(context.Background(), "a", 2); err == nil {
+		t.Error("Expected compacted version to be gone")
+	}
+	if v, err := repo.GetVersion(context.Background(), "a", 4); err != nil || v.Data["max_limit"] != 4 {
+		t.Errorf("Expected version 4 to remain addressable, got %v, %v", v, err)
+	}
+
+	// New versions keep numbering after compaction
+	repo.Update(context.Background(), &models.Config{Name: "a", Type: "payment_config", Data: map[string]interface{}{"max_limit": 6}})
+	if config, _ := repo.Get(context.Background(), "a"); config.Version != 6 {
+		t.Errorf("Expected version 6, got %d", config.Version)
+	}
+}
+
+func TestCompactKeepAll(t *testing.T) {
+	repo := NewInMemoryRepository()
+	seedVersions(t, repo, "a", 3)
+
+	stats, _ := repo.Compact(context.Background(), 0)
+	if stats.VersionsRemoved != 0 {
+		t.Errorf("Expected nothing removed, got %+v", stats)
+	}
+}
+
+// TestCompactConcurrentReads is meant to be run with -race
+func TestCompactConcurrentReads(t *testing.T) {
+	repo := NewInMemoryRepository()
+	seedVersions(t, repo, "a", 50)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repo.ListVersions(context.Background(), "a")
+			repo.Get(context.Background(), "a")
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		repo.Compact(context.Background(), 5)
+	}()
+	wg.Wait()
+
+	if versions, _ := repo.ListVersions(context.Background(), "a"); len(versions) != 5 {
+		t.Errorf("Expected 5 versions after compaction, got %d", len(versions))
+	}
+}
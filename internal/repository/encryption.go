@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// formatEncryptedV1 marks a file encrypted with AES-256-GCM under the
+	// SHA-256 of the key: version byte, nonce, ciphertext. It is only read,
+	// to migrate it.
+	formatEncryptedV1 byte = 1
+	// formatEncryptedV2 marks a file encrypted with AES-256-GCM under a key
+	// derived with scrypt: version byte, salt, nonce, ciphertext. The
+	// configuration name is authenticated as additional data, so a file
+	// cannot be moved to another name.
+	formatEncryptedV2 byte = 2
+
+	// saltSize is the length of the scrypt salt in the V2 header
+	saltSize = 16
+	// scrypt cost parameters, the recommended interactive settings
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	// errPlaintextFile is returned for a plaintext file when a key is configured
+	errPlaintextFile = errors.New("file is not encrypted but a key is configured; open the repository once with migration to encrypt it")
+	// errLegacyFile is returned for a file in an older encrypted format
+	errLegacyFile = errors.New("file is encrypted in an older format; open the repository once with migration to re-encrypt it")
+)
+
+// deriveCipher derives the AES-256-GCM cipher of key and salt with scrypt
+func deriveCipher(key, salt []byte) (cipher.AEAD, error) {
+	derived, err := scrypt.Key(key, salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return newGCM(derived)
+}
+
+// newGCM creates an AES-GCM cipher for a 32-byte key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return aead, nil
+}
+
+// cipherFor returns the cipher of a salt, deriving it once. The first salt
+// seen becomes the one files are written with, so reopening a directory
+// costs one derivation rather than one per file. Callers must hold mu.
+func (r *FileRepository) cipherFor(salt []byte) (cipher.AEAD, error) {
+	if aead, ok := r.ciphers[string(salt)]; ok {
+		return aead, nil
+	}
+	// Keep a copy; salt may point into a file's contents
+	salt = append([]byte(nil), salt...)
+	aead, err := deriveCipher(r.key, salt)
+	if err != nil {
+		return nil, err
+	}
+	r.ciphers[string(salt)] = aead
+	if r.aead == nil {
+		r.salt, r.aead = salt, aead
+	}
+	return aead, nil
+}
+
+// initCipher picks a new random salt to write files with when a key is
+// configured and no existing file supplied one
+func (r *FileRepository) initCipher() error {
+	if len(r.key) == 0 || r.aead != nil {
+		return nil
+	}
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	_, err := r.cipherFor(salt)
+	return err
+}
+
+// encode encrypts the file of a configuration when a key is configured
+func (r *FileRepository) encode(name string, plain []byte) ([]byte, error) {
+	if r.aead == nil {
+		return plain, nil
+	}
+
+	nonce := make([]byte, r.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, 1+len(r.salt)+len(nonce)+len(plain)+r.aead.Overhead())
+	out = append(out, formatEncryptedV2)
+	out = append(out, r.salt...)
+	out = append(out, nonce...)
+	return r.aead.Seal(out, nonce, plain, []byte(name)), nil
+}
+
+// decode decrypts the file of a configuration. Without a key only plaintext
+// is accepted. With one, plaintext and V1 files are refused unless migrate
+// is set, in which case they are decoded and reported as outdated so the
+// caller rewrites them.
+func (r *FileRepository) decode(name string, raw []byte, migrate bool) (plain []byte, outdated bool, err error) {
+	if len(raw) == 0 {
+		return nil, false, errors.New("empty file")
+	}
+
+	encrypted := len(r.key) > 0
+	switch raw[0] {
+	case '{':
+		if !encrypted {
+			return raw, false, nil
+		}
+		if !migrate {
+			return nil, false, errPlaintextFile
+		}
+		return raw, true, nil
+	case formatEncryptedV1:
+		if !encrypted {
+			return nil, false, errors.New("file is encrypted but no key is configured")
+		}
+		if !migrate {
+			return nil, false, errLegacyFile
+		}
+		sum := sha256.Sum256(r.key)
+		aead, err := newGCM(sum[:])
+		if err != nil {
+			return nil, false, err
+		}
+		plain, err := open(aead, raw[1:], nil)
+		return plain, true, err
+	case formatEncryptedV2:
+		if !encrypted {
+			return nil, false, errors.New("file is encrypted but no key is configured")
+		}
+		if len(raw) < 1+saltSize {
+			return nil, false, errors.New("encrypted file is truncated")
+		}
+		aead, err := r.cipherFor(raw[1 : 1+saltSize])
+		if err != nil {
+			return nil, false, err
+		}
+		plain, err := open(aead, raw[1+saltSize:], []byte(name))
+		return plain, false, err
+	default:
+		return nil, false, fmt.Errorf("unsupported file format version: %d", raw[0])
+	}
+}
+
+// open decrypts a nonce followed by its ciphertext
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errors.New("encrypted file is truncated")
+	}
+	plain, err := aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plain, nil
+}
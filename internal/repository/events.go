@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"config-engine/internal/models"
+)
+
+// subscriberBuffer is the number of events buffered per subscriber.
+//
+// Delivery is non-blocking: each subscriber gets a buffered channel and, once
+// that buffer is full, further events for that subscriber are dropped rather
+// than stalling the write path. Subscribers that cannot afford to miss events
+// should drain promptly and re-read state from the repository after a gap.
+const subscriberBuffer = 64
+
+// Subscriber is implemented by repositories that publish change events
+type Subscriber interface {
+	Subscribe() (<-chan models.ChangeEvent, func())
+}
+
+// eventBus fans out change events to subscribers
+type eventBus struct {
+	mu          sync.Mutex
+	nextID      int
+	subscribers map[int]chan models.ChangeEvent
+}
+
+// Subscribe registers a subscriber for create/update/delete events.
+// The returned func unsubscribes and closes the channel; it is safe to call more than once.
+func (r *InMemoryRepository) Subscribe() (<-chan models.ChangeEvent, func()) {
+	return r.events.subscribe()
+}
+
+// publish delivers events to every subscriber, dropping them for subscribers whose buffer is full
+func (r *InMemoryRepository) publish(events ...models.ChangeEvent) {
+	r.events.publish(events...)
+}
+
+// subscribe registers a subscriber; see Subscribe
+func (b *eventBus) subscribe() (<-chan models.ChangeEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers == nil {
+		b.subscribers = make(map[int]chan models.ChangeEvent)
+	}
+
+	id := b.nextID
+	b.nextID++
+	ch := make(chan models.ChangeEvent, subscriberBuffer)
+	b.subscribers[id] = ch
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscribers, id)
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+// publish delivers events to every subscriber, dropping them for subscribers whose buffer is full
+func (b *eventBus) publish(events ...models.ChangeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, event := range events {
+		for _, ch := range b.subscribers {
+			select {
+			case ch <- event:
+			default:
+			}
+		}
+	}
+}
+
+// newChangeEvent builds a change event stamped with the current time
+func newChangeEvent(changeType models.ChangeType, name string, version int) models.ChangeEvent {
+	return models.ChangeEvent{
+		Type:      changeType,
+		Name:      name,
+		Version:   version,
+		Timestamp: time.Now(),
+	}
+}
+
+// Validate that InMemoryRepository implements Subscriber
+var _ Subscriber = (*InMemoryRepository)(nil)
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"config-engine/internal/models"
+)
+
+func TestSubscribeReceivesChanges(t *testing.T) {
+	repo := NewInMemoryRepository()
+	events, unsubscribe := repo.Subscribe()
+	defer unsubscribe()
+
+	repo.Create(context.Background(), &models.Config{Name: "a", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}})
+	repo.Update(context.Background(), &models.Config{Name: "a", Type: "payment_config", Data: map[string]interface{}{"max_limit": 2}})
+	repo.Delete(context.Background(), "a")
+
+	expected := []models.ChangeEvent{
+		{Type: models.ChangeCreated, Name: "a", Version: 1},
+		{Type: models.ChangeUpdated, Name: "a", Version: 2},
+		{Type: models.ChangeDeleted, Name: "a"},
+	}
+	for _, want := range expected {
+		got := <-events
+		if got.Type != want.Type || got.Name != want.Name || got.Version != want.Version {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+		if got.Timestamp.IsZero() {
+			t.Error("Expected event timestamp to be set")
+		}
+	}
+}
+
+func TestSubscribeSkipsFailedWrites(t *testing.T) {
+	repo := NewInMemoryRepository()
+	events, unsubscribe := repo.Subscribe()
+	defer unsubscribe()
+
+	repo.Update(context.Background(), &models.Config{Name: "missing"})
+	repo.Delete(context.Background(), "missing")
+
+	select {
+	case event := <-events:
+		t.Errorf("Expected no events for failed writes, got %+v", event)
+	default:
+	}
+}
+
+func TestSubscribeTransactionEvents(t *testing.T) {
+	repo := NewInMemoryRepository()
+	events, unsubscribe := repo.Subscribe()
+	defer unsubscribe()
+
+	repo.WithTransaction(context.Background(), func(tx ConfigRepository) error {
+		tx.Create(context.Background(), &models.Config{Name: "a", Type: "payment_config"})
+		return errors.New("abort")
+	})
+	select {
+	case event := <-events:
+		t.Fatalf("Expected no events from a rolled back transaction, got %+v", event)
+	default:
+	}
+
+	repo.WithTransaction(context.Background(), func(tx ConfigRepository) error {
+		return tx.Create(context.Background(), &models.Config{Name: "b", Type: "payment_config"})
+	})
+	if event := <-events; event.Type != models.ChangeCreated || event.Name != "b" {
+		t.Errorf("Expected created event for b, got %+v", event)
+	}
+}
+
+func TestSubscribeSlowSubscriberDoesNotBlock(t *testing.T) {
+	repo := NewInMemoryRepository()
+	events, unsubscribe := repo.Subscribe()
+
+	// Writes beyond the buffer must not block even though nobody is reading
+	repo.Create(context.Background(), &models.Config{Name: "a", Type: "payment_config"})
+	for i := 0; i < subscriberBuffer*2; i++ {
+		repo.Update(context.Background(), &models.Config{Name: "a", Type: "payment_config"})
+	}
+
+	if len(events) != subscriberBuffer {
+		t.Errorf("Expected %d buffered events, got %d", subscriberBuffer, len(events))
+	}
+
+	unsubscribe()
+	unsubscribe()
+	for range events {
+	}
+	repo.Delete(context.Background(), "a")
+}
@@ -0,0 +1,411 @@
+package repository
+
+import (
+	"context"
+	"crypto/cipher"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"config-engine/internal/models"
+)
+
+// fileExtension is the extension of persisted configuration files
+const fileExtension = ".json"
+
+// FileRepository implements ConfigRepository on top of InMemoryRepository,
+// persisting every configuration to its own file in a directory.
+// When a key is configured, files are encrypted at rest with AES-GCM.
+type FileRepository struct {
+	*InMemoryRepository
+	dir string
+	// key is the configured passphrase; ciphers are derived from it per salt
+	key     []byte
+	ciphers map[string]cipher.AEAD
+	// salt and aead encrypt every file written
+	salt []byte
+	aead cipher.AEAD
+}
+
+// fileRecord is the on-disk representation of a configuration and its history
+type fileRecord struct {
+	Config    *models.Config             `json:"config"`
+	Versions  []models.ConfigVersion     `json:"versions"`
+	Tags      map[string]int             `json:"tags,omitempty"`
+	Proposals map[string]models.Proposal `json:"proposals,omitempty"`
+}
+
+// NewFileRepository creates a file-backed repository rooted at dir and loads
+// any existing configurations. If key is empty, files are stored in plaintext.
+// With a key, a plaintext file fails the load: anyone able to write to the
+// directory could otherwise add configurations that bypass the encryption.
+func NewFileRepository(dir string, key []byte) (*FileRepository, error) {
+	return newFileRepository(dir, key, false)
+}
+
+// NewFileRepositoryWithMigration creates a file-backed repository like
+// NewFileRepository, but loads plaintext files and files in an older
+// encrypted format and rewrites them in the current format. It is meant to be
+// used once, when encryption is turned on for an existing data directory or
+// after upgrading.
+func NewFileRepositoryWithMigration(dir string, key []byte) (*FileRepository, error) {
+	if len(key) == 0 {
+		return nil, errors.New("migrating files requires a key")
+	}
+	return newFileRepository(dir, key, true)
+}
+
+// newFileRepository creates a file-backed repository, migrating outdated
+// files when migrate is set
+func newFileRepository(dir string, key []byte, migrate bool) (*FileRepository, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	r := &FileRepository{
+		InMemoryRepository: NewInMemoryRepository(),
+		dir:                dir,
+		key:                key,
+		ciphers:            make(map[string]cipher.AEAD),
+	}
+
+	if err := r.load(migrate); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Create creates a new configuration and persists it
+func (r *FileRepository) Create(ctx context.Context, config *models.Config) error {
+	return r.write(func(tx ConfigRepository) error {
+		return tx.Create(ctx, config)
+	})
+}
+
+// Update updates an existing configuration and persists it
+func (r *FileRepository) Update(ctx context.Context, config *models.Config) error {
+	return r.write(func(tx ConfigRepository) error {
+		return tx.Update(ctx, config)
+	})
+}
+
+// Rollback makes a previous version the head and persists the result
+func (r *FileRepository) Rollback(ctx context.Context, config *models.Config, version int) error {
+	return r.write(func(tx ConfigRepository) error {
+		return tx.Rollback(ctx, config, version)
+	})
+}
+
+// Delete removes a configuration and its file
+func (r *FileRepository) Delete(ctx context.Context, name string) error {
+	return r.write(func(tx ConfigRepository) error {
+		return tx.Delete(ctx, name)
+	})
+}
+
+// Rename moves a configuration and persists the files it touched. The new
+// file is written before the old one is removed, so a crash part way leaves
+// both rather than neither.
+func (r *FileRepository) Rename(ctx context.Context, oldName, newName string) error {
+	return r.write(func(tx ConfigRepository) error {
+		return tx.Rename(ctx, oldName, newName)
+	})
+}
+
+// SetTag assigns a tag to a version and persists it
+func (r *FileRepository) SetTag(ctx context.Context, name, tag string, version int) error {
+	return r.write(func(tx ConfigRepository) error {
+		return tx.SetTag(ctx, name, tag, version)
+	})
+}
+
+// SetLocked sets the lock flag and persists the configuration
+func (r *FileRepository) SetLocked(ctx context.Context, name string, locked bool) error {
+	return r.write(func(tx ConfigRepository) error {
+		return tx.SetLocked(ctx, name, locked)
+	})
+}
+
+// SetDeprecation sets the deprecation and persists the configuration
+func (r *FileRepository) SetDeprecation(ctx context.Context, name string, deprecated bool, message string) error {
+	return r.write(func(tx ConfigRepository) error {
+		return tx.SetDeprecation(ctx, name, deprecated, message)
+	})
+}
+
+// SquashVersions collapses a range of versions and persists the result
+func (r *FileRepository) SquashVersions(ctx context.Context, name string, from, to int) (int, error) {
+	var removed int
+	err := r.write(func(tx ConfigRepository) error {
+		var err error
+		removed, err = tx.SquashVersions(ctx, name, from, to)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// RevertWrite undoes the latest write and persists the restored state
+func (r *FileRepository) RevertWrite(ctx context.Context, name string, version int) error {
+	return r.write(func(tx ConfigRepository) error {
+		return tx.RevertWrite(ctx, name, version)
+	})
+}
+
+// SaveProposal stores a pending proposal and persists it
+func (r *FileRepository) SaveProposal(ctx context.Context, proposal *models.Proposal) error {
+	return r.write(func(tx ConfigRepository) error {
+		return tx.SaveProposal(ctx, proposal)
+	})
+}
+
+// DeleteProposal removes a pending proposal and persists the change
+func (r *FileRepository) DeleteProposal(ctx context.Context, name, id string) error {
+	return r.write(func(tx ConfigRepository) error {
+		return tx.DeleteProposal(ctx, name, id)
+	})
+}
+
+// WithTransaction runs fn atomically and persists the configurations it touched
+func (r *FileRepository) WithTransaction(ctx context.Context, fn func(tx ConfigRepository) error) error {
+	return r.write(fn)
+}
+
+// write applies fn to the in-memory state as a transaction and persists the
+// configurations it touched before committing. If persisting fails the
+// transaction is rolled back, so memory never keeps a write the disk lacks.
+func (r *FileRepository) write(fn func(tx ConfigRepository) error) error {
+	return r.InMemoryRepository.transact(func(tx *inMemoryTx) error { return fn(tx) }, r.persistLocked)
+}
+
+// Compact drops old version history and rewrites the affected files
+func (r *FileRepository) Compact(ctx context.Context, keepVersions int) (*models.CompactResponse, error) {
+	return r.InMemoryRepository.compactAll(ctx, keepVersions, r.persistLocked)
+}
+
+// CompactConfig drops old version history of one configuration and rewrites its file
+func (r *FileRepository) CompactConfig(ctx context.Context, name string, keepVersions int) (*models.CompactResponse, error) {
+	return r.InMemoryRepository.compactConfig(ctx, name, keepVersions, r.persistLocked)
+}
+
+// Restore replaces the entire state with a snapshot and rewrites the data directory
+func (r *FileRepository) Restore(ctx context.Context, data []byte) (int, error) {
+	return r.InMemoryRepository.restore(ctx, data, r.persistLocked)
+}
+
+// Ping checks that the data directory is still accessible
+func (r *FileRepository) Ping(ctx context.Context) error {
+	if _, err := os.Stat(r.dir); err != nil {
+		return fmt.Errorf("data directory unavailable: %w", err)
+	}
+	return nil
+}
+
+// Clear removes all configurations and their files. If a file cannot be
+// removed, nothing is cleared.
+func (r *FileRepository) Clear() error {
+	return r.InMemoryRepository.clear(r.persistLocked)
+}
+
+// load reads every configuration file in the data directory into memory.
+// Plaintext and legacy encrypted files are refused when a key is configured
+// unless migrate is set, in which case they are rewritten in the current
+// format.
+func (r *FileRepository) load(migrate bool) error {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read data directory: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var migrated []string
+	for _, entry := range entries {
+		name, ok := r.nameFromFile(entry.Name())
+		if entry.IsDir() || !ok {
+			continue
+		}
+
+		raw, err := os.ReadFile(filepath.Join(r.dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		plain, outdated, err := r.decode(name, raw, migrate)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %w", entry.Name(), err)
+		}
+
+		var record fileRecord
+		if err := models.DecodeJSON(plain, &record); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", entry.Name(), err)
+		}
+		if record.Config == nil {
+			return fmt.Errorf("failed to parse %s: missing config", entry.Name())
+		}
+		if record.Config.Name != name {
+			return fmt.Errorf("failed to parse %s: holds configuration %s", entry.Name(), record.Config.Name)
+		}
+
+		r.entries[record.Config.Name] = &configEntry{
+			config:    record.Config,
+			versions:  record.Versions,
+			tags:      record.Tags,
+			proposals: record.Proposals,
+		}
+		r.index(record.Config.Name, record.Config.DependsOn)
+		if outdated {
+			migrated = append(migrated, name)
+		}
+	}
+
+	policiesOutdated, err := r.loadPolicies(migrate)
+	if err != nil {
+		return err
+	}
+
+	if err := r.initCipher(); err != nil {
+		return err
+	}
+	if err := r.persistLocked(migrated); err != nil {
+		return fmt.Errorf("failed to migrate files: %w", err)
+	}
+	if policiesOutdated {
+		if err := r.persistPolicies(r.policies); err != nil {
+			return fmt.Errorf("failed to migrate files: %w", err)
+		}
+	}
+	return nil
+}
+
+// persistLocked writes the current state of configurations to disk, removing
+// the files of deleted ones; callers must hold mu. Every file is written and
+// synced to its own temporary file first, and only once all of them are
+// written are they moved into place, so a failure while writing changes
+// nothing on disk. Each file is replaced atomically, but a write touching
+// several files is not: if moving them into place fails part way, the files
+// already moved keep the new state while the caller rolls memory back.
+func (r *FileRepository) persistLocked(names []string) error {
+	var written []string // temporary files, parallel to replaced
+	var replaced, removed []string
+	moved := 0
+	defer func() {
+		// Clean up whatever was not moved into place
+		for _, tmp := range written[moved:] {
+			os.Remove(tmp)
+		}
+	}()
+
+	for _, name := range names {
+		e, exists := r.entries[name]
+		if !exists {
+			removed = append(removed, r.path(name))
+			continue
+		}
+
+		e.mu.RLock()
+		plain, err := json.Marshal(fileRecord{
+			Config:    e.config,
+			Versions:  e.versions,
+			Tags:      e.tags,
+			Proposals: e.proposals,
+		})
+		e.mu.RUnlock()
+		if err != nil {
+			return fmt.Errorf("failed to serialize config: %w", err)
+		}
+
+		encoded, err := r.encode(name, plain)
+		if err != nil {
+			return err
+		}
+		tmp, err := r.writeTemp(encoded)
+		if err != nil {
+			return err
+		}
+		written = append(written, tmp)
+		replaced = append(replaced, r.path(name))
+	}
+
+	for i, path := range replaced {
+		if err := os.Rename(written[i], path); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", path, err)
+		}
+		moved++
+	}
+	for _, path := range removed {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	if len(replaced) == 0 && len(removed) == 0 {
+		return nil
+	}
+	return r.syncDir()
+}
+
+// syncDir flushes the data directory so renames and removals survive a crash
+func (r *FileRepository) syncDir() error {
+	dir, err := os.Open(r.dir)
+	if err != nil {
+		return fmt.Errorf("failed to open data directory: %w", err)
+	}
+	defer dir.Close()
+	if err := dir.Sync(); err != nil {
+		return fmt.Errorf("failed to sync data directory: %w", err)
+	}
+	return nil
+}
+
+// writeTemp writes contents to a new temporary file in the data directory,
+// unique to the call so concurrent writers never share one, and syncs it so
+// the contents are on disk before the file is renamed into place
+func (r *FileRepository) writeTemp(contents []byte) (string, error) {
+	file, err := os.CreateTemp(r.dir, "*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmp := file.Name()
+	_, err = file.Write(contents)
+	if err == nil {
+		err = file.Sync()
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	return tmp, nil
+}
+
+// path returns the file path for a configuration name
+func (r *FileRepository) path(name string) string {
+	return filepath.Join(r.dir, url.PathEscape(name)+fileExtension)
+}
+
+// nameFromFile returns the configuration name for a data file name
+func (r *FileRepository) nameFromFile(file string) (string, bool) {
+	if !strings.HasSuffix(file, fileExtension) {
+		return "", false
+	}
+	name, err := url.PathUnescape(strings.TrimSuffix(file, fileExtension))
+	if err != nil {
+		return "", false
+	}
+	return name, true
+}
+
+// Validate that FileRepository implements ConfigRepository
+var _ ConfigRepository = (*FileRepository)(nil)
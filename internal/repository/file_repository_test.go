@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"bytes"
+	"config-engine/internal/models"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileRepositoryEncryptedRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	key := []byte("correct horse battery staple")
+
+	repo, err := NewFileRepository(dir, key)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	repo.Create(context.Background(), &models.Config{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true, "api_key": "sk_live_secret"},
+	})
+	repo.Update(context.Background(), &models.Config{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 2000, "enabled": false, "api_key": "sk_live_secret"},
+	})
+
+	raw, err := os.ReadFile(filepath.Join(dir, "payment_config.json"))
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if raw[0] != formatEncryptedV2 {
+		t.Errorf("Expected encrypted format byte, got %d", raw[0])
+	}
+	if bytes.Contains(raw, []byte("sk_live_secret")) {
+		t.Error("Secret data should not be stored in plaintext")
+	}
+
+	// Simulate a restart with a new repository instance
+	restarted, err := NewFileRepository(dir, key)
+	if err != nil {
+		t.Fatalf("Failed to reopen repository: %v", err)
+	}
+
+	if !bytes.Equal(restarted.salt, repo.salt) {
+		t.Error("Expected the reopened repository to keep writing with the stored salt")
+	}
+
+	config, err := restarted.Get(context.Background(), "payment_config")
+	if err != nil {
+		t.Fatalf("Failed to get config after restart: %v", err)
+	}
+	if config.Version != 2 {
+		t.Errorf("Expected version 2, got %d", config.Version)
+	}
+	if config.Data["api_key"] != "sk_live_secret" {
+		t.Errorf("Expected decrypted api_key, got %v", config.Data["api_key"])
+	}
+
+	versions, _ := restarted.ListVersions(context.Background(), "payment_config")
+	if len(versions) != 2 {
+		t.Errorf("Expected 2 versions after restart, got %d", len(versions))
+	}
+
+	// Reading encrypted data without the key must fail
+	if _, err := NewFileRepository(dir, nil); err == nil {
+		t.Error("Expected error opening encrypted data without a key")
+	}
+	if _, err := NewFileRepository(dir, []byte("wrong key")); err == nil {
+		t.Error("Expected error opening encrypted data with the wrong key")
+	}
+
+	// A file moved to another configuration's name fails authentication
+	if err := os.Rename(filepath.Join(dir, "payment_config.json"), filepath.Join(dir, "other_config.json")); err != nil {
+		t.Fatalf("Failed to rename file: %v", err)
+	}
+	if _, err := NewFileRepository(dir, key); err == nil {
+		t.Error("Expected error opening a file stored under another name")
+	}
+}
+
+func TestFileRepositoryMigratesLegacyEncryption(t *testing.T) {
+	dir := t.TempDir()
+	key := []byte("correct horse battery staple")
+
+	// Write a file the way the V1 format did: SHA-256 key, no additional data
+	plain, _ := json.Marshal(fileRecord{
+		Config:   &models.Config{Name: "payment_config", Type: "payment_config", Version: 1, Data: map[string]interface{}{"max_limit": 1000}},
+		Versions: []models.ConfigVersion{{Version: 1, Data: map[string]interface{}{"max_limit": 1000}}},
+	})
+	sum := sha256.Sum256(key)
+	aead, err := newGCM(sum[:])
+	if err != nil {
+		t.Fatalf("Failed to create cipher: %v", err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	legacy := aead.Seal(append([]byte{formatEncryptedV1}, nonce...), nonce, plain, nil)
+	path := filepath.Join(dir, "payment_config.json")
+	if err := os.WriteFile(path, legacy, 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if _, err := NewFileRepository(dir, key); !errors.Is(err, errLegacyFile) {
+		t.Fatalf("Expected legacy files to be refused without migration, got %v", err)
+	}
+	if _, err := NewFileRepositoryWithMigration(dir, key); err != nil {
+		t.Fatalf("Failed to migrate repository: %v", err)
+	}
+	raw, _ := os.ReadFile(path)
+	if raw[0] != formatEncryptedV2 {
+		t.Errorf("Expected the migrated file in format %d, got %d", formatEncryptedV2, raw[0])
+	}
+
+	repo, err := NewFileRepository(dir, key)
+	if err != nil {
+		t.Fatalf("Failed to reopen migrated repository: %v", err)
+	}
+	config, err := repo.Get(context.Background(), "payment_config")
+	if err != nil || config.Data["max_limit"] != json.Number("1000") {
+		t.Errorf("Expected the migrated config, got %v (%v)", config, err)
+	}
+}
+
+func TestFileRepositoryPlaintext(t *testing.T) {
+	dir := t.TempDir()
+
+	repo, err := NewFileRepository(dir, nil)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	repo.Create(context.Background(), &models.Config{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+
+	raw, _ := os.ReadFile(filepath.Join(dir, "payment_config.json"))
+	if raw[0] != '{' {
+		t.Errorf("Expected plaintext JSON, got format byte %d", raw[0])
+	}
+
+	// With a key, plaintext files are refused unless explicitly migrated
+	if _, err := NewFileRepository(dir, []byte("new key")); !errors.Is(err, errPlaintextFile) {
+		t.Fatalf("Expected plaintext files to be refused with a key, got %v", err)
+	}
+	restarted, err := NewFileRepositoryWithMigration(dir, []byte("new key"))
+	if err != nil {
+		t.Fatalf("Failed to migrate repository: %v", err)
+	}
+	if !restarted.Exists(context.Background(), "payment_config") {
+		t.Error("Expected config to be loaded from plaintext file")
+	}
+	raw, _ = os.ReadFile(filepath.Join(dir, "payment_config.json"))
+	if raw[0] == '{' {
+		t.Error("Expected the migrated file to be encrypted")
+	}
+	if _, err := NewFileRepository(dir, []byte("new key")); err != nil {
+		t.Fatalf("Failed to reopen migrated repository: %v", err)
+	}
+
+	if err := restarted.Delete(context.Background(), "payment_config"); err != nil {
+		t.Fatalf("Failed to delete config: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "payment_config.json")); !os.IsNotExist(err) {
+		t.Error("Expected config file to be removed on delete")
+	}
+}
+
+func TestFileRepositoryPersistFailureRollsBack(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	repo, err := NewFileRepository(dir, nil)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	if err := repo.Create(ctx, &models.Config{Name: "payment_config", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1000}}); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	events, unsubscribe := repo.Subscribe()
+	defer unsubscribe()
+
+	// Without the data directory no file can be written
+	os.RemoveAll(dir)
+	if err := repo.Update(ctx, &models.Config{Name: "payment_config", Type: "payment_config", Data: map[string]interface{}{"max_limit": 2000}}); err == nil {
+		t.Fatal("Expected the update to fail when it cannot be persisted")
+	}
+
+	config, err := repo.Get(ctx, "payment_config")
+	if err != nil {
+		t.Fatalf("Failed to get config: %v", err)
+	}
+	if config.Version != 1 || config.Data["max_limit"] != 1000 {
+		t.Errorf("Expected the failed update to be rolled back, got version %d with %v", config.Version, config.Data)
+	}
+	select {
+	case event := <-events:
+		t.Errorf("Expected no event for a failed write, got %+v", event)
+	default:
+	}
+}
+
+func TestFileRepositoryTransactionPersistsTouchedConfigs(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	repo, err := NewFileRepository(dir, nil)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	for _, name := range []string{"touched", "untouched"} {
+		if err := repo.Create(ctx, &models.Config{Name: name, Type: "payment_config", Data: map[string]interface{}{"max_limit": 1000}}); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	// A file the transaction does not touch must not be rewritten
+	untouched := filepath.Join(dir, "untouched.json")
+	if err := os.WriteFile(untouched, []byte("sentinel"), 0o600); err != nil {
+		t.Fatalf("Failed to overwrite file: %v", err)
+	}
+
+	err = repo.WithTransaction(ctx, func(tx ConfigRepository) error {
+		return tx.Update(ctx, &models.Config{Name: "touched", Type: "payment_config", Data: map[string]interface{}{"max_limit": 2000}})
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+
+	if raw, _ := os.ReadFile(untouched); string(raw) != "sentinel" {
+		t.Errorf("Expected the untouched file to be left alone, got %s", raw)
+	}
+	raw, _ := os.ReadFile(filepath.Join(dir, "touched.json"))
+	if !bytes.Contains(raw, []byte(`"max_limit":2000`)) {
+		t.Errorf("Expected the touched file to hold the update, got %s", raw)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != fileExtension {
+			t.Errorf("Expected no temporary files to remain, found %s", entry.Name())
+		}
+	}
+}
+
+func TestFileRepositoryMaintenanceFailureRollsBack(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	repo, err := NewFileRepository(dir, nil)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	if err := repo.Create(ctx, &models.Config{Name: "payment_config", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1000}}); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	for _, limit := range []int{2000, 3000} {
+		if err := repo.Update(ctx, &models.Config{Name: "payment_config", Type: "payment_config", Data: map[string]interface{}{"max_limit": limit}}); err != nil {
+			t.Fatalf("Failed to update config: %v", err)
+		}
+	}
+	snapshot, err := repo.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Failed to snapshot: %v", err)
+	}
+	if err := repo.Delete(ctx, "payment_config"); err != nil {
+		t.Fatalf("Failed to delete config: %v", err)
+	}
+	if _, err := repo.Restore(ctx, snapshot); err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+	if err := repo.Create(ctx, &models.Config{Name: "extra", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}}); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	// Without the data directory no file can be written
+	os.RemoveAll(dir)
+
+	if _, err := repo.Compact(ctx, 1); err == nil {
+		t.Error("Expected compaction to fail when it cannot be persisted")
+	}
+	if versions, _ := repo.ListVersions(ctx, "payment_config"); len(versions) != 3 {
+		t.Errorf("Expected the failed compaction to keep 3 versions, got %d", len(versions))
+	}
+	if _, err := repo.CompactConfig(ctx, "payment_config", 1); err == nil {
+		t.Error("Expected config compaction to fail when it cannot be persisted")
+	}
+	if versions, _ := repo.ListVersions(ctx, "payment_config"); len(versions) != 3 {
+		t.Errorf("Expected the failed config compaction to keep 3 versions, got %d", len(versions))
+	}
+
+	if _, err := repo.Restore(ctx, snapshot); err == nil {
+		t.Error("Expected the restore to fail when it cannot be persisted")
+	}
+	if !repo.Exists(ctx, "extra") {
+		t.Error("Expected the failed restore to be rolled back")
+	}
+	if err := repo.Clear(); err == nil {
+		t.Error("Expected clearing to fail when it cannot be persisted")
+	}
+	if !repo.Exists(ctx, "payment_config") {
+		t.Error("Expected the failed clear to be rolled back")
+	}
+}
+
+func TestFileRepositoryPersistsTypePolicies(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	key := []byte("policy key")
+
+	repo, err := NewFileRepository(dir, key)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	if err := repo.SetTypePolicy(ctx, "payment_config", models.TypePolicy{DefaultTTL: "1h"}); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+	if err := repo.SetTypePolicy(ctx, "feature_flag", models.TypePolicy{VersionLimit: 5}); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+	if err := repo.SetTypePolicy(ctx, "feature_flag", models.TypePolicy{}); err != nil {
+		t.Fatalf("Failed to remove policy: %v", err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, policiesFile))
+	if err != nil {
+		t.Fatalf("Failed to read policies file: %v", err)
+	}
+	if raw[0] != formatEncryptedV2 {
+		t.Errorf("Expected encrypted format byte, got %d", raw[0])
+	}
+
+	restarted, err := NewFileRepository(dir, key)
+	if err != nil {
+		t.Fatalf("Failed to reopen repository: %v", err)
+	}
+	policies, err := restarted.TypePolicies(ctx)
+	if err != nil {
+		t.Fatalf("Failed to read policies: %v", err)
+	}
+	if len(policies) != 1 || policies["payment_config"].DefaultTTL != "1h" {
+		t.Errorf("Expected only the payment_config policy, got %+v", policies)
+	}
+	if configs, _, _ := restarted.List(ctx, models.ConfigFilter{}); len(configs) != 0 {
+		t.Errorf("Expected the policies file not to load as a config, got %d configs", len(configs))
+	}
+
+	// Without the data directory the policy cannot be stored
+	os.RemoveAll(dir)
+	if err := restarted.SetTypePolicy(ctx, "payment_config", models.TypePolicy{}); err == nil {
+		t.Error("Expected setting a policy to fail when it cannot be persisted")
+	}
+	if policies, _ := restarted.TypePolicies(ctx); len(policies) != 1 {
+		t.Errorf("Expected the failed change to keep the policy, got %+v", policies)
+	}
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"config-engine/internal/models"
+)
+
+// PolicyStore is implemented by repositories that keep the policies of
+// config types, so a persistent repository keeps them across restarts
+type PolicyStore interface {
+	// SetTypePolicy stores the policy of configType; the zero policy removes it
+	SetTypePolicy(ctx context.Context, configType string, policy models.TypePolicy) error
+	// TypePolicies returns the stored policies by config type
+	TypePolicies(ctx context.Context) (map[string]models.TypePolicy, error)
+}
+
+// policiesFile is the file the file backend keeps type policies in; its
+// extension keeps it apart from configuration files
+const policiesFile = "_policies.dat"
+
+// SetTypePolicy stores the policy of configType; the zero policy removes it
+func (r *InMemoryRepository) SetTypePolicy(ctx context.Context, configType string, policy models.TypePolicy) error {
+	return r.setTypePolicy(ctx, configType, policy, nil)
+}
+
+// setTypePolicy stores a policy like SetTypePolicy. commit is called with
+// the resulting policies before they take effect; if it fails, nothing changes.
+func (r *InMemoryRepository) setTypePolicy(ctx context.Context, configType string, policy models.TypePolicy, commit func(policies map[string]models.TypePolicy) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.policiesMu.Lock()
+	defer r.policiesMu.Unlock()
+
+	policies := make(map[string]models.TypePolicy, len(r.policies)+1)
+	for t, p := range r.policies {
+		policies[t] = p
+	}
+	if policy == (models.TypePolicy{}) {
+		delete(policies, configType)
+	} else {
+		policies[configType] = policy
+	}
+
+	if commit != nil {
+		if err := commit(policies); err != nil {
+			return err
+		}
+	}
+	r.policies = policies
+	return nil
+}
+
+// TypePolicies returns the stored policies by config type
+func (r *InMemoryRepository) TypePolicies(ctx context.Context) (map[string]models.TypePolicy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.policiesMu.Lock()
+	defer r.policiesMu.Unlock()
+
+	policies := make(map[string]models.TypePolicy, len(r.policies))
+	for t, p := range r.policies {
+		policies[t] = p
+	}
+	return policies, nil
+}
+
+// SetTypePolicy stores the policy of configType and rewrites the policies file
+func (r *FileRepository) SetTypePolicy(ctx context.Context, configType string, policy models.TypePolicy) error {
+	return r.InMemoryRepository.setTypePolicy(ctx, configType, policy, r.persistPolicies)
+}
+
+// persistPolicies replaces the policies file, encrypted like configuration files
+func (r *FileRepository) persistPolicies(policies map[string]models.TypePolicy) error {
+	plain, err := json.Marshal(policies)
+	if err != nil {
+		return fmt.Errorf("failed to serialize policies: %w", err)
+	}
+	encoded, err := r.encode(policiesFile, plain)
+	if err != nil {
+		return err
+	}
+	tmp, err := r.writeTemp(encoded)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(r.dir, policiesFile)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace %s: %w", policiesFile, err)
+	}
+	return r.syncDir()
+}
+
+// loadPolicies reads the policies file, if there is one, reporting whether
+// it is outdated and must be rewritten; see decode
+func (r *FileRepository) loadPolicies(migrate bool) (bool, error) {
+	raw, err := os.ReadFile(filepath.Join(r.dir, policiesFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", policiesFile, err)
+	}
+
+	plain, outdated, err := r.decode(policiesFile, raw, migrate)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", policiesFile, err)
+	}
+	var policies map[string]models.TypePolicy
+	if err := json.Unmarshal(plain, &policies); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", policiesFile, err)
+	}
+	r.policies = policies
+	return outdated, nil
+}
+
+// SetTypePolicy forwards to the inner repository
+func (r *CachingRepository) SetTypePolicy(ctx context.Context, configType string, policy models.TypePolicy) error {
+	store, ok := r.ConfigRepository.(PolicyStore)
+	if !ok {
+		return errors.New("repository does not store type policies")
+	}
+	return store.SetTypePolicy(ctx, configType, policy)
+}
+
+// TypePolicies forwards to the inner repository
+func (r *CachingRepository) TypePolicies(ctx context.Context) (map[string]models.TypePolicy, error) {
+	store, ok := r.ConfigRepository.(PolicyStore)
+	if !ok {
+		return nil, errors.New("repository does not store type policies")
+	}
+	return store.TypePolicies(ctx)
+}
+
+// Validate that the repositories implement PolicyStore
+var (
+	_ PolicyStore = (*InMemoryRepository)(nil)
+	_ PolicyStore = (*FileRepository)(nil)
+	_ PolicyStore = (*CachingRepository)(nil)
+)
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"config-engine/internal/models"
+)
+
+// SaveProposal stores a pending proposal for an existing configuration
+func (r *InMemoryRepository) SaveProposal(ctx context.Context, proposal *models.Proposal) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.saveProposal(proposal)
+}
+
+// saveProposal stores a proposal; callers must hold the lock
+func (r *InMemoryRepository) saveProposal(proposal *models.Proposal) error {
+	e, err := r.entry(proposal.Name)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	stored := *proposal
+	stored.Data = copyData(proposal.Data)
+	stored.DependsOn = copyStrings(proposal.DependsOn)
+
+	// Copy on write so transaction snapshots keep the previous proposal set
+	proposals := make(map[string]models.Proposal, len(e.proposals)+1)
+	for id, p := range e.proposals {
+		proposals[id] = p
+	}
+	proposals[proposal.ID] = stored
+	e.proposals = proposals
+	return nil
+}
+
+// GetProposal retrieves a pending proposal
+func (r *InMemoryRepository) GetProposal(ctx context.Context, name, id string) (*models.Proposal, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.getProposal(name, id)
+}
+
+// getProposal returns a copy of a proposal; callers must hold the lock
+func (r *InMemoryRepository) getProposal(name, id string) (*models.Proposal, error) {
+	e, err := r.entry(name)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	proposal, exists := e.proposals[id]
+	if !exists {
+		return nil, &models.ProposalNotFoundError{Name: name, ID: id}
+	}
+
+	proposal.Data = copyData(proposal.Data)
+	proposal.DependsOn = copyStrings(proposal.DependsOn)
+	return &proposal, nil
+}
+
+// ListProposals lists the pending proposals of a configuration, oldest first
+func (r *InMemoryRepository) ListProposals(ctx context.Context, name string) ([]models.Proposal, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.listProposals(ctx, name)
+}
+
+// listProposals returns copies of all proposals; callers must hold the lock
+func (r *InMemoryRepository) listProposals(ctx context.Context, name string) ([]models.Proposal, error) {
+	e, err := r.entry(name)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	proposals := make([]models.Proposal, 0, len(e.proposals))
+	for _, p := range e.proposals {
+		if err := ctx.Err(); err != nil {
+			e.mu.RUnlock()
+			return nil, err
+		}
+		p.Data = copyData(p.Data)
+		p.DependsOn = copyStrings(p.DependsOn)
+		proposals = append(proposals, p)
+	}
+	e.mu.RUnlock()
+
+	sort.Slice(proposals, func(i, j int) bool {
+		if proposals[i].CreatedAt.Equal(proposals[j].CreatedAt) {
+			return proposals[i].ID < proposals[j].ID
+		}
+		return proposals[i].CreatedAt.Before(proposals[j].CreatedAt)
+	})
+	return proposals, nil
+}
+
+// DeleteProposal removes a pending proposal
+func (r *InMemoryRepository) DeleteProposal(ctx context.Context, name, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.deleteProposal(name, id)
+}
+
+// deleteProposal removes a proposal; callers must hold the lock
+func (r *InMemoryRepository) deleteProposal(name, id string) error {
+	e, err := r.entry(name)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, exists := e.proposals[id]; !exists {
+		return &models.ProposalNotFoundError{Name: name, ID: id}
+	}
+
+	proposals := make(map[string]models.Proposal, len(e.proposals))
+	for pid, p := range e.proposals {
+		if pid != id {
+			proposals[pid] = p
+		}
+	}
+	if len(proposals) == 0 {
+		proposals = nil
+	}
+	e.proposals = proposals
+	return nil
+}
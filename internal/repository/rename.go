@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"config-engine/internal/models"
+)
+
+// Rename moves a configuration with its version history, tags and pending
+// proposals from oldName to newName in one step, failing with
+// ConfigExistsError if newName is taken. Configurations that depend on
+// oldName are pointed at newName. Renaming is not a write: no version is
+// created and the latest write can no longer be reverted.
+func (r *InMemoryRepository) Rename(ctx context.Context, oldName, newName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events, err := r.rename(oldName, newName)
+	if err != nil {
+		return err
+	}
+	r.publish(events...)
+	return nil
+}
+
+// rename moves a configuration and rewrites the references to it, returning
+// the change events to publish; callers must hold the write lock
+func (r *InMemoryRepository) rename(oldName, newName string) ([]models.ChangeEvent, error) {
+	e, err := r.entry(oldName)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := r.entries[newName]; exists {
+		return nil, &models.ConfigExistsError{Name: newName}
+	}
+	dependents, err := r.listDependents(oldName)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	// Copy on write so transaction snapshots keep the previous state
+	renamed := *e.config
+	renamed.Name = newName
+	r.unindex(oldName, e.config.DependsOn)
+	e.config = &renamed
+	e.proposals = renameProposals(e.proposals, oldName, newName, true)
+	e.undo = nil
+	e.mu.Unlock()
+
+	delete(r.entries, oldName)
+	r.entries[newName] = e
+	r.index(newName, renamed.DependsOn)
+
+	events := []models.ChangeEvent{
+		newChangeEvent(models.ChangeDeleted, oldName, 0),
+		newChangeEvent(models.ChangeCreated, newName, renamed.Version),
+	}
+	for _, dependent := range dependents {
+		if dependent == oldName {
+			continue
+		}
+		d := r.entries[dependent]
+		d.mu.Lock()
+		updated := *d.config
+		updated.DependsOn = replaceName(d.config.DependsOn, oldName, newName)
+		r.unindex(dependent, d.config.DependsOn)
+		d.config = &updated
+		d.proposals = renameProposals(d.proposals, oldName, newName, false)
+		d.undo = nil
+		r.index(dependent, updated.DependsOn)
+		d.mu.Unlock()
+		events = append(events, newChangeEvent(models.ChangeUpdated, dependent, updated.Version))
+	}
+	return events, nil
+}
+
+// renameProposals returns a copy of proposals whose references to oldName
+// point at newName; owned marks the proposals of the renamed configuration
+func renameProposals(proposals map[string]models.Proposal, oldName, newName string, owned bool) map[string]models.Proposal {
+	if len(proposals) == 0 {
+		return proposals
+	}
+
+	renamed := make(map[string]models.Proposal, len(proposals))
+	for id, proposal := range proposals {
+		if owned {
+			proposal.Name = newName
+		}
+		proposal.DependsOn = replaceName(proposal.DependsOn, oldName, newName)
+		renamed[id] = proposal
+	}
+	return renamed
+}
+
+// replaceName returns a copy of names with oldName replaced by newName
+func replaceName(names []string, oldName, newName string) []string {
+	replaced := slices.Clone(names)
+	for i, name := range replaced {
+		if name == oldName {
+			replaced[i] = newName
+		}
+	}
+	return replaced
+}
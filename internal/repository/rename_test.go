@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"config-engine/internal/models"
+)
+
+func TestRename(t *testing.T) {
+	repo := NewInMemoryRepository()
+	ctx := context.Background()
+
+	repo.Create(ctx, &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": 100}})
+	repo.Update(ctx, &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": 200}})
+	repo.SetTag(ctx, "payments", "stable", 1)
+	repo.Create(ctx, &models.Config{Name: "routing", Type: "routing_config", Data: map[string]interface{}{}, DependsOn: []string{"payments"}})
+	repo.Create(ctx, &models.Config{Name: "billing", Type: "billing_config", Data: map[string]interface{}{}})
+
+	var existsErr *models.ConfigExistsError
+	if err := repo.Rename(ctx, "payments", "billing"); !errors.As(err, &existsErr) {
+		t.Fatalf("Expected ConfigExistsError for a taken name, got %v", err)
+	}
+
+	if err := repo.Rename(ctx, "payments", "payments_eu"); err != nil {
+		t.Fatalf("Failed to rename config: %v", err)
+	}
+	if repo.Exists(ctx, "payments") {
+		t.Error("Expected the old name to be gone")
+	}
+
+	config, err := repo.Get(ctx, "payments_eu")
+	if err != nil {
+		t.Fatalf("Failed to get renamed config: %v", err)
+	}
+	if config.Name != "payments_eu" || config.Version != 2 || config.Data["max_limit"] != 200 {
+		t.Errorf("Expected version 2 under the new name, got %+v", config)
+	}
+	versions, _ := repo.ListVersions(ctx, "payments_eu")
+	if len(versions) != 2 || versions[0].Data["max_limit"] != 100 {
+		t.Errorf("Expected the full history to move, got %+v", versions)
+	}
+	if tagged, err := repo.GetTag(ctx, "payments_eu", "stable"); err != nil || tagged != 1 {
+		t.Errorf("Expected the stable tag to move, got %d (%v)", tagged, err)
+	}
+
+	// Dependents are pointed at the new name
+	routing, _ := repo.Get(ctx, "routing")
+	if len(routing.DependsOn) != 1 || routing.DependsOn[0] != "payments_eu" {
+		t.Errorf("Expected routing to depend on payments_eu, got %v", routing.DependsOn)
+	}
+	if dependents, _ := repo.ListDependents(ctx, "payments_eu"); len(dependents) != 1 || dependents[0] != "routing" {
+		t.Errorf("Expected [routing], got %v", dependents)
+	}
+
+	// A renamed config cannot have its last write reverted
+	var notRevertible *models.WriteNotRevertibleError
+	if err := repo.RevertWrite(ctx, "payments_eu", 2); !errors.As(err, &notRevertible) {
+		t.Errorf("Expected WriteNotRevertibleError, got %v", err)
+	}
+}
+
+func TestRenameTransactionRollback(t *testing.T) {
+	repo := NewInMemoryRepository()
+	ctx := context.Background()
+
+	repo.Create(ctx, &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{}})
+	repo.Create(ctx, &models.Config{Name: "routing", Type: "routing_config", Data: map[string]interface{}{}, DependsOn: []string{"payments"}})
+
+	failure := errors.New("abort")
+	err := repo.WithTransaction(ctx, func(tx ConfigRepository) error {
+		if err := tx.Rename(ctx, "payments", "payments_eu"); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Expected the transaction to fail, got %v", err)
+	}
+
+	if !repo.Exists(ctx, "payments") || repo.Exists(ctx, "payments_eu") {
+		t.Error("Expected the rename to be rolled back")
+	}
+	routing, _ := repo.Get(ctx, "routing")
+	if len(routing.DependsOn) != 1 || routing.DependsOn[0] != "payments" {
+		t.Errorf("Expected routing to depend on payments again, got %v", routing.DependsOn)
+	}
+	if dependents, _ := repo.ListDependents(ctx, "payments"); len(dependents) != 1 {
+		t.Errorf("Expected the dependents index to be restored, got %v", dependents)
+	}
+}
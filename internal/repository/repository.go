@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -9,139 +11,513 @@ import (
 
 // ConfigRepository defines the interface for configuration storage
 type ConfigRepository interface {
-	Create(config *models.Config) error
-	Get(name string) (*models.Config, error)
-	Update(config *models.Config) error
-	GetVersion(name string, version int) (*models.ConfigVersion, error)
-	ListVersions(name string) ([]models.ConfigVersion, error)
-	Exists(name string) bool
+	Create(ctx context.Context, config *models.Config) error
+	Get(ctx context.Context, name string) (*models.Config, error)
+	GetMany(ctx context.Context, names []string) (map[string]*models.Config, error)
+	Update(ctx context.Context, config *models.Config) error
+	Rollback(ctx context.Context, config *models.Config, version int) error
+	GetVersion(ctx context.Context, name string, version int) (*models.ConfigVersion, error)
+	VersionAt(ctx context.Context, name string, at time.Time) (*models.ConfigVersion, error)
+	ListVersions(ctx context.Context, name string) ([]models.ConfigVersion, error)
+	SquashVersions(ctx context.Context, name string, from, to int) (int, error)
+	RevertWrite(ctx context.Context, name string, version int) error
+	Exists(ctx context.Context, name string) bool
+	ListNames(ctx context.Context) ([]string, error)
+	List(ctx context.Context, filter models.ConfigFilter) ([]*models.Config, int, error)
+	Delete(ctx context.Context, name string) error
+	Rename(ctx context.Context, oldName, newName string) error
+	ListDependents(ctx context.Context, name string) ([]string, error)
+	SetTag(ctx context.Context, name, tag string, version int) error
+	SetLocked(ctx context.Context, name string, locked bool) error
+	SetDeprecation(ctx context.Context, name string, deprecated bool, message string) error
+	GetTag(ctx context.Context, name, tag string) (int, error)
+	SaveProposal(ctx context.Context, proposal *models.Proposal) error
+	GetProposal(ctx context.Context, name, id string) (*models.Proposal, error)
+	ListProposals(ctx context.Context, name string) ([]models.Proposal, error)
+	DeleteProposal(ctx context.Context, name, id string) error
+	Ping(ctx context.Context) error
+	WithTransaction(ctx context.Context, fn func(tx ConfigRepository) error) error
 }
 
-// InMemoryRepository implements ConfigRepository using in-memory storage
+// InMemoryRepository implements ConfigRepository using in-memory storage.
+//
+// Locking is two-level. mu guards the set of configurations and is only held
+// for writing when configurations are added or removed (and by transactions
+// and compaction, which span configurations). Every other operation holds mu
+// for reading and locks just the entry it touches, so writes to different
+// configurations run in parallel. Locks are taken in the order mu, entry,
+// indexMu.
 type InMemoryRepository struct {
+	mu      sync.RWMutex
+	entries map[string]*configEntry // key: config name
+	// indexMu guards dependents, which is shared by all entries
+	indexMu sync.Mutex
+	// dependents is a reverse index of DependsOn: key: referenced config, value: set of dependent configs
+	dependents map[string]map[string]struct{}
+	events     eventBus
+	strategy   VersionStrategy
+	// maxConfigs caps the number of stored configurations (0 means unlimited)
+	maxConfigs int
+	// policiesMu guards policies, the stored config type policies
+	policiesMu sync.Mutex
+	policies   map[string]models.TypePolicy
+}
+
+// configEntry holds a configuration and its history under its own lock.
+// The head is config.Version; with the branching strategy it may be older
+// than the latest version created (see latestVersion).
+type configEntry struct {
 	mu       sync.RWMutex
-	configs  map[string]*models.Config
-	versions map[string][]models.ConfigVersion // key: config name, value: list of versions
+	config   *models.Config
+	versions []models.ConfigVersion
+	tags     map[string]int // tag -> version
+	// proposals holds pending changes separately from live versions: id -> proposal
+	proposals map[string]models.Proposal
+	// reads tracks Get and GetVersion calls without taking a write lock
+	reads readCounter
+	// undo records how to revert the latest write; nil when it cannot be reverted
+	undo *undoState
 }
 
-// NewInMemoryRepository creates a new in-memory repository
+// NewInMemoryRepository creates a new in-memory repository using the append version strategy
 func NewInMemoryRepository() *InMemoryRepository {
+	return NewInMemoryRepositoryWithStrategy(VersionStrategyAppend)
+}
+
+// NewInMemoryRepositoryWithStrategy creates a new in-memory repository that
+// numbers rollback versions according to strategy
+func NewInMemoryRepositoryWithStrategy(strategy VersionStrategy) *InMemoryRepository {
 	return &InMemoryRepository{
-		configs:  make(map[string]*models.Config),
-		versions: make(map[string][]models.ConfigVersion),
+		entries:    make(map[string]*configEntry),
+		dependents: make(map[string]map[string]struct{}),
+		strategy:   strategy,
+	}
+}
+
+// SetMaxConfigs caps the number of configurations the repository stores;
+// creates beyond the cap fail with CapacityExceededError. 0 removes the cap.
+func (r *InMemoryRepository) SetMaxConfigs(max int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxConfigs = max
+}
+
+// CapacityReporter is implemented by repositories that cap the number of
+// configurations they store
+type CapacityReporter interface {
+	// Capacity returns the number of stored configurations and the cap (0 is unlimited)
+	Capacity(ctx context.Context) (used, limit int, err error)
+}
+
+// Capacity returns the number of stored configurations and the cap set by SetMaxConfigs
+func (r *InMemoryRepository) Capacity(ctx context.Context) (int, int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
 	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.entries), r.maxConfigs, nil
+}
+
+// entry looks up the entry of a configuration; callers must hold mu
+func (r *InMemoryRepository) entry(name string) (*configEntry, error) {
+	e, exists := r.entries[name]
+	if !exists {
+		return nil, &models.ConfigNotFoundError{Name: name}
+	}
+	return e, nil
 }
 
 // Create creates a new configuration
-func (r *InMemoryRepository) Create(config *models.Config) error {
+func (r *InMemoryRepository) Create(ctx context.Context, config *models.Config) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.configs[config.Name]; exists {
+	if err := r.create(config); err != nil {
+		return err
+	}
+	r.publish(newChangeEvent(models.ChangeCreated, config.Name, config.Version))
+	return nil
+}
+
+// create stores a new configuration; callers must hold the write lock
+func (r *InMemoryRepository) create(config *models.Config) error {
+	if _, exists := r.entries[config.Name]; exists {
 		return &models.ConfigExistsError{Name: config.Name}
 	}
+	// Counted under the write lock so concurrent creates cannot overshoot the cap
+	if r.maxConfigs > 0 && len(r.entries) >= r.maxConfigs {
+		return &models.CapacityExceededError{Limit: r.maxConfigs}
+	}
 
 	// Set initial version and timestamps
 	config.Version = 1
 	config.CreatedAt = time.Now()
 	config.UpdatedAt = config.CreatedAt
+	config.Checksum = models.DataChecksum(config.Data)
 
-	// Store the config
-	r.configs[config.Name] = config
-
-	// Store the version
+	// Store the config with its first version
 	version := models.ConfigVersion{
-		Version:   config.Version,
-		Data:      copyData(config.Data),
-		CreatedAt: config.CreatedAt,
+		Version:    config.Version,
+		Data:       copyData(config.Data),
+		CreatedAt:  config.CreatedAt,
+		CreatedBy:  config.UpdatedBy,
+		Origin:     config.Origin,
+		Checksum:   config.Checksum,
+		Message:    config.Message,
+		SchemaHash: config.SchemaHash,
 	}
-	r.versions[config.Name] = []models.ConfigVersion{version}
+	r.entries[config.Name] = &configEntry{
+		config:   config,
+		versions: []models.ConfigVersion{version},
+		undo:     &undoState{appended: true},
+	}
+	r.index(config.Name, config.DependsOn)
 
 	return nil
 }
 
 // Get retrieves the latest version of a configuration
-func (r *InMemoryRepository) Get(name string) (*models.Config, error) {
+func (r *InMemoryRepository) Get(ctx context.Context, name string) (*models.Config, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	config, exists := r.configs[name]
-	if !exists {
-		return nil, &models.ConfigNotFoundError{Name: name}
+	e, err := r.entry(name)
+	if err != nil {
+		return nil, err
 	}
+	e.reads.record()
+	return e.copyConfig(), nil
+}
 
-	// Return a copy to prevent external modifications
-	configCopy := *config
-	configCopy.Data = copyData(config.Data)
-	return &configCopy, nil
+// get returns a copy of the latest configuration; callers must hold the lock
+func (r *InMemoryRepository) get(name string) (*models.Config, error) {
+	e, err := r.entry(name)
+	if err != nil {
+		return nil, err
+	}
+	return e.copyConfig(), nil
 }
 
-// Update updates an existing configuration
-func (r *InMemoryRepository) Update(config *models.Config) error {
+// copyConfig returns a copy of the latest configuration to prevent external modifications
+func (e *configEntry) copyConfig() *models.Config {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	configCopy := *e.config
+	configCopy.Data = copyData(e.config.Data)
+	configCopy.DependsOn = copyStrings(e.config.DependsOn)
+	configCopy.Rollout = copyRollout(e.config.Rollout)
+	return &configCopy
+}
+
+// GetMany returns copies of the latest named configurations, keyed by name.
+// Missing names are omitted. The write lock excludes concurrent updates so
+// the configurations form a consistent snapshot.
+func (r *InMemoryRepository) GetMany(ctx context.Context, names []string) (map[string]*models.Config, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	existing, exists := r.configs[config.Name]
-	if !exists {
-		return &models.ConfigNotFoundError{Name: config.Name}
+	return r.getMany(ctx, names)
+}
+
+// getMany returns copies of the named configurations; callers must hold the lock
+func (r *InMemoryRepository) getMany(ctx context.Context, names []string) (map[string]*models.Config, error) {
+	configs := make(map[string]*models.Config, len(names))
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if _, exists := r.entries[name]; !exists {
+			continue
+		}
+		config, err := r.get(name)
+		if err != nil {
+			return nil, err
+		}
+		configs[name] = config
+	}
+	return configs, nil
+}
+
+// Update updates an existing configuration
+func (r *InMemoryRepository) Update(ctx context.Context, config *models.Config) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	// Increment version
-	config.Version = existing.Version + 1
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, err := r.entry(config.Name)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.update(r, config)
+	// Publish under the entry lock so events of one configuration stay ordered
+	r.publish(newChangeEvent(models.ChangeUpdated, config.Name, config.Version))
+	return nil
+}
+
+// update stores a new version of a configuration; callers must hold the lock
+func (r *InMemoryRepository) update(config *models.Config) error {
+	e, err := r.entry(config.Name)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.update(r, config)
+	return nil
+}
+
+// update appends a new version to the entry; callers must hold its write lock
+func (e *configEntry) update(r *InMemoryRepository, config *models.Config) {
+	existing := e.config
+
+	// Number after the latest version, which may be ahead of the head after a branching rollback
+	config.Version = e.latestVersion() + 1
 	config.CreatedAt = existing.CreatedAt
 	config.UpdatedAt = time.Now()
+	// Deprecation belongs to the configuration, not to a version
+	config.Deprecated = existing.Deprecated
+	config.DeprecationMessage = existing.DeprecationMessage
+	config.Checksum = models.DataChecksum(config.Data)
 
 	// Update the config
-	r.configs[config.Name] = config
+	r.unindex(config.Name, existing.DependsOn)
+	e.config = config
+	r.index(config.Name, config.DependsOn)
 
 	// Store the new version
 	version := models.ConfigVersion{
-		Version:   config.Version,
-		Data:      copyData(config.Data),
-		CreatedAt: config.UpdatedAt,
+		Version:    config.Version,
+		Data:       copyData(config.Data),
+		CreatedAt:  config.UpdatedAt,
+		CreatedBy:  config.UpdatedBy,
+		Origin:     config.Origin,
+		Checksum:   config.Checksum,
+		Message:    config.Message,
+		SchemaHash: config.SchemaHash,
 	}
-	r.versions[config.Name] = append(r.versions[config.Name], version)
+	e.versions = append(e.versions, version)
+	e.undo = &undoState{config: existing, appended: true}
+}
 
+// latestVersion returns the highest version number created; callers must hold its lock.
+// History is sorted and compaction and squashing always keep the newest version.
+func (e *configEntry) latestVersion() int {
+	if len(e.versions) == 0 {
+		return e.config.Version
+	}
+	return e.versions[len(e.versions)-1].Version
+}
+
+// Rollback makes a previous version the head. With the append strategy it
+// stores config, which carries the target's data, as a new version; with the
+// branching strategy it moves the head to the target version instead.
+func (r *InMemoryRepository) Rollback(ctx context.Context, config *models.Config, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, err := r.entry(config.Name)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.rollback(r, config, version); err != nil {
+		return err
+	}
+	r.publish(newChangeEvent(models.ChangeUpdated, config.Name, config.Version))
+	return nil
+}
+
+// rollback applies the version strategy to a rollback; callers must hold its write lock
+func (e *configEntry) rollback(r *InMemoryRepository, config *models.Config, version int) error {
+	i, found := findVersion(e.versions, version)
+	if !found {
+		return &models.VersionNotFoundError{Name: config.Name, Version: version}
+	}
+
+	if r.strategy != VersionStrategyBranching {
+		e.update(r, config)
+		return nil
+	}
+
+	existing := e.config
+	config.Version = version
+	config.Data = copyData(e.versions[i].Data)
+	config.Checksum = e.versions[i].Checksum
+	// No version is created, so the head keeps the target version's message
+	config.Message = e.versions[i].Message
+	config.SchemaHash = e.versions[i].SchemaHash
+	config.CreatedAt = existing.CreatedAt
+	config.UpdatedAt = time.Now()
+	config.Deprecated = existing.Deprecated
+	config.DeprecationMessage = existing.DeprecationMessage
+
+	r.unindex(config.Name, existing.DependsOn)
+	e.config = config
+	r.index(config.Name, config.DependsOn)
+	e.undo = &undoState{config: existing}
 	return nil
 }
 
 // GetVersion retrieves a specific version of a configuration
-func (r *InMemoryRepository) GetVersion(name string, version int) (*models.ConfigVersion, error) {
+func (r *InMemoryRepository) GetVersion(ctx context.Context, name string, version int) (*models.ConfigVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	versions, exists := r.versions[name]
-	if !exists {
-		return nil, &models.ConfigNotFoundError{Name: name}
+	e, err := r.entry(name)
+	if err != nil {
+		return nil, err
+	}
+	configVersion, err := e.copyVersion(name, version)
+	if err == nil {
+		e.reads.record()
+	}
+	return configVersion, err
+}
+
+// getVersion returns a copy of a specific version; callers must hold the lock
+func (r *InMemoryRepository) getVersion(name string, version int) (*models.ConfigVersion, error) {
+	e, err := r.entry(name)
+	if err != nil {
+		return nil, err
 	}
+	return e.copyVersion(name, version)
+}
 
-	if version < 1 || version > len(versions) {
+// copyVersion returns a copy of a specific version of the configuration name
+func (e *configEntry) copyVersion(name string, version int) (*models.ConfigVersion, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	i, found := findVersion(e.versions, version)
+	if !found {
 		return nil, &models.VersionNotFoundError{Name: name, Version: version}
 	}
 
-	// Versions are 1-indexed, array is 0-indexed
-	versionCopy := versions[version-1]
+	versionCopy := e.versions[i]
+	versionCopy.Data = copyData(versionCopy.Data)
+	return &versionCopy, nil
+}
+
+// VersionAt returns the latest version created at or before at, which is the
+// version that was active then unless the branching strategy moved the head
+// back in the meantime. A config created after at has no such version.
+func (r *InMemoryRepository) VersionAt(ctx context.Context, name string, at time.Time) (*models.ConfigVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.versionAt(name, at)
+}
+
+// versionAt returns a copy of the version active at a time; callers must hold the lock
+func (r *InMemoryRepository) versionAt(name string, at time.Time) (*models.ConfigVersion, error) {
+	e, err := r.entry(name)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	// Versions are appended in creation order, so their times are sorted too
+	i := sort.Search(len(e.versions), func(i int) bool {
+		return e.versions[i].CreatedAt.After(at)
+	})
+	if i == 0 {
+		compacted := len(e.versions) > 0 && e.versions[0].Version > 1
+		return nil, &models.NoVersionAtError{Name: name, At: at, Compacted: compacted}
+	}
+
+	versionCopy := e.versions[i-1]
 	versionCopy.Data = copyData(versionCopy.Data)
 	return &versionCopy, nil
 }
 
+// findVersion locates a version in history sorted by version number.
+// History may have gaps once old versions are compacted away.
+func findVersion(versions []models.ConfigVersion, version int) (int, bool) {
+	i := sort.Search(len(versions), func(i int) bool {
+		return versions[i].Version >= version
+	})
+	return i, i < len(versions) && versions[i].Version == version
+}
+
 // ListVersions lists all versions of a configuration
-func (r *InMemoryRepository) ListVersions(name string) ([]models.ConfigVersion, error) {
+func (r *InMemoryRepository) ListVersions(ctx context.Context, name string) ([]models.ConfigVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	versions, exists := r.versions[name]
-	if !exists {
-		return nil, &models.ConfigNotFoundError{Name: name}
+	return r.listVersions(ctx, name)
+}
+
+// listVersions returns a copy of all versions; callers must hold the lock
+func (r *InMemoryRepository) listVersions(ctx context.Context, name string) ([]models.ConfigVersion, error) {
+	e, err := r.entry(name)
+	if err != nil {
+		return nil, err
 	}
 
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	// Return a copy of the versions
-	versionsCopy := make([]models.ConfigVersion, len(versions))
-	for i, v := range versions {
+	versionsCopy := make([]models.ConfigVersion, len(e.versions))
+	for i, v := range e.versions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		versionsCopy[i] = models.ConfigVersion{
-			Version:   v.Version,
-			Data:      copyData(v.Data),
-			CreatedAt: v.CreatedAt,
+			Version:    v.Version,
+			Data:       copyData(v.Data),
+			CreatedAt:  v.CreatedAt,
+			CreatedBy:  v.CreatedBy,
+			Origin:     v.Origin,
+			IsCurrent:  v.Version == e.config.Version,
+			Checksum:   v.Checksum,
+			Message:    v.Message,
+			SchemaHash: v.SchemaHash,
 		}
 	}
 
@@ -149,39 +525,375 @@ func (r *InMemoryRepository) ListVersions(name string) ([]models.ConfigVersion,
 }
 
 // Exists checks if a configuration exists
-func (r *InMemoryRepository) Exists(name string) bool {
+func (r *InMemoryRepository) Exists(ctx context.Context, name string) bool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	_, exists := r.configs[name]
+	return r.exists(name)
+}
+
+// exists checks if a configuration exists; callers must hold the lock
+func (r *InMemoryRepository) exists(name string) bool {
+	_, exists := r.entries[name]
 	return exists
 }
 
-// copyData creates a deep copy of the data map
+// ListNames returns the names of all configurations in sorted order
+func (r *InMemoryRepository) ListNames(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.listNames(), nil
+}
+
+// listNames returns the sorted configuration names; callers must hold the lock
+func (r *InMemoryRepository) listNames() []string {
+	names := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// List returns copies of the configurations matching the filter, sorted by
+// name and paginated, along with the number of matches before pagination.
+// Listing does not count as reading the configurations.
+func (r *InMemoryRepository) List(ctx context.Context, filter models.ConfigFilter) ([]*models.Config, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	configs, total := r.list(filter)
+	return configs, total, nil
+}
+
+// list filters and paginates the configurations; callers must hold the lock
+func (r *InMemoryRepository) list(filter models.ConfigFilter) ([]*models.Config, int) {
+	var matched []*configEntry
+	for _, name := range r.listNames() {
+		e := r.entries[name]
+		e.mu.RLock()
+		configType := e.config.Type
+		e.mu.RUnlock()
+		if filter.Matches(name, configType) {
+			matched = append(matched, e)
+		}
+	}
+
+	total := len(matched)
+	if filter.Offset >= total {
+		return []*models.Config{}, total
+	}
+	end := total
+	// Compare against the remainder so a huge limit cannot overflow
+	if filter.Limit > 0 && filter.Limit < total-filter.Offset {
+		end = filter.Offset + filter.Limit
+	}
+
+	configs := make([]*models.Config, 0, end-filter.Offset)
+	for _, e := range matched[filter.Offset:end] {
+		configs = append(configs, e.copyConfig())
+	}
+	return configs, total
+}
+
+// Delete removes a configuration and its version history
+func (r *InMemoryRepository) Delete(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.delete(name); err != nil {
+		return err
+	}
+	r.publish(newChangeEvent(models.ChangeDeleted, name, 0))
+	return nil
+}
+
+// delete removes a configuration; callers must hold the write lock
+func (r *InMemoryRepository) delete(name string) error {
+	e, err := r.entry(name)
+	if err != nil {
+		return err
+	}
+
+	r.unindex(name, e.config.DependsOn)
+	delete(r.entries, name)
+	return nil
+}
+
+// SetTag assigns a tag to a version, moving the tag if it already exists
+func (r *InMemoryRepository) SetTag(ctx context.Context, name, tag string, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.setTag(name, tag, version)
+}
+
+// setTag assigns a tag; callers must hold the lock
+func (r *InMemoryRepository) setTag(name, tag string, version int) error {
+	e, err := r.entry(name)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, found := findVersion(e.versions, version); !found {
+		return &models.VersionNotFoundError{Name: name, Version: version}
+	}
+
+	// Copy on write so transaction snapshots keep the previous tag set
+	tags := make(map[string]int, len(e.tags)+1)
+	for t, v := range e.tags {
+		tags[t] = v
+	}
+	tags[tag] = version
+	e.tags = tags
+	return nil
+}
+
+// SetLocked locks or unlocks a configuration without creating a new version
+func (r *InMemoryRepository) SetLocked(ctx context.Context, name string, locked bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.setLocked(name, locked)
+}
+
+// setLocked sets the lock flag; callers must hold the lock
+func (r *InMemoryRepository) setLocked(name string, locked bool) error {
+	e, err := r.entry(name)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Copy on write so transaction snapshots keep the previous flag
+	config := *e.config
+	config.Locked = locked
+	e.config = &config
+	return nil
+}
+
+// SetDeprecation marks a configuration as deprecated with a message for its
+// readers, or clears the mark, without creating a new version
+func (r *InMemoryRepository) SetDeprecation(ctx context.Context, name string, deprecated bool, message string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.setDeprecation(name, deprecated, message)
+}
+
+// setDeprecation sets the deprecation; callers must hold the lock
+func (r *InMemoryRepository) setDeprecation(name string, deprecated bool, message string) error {
+	e, err := r.entry(name)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Copy on write so transaction snapshots keep the previous deprecation
+	config := *e.config
+	config.Deprecated = deprecated
+	config.DeprecationMessage = message
+	if !deprecated {
+		config.DeprecationMessage = ""
+	}
+	e.config = &config
+	return nil
+}
+
+// GetTag resolves a tag to its version number
+func (r *InMemoryRepository) GetTag(ctx context.Context, name, tag string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.getTag(name, tag)
+}
+
+// getTag resolves a tag; callers must hold the lock
+func (r *InMemoryRepository) getTag(name, tag string) (int, error) {
+	e, err := r.entry(name)
+	if err != nil {
+		return 0, err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	version, exists := e.tags[tag]
+	if !exists {
+		return 0, &models.TagNotFoundError{Name: name, Tag: tag}
+	}
+	return version, nil
+}
+
+// ListDependents lists the configurations that depend on the given configuration
+func (r *InMemoryRepository) ListDependents(ctx context.Context, name string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.listDependents(name)
+}
+
+// listDependents returns sorted dependent names; callers must hold the lock
+func (r *InMemoryRepository) listDependents(name string) ([]string, error) {
+	if !r.exists(name) {
+		return nil, &models.ConfigNotFoundError{Name: name}
+	}
+
+	r.indexMu.Lock()
+	dependents := make([]string, 0, len(r.dependents[name]))
+	for dependent := range r.dependents[name] {
+		dependents = append(dependents, dependent)
+	}
+	r.indexMu.Unlock()
+
+	sort.Strings(dependents)
+	return dependents, nil
+}
+
+// index records name as a dependent of each referenced configuration
+func (r *InMemoryRepository) index(name string, dependsOn []string) {
+	r.indexMu.Lock()
+	defer r.indexMu.Unlock()
+
+	for _, ref := range dependsOn {
+		if r.dependents[ref] == nil {
+			r.dependents[ref] = make(map[string]struct{})
+		}
+		r.dependents[ref][name] = struct{}{}
+	}
+}
+
+// unindex removes name as a dependent of each referenced configuration
+func (r *InMemoryRepository) unindex(name string, dependsOn []string) {
+	r.indexMu.Lock()
+	defer r.indexMu.Unlock()
+
+	for _, ref := range dependsOn {
+		delete(r.dependents[ref], name)
+		if len(r.dependents[ref]) == 0 {
+			delete(r.dependents, ref)
+		}
+	}
+}
+
+// Ping checks backend connectivity (always reachable for in-memory storage)
+func (r *InMemoryRepository) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// copyData creates a deep copy of the data map, including objects nested
+// inside arrays, so callers can never mutate stored data through a result.
+// Walking the tree with a type switch allocates only the copied containers
+// and keeps scalars as they are; an encode/decode round trip through JSON or
+// gob was measured at several times the cost and would turn ints into floats.
 func copyData(data map[string]interface{}) map[string]interface{} {
 	if data == nil {
 		return nil
 	}
 
-	copy := make(map[string]interface{}, len(data))
+	copied := make(map[string]interface{}, len(data))
 	for k, v := range data {
-		// For nested maps, recursively copy
-		if nested, ok := v.(map[string]interface{}); ok {
-			copy[k] = copyData(nested)
-		} else {
-			copy[k] = v
+		copied[k] = copyValue(v)
+	}
+	return copied
+}
+
+// copyValue deep-copies a JSON value; scalars are immutable and returned as is
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return copyData(v)
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyValue(item)
 		}
+		return copied
+	default:
+		return v
 	}
-	return copy
+}
+
+// copyStrings creates a copy of a string slice
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+
+	copied := make([]string, len(values))
+	copy(copied, values)
+	return copied
+}
+
+// copyRollout creates a copy of a rollout block
+func copyRollout(rollout *models.Rollout) *models.Rollout {
+	if rollout == nil {
+		return nil
+	}
+
+	copied := *rollout
+	copied.Cohorts = copyStrings(rollout.Cohorts)
+	return &copied
 }
 
 // Clear removes all configurations (useful for testing)
 func (r *InMemoryRepository) Clear() {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	r.clear(nil)
+}
 
-	r.configs = make(map[string]*models.Config)
-	r.versions = make(map[string][]models.ConfigVersion)
+// clear removes every configuration as a transaction, passing commit to transact
+func (r *InMemoryRepository) clear(commit func(names []string) error) error {
+	return r.transact(func(tx *inMemoryTx) error {
+		tx.replace(map[string]*configEntry{})
+		return nil
+	}, commit)
 }
 
 // Stats returns statistics about the repository (useful for monitoring)
@@ -190,12 +902,14 @@ func (r *InMemoryRepository) Stats() map[string]interface{} {
 	defer r.mu.RUnlock()
 
 	totalVersions := 0
-	for _, versions := range r.versions {
-		totalVersions += len(versions)
+	for _, e := range r.entries {
+		e.mu.RLock()
+		totalVersions += len(e.versions)
+		e.mu.RUnlock()
 	}
 
 	return map[string]interface{}{
-		"total_configs":  len(r.configs),
+		"total_configs":  len(r.entries),
 		"total_versions": totalVersions,
 	}
 }
@@ -2,6 +2,13 @@ package repository
 
 import (
 	"config-engine/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -18,7 +25,7 @@ func TestCreate(t *testing.T) {
 		},
 	}
 
-	err := repo.Create(config)
+	err := repo.Create(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Failed to create config: %v", err)
 	}
@@ -45,8 +52,8 @@ func TestCreateDuplicate(t *testing.T) {
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
 
-	repo.Create(config)
-	err := repo.Create(config)
+	repo.Create(context.Background(), config)
+	err := repo.Create(context.Background(), config)
 
 	if _, ok := err.(*models.ConfigExistsError); !ok {
 		t.Errorf("Expected ConfigExistsError, got %v", err)
@@ -62,9 +69,9 @@ func TestGet(t *testing.T) {
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
 
-	repo.Create(original)
+	repo.Create(context.Background(), original)
 
-	retrieved, err := repo.Get("test_config")
+	retrieved, err := repo.Get(context.Background(), "test_config")
 	if err != nil {
 		t.Fatalf("Failed to get config: %v", err)
 	}
@@ -81,7 +88,7 @@ func TestGet(t *testing.T) {
 func TestGetNotFound(t *testing.T) {
 	repo := NewInMemoryRepository()
 
-	_, err := repo.Get("nonexistent")
+	_, err := repo.Get(context.Background(), "nonexistent")
 	if _, ok := err.(*models.ConfigNotFoundError); !ok {
 		t.Errorf("Expected ConfigNotFoundError, got %v", err)
 	}
@@ -96,7 +103,7 @@ func TestUpdate(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	repo.Create(original)
+	repo.Create(context.Background(), original)
 
 	time.Sleep(10 * time.Millisecond) // Ensure timestamp difference
 
@@ -107,7 +114,7 @@ func TestUpdate(t *testing.T) {
 		Data: map[string]interface{}{"max_limit": 2000, "enabled": false},
 	}
 
-	err := repo.Update(updated)
+	err := repo.Update(context.Background(), updated)
 	if err != nil {
 		t.Fatalf("Failed to update config: %v", err)
 	}
@@ -121,7 +128,7 @@ func TestUpdate(t *testing.T) {
 	}
 
 	// Verify the update is stored
-	retrieved, _ := repo.Get("test_config")
+	retrieved, _ := repo.Get(context.Background(), "test_config")
 	if retrieved.Version != 2 {
 		t.Errorf("Expected stored version 2, got %d", retrieved.Version)
 	}
@@ -140,7 +147,7 @@ func TestUpdateNotFound(t *testing.T) {
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
 
-	err := repo.Update(config)
+	err := repo.Update(context.Background(), config)
 	if _, ok := err.(*models.ConfigNotFoundError); !ok {
 		t.Errorf("Expected ConfigNotFoundError, got %v", err)
 	}
@@ -155,16 +162,16 @@ func TestGetVersion(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	repo.Create(config)
+	repo.Create(context.Background(), config)
 
 	config.Data = map[string]interface{}{"max_limit": 2000, "enabled": false}
-	repo.Update(config)
+	repo.Update(context.Background(), config)
 
 	config.Data = map[string]interface{}{"max_limit": 3000, "enabled": true}
-	repo.Update(config)
+	repo.Update(context.Background(), config)
 
 	// Get version 1
-	v1, err := repo.GetVersion("test_config", 1)
+	v1, err := repo.GetVersion(context.Background(), "test_config", 1)
 	if err != nil {
 		t.Fatalf("Failed to get version 1: %v", err)
 	}
@@ -178,7 +185,7 @@ func TestGetVersion(t *testing.T) {
 	}
 
 	// Get version 2
-	v2, err := repo.GetVersion("test_config", 2)
+	v2, err := repo.GetVersion(context.Background(), "test_config", 2)
 	if err != nil {
 		t.Fatalf("Failed to get version 2: %v", err)
 	}
@@ -196,16 +203,16 @@ func TestGetVersionNotFound(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	repo.Create(config)
+	repo.Create(context.Background(), config)
 
 	// Try to get non-existent version
-	_, err := repo.GetVersion("test_config", 5)
+	_, err := repo.GetVersion(context.Background(), "test_config", 5)
 	if _, ok := err.(*models.VersionNotFoundError); !ok {
 		t.Errorf("Expected VersionNotFoundError, got %v", err)
 	}
 
 	// Try to get version of non-existent config
-	_, err = repo.GetVersion("nonexistent", 1)
+	_, err = repo.GetVersion(context.Background(), "nonexistent", 1)
 	if _, ok := err.(*models.ConfigNotFoundError); !ok {
 		t.Errorf("Expected ConfigNotFoundError, got %v", err)
 	}
@@ -220,12 +227,12 @@ func TestListVersions(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	repo.Create(config)
+	repo.Create(context.Background(), config)
 
 	config.Data = map[string]interface{}{"max_limit": 2000, "enabled": false}
-	repo.Update(config)
+	repo.Update(context.Background(), config)
 
-	versions, err := repo.ListVersions("test_config")
+	versions, err := repo.ListVersions(context.Background(), "test_config")
 	if err != nil {
 		t.Fatalf("Failed to list versions: %v", err)
 	}
@@ -246,16 +253,136 @@ func TestListVersions(t *testing.T) {
 func TestListVersionsNotFound(t *testing.T) {
 	repo := NewInMemoryRepository()
 
-	_, err := repo.ListVersions("nonexistent")
+	_, err := repo.ListVersions(context.Background(), "nonexistent")
 	if _, ok := err.(*models.ConfigNotFoundError); !ok {
 		t.Errorf("Expected ConfigNotFoundError, got %v", err)
 	}
 }
 
+func TestRollbackVersionStrategies(t *testing.T) {
+	tests := []struct {
+		strategy VersionStrategy
+		// head after each step: create, update, update, rollback to 1, update
+		wantHeads    []int
+		wantVersions []int
+		wantCurrent  int
+	}{
+		{VersionStrategyAppend, []int{1, 2, 3, 4, 5}, []int{1, 2, 3, 4, 5}, 5},
+		{VersionStrategyBranching, []int{1, 2, 3, 1, 4}, []int{1, 2, 3, 4}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			repo := NewInMemoryRepositoryWithStrategy(tt.strategy)
+			limit := func(n int) map[string]interface{} { return map[string]interface{}{"max_limit": n} }
+
+			var heads []int
+			record := func(config *models.Config, err error) {
+				t.Helper()
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				heads = append(heads, config.Version)
+			}
+
+			config := &models.Config{Name: "test_config", Type: "payment_config", Data: limit(1)}
+			record(config, repo.Create(context.Background(), config))
+			for _, n := range []int{2, 3} {
+				config = &models.Config{Name: "test_config", Type: "payment_config", Data: limit(n)}
+				record(config, repo.Update(context.Background(), config))
+			}
+			config = &models.Config{Name: "test_config", Type: "payment_config", Data: limit(1)}
+			record(config, repo.Rollback(context.Background(), config, 1))
+
+			if head, _ := repo.Get(context.Background(), "test_config"); head.Data["max_limit"] != 1 {
+				t.Errorf("Expected rolled back data, got %v", head.Data)
+			}
+
+			config = &models.Config{Name: "test_config", Type: "payment_config", Data: limit(4)}
+			record(config, repo.Update(context.Background(), config))
+
+			if fmt.Sprint(heads) != fmt.Sprint(tt.wantHeads) {
+				t.Errorf("Expected head versions %v, got %v", tt.wantHeads, heads)
+			}
+
+			versions, _ := repo.ListVersions(context.Background(), "test_config")
+			var numbers []int
+			current := 0
+			for _, v := range versions {
+				numbers = append(numbers, v.Version)
+				if v.IsCurrent {
+					current = v.Version
+				}
+			}
+			if fmt.Sprint(numbers) != fmt.Sprint(tt.wantVersions) {
+				t.Errorf("Expected versions %v, got %v", tt.wantVersions, numbers)
+			}
+			if current != tt.wantCurrent {
+				t.Errorf("Expected version %d marked current, got %d", tt.wantCurrent, current)
+			}
+		})
+	}
+}
+
+func TestCompactKeepsBranchingHead(t *testing.T) {
+	repo := NewInMemoryRepositoryWithStrategy(VersionStrategyBranching)
+	repo.Create(context.Background(), &models.Config{Name: "test_config", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}})
+	for n := 2; n <= 3; n++ {
+		repo.Update(context.Background(), &models.Config{Name: "test_config", Type: "payment_config", Data: map[string]interface{}{"max_limit": n}})
+	}
+	if err := repo.Rollback(context.Background(), &models.Config{Name: "test_config", Type: "payment_config"}, 1); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+
+	repo.Compact(context.Background(), 1)
+
+	// The head is kept even though it is not among the newest versions
+	if _, err := repo.GetVersion(context.Background(), "test_config", 1); err != nil {
+		t.Errorf("Expected compaction to keep the head version: %v", err)
+	}
+	if _, err := repo.GetVersion(context.Background(), "test_config", 2); err == nil {
+		t.Error("Expected version 2 to be compacted away")
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	repo := NewInMemoryRepository()
+	repo.Create(context.Background(), &models.Config{Name: "test_config", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := repo.Get(ctx, "test_config"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from Get, got %v", err)
+	}
+	if _, err := repo.ListVersions(ctx, "test_config"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from ListVersions, got %v", err)
+	}
+	if err := repo.Update(ctx, &models.Config{Name: "test_config", Type: "payment_config"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from Update, got %v", err)
+	}
+
+	// A transaction cancelled part way through is rolled back
+	txCtx, cancelTx := context.WithCancel(context.Background())
+	err := repo.WithTransaction(txCtx, func(tx ConfigRepository) error {
+		if err := tx.Update(txCtx, &models.Config{Name: "test_config", Type: "payment_config", Data: map[string]interface{}{"max_limit": 2}}); err != nil {
+			return err
+		}
+		cancelTx()
+		return tx.Update(txCtx, &models.Config{Name: "test_config", Type: "payment_config", Data: map[string]interface{}{"max_limit": 3}})
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from the transaction, got %v", err)
+	}
+	if config, _ := repo.Get(context.Background(), "test_config"); config.Version != 1 {
+		t.Errorf("Expected the cancelled transaction to be rolled back, got version %d", config.Version)
+	}
+}
+
 func TestExists(t *testing.T) {
 	repo := NewInMemoryRepository()
 
-	if repo.Exists("test_config") {
+	if repo.Exists(context.Background(), "test_config") {
 		t.Error("Config should not exist yet")
 	}
 
@@ -264,9 +391,9 @@ func TestExists(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	repo.Create(config)
+	repo.Create(context.Background(), config)
 
-	if !repo.Exists("test_config") {
+	if !repo.Exists(context.Background(), "test_config") {
 		t.Error("Config should exist")
 	}
 }
@@ -279,14 +406,14 @@ func TestConcurrency(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	repo.Create(config)
+	repo.Create(context.Background(), config)
 
 	// Run concurrent reads and writes
 	done := make(chan bool)
 	for i := 0; i < 10; i++ {
 		go func() {
 			for j := 0; j < 100; j++ {
-				repo.Get("test_config")
+				repo.Get(context.Background(), "test_config")
 			}
 			done <- true
 		}()
@@ -303,7 +430,7 @@ func TestConcurrency(t *testing.T) {
 						"enabled":   true,
 					},
 				}
-				repo.Update(updated)
+				repo.Update(context.Background(), updated)
 			}
 			done <- true
 		}(i)
@@ -315,7 +442,7 @@ func TestConcurrency(t *testing.T) {
 	}
 
 	// Verify final state is consistent
-	final, err := repo.Get("test_config")
+	final, err := repo.Get(context.Background(), "test_config")
 	if err != nil {
 		t.Fatalf("Failed to get final config: %v", err)
 	}
@@ -333,15 +460,337 @@ func TestDataIsolation(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	repo.Create(config)
+	repo.Create(context.Background(), config)
 
 	// Get config and modify the returned data
-	retrieved, _ := repo.Get("test_config")
+	retrieved, _ := repo.Get(context.Background(), "test_config")
 	retrieved.Data["max_limit"] = 9999
 
 	// Get config again and verify it wasn't affected
-	retrieved2, _ := repo.Get("test_config")
+	retrieved2, _ := repo.Get(context.Background(), "test_config")
 	if retrieved2.Data["max_limit"].(int) != 1000 {
 		t.Error("Data modification should not affect stored config")
 	}
-}
\ No newline at end of file
+}
+
+func TestNestedSliceIsolation(t *testing.T) {
+	repo := NewInMemoryRepository()
+
+	repo.Create(context.Background(), &models.Config{
+		Name: "test_config",
+		Type: "routing_config",
+		Data: map[string]interface{}{
+			"routes": []interface{}{
+				map[string]interface{}{"host": "a.example.com", "weights": []interface{}{1, 2}},
+			},
+		},
+	})
+
+	// Mutate an object and a nested array inside a retrieved slice
+	retrieved, _ := repo.Get(context.Background(), "test_config")
+	route := retrieved.Data["routes"].([]interface{})[0].(map[string]interface{})
+	route["host"] = "evil.example.com"
+	route["weights"].([]interface{})[0] = 99
+
+	stored, _ := repo.Get(context.Background(), "test_config")
+	route = stored.Data["routes"].([]interface{})[0].(map[string]interface{})
+	if route["host"] != "a.example.com" {
+		t.Errorf("Expected stored host to be unchanged, got %v", route["host"])
+	}
+	if route["weights"].([]interface{})[0] != 1 {
+		t.Errorf("Expected stored weights to be unchanged, got %v", route["weights"])
+	}
+
+	version, _ := repo.GetVersion(context.Background(), "test_config", 1)
+	if version.Data["routes"].([]interface{})[0].(map[string]interface{})["host"] != "a.example.com" {
+		t.Error("Expected version history to be unchanged")
+	}
+}
+
+func TestVersionAt(t *testing.T) {
+	repo := NewInMemoryRepository()
+	ctx := context.Background()
+	before := time.Now()
+	time.Sleep(time.Millisecond)
+
+	var times []time.Time
+	repo.Create(ctx, &models.Config{Name: "limits", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}})
+	for i := 2; i <= 3; i++ {
+		time.Sleep(time.Millisecond)
+		times = append(times, time.Now())
+		repo.Update(ctx, &models.Config{Name: "limits", Data: map[string]interface{}{"max_limit": i}})
+	}
+
+	tests := []struct {
+		at   time.Time
+		want int
+	}{
+		{times[0], 1},
+		{times[1], 2},
+		{time.Now(), 3},
+	}
+	for _, tt := range tests {
+		version, err := repo.VersionAt(ctx, "limits", tt.at)
+		if err != nil || version.Version != tt.want {
+			t.Errorf("Expected version %d at %v, got %+v, %v", tt.want, tt.at, version, err)
+		}
+	}
+
+	var noVersion *models.NoVersionAtError
+	if _, err := repo.VersionAt(ctx, "limits", before); !errors.As(err, &noVersion) || noVersion.Compacted {
+		t.Errorf("Expected a NoVersionAtError before creation, got %v", err)
+	}
+
+	// Once the history is compacted away the error says so
+	repo.Compact(ctx, 1)
+	if _, err := repo.VersionAt(ctx, "limits", times[0]); !errors.As(err, &noVersion) || !noVersion.Compacted {
+		t.Errorf("Expected a NoVersionAtError for compacted history, got %v", err)
+	}
+}
+
+func TestDependentsIndex(t *testing.T) {
+	repo := NewInMemoryRepository()
+
+	repo.Create(context.Background(), &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{}})
+	repo.Create(context.Background(), &models.Config{Name: "routing", Type: "routing_config", Data: map[string]interface{}{}, DependsOn: []string{"payments"}})
+	repo.Create(context.Background(), &models.Config{Name: "billing", Type: "billing_config", Data: map[string]interface{}{}, DependsOn: []string{"payments"}})
+
+	dependents, err := repo.ListDependents(context.Background(), "payments")
+	if err != nil {
+		t.Fatalf("Failed to list dependents: %v", err)
+	}
+	if len(dependents) != 2 || dependents[0] != "billing" || dependents[1] != "routing" {
+		t.Errorf("Expected [billing routing], got %v", dependents)
+	}
+
+	// Dropping the dependency removes it from the index
+	repo.Update(context.Background(), &models.Config{Name: "routing", Type: "routing_config", Data: map[string]interface{}{}})
+	dependents, _ = repo.ListDependents(context.Background(), "payments")
+	if len(dependents) != 1 || dependents[0] != "billing" {
+		t.Errorf("Expected [billing], got %v", dependents)
+	}
+
+	// Deleting a dependent removes it from the index
+	if err := repo.Delete(context.Background(), "billing"); err != nil {
+		t.Fatalf("Failed to delete config: %v", err)
+	}
+	dependents, _ = repo.ListDependents(context.Background(), "payments")
+	if len(dependents) != 0 {
+		t.Errorf("Expected no dependents, got %v", dependents)
+	}
+}
+
+func TestListFilters(t *testing.T) {
+	repo := NewInMemoryRepository()
+	ctx := context.Background()
+	for _, c := range []struct{ name, configType string }{
+		{"payments_eu", "payment_config"},
+		{"payments_us", "payment_config"},
+		{"payouts", "payment_config"},
+		{"payments_flags", "feature_flag"},
+		{"search_flags", "feature_flag"},
+	} {
+		repo.Create(ctx, &models.Config{Name: c.name, Type: c.configType, Data: map[string]interface{}{}})
+	}
+
+	tests := []struct {
+		name   string
+		filter models.ConfigFilter
+		want   []string
+		total  int
+	}{
+		{"no filter", models.ConfigFilter{}, []string{"payments_eu", "payments_flags", "payments_us", "payouts", "search_flags"}, 5},
+		{"type", models.ConfigFilter{Type: "payment_config"}, []string{"payments_eu", "payments_us", "payouts"}, 3},
+		{"name prefix", models.ConfigFilter{NamePrefix: "payments"}, []string{"payments_eu", "payments_flags", "payments_us"}, 3},
+		{"type and prefix", models.ConfigFilter{Type: "payment_config", NamePrefix: "payments"}, []string{"payments_eu", "payments_us"}, 2},
+		{"no match", models.ConfigFilter{Type: "feature_flag", NamePrefix: "payouts"}, []string{}, 0},
+		{"page", models.ConfigFilter{Type: "payment_config", Limit: 2, Offset: 1}, []string{"payments_us", "payouts"}, 3},
+		{"offset past end", models.ConfigFilter{NamePrefix: "payments", Offset: 3}, []string{}, 3},
+		{"huge limit", models.ConfigFilter{Type: "payment_config", Limit: math.MaxInt, Offset: 1}, []string{"payments_us", "payouts"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configs, total, err := repo.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("Failed to list configs: %v", err)
+			}
+			names := make([]string, len(configs))
+			for i, config := range configs {
+				names[i] = config.Name
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, names)
+			}
+			if total != tt.total {
+				t.Errorf("Expected total %d, got %d", tt.total, total)
+			}
+		})
+	}
+}
+
+func TestDeleteNotFound(t *testing.T) {
+	repo := NewInMemoryRepository()
+
+	err := repo.Delete(context.Background(), "nonexistent")
+	if _, ok := err.(*models.ConfigNotFoundError); !ok {
+		t.Errorf("Expected ConfigNotFoundError, got %v", err)
+	}
+}
+
+func TestMaxConfigs(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryRepository()
+	repo.SetMaxConfigs(3)
+
+	for i := 0; i < 3; i++ {
+		if err := repo.Create(ctx, &models.Config{Name: fmt.Sprintf("config-%d", i), Type: "test", Data: map[string]interface{}{}}); err != nil {
+			t.Fatalf("Failed to create config %d within the limit: %v", i, err)
+		}
+	}
+
+	err := repo.Create(ctx, &models.Config{Name: "one-too-many", Type: "test", Data: map[string]interface{}{}})
+	if capErr, ok := err.(*models.CapacityExceededError); !ok || capErr.Limit != 3 {
+		t.Fatalf("Expected CapacityExceededError with limit 3, got %v", err)
+	}
+
+	// Deleting a config frees a slot
+	if err := repo.Delete(ctx, "config-0"); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	if err := repo.Create(ctx, &models.Config{Name: "one-too-many", Type: "test", Data: map[string]interface{}{}}); err != nil {
+		t.Errorf("Expected create to succeed after a delete, got %v", err)
+	}
+
+	// Concurrent creates never overshoot the cap
+	repo = NewInMemoryRepository()
+	repo.SetMaxConfigs(5)
+	var wg sync.WaitGroup
+	var created atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if repo.Create(ctx, &models.Config{Name: fmt.Sprintf("config-%d", i), Type: "test", Data: map[string]interface{}{}}) == nil {
+				created.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if created.Load() != 5 {
+		t.Errorf("Expected exactly 5 concurrent creates to succeed, got %d", created.Load())
+	}
+}
+
+func TestConcurrentUpdatesAndDeletes(t *testing.T) {
+	repo := NewInMemoryRepository()
+
+	const configs = 8
+	for i := 0; i < configs; i++ {
+		repo.Create(context.Background(), &models.Config{Name: fmt.Sprintf("config-%d", i), Type: "test", Data: map[string]interface{}{}})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < configs; i++ {
+		name := fmt.Sprintf("config-%d", i)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				repo.Update(context.Background(), &models.Config{Name: name, Type: "test", Data: map[string]interface{}{"n": j}})
+				repo.SetTag(context.Background(), name, "latest", 1)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				repo.Get(context.Background(), name)
+				repo.ListVersions(context.Background(), name)
+			}
+		}()
+	}
+
+	// Structural changes interleave with the per-config updates
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 20; j++ {
+			name := fmt.Sprintf("extra-%d", j)
+			repo.Create(context.Background(), &models.Config{Name: name, Type: "test", Data: map[string]interface{}{}})
+			repo.Delete(context.Background(), name)
+		}
+	}()
+	wg.Wait()
+
+	for i := 0; i < configs; i++ {
+		config, err := repo.Get(context.Background(), fmt.Sprintf("config-%d", i))
+		if err != nil {
+			t.Fatalf("Failed to get config: %v", err)
+		}
+		if config.Version != 51 {
+			t.Errorf("Expected version 51, got %d", config.Version)
+		}
+	}
+	if stats := repo.Stats(); stats["total_configs"] != configs {
+		t.Errorf("Expected %d configs, got %v", configs, stats["total_configs"])
+	}
+}
+
+// BenchmarkConcurrentUpdatesDistinctConfigs measures update throughput when
+// parallel writers each update their own configuration. Each update only
+// locks its own entry, so throughput scales with GOMAXPROCS instead of being
+// serialized on the repository lock; run with -cpu=1,4,8 to compare.
+func BenchmarkConcurrentUpdatesDistinctConfigs(b *testing.B) {
+	repo := NewInMemoryRepository()
+
+	const configs = 256
+	data := make(map[string]interface{}, 32)
+	for i := 0; i < 32; i++ {
+		data[fmt.Sprintf("key_%d", i)] = i
+	}
+	for i := 0; i < configs; i++ {
+		repo.Create(context.Background(), &models.Config{Name: fmt.Sprintf("config-%d", i), Type: "test", Data: data})
+	}
+
+	var next int64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		name := fmt.Sprintf("config-%d", atomic.AddInt64(&next, 1)%configs)
+		for pb.Next() {
+			if err := repo.Update(context.Background(), &models.Config{Name: name, Type: "test", Data: data}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkCopyData(b *testing.B) {
+	// 100 routes, each mixing nested objects and arrays
+	routes := make([]interface{}, 100)
+	for i := range routes {
+		routes[i] = map[string]interface{}{
+			"host":    fmt.Sprintf("host-%d.example.com", i),
+			"weights": []interface{}{1, 2, 3, 4},
+			"headers": map[string]interface{}{"x-team": "payments", "x-tier": i},
+			"rules": []interface{}{
+				map[string]interface{}{"path": "/api", "timeout_ms": 500},
+				map[string]interface{}{"path": "/health", "timeout_ms": 50},
+			},
+		}
+	}
+	data := map[string]interface{}{"routes": routes, "enabled": true}
+
+	b.Run("walk", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			copyData(data)
+		}
+	})
+	b.Run("json_roundtrip", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			encoded, _ := json.Marshal(data)
+			var copied map[string]interface{}
+			json.Unmarshal(encoded, &copied)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+
+	"config-engine/internal/models"
+)
+
+// undoState records what the latest write to an entry replaced
+type undoState struct {
+	// config is the previous head; nil when the write created the configuration
+	config *models.Config
+	// appended is set when the write added a version to the history
+	appended bool
+}
+
+// RevertWrite undoes the write that produced version, restoring the
+// configuration to its state before it. Reverting a create deletes the
+// configuration. Only the latest write can be reverted, and only while no
+// other change has been made since.
+func (r *InMemoryRepository) RevertWrite(ctx context.Context, name string, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Reverting a create removes the entry, which needs the write lock
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, err := r.revertWrite(name, version)
+	if err != nil {
+		return err
+	}
+	r.publish(event)
+	return nil
+}
+
+// revertWrite undoes the latest write; callers must hold the write lock
+func (r *InMemoryRepository) revertWrite(name string, version int) (models.ChangeEvent, error) {
+	e, err := r.entry(name)
+	if err != nil {
+		return models.ChangeEvent{}, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	undo := e.undo
+	if undo == nil || e.config.Version != version || (undo.appended && e.latestVersion() != version) {
+		return models.ChangeEvent{}, &models.WriteNotRevertibleError{Name: name, Version: version}
+	}
+
+	if undo.config == nil {
+		r.unindex(name, e.config.DependsOn)
+		delete(r.entries, name)
+		return newChangeEvent(models.ChangeDeleted, name, 0), nil
+	}
+
+	if undo.appended {
+		// Build a new slice so transaction snapshots sharing the old one are unaffected
+		e.versions = append([]models.ConfigVersion(nil), e.versions[:len(e.versions)-1]...)
+		if len(e.tags) > 0 {
+			tags := make(map[string]int, len(e.tags))
+			for tag, tagged := range e.tags {
+				if tagged != version {
+					tags[tag] = tagged
+				}
+			}
+			e.tags = tags
+		}
+	}
+
+	// Lock and deprecation belong to the configuration, not to the write
+	restored := *undo.config
+	restored.Locked = e.config.Locked
+	restored.Deprecated = e.config.Deprecated
+	restored.DeprecationMessage = e.config.DeprecationMessage
+
+	r.unindex(name, e.config.DependsOn)
+	e.config = &restored
+	r.index(name, restored.DependsOn)
+	e.undo = nil
+	return newChangeEvent(models.ChangeUpdated, name, restored.Version), nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"config-engine/internal/models"
+)
+
+// snapshotFormat is the version of the snapshot document layout
+const snapshotFormat = 1
+
+// Snapshotter is implemented by repositories that can capture and replace their entire state
+type Snapshotter interface {
+	Snapshot(ctx context.Context) ([]byte, error)
+	Restore(ctx context.Context, data []byte) (int, error)
+}
+
+// snapshotDocument is the serialized state of a repository. Each configuration
+// uses the same record layout as FileRepository's files.
+type snapshotDocument struct {
+	Format  int          `json:"format"`
+	Configs []fileRecord `json:"configs"`
+}
+
+// Snapshot serializes every configuration with its history, tags and
+// proposals. The write lock is held so the snapshot is a consistent point in
+// time across configurations.
+func (r *InMemoryRepository) Snapshot(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := r.listNames()
+	doc := snapshotDocument{Format: snapshotFormat, Configs: make([]fileRecord, 0, len(names))}
+	for _, name := range names {
+		e := r.entries[name]
+		doc.Configs = append(doc.Configs, fileRecord{
+			Config:    e.config,
+			Versions:  e.versions,
+			Tags:      e.tags,
+			Proposals: e.proposals,
+		})
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// Restore replaces the entire state with a snapshot and returns the number of
+// configurations restored. The snapshot is checked for consistency before
+// anything is swapped in, so an invalid snapshot leaves the state untouched.
+func (r *InMemoryRepository) Restore(ctx context.Context, data []byte) (int, error) {
+	return r.restore(ctx, data, nil)
+}
+
+// restore replaces the state with a snapshot like Restore, swapping it in as
+// a transaction and passing commit to transact
+func (r *InMemoryRepository) restore(ctx context.Context, data []byte, commit func(names []string) error) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	var doc snapshotDocument
+	if err := models.DecodeJSON(data, &doc); err != nil {
+		return 0, snapshotError("invalid snapshot: %v", err)
+	}
+	if doc.Format != snapshotFormat {
+		return 0, snapshotError("unsupported snapshot format %d", doc.Format)
+	}
+
+	entries := make(map[string]*configEntry, len(doc.Configs))
+	for _, record := range doc.Configs {
+		if err := r.checkRecord(record); err != nil {
+			return 0, err
+		}
+		if _, exists := entries[record.Config.Name]; exists {
+			return 0, snapshotError("configuration %s appears more than once", record.Config.Name)
+		}
+		entries[record.Config.Name] = &configEntry{
+			config:    record.Config,
+			versions:  record.Versions,
+			tags:      record.Tags,
+			proposals: record.Proposals,
+		}
+	}
+
+	err := r.transact(func(tx *inMemoryTx) error {
+		if r.maxConfigs > 0 && len(entries) > r.maxConfigs {
+			return &models.CapacityExceededError{Limit: r.maxConfigs}
+		}
+		// Waiters are woken on commit and re-read the restored state
+		tx.replace(entries)
+		return nil
+	}, commit)
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// checkRecord verifies a snapshot record is internally consistent. Version
+// numbers must be strictly increasing but need not be contiguous, since
+// compaction and squashing remove versions from the middle of the history.
+// The head must be a version in the history with matching data, and with the
+// append strategy it must be the latest version.
+func (r *InMemoryRepository) checkRecord(record fileRecord) error {
+	config := record.Config
+	if config == nil || config.Name == "" {
+		return snapshotError("configuration without a name")
+	}
+	if len(record.Versions) == 0 {
+		return snapshotError("configuration %s has no versions", config.Name)
+	}
+
+	for i, version := range record.Versions {
+		if version.Version < 1 || (i > 0 && version.Version <= record.Versions[i-1].Version) {
+			return snapshotError("configuration %s has out-of-order version %d", config.Name, version.Version)
+		}
+	}
+
+	i, found := findVersion(record.Versions, config.Version)
+	if !found {
+		return snapshotError("configuration %s is at version %d, which is not in its history", config.Name, config.Version)
+	}
+	latest := record.Versions[len(record.Versions)-1].Version
+	if r.strategy != VersionStrategyBranching && config.Version != latest {
+		return snapshotError("configuration %s is at version %d but its latest version is %d", config.Name, config.Version, latest)
+	}
+	if !reflect.DeepEqual(config.Data, record.Versions[i].Data) {
+		return snapshotError("configuration %s data does not match version %d", config.Name, config.Version)
+	}
+
+	for tag, version := range record.Tags {
+		if _, found := findVersion(record.Versions, version); !found {
+			return snapshotError("tag %s of configuration %s points at missing version %d", tag, config.Name, version)
+		}
+	}
+	return nil
+}
+
+// snapshotError reports a snapshot that cannot be restored
+func snapshotError(format string, args ...interface{}) error {
+	return &models.ValidationError{Field: "snapshot", Message: fmt.Sprintf(format, args...)}
+}
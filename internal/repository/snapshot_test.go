@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"config-engine/internal/models"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryRepository()
+	repo.Create(ctx, &models.Config{Name: "base", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}})
+	repo.Create(ctx, &models.Config{Name: "app", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}, DependsOn: []string{"base"}})
+	for i := 2; i <= 3; i++ {
+		repo.Update(ctx, &models.Config{Name: "app", Type: "payment_config", Data: map[string]interface{}{"max_limit": i}, DependsOn: []string{"base"}})
+	}
+	repo.SetTag(ctx, "app", "stable", 2)
+
+	snapshot, err := repo.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Failed to snapshot: %v", err)
+	}
+
+	restored := NewInMemoryRepository()
+	restored.Create(ctx, &models.Config{Name: "stale", Type: "payment_config", Data: map[string]interface{}{}})
+	count, err := restored.Restore(ctx, snapshot)
+	if err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 configs restored, got %d", count)
+	}
+	if restored.Exists(ctx, "stale") {
+		t.Error("Expected restore to replace existing state")
+	}
+
+	versions, err := restored.ListVersions(ctx, "app")
+	if err != nil || len(versions) != 3 {
+		t.Fatalf("Expected 3 versions after restore, got %d (%v)", len(versions), err)
+	}
+	for i, version := range versions {
+		if version.Version != i+1 || version.Data["max_limit"] != json.Number(strconv.Itoa(i+1)) {
+			t.Errorf("Expected version %d with max_limit %d, got %+v", i+1, i+1, version)
+		}
+	}
+	if version, err := restored.GetTag(ctx, "app", "stable"); err != nil || version != 2 {
+		t.Errorf("Expected tag stable at version 2, got %d (%v)", version, err)
+	}
+	if dependents, _ := restored.ListDependents(ctx, "base"); len(dependents) != 1 || dependents[0] != "app" {
+		t.Errorf("Expected the dependents index to be rebuilt, got %v", dependents)
+	}
+
+	// Restored entries keep working: the next update continues the numbering
+	if err := restored.Update(ctx, &models.Config{Name: "app", Type: "payment_config", Data: map[string]interface{}{"max_limit": 4}}); err != nil {
+		t.Fatalf("Failed to update restored config: %v", err)
+	}
+	if config, _ := restored.Get(ctx, "app"); config.Version != 4 {
+		t.Errorf("Expected version 4 after update, got %d", config.Version)
+	}
+}
+
+func TestRestoreRejectsInconsistentSnapshot(t *testing.T) {
+	ctx := context.Background()
+	source := NewInMemoryRepository()
+	source.Create(ctx, &models.Config{Name: "app", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}})
+	source.Update(ctx, &models.Config{Name: "app", Type: "payment_config", Data: map[string]interface{}{"max_limit": 2}})
+	snapshot, _ := source.Snapshot(ctx)
+
+	tests := []struct {
+		name   string
+		mutate func(doc *snapshotDocument)
+	}{
+		{"unknown format", func(doc *snapshotDocument) { doc.Format = 99 }},
+		{"no versions", func(doc *snapshotDocument) { doc.Configs[0].Versions = nil }},
+		{"out of order", func(doc *snapshotDocument) {
+			v := doc.Configs[0].Versions
+			v[0], v[1] = v[1], v[0]
+		}},
+		{"head behind latest", func(doc *snapshotDocument) { doc.Configs[0].Config.Version = 1 }},
+		{"head missing", func(doc *snapshotDocument) { doc.Configs[0].Config.Version = 7 }},
+		{"data mismatch", func(doc *snapshotDocument) { doc.Configs[0].Config.Data = map[string]interface{}{"max_limit": 99} }},
+		{"dangling tag", func(doc *snapshotDocument) { doc.Configs[0].Tags = map[string]int{"gone": 5} }},
+		{"duplicate name", func(doc *snapshotDocument) { doc.Configs = append(doc.Configs, doc.Configs[0]) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc snapshotDocument
+			json.Unmarshal(snapshot, &doc)
+			tt.mutate(&doc)
+			data, _ := json.Marshal(doc)
+
+			repo := NewInMemoryRepository()
+			repo.Create(ctx, &models.Config{Name: "keep", Type: "payment_config", Data: map[string]interface{}{}})
+			_, err := repo.Restore(ctx, data)
+			if _, ok := err.(*models.ValidationError); !ok {
+				t.Fatalf("Expected ValidationError, got %v", err)
+			}
+			if !repo.Exists(ctx, "keep") {
+				t.Error("Expected a rejected restore to leave the state untouched")
+			}
+		})
+	}
+
+	// A branching repository accepts a head behind the latest version
+	var doc snapshotDocument
+	json.Unmarshal(snapshot, &doc)
+	doc.Configs[0].Config.Version = 1
+	doc.Configs[0].Config.Data = doc.Configs[0].Versions[0].Data
+	data, _ := json.Marshal(doc)
+	if _, err := NewInMemoryRepositoryWithStrategy(VersionStrategyBranching).Restore(ctx, data); err != nil {
+		t.Errorf("Expected the branching strategy to accept a rolled back head, got %v", err)
+	}
+}
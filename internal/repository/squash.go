@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"config-engine/internal/models"
+)
+
+// SquashVersions collapses versions from..to into the single version to,
+// discarding the versions in between. Later version numbers are not
+// renumbered: the squashed range leaves a gap so version numbers already
+// referenced in audit logs, tags and clients stay valid. Tags pointing at a
+// discarded version are moved to version to, whose data supersedes them.
+// It returns the number of versions removed.
+func (r *InMemoryRepository) SquashVersions(ctx context.Context, name string, from, to int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.squashVersions(name, from, to)
+}
+
+// squashVersions rewrites the version history; callers must hold the lock
+func (r *InMemoryRepository) squashVersions(name string, from, to int) (int, error) {
+	e, err := r.entry(name)
+	if err != nil {
+		return 0, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	versions := e.versions
+
+	start, found := findVersion(versions, from)
+	if !found {
+		return 0, &models.VersionNotFoundError{Name: name, Version: from}
+	}
+	end, found := findVersion(versions, to)
+	if !found {
+		return 0, &models.VersionNotFoundError{Name: name, Version: to}
+	}
+
+	// Build a new slice so transaction snapshots sharing the old one are unaffected
+	squashed := make([]models.ConfigVersion, 0, len(versions)-(end-start))
+	squashed = append(squashed, versions[:start]...)
+	squashed = append(squashed, versions[end:]...)
+	e.versions = squashed
+	// The latest version may now stand for several writes
+	e.undo = nil
+
+	// Copy on write so transaction snapshots keep the previous tag set
+	if len(e.tags) > 0 {
+		tags := make(map[string]int, len(e.tags))
+		for tag, version := range e.tags {
+			if version >= from && version < to {
+				version = to
+			}
+			tags[tag] = version
+		}
+		e.tags = tags
+	}
+
+	return end - start, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"config-engine/internal/models"
+)
+
+func TestSquashVersions(t *testing.T) {
+	repo := NewInMemoryRepository()
+	seedVersions(t, repo, "a", 6)
+	repo.SetTag(context.Background(), "a", "old", 3)
+	repo.SetTag(context.Background(), "a", "first", 1)
+
+	removed, err := repo.SquashVersions(context.Background(), "a", 2, 5)
+	if err != nil {
+		t.Fatalf("Squash failed: %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("Expected 3 versions removed, got %d", removed)
+	}
+
+	versions, _ := repo.ListVersions(context.Background(), "a")
+	got := make([]int, len(versions))
+	for i, v := range versions {
+		got[i] = v.Version
+	}
+	if len(got) != 3 || got[0] != 1 || got[1] != 5 || got[2] != 6 {
+		t.Errorf("Expected versions [1 5 6] with a gap, got %v", got)
+	}
+	if v, _ := repo.GetVersion(context.Background(), "a", 5); v.Data["max_limit"] != 5 {
+		t.Errorf("Expected range end data to be kept, got %v", v.Data)
+	}
+
+	if version, _ := repo.GetTag(context.Background(), "a", "old"); version != 5 {
+		t.Errorf("Expected tag on a squashed version to move to 5, got %d", version)
+	}
+	if version, _ := repo.GetTag(context.Background(), "a", "first"); version != 1 {
+		t.Errorf("Expected tag outside the range to stay on 1, got %d", version)
+	}
+}
+
+func TestSquashVersionsErrors(t *testing.T) {
+	repo := NewInMemoryRepository()
+	seedVersions(t, repo, "a", 3)
+
+	if _, err := repo.SquashVersions(context.Background(), "missing", 1, 2); err == nil {
+		t.Error("Expected error for missing config")
+	}
+	if _, err := repo.SquashVersions(context.Background(), "a", 1, 7); err == nil {
+		t.Error("Expected error for unknown range end")
+	}
+
+	repo.SquashVersions(context.Background(), "a", 1, 2)
+	if _, err := repo.SquashVersions(context.Background(), "a", 1, 3); err == nil {
+		t.Error("Expected error for a range start that was already squashed away")
+	}
+}
+
+func TestSquashVersionsTransactionRollback(t *testing.T) {
+	repo := NewInMemoryRepository()
+	seedVersions(t, repo, "a", 4)
+
+	repo.WithTransaction(context.Background(), func(tx ConfigRepository) error {
+		if _, err := tx.SquashVersions(context.Background(), "a", 1, 3); err != nil {
+			return err
+		}
+		return &models.ValidationError{Message: "abort"}
+	})
+
+	if versions, _ := repo.ListVersions(context.Background(), "a"); len(versions) != 4 {
+		t.Errorf("Expected squash to be rolled back, got %d versions", len(versions))
+	}
+}
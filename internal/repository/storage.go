@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StorageBackend names a ConfigRepository implementation
+type StorageBackend string
+
+const (
+	// StorageMemory keeps configurations in memory only; they are lost on restart
+	StorageMemory StorageBackend = "memory"
+	// StorageFile persists every configuration to its own file in a directory
+	StorageFile StorageBackend = "file"
+)
+
+// StorageConfig selects and configures the repository built by NewRepository
+type StorageConfig struct {
+	// Backend defaults to StorageMemory when empty
+	Backend StorageBackend
+	// Dir is the data directory of the file backend
+	Dir string
+	// Key encrypts files at rest when set; file backend only
+	Key []byte
+	// MigrateFiles rewrites plaintext and legacy encrypted files found in Dir
+	// with Key instead of refusing to load them; file backend only
+	MigrateFiles bool
+	// Strategy defaults to VersionStrategyAppend when empty
+	Strategy VersionStrategy
+	// MaxConfigs caps the number of stored configurations (0 is unlimited)
+	MaxConfigs int
+}
+
+// NewRepository constructs the repository cfg selects, rejecting options that
+// do not apply to the chosen backend rather than silently ignoring them
+func NewRepository(cfg StorageConfig) (ConfigRepository, error) {
+	if cfg.Strategy == "" {
+		cfg.Strategy = VersionStrategyAppend
+	}
+	if _, err := ParseVersionStrategy(string(cfg.Strategy)); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Backend {
+	case "", StorageMemory:
+		if cfg.Dir != "" || len(cfg.Key) > 0 || cfg.MigrateFiles {
+			return nil, errors.New("a storage directory, key and migration require the file backend")
+		}
+		repo := NewInMemoryRepositoryWithStrategy(cfg.Strategy)
+		repo.SetMaxConfigs(cfg.MaxConfigs)
+		return repo, nil
+	case StorageFile:
+		if cfg.Dir == "" {
+			return nil, errors.New("the file backend requires a storage directory")
+		}
+		newFileRepository := NewFileRepository
+		if cfg.MigrateFiles {
+			newFileRepository = NewFileRepositoryWithMigration
+		}
+		repo, err := newFileRepository(cfg.Dir, cfg.Key)
+		if err != nil {
+			return nil, err
+		}
+		repo.strategy = cfg.Strategy
+		repo.SetMaxConfigs(cfg.MaxConfigs)
+		return repo, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (want %q or %q)", cfg.Backend, StorageMemory, StorageFile)
+	}
+}
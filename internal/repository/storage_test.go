@@ -0,0 +1,68 @@
+package repository
+
+import "testing"
+
+func TestNewRepository(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		cfg     StorageConfig
+		check   func(ConfigRepository) bool
+		wantErr bool
+	}{
+		{
+			name: "default is memory",
+			cfg:  StorageConfig{},
+			check: func(repo ConfigRepository) bool {
+				r, ok := repo.(*InMemoryRepository)
+				return ok && r.strategy == VersionStrategyAppend
+			},
+		},
+		{
+			name: "memory",
+			cfg:  StorageConfig{Backend: StorageMemory, Strategy: VersionStrategyBranching, MaxConfigs: 3},
+			check: func(repo ConfigRepository) bool {
+				r, ok := repo.(*InMemoryRepository)
+				return ok && r.strategy == VersionStrategyBranching && r.maxConfigs == 3
+			},
+		},
+		{
+			name: "file",
+			cfg:  StorageConfig{Backend: StorageFile, Dir: dir, Strategy: VersionStrategyBranching},
+			check: func(repo ConfigRepository) bool {
+				r, ok := repo.(*FileRepository)
+				return ok && r.dir == dir && r.aead == nil && r.strategy == VersionStrategyBranching
+			},
+		},
+		{
+			name: "encrypted file",
+			cfg:  StorageConfig{Backend: StorageFile, Dir: dir, Key: []byte("secret")},
+			check: func(repo ConfigRepository) bool {
+				r, ok := repo.(*FileRepository)
+				return ok && r.aead != nil
+			},
+		},
+		{name: "unknown backend", cfg: StorageConfig{Backend: "redis"}, wantErr: true},
+		{name: "file without directory", cfg: StorageConfig{Backend: StorageFile}, wantErr: true},
+		{name: "memory with directory", cfg: StorageConfig{Backend: StorageMemory, Dir: dir}, wantErr: true},
+		{name: "unknown strategy", cfg: StorageConfig{Strategy: "rewind"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewRepository(tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %T", repo)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to create repository: %v", err)
+			}
+			if !tt.check(repo) {
+				t.Errorf("Unexpected repository %T", repo)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package repository
+
+import "fmt"
+
+// VersionStrategy controls how rollbacks number versions
+type VersionStrategy string
+
+const (
+	// VersionStrategyAppend records a rollback as a new version holding the
+	// target version's data, so version numbers only ever increase (v1, v2,
+	// rollback to v1 = v3)
+	VersionStrategyAppend VersionStrategy = "append"
+	// VersionStrategyBranching moves the head back to the target version
+	// without creating one (v1, v2, rollback to v1 = v1). The next update
+	// branches from the head with the next unused version number (v3).
+	VersionStrategyBranching VersionStrategy = "branching"
+)
+
+// ParseVersionStrategy parses a version strategy name
+func ParseVersionStrategy(name string) (VersionStrategy, error) {
+	switch strategy := VersionStrategy(name); strategy {
+	case VersionStrategyAppend, VersionStrategyBranching:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown version strategy %q (want %q or %q)", name, VersionStrategyAppend, VersionStrategyBranching)
+	}
+}
@@ -0,0 +1,427 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"config-engine/internal/models"
+)
+
+// WithTransaction runs fn against a transactional view of the repository.
+// All changes made through tx are applied atomically: if fn returns an error,
+// every affected configuration is restored to its state before the transaction.
+// Change events are only published once the transaction commits.
+func (r *InMemoryRepository) WithTransaction(ctx context.Context, fn func(tx ConfigRepository) error) error {
+	return r.transact(func(tx *inMemoryTx) error { return fn(tx) }, nil)
+}
+
+// transact runs fn like WithTransaction. Before the transaction commits,
+// commit is called with the sorted names of the configurations fn touched,
+// still under the lock; if it fails, the transaction is rolled back.
+func (r *InMemoryRepository) transact(fn func(tx *inMemoryTx) error, commit func(names []string) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx := &inMemoryTx{
+		repo:      r,
+		snapshots: make(map[string]*snapshot),
+	}
+
+	if err := fn(tx); err != nil {
+		tx.rollback()
+		return err
+	}
+	if commit != nil {
+		if err := commit(tx.touched()); err != nil {
+			tx.rollback()
+			return err
+		}
+	}
+
+	r.publish(tx.events...)
+	return nil
+}
+
+// snapshot holds the pre-transaction state of a single configuration
+type snapshot struct {
+	// entry is the configuration's entry before the transaction, restored
+	// on rollback so its read counts survive; nil if it did not exist
+	entry     *configEntry
+	config    *models.Config
+	versions  []models.ConfigVersion
+	tags      map[string]int
+	proposals map[string]models.Proposal
+	undo      *undoState
+}
+
+// inMemoryTx implements ConfigRepository on top of an InMemoryRepository whose
+// structure lock is held exclusively, so no entry is locked by anyone else
+type inMemoryTx struct {
+	repo      *InMemoryRepository
+	snapshots map[string]*snapshot
+	events    []models.ChangeEvent // pending until commit
+}
+
+// track snapshots a configuration before its first modification in the transaction
+func (tx *inMemoryTx) track(name string) {
+	if _, tracked := tx.snapshots[name]; tracked {
+		return
+	}
+
+	e, existed := tx.repo.entries[name]
+	if !existed {
+		tx.snapshots[name] = &snapshot{}
+		return
+	}
+	tx.snapshots[name] = &snapshot{
+		entry:     e,
+		config:    e.config,
+		versions:  e.versions,
+		tags:      e.tags,
+		proposals: e.proposals,
+		undo:      e.undo,
+	}
+}
+
+// touched returns the sorted names of the tracked configurations
+func (tx *inMemoryTx) touched() []string {
+	names := make([]string, 0, len(tx.snapshots))
+	for name := range tx.snapshots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rollback restores every tracked configuration to its snapshot
+func (tx *inMemoryTx) rollback() {
+	for name, snap := range tx.snapshots {
+		if current, exists := tx.repo.entries[name]; exists {
+			tx.repo.unindex(name, current.config.DependsOn)
+		}
+		if snap.entry == nil {
+			delete(tx.repo.entries, name)
+			continue
+		}
+		// Restore into the original entry, which the transaction may have
+		// deleted or replaced, keeping its reads and undo state
+		e := snap.entry
+		e.config = snap.config
+		e.versions = snap.versions
+		e.tags = snap.tags
+		e.proposals = snap.proposals
+		e.undo = snap.undo
+		tx.repo.entries[name] = e
+		tx.repo.index(name, snap.config.DependsOn)
+	}
+}
+
+// compact drops old version history of a configuration within the
+// transaction, adding what it removed to stats. A configuration compaction
+// leaves unchanged is not counted as touched.
+func (tx *inMemoryTx) compact(name string, keepVersions int, stats *models.CompactResponse) {
+	_, tracked := tx.snapshots[name]
+	tx.track(name)
+	compacted := stats.ConfigsCompacted
+	tx.repo.entries[name].compact(keepVersions, stats)
+	if !tracked && stats.ConfigsCompacted == compacted {
+		delete(tx.snapshots, name)
+	}
+}
+
+// replace swaps the entire state for entries within the transaction
+func (tx *inMemoryTx) replace(entries map[string]*configEntry) {
+	for name, e := range tx.repo.entries {
+		if _, kept := entries[name]; !kept {
+			tx.events = append(tx.events, newChangeEvent(models.ChangeDeleted, name, 0))
+		}
+		tx.track(name)
+		tx.repo.unindex(name, e.config.DependsOn)
+		delete(tx.repo.entries, name)
+	}
+	for name, e := range entries {
+		tx.track(name)
+		tx.repo.entries[name] = e
+		tx.repo.index(name, e.config.DependsOn)
+		tx.events = append(tx.events, newChangeEvent(models.ChangeUpdated, name, e.config.Version))
+	}
+}
+
+// Create creates a new configuration within the transaction
+func (tx *inMemoryTx) Create(ctx context.Context, config *models.Config) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.track(config.Name)
+	if err := tx.repo.create(config); err != nil {
+		return err
+	}
+	tx.events = append(tx.events, newChangeEvent(models.ChangeCreated, config.Name, config.Version))
+	return nil
+}
+
+// Get retrieves the latest version of a configuration within the transaction
+func (tx *inMemoryTx) Get(ctx context.Context, name string) (*models.Config, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tx.repo.get(name)
+}
+
+// GetMany retrieves configurations within the transaction
+func (tx *inMemoryTx) GetMany(ctx context.Context, names []string) (map[string]*models.Config, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tx.repo.getMany(ctx, names)
+}
+
+// Update updates an existing configuration within the transaction
+func (tx *inMemoryTx) Update(ctx context.Context, config *models.Config) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.track(config.Name)
+	if err := tx.repo.update(config); err != nil {
+		return err
+	}
+	tx.events = append(tx.events, newChangeEvent(models.ChangeUpdated, config.Name, config.Version))
+	return nil
+}
+
+// Rollback makes a previous version the head within the transaction
+func (tx *inMemoryTx) Rollback(ctx context.Context, config *models.Config, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.track(config.Name)
+	e, err := tx.repo.entry(config.Name)
+	if err != nil {
+		return err
+	}
+	if err := e.rollback(tx.repo, config, version); err != nil {
+		return err
+	}
+	tx.events = append(tx.events, newChangeEvent(models.ChangeUpdated, config.Name, config.Version))
+	return nil
+}
+
+// GetVersion retrieves a specific version of a configuration within the transaction
+func (tx *inMemoryTx) GetVersion(ctx context.Context, name string, version int) (*models.ConfigVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tx.repo.getVersion(name, version)
+}
+
+// ListVersions lists all versions of a configuration within the transaction
+func (tx *inMemoryTx) ListVersions(ctx context.Context, name string) ([]models.ConfigVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tx.repo.listVersions(ctx, name)
+}
+
+// SquashVersions collapses a range of versions within the transaction
+func (tx *inMemoryTx) SquashVersions(ctx context.Context, name string, from, to int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	tx.track(name)
+	return tx.repo.squashVersions(name, from, to)
+}
+
+// RevertWrite undoes the latest write of a configuration within the transaction
+func (tx *inMemoryTx) RevertWrite(ctx context.Context, name string, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.track(name)
+	event, err := tx.repo.revertWrite(name, version)
+	if err != nil {
+		return err
+	}
+	tx.events = append(tx.events, event)
+	return nil
+}
+
+// VersionAt retrieves the version active at a time within the transaction
+func (tx *inMemoryTx) VersionAt(ctx context.Context, name string, at time.Time) (*models.ConfigVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return tx.repo.versionAt(name, at)
+}
+
+// Exists checks if a configuration exists within the transaction
+func (tx *inMemoryTx) Exists(ctx context.Context, name string) bool {
+	return tx.repo.exists(name)
+}
+
+// ListNames returns the names of all configurations within the transaction
+func (tx *inMemoryTx) ListNames(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return tx.repo.listNames(), nil
+}
+
+// List returns a page of the configurations matching the filter within the transaction
+func (tx *inMemoryTx) List(ctx context.Context, filter models.ConfigFilter) ([]*models.Config, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	configs, total := tx.repo.list(filter)
+	return configs, total, nil
+}
+
+// Delete removes a configuration within the transaction
+func (tx *inMemoryTx) Delete(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.track(name)
+	if err := tx.repo.delete(name); err != nil {
+		return err
+	}
+	tx.events = append(tx.events, newChangeEvent(models.ChangeDeleted, name, 0))
+	return nil
+}
+
+// Rename moves a configuration within the transaction
+func (tx *inMemoryTx) Rename(ctx context.Context, oldName, newName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	dependents, err := tx.repo.listDependents(oldName)
+	if err != nil {
+		return err
+	}
+	tx.track(oldName)
+	tx.track(newName)
+	for _, dependent := range dependents {
+		tx.track(dependent)
+	}
+
+	events, err := tx.repo.rename(oldName, newName)
+	if err != nil {
+		return err
+	}
+	tx.events = append(tx.events, events...)
+	return nil
+}
+
+// ListDependents lists dependent configurations within the transaction
+func (tx *inMemoryTx) ListDependents(ctx context.Context, name string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tx.repo.listDependents(name)
+}
+
+// SetTag assigns a tag to a version within the transaction
+func (tx *inMemoryTx) SetTag(ctx context.Context, name, tag string, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.track(name)
+	return tx.repo.setTag(name, tag, version)
+}
+
+// SetLocked locks or unlocks a configuration within the transaction
+func (tx *inMemoryTx) SetLocked(ctx context.Context, name string, locked bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.track(name)
+	return tx.repo.setLocked(name, locked)
+}
+
+// SetDeprecation sets the deprecation of a configuration within the transaction
+func (tx *inMemoryTx) SetDeprecation(ctx context.Context, name string, deprecated bool, message string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.track(name)
+	return tx.repo.setDeprecation(name, deprecated, message)
+}
+
+// GetTag resolves a tag within the transaction
+func (tx *inMemoryTx) GetTag(ctx context.Context, name, tag string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return tx.repo.getTag(name, tag)
+}
+
+// SaveProposal stores a pending proposal within the transaction
+func (tx *inMemoryTx) SaveProposal(ctx context.Context, proposal *models.Proposal) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.track(proposal.Name)
+	return tx.repo.saveProposal(proposal)
+}
+
+// GetProposal retrieves a pending proposal within the transaction
+func (tx *inMemoryTx) GetProposal(ctx context.Context, name, id string) (*models.Proposal, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tx.repo.getProposal(name, id)
+}
+
+// ListProposals lists pending proposals within the transaction
+func (tx *inMemoryTx) ListProposals(ctx context.Context, name string) ([]models.Proposal, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tx.repo.listProposals(ctx, name)
+}
+
+// DeleteProposal removes a pending proposal within the transaction
+func (tx *inMemoryTx) DeleteProposal(ctx context.Context, name, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.track(name)
+	return tx.repo.deleteProposal(name, id)
+}
+
+// Ping checks backend connectivity
+func (tx *inMemoryTx) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WithTransaction runs fn within the current transaction (nested transactions are flattened)
+func (tx *inMemoryTx) WithTransaction(ctx context.Context, fn func(tx ConfigRepository) error) error {
+	return fn(tx)
+}
+
+// Validate that inMemoryTx implements ConfigRepository
+var _ ConfigRepository = (*inMemoryTx)(nil)
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"config-engine/internal/models"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestWithTransactionCommit(t *testing.T) {
+	repo := NewInMemoryRepository()
+
+	err := repo.WithTransaction(context.Background(), func(tx ConfigRepository) error {
+		if err := tx.Create(context.Background(), &models.Config{Name: "a", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}}); err != nil {
+			return err
+		}
+		return tx.Update(context.Background(), &models.Config{Name: "a", Type: "payment_config", Data: map[string]interface{}{"max_limit": 2}})
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+
+	config, err := repo.Get(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Failed to get config: %v", err)
+	}
+	if config.Version != 2 {
+		t.Errorf("Expected version 2, got %d", config.Version)
+	}
+}
+
+func TestWithTransactionRollback(t *testing.T) {
+	repo := NewInMemoryRepository()
+	repo.Create(context.Background(), &models.Config{Name: "existing", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}})
+
+	txErr := errors.New("boom")
+	err := repo.WithTransaction(context.Background(), func(tx ConfigRepository) error {
+		tx.Create(context.Background(), &models.Config{Name: "new", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}})
+		tx.Update(context.Background(), &models.Config{Name: "existing", Type: "payment_config", Data: map[string]interface{}{"max_limit": 2}})
+		return txErr
+	})
+	if err != txErr {
+		t.Fatalf("Expected transaction error, got %v", err)
+	}
+
+	if repo.Exists(context.Background(), "new") {
+		t.Error("Config created in failed transaction should not exist")
+	}
+
+	config, _ := repo.Get(context.Background(), "existing")
+	if config.Version != 1 {
+		t.Errorf("Expected version 1 after rollback, got %d", config.Version)
+	}
+	if config.Data["max_limit"].(int) != 1 {
+		t.Errorf("Expected max_limit 1 after rollback, got %v", config.Data["max_limit"])
+	}
+
+	versions, _ := repo.ListVersions(context.Background(), "existing")
+	if len(versions) != 1 {
+		t.Errorf("Expected 1 version after rollback, got %d", len(versions))
+	}
+}
+
+func TestWithTransactionRollbackKeepsReadsAndUndo(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryRepository()
+	repo.Create(ctx, &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}})
+	repo.Update(ctx, &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": 2}})
+	repo.Get(ctx, "payments")
+	repo.Get(ctx, "payments")
+
+	// Deleting and recreating replaces the entry before the rollback
+	txErr := errors.New("boom")
+	err := repo.WithTransaction(ctx, func(tx ConfigRepository) error {
+		if err := tx.Delete(ctx, "payments"); err != nil {
+			return err
+		}
+		if err := tx.Create(ctx, &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": 3}}); err != nil {
+			return err
+		}
+		return txErr
+	})
+	if err != txErr {
+		t.Fatalf("Expected transaction error, got %v", err)
+	}
+
+	usage, err := repo.Usage(ctx, "payments")
+	if err != nil {
+		t.Fatalf("Failed to get usage: %v", err)
+	}
+	if usage.Reads != 2 || usage.LastReadAt == nil {
+		t.Errorf("Expected the rollback to keep 2 reads and the last read, got %+v", usage)
+	}
+
+	// The update before the transaction can still be reverted
+	if err := repo.RevertWrite(ctx, "payments", 2); err != nil {
+		t.Fatalf("Expected the undo state to survive the rollback, got %v", err)
+	}
+	config, _ := repo.Get(ctx, "payments")
+	if config.Version != 1 {
+		t.Errorf("Expected version 1 after reverting, got %d", config.Version)
+	}
+}
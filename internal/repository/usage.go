@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"config-engine/internal/models"
+)
+
+// UsageReporter is implemented by repositories that count reads of each
+// configuration, to find stale configurations nobody reads
+type UsageReporter interface {
+	Usage(ctx context.Context, name string) (*models.ConfigStatsResponse, error)
+}
+
+const (
+	// readShards is the number of counters a config's reads are spread over
+	readShards = 4
+	// lastReadResolution is how stale a last read time may be; within it
+	// readers skip rewriting the shared timestamp
+	lastReadResolution = time.Millisecond
+)
+
+// readCounter counts reads of a configuration. Reads hold only read locks,
+// so the counter must be safe to update concurrently, and it must not
+// become the point where concurrent readers of a hot configuration
+// serialize: the count is spread over shards on separate cache lines and
+// the last read time is only written when it moves on by lastReadResolution.
+// Counters live on the entry, so they reset when a configuration is deleted
+// and recreated.
+type readCounter struct {
+	shards [readShards]struct {
+		n atomic.Int64
+		_ [56]byte // pad each shard to its own 64-byte cache line
+	}
+	// lastRead is in Unix nanoseconds (0 if never read)
+	lastRead atomic.Int64
+}
+
+// record counts a read
+func (c *readCounter) record() {
+	c.shards[rand.Uint32()%readShards].n.Add(1)
+
+	now := time.Now().UnixNano()
+	if now-c.lastRead.Load() >= int64(lastReadResolution) {
+		c.lastRead.Store(now)
+	}
+}
+
+// total returns the number of reads counted
+func (c *readCounter) total() int64 {
+	var total int64
+	for i := range c.shards {
+		total += c.shards[i].n.Load()
+	}
+	return total
+}
+
+// Usage returns the read count, last read time and number of retained
+// versions of a configuration. Reading the statistics is not a read.
+func (r *InMemoryRepository) Usage(ctx context.Context, name string) (*models.ConfigStatsResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, err := r.entry(name)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	stats := &models.ConfigStatsResponse{
+		Name:         name,
+		Reads:        e.reads.total(),
+		VersionCount: len(e.versions),
+	}
+	if lastRead := e.reads.lastRead.Load(); lastRead != 0 {
+		t := time.Unix(0, lastRead).UTC()
+		stats.LastReadAt = &t
+	}
+	return stats, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"config-engine/internal/models"
+)
+
+func TestUsage(t *testing.T) {
+	repo := NewInMemoryRepository()
+	repo.Create(context.Background(), &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1}})
+	repo.Update(context.Background(), &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": 2}})
+
+	stats, err := repo.Usage(context.Background(), "payments")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.Reads != 0 || stats.LastReadAt != nil || stats.VersionCount != 2 {
+		t.Fatalf("Expected no reads and 2 versions, got %+v", stats)
+	}
+
+	before := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repo.Get(context.Background(), "payments")
+		}()
+	}
+	wg.Wait()
+	repo.GetVersion(context.Background(), "payments", 1)
+	// Failed reads are not counted
+	repo.GetVersion(context.Background(), "payments", 9)
+
+	stats, _ = repo.Usage(context.Background(), "payments")
+	if stats.Reads != 11 {
+		t.Errorf("Expected 11 reads, got %d", stats.Reads)
+	}
+	if stats.LastReadAt == nil || stats.LastReadAt.Before(before) {
+		t.Errorf("Expected last read after %v, got %v", before, stats.LastReadAt)
+	}
+
+	// Usage itself is not a read
+	again, _ := repo.Usage(context.Background(), "payments")
+	if again.Reads != stats.Reads {
+		t.Errorf("Expected reading stats not to count, got %d", again.Reads)
+	}
+
+	if _, err := repo.Usage(context.Background(), "missing"); err == nil {
+		t.Error("Expected an error for a missing config")
+	}
+}
+
+// BenchmarkGetReadCounting compares parallel reads of one hot config with and
+// without read counting; run with -cpu to see how contention scales
+func BenchmarkGetReadCounting(b *testing.B) {
+	ctx := context.Background()
+	repo := NewInMemoryRepository()
+	repo.Create(ctx, &models.Config{Name: "payments", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1, "enabled": true}})
+
+	b.Run("uncounted", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				// Get without counting the read
+				repo.mu.RLock()
+				repo.get("payments")
+				repo.mu.RUnlock()
+			}
+		})
+	})
+	b.Run("counted", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				repo.Get(ctx, "payments")
+			}
+		})
+	})
+}
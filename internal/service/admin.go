@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+)
+
+// Compact reclaims version history beyond the retention policy.
+// keepVersions overrides the configured RetainVersions when greater than 0.
+func (s *ConfigService) Compact(ctx context.Context, keepVersions int) (*models.CompactResponse, error) {
+	if keepVersions < 0 {
+		return nil, &models.ValidationError{Field: "keep_versions", Message: "keep_versions must not be negative"}
+	}
+	if keepVersions == 0 {
+		keepVersions = s.opts.RetainVersions
+	}
+
+	compactor, ok := s.repo.(repository.Compactor)
+	if !ok {
+		return nil, errors.New("repository does not support compaction")
+	}
+	return compactor.Compact(ctx, keepVersions)
+}
+
+// Capacity returns the number of stored configurations and the repository's
+// cap on them; the cap is 0 when unlimited or not supported
+func (s *ConfigService) Capacity(ctx context.Context) (used, limit int, err error) {
+	if reporter, ok := s.repo.(repository.CapacityReporter); ok {
+		return reporter.Capacity(ctx)
+	}
+	names, err := s.repo.ListNames(ctx)
+	return len(names), 0, err
+}
+
+// Snapshot captures the entire repository state for backups
+func (s *ConfigService) Snapshot(ctx context.Context) ([]byte, error) {
+	snapshotter, ok := s.repo.(repository.Snapshotter)
+	if !ok {
+		return nil, errors.New("repository does not support snapshots")
+	}
+	return snapshotter.Snapshot(ctx)
+}
+
+// Restore replaces the entire repository state with a snapshot taken by
+// Snapshot. Inconsistent snapshots are rejected and leave the state untouched.
+func (s *ConfigService) Restore(ctx context.Context, data []byte) (*models.RestoreResponse, error) {
+	snapshotter, ok := s.repo.(repository.Snapshotter)
+	if !ok {
+		return nil, errors.New("repository does not support snapshots")
+	}
+	restored, err := snapshotter.Restore(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RestoreResponse{ConfigsRestored: restored}, nil
+}
+
+// ValidationStats reports how often validation failed per config type and field
+func (s *ConfigService) ValidationStats() *models.ValidationStatsResponse {
+	return &models.ValidationStatsResponse{Types: s.validator.FailureStats()}
+}
+
+// Verify recomputes the checksum of every stored version and reports those
+// whose data no longer matches, catching corruption of the stored data.
+// Configurations deleted while verifying are skipped.
+func (s *ConfigService) Verify(ctx context.Context) (*models.VerifyResponse, error) {
+	names, err := s.repo.ListNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.VerifyResponse{Mismatches: []models.ChecksumMismatch{}}
+	for _, name := range names {
+		versions, err := s.repo.ListVersions(ctx, name)
+		var notFound *models.ConfigNotFoundError
+		if errors.As(err, &notFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		resp.ConfigsChecked++
+		for _, version := range versions {
+			resp.VersionsChecked++
+			if version.Checksum == "" {
+				resp.Unverified++
+				continue
+			}
+			if actual := models.DataChecksum(version.Data); actual != version.Checksum {
+				resp.Mismatches = append(resp.Mismatches, models.ChecksumMismatch{
+					Name:     name,
+					Version:  version.Version,
+					Expected: version.Checksum,
+					Actual:   actual,
+				})
+			}
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"config-engine/internal/models"
+)
+
+// CompareConfigs diffs the current data of two configurations of the same
+// type, such as the staging and production copies of a config, to spot drift.
+// With redact set, values of secret fields in the changes are masked; the
+// diff is still computed on the real values so secret drift is reported.
+func (s *ConfigService) CompareConfigs(ctx context.Context, a, b string, redact bool) (*models.VersionDiff, error) {
+	from, err := s.GetConfig(ctx, a, nil)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.GetConfig(ctx, b, nil)
+	if err != nil {
+		return nil, err
+	}
+	if from.Type != to.Type {
+		return nil, &models.ConfigTypeMismatchError{A: from.Name, B: to.Name, TypeA: from.Type, TypeB: to.Type}
+	}
+
+	return s.diffConfigs(from.Name, to.Name, from, to, redact), nil
+}
+
+// DiffVersions diffs the data of two versions of one configuration. Each side
+// is labelled name@version. Redaction works as in CompareConfigs.
+func (s *ConfigService) DiffVersions(ctx context.Context, name string, fromVersion, toVersion int, redact bool) (*models.VersionDiff, error) {
+	from, to, err := s.versionPair(ctx, name, fromVersion, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return s.diffConfigs(versionLabel(from), versionLabel(to), from, to, redact), nil
+}
+
+// DiffVersionsText renders the difference between two versions of one
+// configuration as a unified diff of their indented JSON. With redact set,
+// secret fields are masked before rendering, so a change to only a secret
+// value shows no changed lines.
+func (s *ConfigService) DiffVersionsText(ctx context.Context, name string, fromVersion, toVersion int, redact bool) (string, error) {
+	from, to, err := s.versionPair(ctx, name, fromVersion, toVersion)
+	if err != nil {
+		return "", err
+	}
+	if redact {
+		from, to = s.RedactConfig(from), s.RedactConfig(to)
+	}
+	return models.UnifiedDiff(versionLabel(from), versionLabel(to), from.Data, to.Data)
+}
+
+// versionPair reads two versions of a configuration
+func (s *ConfigService) versionPair(ctx context.Context, name string, fromVersion, toVersion int) (*models.Config, *models.Config, error) {
+	from, err := s.GetConfig(ctx, name, &fromVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := s.GetConfig(ctx, name, &toVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	return from, to, nil
+}
+
+// diffConfigs diffs the data of two configurations of the same type
+func (s *ConfigService) diffConfigs(fromLabel, toLabel string, from, to *models.Config, redact bool) *models.VersionDiff {
+	changes := models.DiffData(from.Data, to.Data)
+	if redact {
+		redactedFrom := s.validator.Redact(from.Type, from.Data)
+		redactedTo := s.validator.Redact(to.Type, to.Data)
+		for i := range changes {
+			if changes[i].From != nil {
+				changes[i].From, _ = models.LookupPointer(redactedFrom, changes[i].Path)
+			}
+			if changes[i].To != nil {
+				changes[i].To, _ = models.LookupPointer(redactedTo, changes[i].Path)
+			}
+		}
+	}
+
+	return &models.VersionDiff{
+		From:      fromLabel,
+		To:        toLabel,
+		Type:      from.Type,
+		Identical: len(changes) == 0,
+		Changes:   changes,
+	}
+}
+
+// versionLabel names a version of a configuration as name@version
+func versionLabel(config *models.Config) string {
+	return fmt.Sprintf("%s@%d", config.Name, config.Version)
+}
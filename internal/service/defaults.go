@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"config-engine/internal/models"
+)
+
+// SetTypeDefault registers the data a get of a missing configuration returns,
+// as version 0, when the caller asks for configType's default. The data must
+// satisfy the type's schema. Nil data removes the default.
+func (s *ConfigService) SetTypeDefault(configType string, data map[string]interface{}) error {
+	if !s.validator.HasSchema(configType) {
+		return &models.SchemaNotFoundError{Type: configType}
+	}
+	if data != nil {
+		if err := s.checkData(configType, data, s.validator.Check); err != nil {
+			return err
+		}
+	}
+
+	s.defaultsMu.Lock()
+	defer s.defaultsMu.Unlock()
+
+	if data == nil {
+		delete(s.typeDefaults, configType)
+	} else {
+		s.typeDefaults[configType] = models.CopyData(data)
+	}
+	return nil
+}
+
+// LoadTypeDefaults registers the defaults in a JSON file mapping config types
+// to their default data, returning the types loaded. Nothing is registered if
+// any default is invalid.
+func (s *ConfigService) LoadTypeDefaults(path string) ([]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var defaults map[string]map[string]interface{}
+	if err := models.DecodeJSON(raw, &defaults); err != nil {
+		return nil, fmt.Errorf("invalid type defaults file %s: %v", path, err)
+	}
+
+	types := make([]string, 0, len(defaults))
+	for configType, data := range defaults {
+		if data == nil {
+			return nil, fmt.Errorf("default for %s must be an object", configType)
+		}
+		if !s.validator.HasSchema(configType) {
+			return nil, &models.SchemaNotFoundError{Type: configType}
+		}
+		if err := s.checkData(configType, data, s.validator.Check); err != nil {
+			return nil, fmt.Errorf("default for %s: %w", configType, err)
+		}
+		types = append(types, configType)
+	}
+	sort.Strings(types)
+
+	for _, configType := range types {
+		s.SetTypeDefault(configType, defaults[configType])
+	}
+	return types, nil
+}
+
+// GetConfigOrDefault returns the latest configuration like GetConfig. When it
+// does not exist and defaultType has a registered default, it returns that
+// data as version 0 of a configuration of defaultType instead of the
+// ConfigNotFoundError, for clients that always expect a value.
+func (s *ConfigService) GetConfigOrDefault(ctx context.Context, name, defaultType string) (*models.Config, error) {
+	config, err := s.GetConfig(ctx, name, nil)
+	if _, notFound := err.(*models.ConfigNotFoundError); !notFound {
+		return config, err
+	}
+
+	s.defaultsMu.RLock()
+	data, exists := s.typeDefaults[defaultType]
+	s.defaultsMu.RUnlock()
+	if !exists {
+		return nil, err
+	}
+
+	return &models.Config{
+		Name:    s.normalizeName(name),
+		Type:    defaultType,
+		Version: 0,
+		Data:    models.CopyData(data),
+	}, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"config-engine/internal/models"
+)
+
+// DeprecateConfig marks a configuration as deprecated, or lifts the mark when
+// req.Deprecated is false. Deprecation does not create a new version.
+func (s *ConfigService) DeprecateConfig(ctx context.Context, name string, req *models.DeprecationRequest) (*models.Config, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	if err := s.repo.SetDeprecation(ctx, name, req.Deprecated, req.Message); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Get(ctx, name)
+}
+
+// DeprecateType marks every configuration of a type as deprecated, or lifts
+// the mark when req.Deprecated is false
+func (s *ConfigService) DeprecateType(configType string, req *models.DeprecationRequest) (*models.TypeDeprecationResponse, error) {
+	if !s.validator.HasSchema(configType) {
+		return nil, &models.SchemaNotFoundError{Type: configType}
+	}
+
+	s.deprecationsMu.Lock()
+	defer s.deprecationsMu.Unlock()
+
+	resp := &models.TypeDeprecationResponse{Type: configType, Deprecated: req.Deprecated}
+	if req.Deprecated {
+		s.typeDeprecations[configType] = req.Message
+		resp.Message = req.Message
+	} else {
+		delete(s.typeDeprecations, configType)
+	}
+	return resp, nil
+}
+
+// DeprecationWarnings returns the warnings readers of a configuration should
+// see: one if the configuration is deprecated and one if its type is
+func (s *ConfigService) DeprecationWarnings(config *models.Config) []string {
+	var warnings []string
+	if config.Deprecated {
+		message := config.DeprecationMessage
+		if message == "" {
+			message = fmt.Sprintf("config %q is deprecated", config.Name)
+		}
+		warnings = append(warnings, message)
+	}
+
+	s.deprecationsMu.RLock()
+	message, deprecated := s.typeDeprecations[config.Type]
+	s.deprecationsMu.RUnlock()
+	if deprecated {
+		if message == "" {
+			message = fmt.Sprintf("config type %q is deprecated", config.Type)
+		}
+		warnings = append(warnings, message)
+	}
+	return warnings
+}
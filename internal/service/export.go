@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"config-engine/internal/models"
+)
+
+// ListConfigNames returns the names of all configurations in sorted order
+func (s *ConfigService) ListConfigNames(ctx context.Context) ([]string, error) {
+	return s.repo.ListNames(ctx)
+}
+
+// ExportConfig reads a configuration and its full version history. Each
+// configuration is read on its own, so an export of many is not a single
+// point in time; use a repository snapshot for that.
+func (s *ConfigService) ExportConfig(ctx context.Context, name string, redact bool) (*models.ExportRecord, error) {
+	config, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := s.repo.ListVersions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if redact {
+		config = s.RedactConfig(config)
+		for i := range versions {
+			versions[i].Data = s.validator.Redact(config.Type, versions[i].Data)
+		}
+	}
+	return &models.ExportRecord{Config: config, Versions: versions}, nil
+}
+
+// ExportConfigs reads every configuration with its history into memory.
+// Configurations deleted while the export runs are skipped.
+func (s *ConfigService) ExportConfigs(ctx context.Context, redact bool) (*models.ExportResponse, error) {
+	names, err := s.ListConfigNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.ExportResponse{Configs: make([]models.ExportRecord, 0, len(names))}
+	for _, name := range names {
+		record, err := s.ExportConfig(ctx, name, redact)
+		var notFound *models.ConfigNotFoundError
+		if errors.As(err, &notFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp.Configs = append(resp.Configs, *record)
+	}
+	return resp, nil
+}
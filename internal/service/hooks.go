@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"config-engine/internal/models"
+)
+
+// Hook holds callbacks run after a successful write of a config type.
+// Either callback may be nil. Rollbacks and approved proposals count as updates.
+type Hook struct {
+	OnCreate func(config *models.Config) error
+	OnUpdate func(config *models.Config) error
+	// Critical lets the hook undo the write by returning a RevertError
+	Critical bool
+}
+
+// RevertError is returned by a critical hook to have the write it observed
+// undone. The configuration is restored to its state before the write and
+// the caller receives a HookError.
+type RevertError struct {
+	Err error
+}
+
+func (e *RevertError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RevertError) Unwrap() error {
+	return e.Err
+}
+
+// hookEvent selects which Hook callback to run
+type hookEvent int
+
+const (
+	hookCreate hookEvent = iota
+	hookUpdate
+)
+
+// RegisterHook registers a hook for a config type. Hooks run synchronously in
+// registration order once the write has been stored. A failing hook is logged
+// and, only when Options.FailOnHookError is set, reported to the caller; the
+// write itself is only undone when a critical hook returns a RevertError.
+// A write that stays stored is returned along with the HookError (see
+// WriteCommitted).
+func (s *ConfigService) RegisterHook(configType string, hook Hook) {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+
+	s.hooks[configType] = append(s.hooks[configType], hook)
+}
+
+// WriteCommitted reports whether a write that returned err was stored all the
+// same because only a post-write hook failed without undoing it. Write
+// methods return their result along with such an error.
+func WriteCommitted(err error) bool {
+	var hookErr *models.HookError
+	return errors.As(err, &hookErr) && !hookErr.Reverted
+}
+
+// written returns the result of a stored write whose hooks returned err: the
+// config along with an error that left it stored, or only the error
+func written(config *models.Config, err error) (*models.Config, error) {
+	if err != nil && !WriteCommitted(err) {
+		return nil, err
+	}
+	return config, err
+}
+
+// runHooks invokes the hooks registered for the config's type. A revert
+// requested by a critical hook stops the remaining hooks and is always
+// reported to the caller.
+func (s *ConfigService) runHooks(ctx context.Context, event hookEvent, config *models.Config) error {
+	s.hooksMu.RLock()
+	hooks := s.hooks[config.Type]
+	s.hooksMu.RUnlock()
+
+	var firstErr error
+	for _, hook := range hooks {
+		fn := hook.OnUpdate
+		if event == hookCreate {
+			fn = hook.OnCreate
+		}
+		if fn == nil {
+			continue
+		}
+
+		err := fn(config)
+		if err == nil {
+			continue
+		}
+		s.logger.Printf("Hook for %s failed on %s: %v", config.Type, config.Name, err)
+
+		var revert *RevertError
+		if hook.Critical && errors.As(err, &revert) {
+			if revertErr := s.repo.RevertWrite(ctx, config.Name, config.Version); revertErr != nil {
+				s.logger.Printf("Failed to revert version %d of %s: %v", config.Version, config.Name, revertErr)
+				return &models.HookError{Name: config.Name, Type: config.Type, Err: err}
+			}
+			return &models.HookError{Name: config.Name, Type: config.Type, Err: err, Reverted: true}
+		}
+		if firstErr == nil {
+			firstErr = &models.HookError{Name: config.Name, Type: config.Type, Err: err}
+		}
+	}
+
+	if s.opts.FailOnHookError {
+		return firstErr
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/validation"
+)
+
+func TestHooksFireOnWrites(t *testing.T) {
+	svc := setupService(t)
+
+	var calls []string
+	svc.RegisterHook("payment_config", Hook{
+		OnCreate: func(config *models.Config) error {
+			calls = append(calls, "create:"+config.Name+":"+config.Type)
+			return nil
+		},
+		OnUpdate: func(config *models.Config) error {
+			calls = append(calls, "update:"+config.Name+":"+config.Type)
+			return nil
+		},
+	})
+	svc.RegisterHook("payment_config", Hook{
+		OnUpdate: func(config *models.Config) error {
+			calls = append(calls, "second")
+			return nil
+		},
+	})
+	svc.RegisterHook("other_config", Hook{
+		OnCreate: func(config *models.Config) error {
+			t.Error("Hook for another type should not fire")
+			return nil
+		},
+	})
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	})
+	svc.UpdateConfig(context.Background(), "payments", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 200, "enabled": true},
+	})
+	svc.RollbackConfig(context.Background(), "payments", &models.RollbackRequest{Version: 1})
+
+	expected := []string{
+		"create:payments:payment_config",
+		"update:payments:payment_config", "second",
+		"update:payments:payment_config", "second",
+	}
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("Expected call %d to be %s, got %s", i, expected[i], calls[i])
+		}
+	}
+}
+
+func TestHookReceivesStoredConfig(t *testing.T) {
+	svc := setupService(t)
+
+	var got *models.Config
+	svc.RegisterHook("payment_config", Hook{
+		OnUpdate: func(config *models.Config) error {
+			got = config
+			return nil
+		},
+	})
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	})
+	svc.UpdateConfig(context.Background(), "payments", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 200, "enabled": true},
+	})
+
+	if got == nil || got.Version != 2 || got.Data["max_limit"] != 200 {
+		t.Errorf("Expected hook to receive version 2 with max_limit 200, got %+v", got)
+	}
+}
+
+func TestHookErrors(t *testing.T) {
+	failing := Hook{OnCreate: func(*models.Config) error { return errors.New("cache unavailable") }}
+	create := &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	}
+
+	// By default hook failures are only logged
+	validator, _ := validation.NewValidator()
+	opts := DefaultOptions()
+	opts.Logger = log.New(io.Discard, "", 0)
+	svc := NewConfigServiceWithOptions(repository.NewInMemoryRepository(), validator, opts)
+	svc.RegisterHook("payment_config", failing)
+	if _, err := svc.CreateConfig(context.Background(), create); err != nil {
+		t.Errorf("Expected hook failure to be ignored, got %v", err)
+	}
+
+	opts.FailOnHookError = true
+	repo := repository.NewInMemoryRepository()
+	svc = NewConfigServiceWithOptions(repo, validator, opts)
+	svc.RegisterHook("payment_config", failing)
+	config, err := svc.CreateConfig(context.Background(), create)
+	var hookErr *models.HookError
+	if !errors.As(err, &hookErr) {
+		t.Fatalf("Expected HookError, got %v", err)
+	}
+	if !repo.Exists(context.Background(), "payments") {
+		t.Error("Expected the write to be kept despite the hook failure")
+	}
+	if !WriteCommitted(err) || config == nil || config.Version != 1 {
+		t.Errorf("Expected the stored write to be returned with the error, got %+v", config)
+	}
+}
+
+func TestCriticalHookRevertsWrite(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	opts := DefaultOptions()
+	opts.Logger = log.New(io.Discard, "", 0)
+	repo := repository.NewInMemoryRepository()
+	svc := NewConfigServiceWithOptions(repo, validator, opts)
+	ctx := context.Background()
+
+	reject := false
+	svc.RegisterHook("payment_config", Hook{
+		OnCreate: func(*models.Config) error {
+			if reject {
+				return &RevertError{Err: errors.New("downstream rejected")}
+			}
+			return nil
+		},
+		OnUpdate: func(*models.Config) error {
+			return &RevertError{Err: errors.New("downstream rejected")}
+		},
+		Critical: true,
+	})
+
+	if _, err := svc.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	}); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	_, err := svc.UpdateConfig(ctx, "payments", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 200, "enabled": true},
+	})
+	var hookErr *models.HookError
+	if !errors.As(err, &hookErr) || !hookErr.Reverted {
+		t.Fatalf("Expected a reverted HookError, got %v", err)
+	}
+
+	versions, _ := repo.ListVersions(ctx, "payments")
+	if len(versions) != 1 {
+		t.Errorf("Expected the version count to stay at 1, got %d", len(versions))
+	}
+	config, _ := repo.Get(ctx, "payments")
+	if config.Version != 1 || config.Data["max_limit"] != 100 {
+		t.Errorf("Expected version 1 with max_limit 100, got version %d with %v", config.Version, config.Data["max_limit"])
+	}
+
+	// A reverted create leaves no configuration behind
+	reject = true
+	_, err = svc.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name: "refunds",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	})
+	if !errors.As(err, &hookErr) || !hookErr.Reverted {
+		t.Fatalf("Expected a reverted HookError, got %v", err)
+	}
+	if repo.Exists(ctx, "refunds") {
+		t.Error("Expected the reverted create to leave no configuration")
+	}
+}
+
+func TestRevertRequiresCriticalHook(t *testing.T) {
+	svc := setupService(t)
+	svc.RegisterHook("payment_config", Hook{
+		OnCreate: func(*models.Config) error {
+			return &RevertError{Err: errors.New("downstream rejected")}
+		},
+	})
+
+	if _, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	}); err != nil {
+		t.Fatalf("Expected a non-critical hook failure to be ignored, got %v", err)
+	}
+	if !svc.repo.Exists(context.Background(), "payments") {
+		t.Error("Expected a non-critical hook to leave the write in place")
+	}
+}
+
+func TestWebhookDeliveriesAreSigned(t *testing.T) {
+	svc := setupService(t)
+
+	var payloads []models.WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !models.VerifyWebhookSignature("s3cret", body, r.Header.Get(models.WebhookSignatureHeader)) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		var payload models.WebhookPayload
+		json.Unmarshal(body, &payload)
+		payloads = append(payloads, payload)
+	}))
+	defer server.Close()
+
+	if err := svc.RegisterWebhook("payment_config", Webhook{URL: server.URL}); err == nil {
+		t.Error("Expected a webhook without a secret to be rejected")
+	}
+	if err := svc.RegisterWebhook("payment_config", Webhook{URL: "ftp://example.com", Secret: "s3cret"}); err == nil {
+		t.Error("Expected a non-HTTP webhook URL to be rejected")
+	}
+	if err := svc.RegisterWebhook("payment_config", Webhook{URL: server.URL, Secret: "s3cret"}); err != nil {
+		t.Fatalf("Failed to register webhook: %v", err)
+	}
+
+	ctx := context.Background()
+	svc.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	})
+	svc.UpdateConfig(ctx, "payments", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 200, "enabled": true},
+	})
+
+	if len(payloads) != 2 || payloads[0].Event != models.WebhookEventCreate || payloads[1].Event != models.WebhookEventUpdate {
+		t.Fatalf("Expected verified create and update deliveries, got %+v", payloads)
+	}
+	if payloads[1].Config.Name != "payments" || payloads[1].Config.Version != 2 {
+		t.Errorf("Expected version 2 of payments, got %+v", payloads[1].Config)
+	}
+
+	// A receiver that rejects the signature fails the delivery like any hook
+	failing := NewConfigServiceWithOptions(repository.NewInMemoryRepository(), svc.validator, Options{FailOnHookError: true, Logger: log.New(io.Discard, "", 0)})
+	failing.RegisterWebhook("payment_config", Webhook{URL: server.URL, Secret: "wrong"})
+	var hookErr *models.HookError
+	if _, err := failing.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	}); !errors.As(err, &hookErr) {
+		t.Errorf("Expected a HookError for a rejected delivery, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+)
+
+// ImportConfigs imports the latest data of each record. New configurations
+// are created; req.Mode decides what happens to existing ones:
+//
+//   - fail aborts the import
+//   - skip keeps the existing configuration
+//   - overwrite stores the imported data as a new version
+//   - merge deep merges the imported data into the existing data and stores
+//     the result as a new version. The imported value wins for every key
+//     present in both; nested objects are merged key by key and an imported
+//     null removes the key, as in a merge patch.
+//
+// Every write is validated against the type's schema like an API write and
+// recorded with OriginImport. The import is atomic: if any record fails,
+// nothing is imported.
+func (s *ConfigService) ImportConfigs(ctx context.Context, req *models.ImportRequest) (*models.ImportResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if s.opts.MaxBatchNames > 0 && len(req.Configs) > s.opts.MaxBatchNames {
+		return nil, &models.ValidationError{
+			Field:   "configs",
+			Message: fmt.Sprintf("%d configs exceeds maximum of %d", len(req.Configs), s.opts.MaxBatchNames),
+		}
+	}
+	mode := req.Mode
+	if mode == "" {
+		mode = models.ImportFail
+	}
+
+	resp := &models.ImportResponse{Results: make([]models.ImportResult, 0, len(req.Configs))}
+	var written []*models.Config
+	var events []hookEvent
+	err := s.repo.WithTransaction(ctx, func(tx repository.ConfigRepository) error {
+		for i, record := range req.Configs {
+			result, config, err := s.importConfig(ctx, tx, record.Config, mode, req.Actor)
+			if err != nil {
+				return &models.TransactionError{Index: i, Err: err}
+			}
+			resp.Results = append(resp.Results, result)
+			if config != nil {
+				event := hookUpdate
+				if result.Result == models.ImportCreated {
+					event = hookCreate
+				}
+				written = append(written, config)
+				events = append(events, event)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Hooks only see committed writes
+	var hookErr error
+	for i, config := range written {
+		if err := s.afterWrite(ctx, events[i], config); err != nil {
+			if !WriteCommitted(err) {
+				return nil, err
+			}
+			if hookErr == nil {
+				hookErr = err
+			}
+		}
+	}
+
+	return resp, hookErr
+}
+
+// importConfig imports a single configuration into the given repository,
+// returning the stored configuration or nil if it was skipped
+func (s *ConfigService) importConfig(ctx context.Context, repo repository.ConfigRepository, imported *models.Config, mode models.ImportMode, actor string) (models.ImportResult, *models.Config, error) {
+	name := s.normalizeName(imported.Name)
+	result := models.ImportResult{Name: name}
+
+	if !repo.Exists(ctx, name) {
+		config, err := s.createConfig(ctx, repo, &models.CreateConfigRequest{
+			Name:      name,
+			Type:      imported.Type,
+			Data:      imported.Data,
+			DependsOn: imported.DependsOn,
+			Actor:     actor,
+			Origin:    models.OriginImport,
+		})
+		if err != nil {
+			return result, nil, err
+		}
+		result.Result, result.Version = models.ImportCreated, config.Version
+		return result, config, nil
+	}
+
+	if mode == models.ImportFail {
+		return result, nil, &models.ConfigExistsError{Name: name}
+	}
+	if mode == models.ImportSkip {
+		result.Result = models.ImportSkipped
+		return result, nil, nil
+	}
+
+	existing, err := repo.Get(ctx, name)
+	if err != nil {
+		return result, nil, err
+	}
+	if existing.Type != imported.Type {
+		return result, nil, &models.ValidationError{
+			Field:   "type",
+			Message: fmt.Sprintf("%s has type %s, not %s", name, existing.Type, imported.Type),
+		}
+	}
+
+	data, outcome := imported.Data, models.ImportOverwritten
+	if mode == models.ImportMerge {
+		data, outcome = mergeDataPatch(existing.Data, imported.Data), models.ImportMerged
+	}
+	config, err := s.updateConfig(ctx, repo, name, &models.UpdateConfigRequest{
+		Data:      data,
+		DependsOn: imported.DependsOn,
+		Actor:     actor,
+		Origin:    models.OriginImport,
+	})
+	if err != nil {
+		return result, nil, err
+	}
+	result.Result, result.Version = outcome, config.Version
+	return result, config, nil
+}
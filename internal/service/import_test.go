@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"config-engine/internal/models"
+)
+
+func importRecord(name, configType string, data map[string]interface{}) models.ExportRecord {
+	return models.ExportRecord{Config: &models.Config{Name: name, Type: configType, Data: data}}
+}
+
+func TestImportMerge(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+	if err := svc.validator.RegisterSchema("service_config", map[string]interface{}{"type": "object"}); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+
+	base := map[string]interface{}{
+		"timeout": 30.0,
+		"retries": 3.0,
+		"tls":     map[string]interface{}{"enabled": true, "min_version": "1.2"},
+	}
+	if _, err := svc.ImportConfigs(ctx, &models.ImportRequest{
+		Configs: []models.ExportRecord{importRecord("gateway", "service_config", base)},
+	}); err != nil {
+		t.Fatalf("Failed to import base: %v", err)
+	}
+
+	// Layer production overrides onto the base
+	override := map[string]interface{}{
+		"timeout": 60.0,
+		"tls":     map[string]interface{}{"min_version": "1.3"},
+		"region":  "eu-west-1",
+	}
+	resp, err := svc.ImportConfigs(ctx, &models.ImportRequest{
+		Mode:    models.ImportMerge,
+		Configs: []models.ExportRecord{importRecord("gateway", "service_config", override)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to merge import: %v", err)
+	}
+	want := []models.ImportResult{{Name: "gateway", Result: models.ImportMerged, Version: 2}}
+	if !reflect.DeepEqual(resp.Results, want) {
+		t.Errorf("Expected results %+v, got %+v", want, resp.Results)
+	}
+
+	config, _ := svc.GetConfig(ctx, "gateway", nil)
+	merged := models.ConfigData{
+		"timeout": 60.0,
+		"retries": 3.0,
+		"tls":     map[string]interface{}{"enabled": true, "min_version": "1.3"},
+		"region":  "eu-west-1",
+	}
+	if !reflect.DeepEqual(config.Data, merged) {
+		t.Errorf("Expected merged data %v, got %v", merged, config.Data)
+	}
+	if config.Version != 2 || config.Origin != models.OriginImport {
+		t.Errorf("Expected an imported version 2, got version %d from %s", config.Version, config.Origin)
+	}
+}
+
+func TestImportMergeValidatesResult(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+	svc.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+
+	// The override alone is incomplete, but merged it is valid
+	resp, err := svc.ImportConfigs(ctx, &models.ImportRequest{
+		Mode:    models.ImportMerge,
+		Configs: []models.ExportRecord{importRecord("payments", "payment_config", map[string]interface{}{"max_limit": 5000})},
+	})
+	if err != nil {
+		t.Fatalf("Failed to merge import: %v", err)
+	}
+	if resp.Results[0].Version != 2 {
+		t.Errorf("Expected version 2, got %+v", resp.Results[0])
+	}
+
+	// A merged result that fails the schema imports nothing
+	_, err = svc.ImportConfigs(ctx, &models.ImportRequest{
+		Mode: models.ImportMerge,
+		Configs: []models.ExportRecord{
+			importRecord("refunds", "payment_config", map[string]interface{}{"max_limit": 10, "enabled": true}),
+			importRecord("payments", "payment_config", map[string]interface{}{"enabled": "yes"}),
+		},
+	})
+	var txErr *models.TransactionError
+	var schemaErr *models.SchemaValidationError
+	if !errors.As(err, &txErr) || txErr.Index != 1 || !errors.As(err, &schemaErr) {
+		t.Fatalf("Expected a schema validation error at index 1, got %v", err)
+	}
+	if svc.repo.Exists(ctx, "refunds") {
+		t.Error("Expected a failed import to import nothing")
+	}
+}
+
+func TestImportModes(t *testing.T) {
+	ctx := context.Background()
+	existing := importRecord("payments", "payment_config", map[string]interface{}{"max_limit": 2000, "enabled": false})
+	fresh := importRecord("refunds", "payment_config", map[string]interface{}{"max_limit": 10, "enabled": true})
+
+	tests := []struct {
+		mode        models.ImportMode
+		wantResults []models.ImportResult
+		wantLimit   interface{}
+		wantErr     error
+	}{
+		{mode: "", wantErr: &models.ConfigExistsError{}},
+		{mode: models.ImportFail, wantErr: &models.ConfigExistsError{}},
+		{
+			mode:        models.ImportSkip,
+			wantResults: []models.ImportResult{{Name: "refunds", Result: models.ImportCreated, Version: 1}, {Name: "payments", Result: models.ImportSkipped}},
+			wantLimit:   1000,
+		},
+		{
+			mode:        models.ImportOverwrite,
+			wantResults: []models.ImportResult{{Name: "refunds", Result: models.ImportCreated, Version: 1}, {Name: "payments", Result: models.ImportOverwritten, Version: 2}},
+			wantLimit:   2000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			svc := setupService(t)
+			svc.CreateConfig(ctx, &models.CreateConfigRequest{
+				Name: "payments",
+				Type: "payment_config",
+				Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+			})
+
+			resp, err := svc.ImportConfigs(ctx, &models.ImportRequest{Mode: tt.mode, Configs: []models.ExportRecord{fresh, existing}})
+			if tt.wantErr != nil {
+				var existsErr *models.ConfigExistsError
+				if !errors.As(err, &existsErr) {
+					t.Fatalf("Expected ConfigExistsError, got %v", err)
+				}
+				if svc.repo.Exists(ctx, "refunds") {
+					t.Error("Expected a failed import to import nothing")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to import: %v", err)
+			}
+			if !reflect.DeepEqual(resp.Results, tt.wantResults) {
+				t.Errorf("Expected results %+v, got %+v", tt.wantResults, resp.Results)
+			}
+			config, _ := svc.GetConfig(ctx, "payments", nil)
+			if config.Data["max_limit"] != tt.wantLimit {
+				t.Errorf("Expected max_limit %v, got %v", tt.wantLimit, config.Data["max_limit"])
+			}
+		})
+	}
+
+	svc := setupService(t)
+	_, err := svc.ImportConfigs(ctx, &models.ImportRequest{Mode: "replace", Configs: []models.ExportRecord{fresh}})
+	var validationErr *models.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "mode" {
+		t.Errorf("Expected a mode validation error, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"config-engine/internal/models"
+)
+
+const (
+	// DefaultMaxDataSize is the default maximum serialized size of config data in bytes
+	DefaultMaxDataSize = 1 << 20
+	// DefaultMaxDataDepth is the default maximum nesting depth of config data
+	DefaultMaxDataDepth = 32
+	// DefaultMaxBatchNames is the default maximum number of names in a batch get
+	DefaultMaxBatchNames = 100
+)
+
+// checkDataLimits enforces the configured size and nesting depth limits on
+// config data, and the key restrictions when enabled. Array data is measured
+// as the array it represents.
+func (s *ConfigService) checkDataLimits(data map[string]interface{}) error {
+	document := models.DataDocument(data)
+	if s.opts.RejectUnsafeKeys {
+		if key, path, found := unsafeKey(document, ""); found {
+			return &models.ValidationError{
+				Field:   "data",
+				Message: fmt.Sprintf("data key %q at %s must not contain '.' or control characters", key, path),
+			}
+		}
+	}
+
+	if s.opts.MaxDataDepth > 0 {
+		if depth := dataDepth(document); depth > s.opts.MaxDataDepth {
+			return &models.ValidationError{
+				Field:   "data",
+				Message: fmt.Sprintf("data nesting depth %d exceeds maximum of %d", depth, s.opts.MaxDataDepth),
+			}
+		}
+	}
+
+	if s.opts.MaxDataSize > 0 {
+		serialized, err := json.Marshal(document)
+		if err != nil {
+			return &models.ValidationError{Field: "data", Message: fmt.Sprintf("data is not serializable: %v", err)}
+		}
+		if len(serialized) > s.opts.MaxDataSize {
+			return &models.ValidationError{
+				Field:   "data",
+				Message: fmt.Sprintf("data size %d bytes exceeds maximum of %d bytes", len(serialized), s.opts.MaxDataSize),
+			}
+		}
+	}
+
+	return nil
+}
+
+// dataDepth returns the nesting depth of a value; scalars have depth 0
+func dataDepth(value interface{}) int {
+	maxChild := 0
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, child := range v {
+			if d := dataDepth(child); d > maxChild {
+				maxChild = d
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if d := dataDepth(child); d > maxChild {
+				maxChild = d
+			}
+		}
+	default:
+		return 0
+	}
+	return maxChild + 1
+}
+
+// unsafeKey finds an object key containing '.' or a control character,
+// returning it with its JSON Pointer location. Keys are checked in sorted
+// order so the same data always reports the same key.
+func unsafeKey(value interface{}, path string) (string, string, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			keyPath := path + "/" + models.EscapePointerToken(key)
+			if strings.ContainsFunc(key, func(r rune) bool { return r == '.' || unicode.IsControl(r) }) {
+				return key, keyPath, true
+			}
+			if key, keyPath, found := unsafeKey(v[key], keyPath); found {
+				return key, keyPath, true
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if key, keyPath, found := unsafeKey(item, path+"/"+strconv.Itoa(i)); found {
+				return key, keyPath, true
+			}
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"config-engine/internal/models"
+)
+
+// UnlockConfig lifts the lock on a configuration so it can be changed again.
+// An update with locked set restores the lock.
+func (s *ConfigService) UnlockConfig(ctx context.Context, name string) (*models.Config, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	if err := s.repo.SetLocked(ctx, name, false); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Get(ctx, name)
+}
+
+// checkUnlocked rejects changes to a locked configuration
+func checkUnlocked(config *models.Config) error {
+	if config.Locked {
+		return &models.ConfigLockedError{Name: config.Name}
+	}
+	return nil
+}
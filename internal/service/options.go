@@ -0,0 +1,86 @@
+package service
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"config-engine/internal/models"
+)
+
+// namePattern restricts config names to URL-safe identifiers
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Options configures optional ConfigService behaviour
+type Options struct {
+	// NormalizeNames lowercases and trims config names so that lookups are
+	// case-insensitive; disable it for strict, exact-match names
+	NormalizeNames bool
+	// MaxDataSize is the maximum serialized size of config data in bytes (0 disables the check)
+	MaxDataSize int
+	// MaxDataDepth is the maximum nesting depth of config data (0 disables the check)
+	MaxDataDepth int
+	// MaxBatchNames is the maximum number of names or items in a batch request,
+	// such as a batch get or batch validation (0 disables the check)
+	MaxBatchNames int
+	// RetainVersions is the number of versions per config kept by compaction (0 keeps all history)
+	RetainVersions int
+	// MaxVersionsPerConfig caps the number of versions a config may ever have;
+	// writes beyond it are rejected, unlike compaction which prunes (0 disables
+	// the cap). A type policy's VersionLimit overrides it.
+	MaxVersionsPerConfig int
+	// MaxWaitTimeout caps how long a client may wait for a change (0 disables the cap)
+	MaxWaitTimeout time.Duration
+	// RejectUnsafeKeys rejects data keys containing '.' or control characters,
+	// which dotted paths such as schema error fields and Lookup cannot address
+	RejectUnsafeKeys bool
+	// FailOnHookError reports hook failures to the caller instead of only
+	// logging them; writes a failing hook leaves stored are still returned
+	FailOnHookError bool
+	// Logger receives hook failures (defaults to the standard logger)
+	Logger *log.Logger
+}
+
+// DefaultOptions returns the options used by NewConfigService
+func DefaultOptions() Options {
+	return Options{
+		NormalizeNames: true,
+		MaxDataSize:    DefaultMaxDataSize,
+		MaxDataDepth:   DefaultMaxDataDepth,
+		MaxBatchNames:  DefaultMaxBatchNames,
+		MaxWaitTimeout: DefaultMaxWaitTimeout,
+	}
+}
+
+// normalizeName applies name normalization when enabled
+func (s *ConfigService) normalizeName(name string) string {
+	if !s.opts.NormalizeNames {
+		return name
+	}
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// normalizeNames applies name normalization to a list of names
+func (s *ConfigService) normalizeNames(names []string) []string {
+	if names == nil {
+		return nil
+	}
+
+	normalized := make([]string, len(names))
+	for i, name := range names {
+		normalized[i] = s.normalizeName(name)
+	}
+	return normalized
+}
+
+// validateName checks a config name against the allowed pattern
+func validateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return &models.ValidationError{
+			Field:   "name",
+			Message: "name may only contain letters, digits, underscores and hyphens",
+		}
+	}
+	return nil
+}
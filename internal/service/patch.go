@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+)
+
+// PatchConfig applies req.Data as a JSON merge patch (RFC 7396) to the
+// latest data of a configuration: keys in the patch replace existing keys,
+// nested objects are merged and null removes a key. An array patch replaces
+// the data outright. The merged document, not the patch, is validated against
+// the schema, so required fields that are already present need not be
+// repeated. The read and write happen in one transaction so concurrent
+// updates cannot be lost.
+func (s *ConfigService) PatchConfig(ctx context.Context, name string, req *models.UpdateConfigRequest) (*models.Config, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	var config *models.Config
+	err := s.repo.WithTransaction(ctx, func(tx repository.ConfigRepository) error {
+		existing, err := tx.Get(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		merged := *req
+		merged.Data = mergeDataPatch(existing.Data, req.Data)
+		config, err = s.updateConfig(ctx, tx, name, &merged)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return written(config, s.afterWrite(ctx, hookUpdate, config))
+}
+
+// JSONPatchConfig applies an RFC 6902 JSON Patch to the latest data of a
+// configuration and stores the result as a new version. Operations apply in
+// order to a copy of the data and the patch is all-or-nothing: an operation
+// that does not apply, including a test whose value does not match, rejects
+// the whole patch. Test operations are checked against the data read in the
+// same transaction as the write, so they guard against concurrent updates of
+// the fields they name. The patched document is validated against the schema.
+func (s *ConfigService) JSONPatchConfig(ctx context.Context, name string, ops []models.PatchOperation, actor string) (*models.Config, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+	if len(ops) == 0 {
+		return nil, &models.ValidationError{Field: "operations", Message: "at least one operation is required"}
+	}
+
+	var config *models.Config
+	err := s.repo.WithTransaction(ctx, func(tx repository.ConfigRepository) error {
+		existing, err := tx.Get(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		data, err := models.ApplyJSONPatch(existing.Data, ops)
+		if err != nil {
+			return err
+		}
+		config, err = s.updateConfig(ctx, tx, name, &models.UpdateConfigRequest{Data: data, Actor: actor})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return written(config, s.afterWrite(ctx, hookUpdate, config))
+}
+
+// mergeDataPatch merges patch into config data. Array data is no object to
+// merge into: an array patch replaces the data, and an object patch applied
+// to array data starts from an empty object, as RFC 7396 prescribes.
+func mergeDataPatch(target, patch map[string]interface{}) map[string]interface{} {
+	if _, isArray := models.DataItems(patch); isArray {
+		return patch
+	}
+	if _, isArray := models.DataItems(target); isArray {
+		target = nil
+	}
+	return mergePatch(target, patch)
+}
+
+// mergePatch returns a copy of target with patch merged in per RFC 7396
+func mergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(target)+len(patch))
+	for key, value := range target {
+		merged[key] = value
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		if patchObject, ok := value.(map[string]interface{}); ok {
+			targetObject, _ := merged[key].(map[string]interface{})
+			merged[key] = mergePatch(targetObject, patchObject)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"config-engine/internal/audit"
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+)
+
+// SetTypePolicy registers the policy applied to configurations of configType
+// by later writes; existing configurations pick it up on their next write.
+// The zero policy removes the type's policy. When the repository is a
+// PolicyStore the policy is stored there, so a persistent repository keeps
+// it across restarts.
+func (s *ConfigService) SetTypePolicy(ctx context.Context, configType string, policy *models.TypePolicy) (*models.TypePolicyResponse, error) {
+	if !s.validator.HasSchema(configType) {
+		return nil, &models.SchemaNotFoundError{Type: configType}
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.policiesMu.Lock()
+	defer s.policiesMu.Unlock()
+
+	if store, ok := s.repo.(repository.PolicyStore); ok {
+		if err := store.SetTypePolicy(ctx, configType, *policy); err != nil {
+			return nil, err
+		}
+	}
+	if *policy == (models.TypePolicy{}) {
+		delete(s.typePolicies, configType)
+	} else {
+		s.typePolicies[configType] = *policy
+	}
+	return &models.TypePolicyResponse{Type: configType, Policy: *policy}, nil
+}
+
+// loadTypePolicies reads the policies the repository stores, if it is a
+// PolicyStore. Failing to read them is logged; the types then have no policy.
+func (s *ConfigService) loadTypePolicies() {
+	store, ok := s.repo.(repository.PolicyStore)
+	if !ok {
+		return
+	}
+	policies, err := store.TypePolicies(context.Background())
+	if err != nil {
+		s.logger.Printf("Failed to load type policies: %v", err)
+		return
+	}
+
+	s.policiesMu.Lock()
+	defer s.policiesMu.Unlock()
+	for configType, policy := range policies {
+		s.typePolicies[configType] = policy
+	}
+}
+
+// TypePolicy returns the policy of configType; the zero policy when it has none
+func (s *ConfigService) TypePolicy(configType string) models.TypePolicy {
+	s.policiesMu.RLock()
+	defer s.policiesMu.RUnlock()
+
+	return s.typePolicies[configType]
+}
+
+// applyTTL sets when a configuration about to be written expires, from the
+// default TTL of its type's policy
+func (s *ConfigService) applyTTL(config *models.Config) {
+	config.ExpiresAt = nil
+	if ttl := s.TypePolicy(config.Type).TTL(); ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		config.ExpiresAt = &expiresAt
+	}
+}
+
+// checkRollbackAllowed rejects a rollback of config its type's policy disallows
+func (s *ConfigService) checkRollbackAllowed(config *models.Config) error {
+	if s.TypePolicy(config.Type).DisallowRollback {
+		return &models.RollbackDisallowedError{Name: config.Name, Type: config.Type}
+	}
+	return nil
+}
+
+// versionLimit returns the number of versions configurations of configType
+// may ever have, or 0 when they are unlimited
+func (s *ConfigService) versionLimit(configType string) int {
+	if limit := s.TypePolicy(configType).VersionLimit; limit > 0 {
+		return limit
+	}
+	return s.opts.MaxVersionsPerConfig
+}
+
+// versionLimited reports whether some configurations have a version limit
+func (s *ConfigService) versionLimited() bool {
+	if s.opts.MaxVersionsPerConfig > 0 {
+		return true
+	}
+
+	s.policiesMu.RLock()
+	defer s.policiesMu.RUnlock()
+
+	for _, policy := range s.typePolicies {
+		if policy.VersionLimit > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkVersionLimit rejects a write that would give config a version beyond
+// its limit. Version numbers are never reused, so the newest one counts every
+// version the configuration has had, compacted ones included. Rollbacks are
+// checked too, even though with the branching strategy they add no version.
+func (s *ConfigService) checkVersionLimit(ctx context.Context, repo repository.ConfigRepository, config *models.Config) error {
+	limit := s.versionLimit(config.Type)
+	if limit == 0 {
+		return nil
+	}
+
+	// With the branching strategy the head may be older than the newest version
+	latest := config.Version
+	versions, err := repo.ListVersions(ctx, config.Name)
+	if err != nil {
+		return err
+	}
+	if n := len(versions); n > 0 && versions[n-1].Version > latest {
+		latest = versions[n-1].Version
+	}
+
+	if latest >= limit {
+		return &models.VersionLimitError{Name: config.Name, Limit: limit}
+	}
+	return nil
+}
+
+// afterWrite runs the steps that follow a stored write: the hooks of the
+// config's type, then, unless a hook undid the write, the retention of its
+// type's policy. Retention runs last because compaction drops the state
+// needed to undo the write.
+func (s *ConfigService) afterWrite(ctx context.Context, event hookEvent, config *models.Config) error {
+	err := s.runHooks(ctx, event, config)
+	var hookErr *models.HookError
+	if errors.As(err, &hookErr) && hookErr.Reverted {
+		return err
+	}
+
+	if maxVersions := s.TypePolicy(config.Type).MaxVersions; maxVersions > 0 {
+		// The write is stored either way, so failing to compact is only logged
+		if compactor, ok := s.repo.(repository.Compactor); ok {
+			if _, compactErr := compactor.CompactConfig(ctx, config.Name, maxVersions); compactErr != nil {
+				s.logger.Printf("Failed to apply the retention of %s to %s: %v", config.Type, config.Name, compactErr)
+			}
+		}
+	}
+	return err
+}
+
+// ExpireConfigs deletes the configurations whose policy TTL ran out by now
+// and returns their names. Only the types whose policy has a TTL are
+// scanned; removing a type's TTL stops its configurations from expiring.
+// Configurations that are locked or that others depend on are kept and
+// logged, to be retried on the next call. Each expiry is recorded in
+// auditLog as a delete by ExpiryActor, unless auditLog is nil.
+func (s *ConfigService) ExpireConfigs(ctx context.Context, now time.Time, auditLog audit.AuditLogger) ([]string, error) {
+	var expired []string
+	for _, configType := range s.ttlTypes() {
+		configs, _, err := s.repo.List(ctx, models.ConfigFilter{Type: configType})
+		if err != nil {
+			return expired, err
+		}
+
+		for _, config := range configs {
+			if config.ExpiresAt == nil || config.ExpiresAt.After(now) {
+				continue
+			}
+			var version int
+			err := s.repo.WithTransaction(ctx, func(tx repository.ConfigRepository) error {
+				// A write since the listing may have renewed the TTL
+				current, err := tx.Get(ctx, config.Name)
+				if err != nil {
+					return err
+				}
+				if current.ExpiresAt == nil || current.ExpiresAt.After(now) {
+					return errNotExpired
+				}
+				if err := checkUnlocked(current); err != nil {
+					return err
+				}
+				dependents, err := tx.ListDependents(ctx, config.Name)
+				if err != nil {
+					return err
+				}
+				if len(dependents) > 0 {
+					return &models.ConfigInUseError{Name: config.Name, Dependents: dependents}
+				}
+				version = current.Version
+				return tx.Delete(ctx, config.Name)
+			})
+
+			var notFound *models.ConfigNotFoundError
+			switch {
+			case err == nil:
+				expired = append(expired, config.Name)
+				s.recordExpiry(auditLog, config.Name, version)
+			case errors.Is(err, errNotExpired), errors.As(err, &notFound):
+			case ctx.Err() != nil:
+				return expired, ctx.Err()
+			default:
+				s.logger.Printf("Failed to expire %s: %v", config.Name, err)
+			}
+		}
+	}
+	return expired, nil
+}
+
+// ExpiryActor is the actor audit events of expired configurations name
+const ExpiryActor = "expiry"
+
+// ttlTypes returns the config types whose policy has a TTL, sorted
+func (s *ConfigService) ttlTypes() []string {
+	s.policiesMu.RLock()
+	defer s.policiesMu.RUnlock()
+
+	var types []string
+	for configType, policy := range s.typePolicies {
+		if policy.DefaultTTL != "" {
+			types = append(types, configType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// recordExpiry records the deletion of an expired configuration
+func (s *ConfigService) recordExpiry(auditLog audit.AuditLogger, name string, version int) {
+	if auditLog == nil {
+		return
+	}
+	err := auditLog.Record(models.AuditEvent{
+		Timestamp: time.Now(),
+		Action:    models.AuditActionDelete,
+		Name:      name,
+		Actor:     ExpiryActor,
+		Version:   version,
+		Details:   "TTL expired",
+	})
+	if err != nil {
+		s.logger.Printf("Failed to record audit event: %v", err)
+	}
+}
+
+// errNotExpired stops the deletion of a configuration whose TTL was renewed
+var errNotExpired = errors.New("configuration has not expired")
+
+// RunExpiry calls ExpireConfigs every interval until ctx is done, recording
+// expiries in auditLog
+func (s *ConfigService) RunExpiry(ctx context.Context, interval time.Duration, auditLog audit.AuditLogger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			expired, err := s.ExpireConfigs(ctx, now, auditLog)
+			if err != nil && ctx.Err() == nil {
+				s.logger.Printf("Failed to expire configs: %v", err)
+			}
+			if len(expired) > 0 {
+				s.logger.Printf("Expired %d configs", len(expired))
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/validation"
+)
+
+// ProposeChange validates a change and stores it as a pending proposal without applying it
+func (s *ConfigService) ProposeChange(ctx context.Context, name string, req *models.UpdateConfigRequest, proposer string) (*models.Proposal, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDataLimits(req.Data); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate up front so reviewers only see changes that can be applied
+	data := s.validator.RemoveNulls(current.Type, req.Data)
+	if err := s.validator.ValidateUpdate(current.Type, data); err != nil {
+		return nil, &models.SchemaValidationError{Details: err.Error(), Suggestions: validation.Suggestions(err), Violations: validation.Violations(err)}
+	}
+
+	dependsOn := s.normalizeNames(req.DependsOn)
+	if err := s.validateDependencies(ctx, s.repo, name, dependsOn); err != nil {
+		return nil, err
+	}
+
+	id, err := newProposalID()
+	if err != nil {
+		return nil, err
+	}
+
+	proposal := &models.Proposal{
+		ID:          id,
+		Name:        name,
+		Data:        data,
+		DependsOn:   dependsOn,
+		BaseVersion: current.Version,
+		Proposer:    proposer,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.repo.SaveProposal(ctx, proposal); err != nil {
+		return nil, err
+	}
+
+	return proposal, nil
+}
+
+// ListProposals lists the pending proposals of a configuration
+func (s *ConfigService) ListProposals(ctx context.Context, name string) (*models.ProposalsResponse, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	proposals, err := s.repo.ListProposals(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProposalsResponse{
+		Name:      name,
+		Proposals: proposals,
+	}, nil
+}
+
+// ApproveProposal applies a pending proposal as a new version.
+// If the config changed since the proposal was created, approval is rejected
+// unless revalidate is set, in which case the proposal is validated again
+// against the current state before being applied.
+func (s *ConfigService) ApproveProposal(ctx context.Context, name, id string, revalidate bool) (*models.Config, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	var config *models.Config
+	err := s.repo.WithTransaction(ctx, func(tx repository.ConfigRepository) error {
+		proposal, err := tx.GetProposal(ctx, name, id)
+		if err != nil {
+			return err
+		}
+
+		current, err := tx.Get(ctx, name)
+		if err != nil {
+			return err
+		}
+		if current.Version != proposal.BaseVersion && !revalidate {
+			return &models.ProposalConflictError{
+				ID:             id,
+				BaseVersion:    proposal.BaseVersion,
+				CurrentVersion: current.Version,
+			}
+		}
+
+		// updateConfig validates the data against the current schema and dependencies
+		config, err = s.updateConfig(ctx, tx, name, &models.UpdateConfigRequest{
+			Data:      proposal.Data,
+			DependsOn: proposal.DependsOn,
+			Actor:     proposal.Proposer,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.DeleteProposal(ctx, name, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return written(config, s.afterWrite(ctx, hookUpdate, config))
+}
+
+// RejectProposal discards a pending proposal
+func (s *ConfigService) RejectProposal(ctx context.Context, name, id string) error {
+	name = s.normalizeName(name)
+	if name == "" {
+		return &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	return s.repo.DeleteProposal(ctx, name, id)
+}
+
+// newProposalID generates a random proposal identifier
+func newProposalID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate proposal id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"config-engine/internal/models"
+	"context"
+	"testing"
+)
+
+func TestProposalApproval(t *testing.T) {
+	svc := setupService(t)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "test_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+
+	proposal, err := svc.ProposeChange(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 5000, "enabled": true},
+	}, "alice")
+	if err != nil {
+		t.Fatalf("Failed to propose change: %v", err)
+	}
+	if proposal.BaseVersion != 1 || proposal.Proposer != "alice" {
+		t.Errorf("Unexpected proposal: %+v", proposal)
+	}
+
+	// Proposals are not applied until approved
+	config, _ := svc.GetConfig(context.Background(), "test_config", nil)
+	if config.Version != 1 {
+		t.Errorf("Expected version 1 before approval, got %d", config.Version)
+	}
+
+	list, _ := svc.ListProposals(context.Background(), "test_config")
+	if len(list.Proposals) != 1 {
+		t.Fatalf("Expected 1 pending proposal, got %d", len(list.Proposals))
+	}
+
+	config, err = svc.ApproveProposal(context.Background(), "test_config", proposal.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to approve proposal: %v", err)
+	}
+	if config.Version != 2 || config.Data["max_limit"].(int) != 5000 {
+		t.Errorf("Unexpected config after approval: %+v", config)
+	}
+
+	list, _ = svc.ListProposals(context.Background(), "test_config")
+	if len(list.Proposals) != 0 {
+		t.Errorf("Expected approved proposal to be removed, got %d", len(list.Proposals))
+	}
+}
+
+func TestProposalStaleApproval(t *testing.T) {
+	svc := setupService(t)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "test_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	proposal, _ := svc.ProposeChange(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 5000, "enabled": true},
+	}, "alice")
+
+	// Config changes after the proposal was created
+	svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 2000, "enabled": true},
+	})
+
+	_, err := svc.ApproveProposal(context.Background(), "test_config", proposal.ID, false)
+	if _, ok := err.(*models.ProposalConflictError); !ok {
+		t.Fatalf("Expected ProposalConflictError, got %v", err)
+	}
+
+	config, err := svc.ApproveProposal(context.Background(), "test_config", proposal.ID, true)
+	if err != nil {
+		t.Fatalf("Failed to approve with revalidation: %v", err)
+	}
+	if config.Version != 3 {
+		t.Errorf("Expected version 3, got %d", config.Version)
+	}
+}
+
+func TestProposalRejection(t *testing.T) {
+	svc := setupService(t)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "test_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	proposal, _ := svc.ProposeChange(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 5000, "enabled": true},
+	}, "alice")
+
+	if err := svc.RejectProposal(context.Background(), "test_config", proposal.ID); err != nil {
+		t.Fatalf("Failed to reject proposal: %v", err)
+	}
+
+	_, err := svc.ApproveProposal(context.Background(), "test_config", proposal.ID, false)
+	if _, ok := err.(*models.ProposalNotFoundError); !ok {
+		t.Errorf("Expected ProposalNotFoundError, got %v", err)
+	}
+
+	// Invalid changes are rejected at proposal time
+	_, err = svc.ProposeChange(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": "invalid"},
+	}, "alice")
+	if _, ok := err.(*models.SchemaValidationError); !ok {
+		t.Errorf("Expected SchemaValidationError, got %v", err)
+	}
+}
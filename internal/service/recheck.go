@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"config-engine/internal/models"
+	"config-engine/internal/validation"
+)
+
+// ValidateStoredConfig re-runs the current schema and custom rules of a
+// configuration's type against its stored latest data without changing
+// anything, surfacing configurations that a tightened schema no longer
+// accepts. A configuration whose type has no schema any more is invalid.
+func (s *ConfigService) ValidateStoredConfig(ctx context.Context, name string) (*models.ConfigValidationResponse, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	config, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.ConfigValidationResponse{
+		Name:    config.Name,
+		Type:    config.Type,
+		Version: config.Version,
+		Errors:  []models.FieldViolation{},
+	}
+	if !s.validator.HasSchema(config.Type) {
+		resp.Errors = append(resp.Errors, models.FieldViolation{
+			Field:   "type",
+			Message: fmt.Sprintf("unknown config type: %s", config.Type),
+		})
+		return resp, nil
+	}
+
+	if err := s.validator.Check(config.Type, config.Data); err != nil {
+		violations := validation.Violations(err)
+		if violations == nil {
+			return nil, err
+		}
+		resp.Errors = violations
+		return resp, nil
+	}
+
+	resp.Valid = true
+	return resp, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+)
+
+// RenameConfig moves a configuration with its full history to a new name.
+// Configurations that depend on it are pointed at the new name; ${name.field}
+// templates in their data are left as written. Locked configurations cannot
+// be renamed.
+func (s *ConfigService) RenameConfig(ctx context.Context, name string, req *models.RenameRequest) (*models.Config, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	newName := s.normalizeName(req.NewName)
+	if err := validateName(newName); err != nil {
+		return nil, &models.ValidationError{Field: "new_name", Message: err.(*models.ValidationError).Message}
+	}
+	if newName == name {
+		return nil, &models.ValidationError{Field: "new_name", Message: "new_name must differ from the current name"}
+	}
+
+	var renamed *models.Config
+	err := s.repo.WithTransaction(ctx, func(tx repository.ConfigRepository) error {
+		current, err := tx.Get(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := checkUnlocked(current); err != nil {
+			return err
+		}
+
+		if err := tx.Rename(ctx, name, newName); err != nil {
+			return err
+		}
+		renamed, err = tx.Get(ctx, newName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return renamed, nil
+}
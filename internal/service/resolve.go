@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"config-engine/internal/models"
+)
+
+// templatePattern matches ${config.field} references inside string values
+var templatePattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// ResolveOptions limits what the references resolved by ResolveConfig may reveal
+type ResolveOptions struct {
+	// Allow filters the configs references may read; nil allows all.
+	// References to configs it rejects fail as if the config did not exist,
+	// so callers limited to some configs cannot read others through references.
+	Allow func(*models.Config) bool
+	// Redact masks the secret fields of the resolved config and of every
+	// config its references read, so no secret is copied into another field
+	Redact bool
+}
+
+// ResolveConfig returns a copy of the config with every ${config.field}
+// reference in its data replaced by the referenced value of the latest
+// version of that config (references to the config itself use the data being
+// returned). References are resolved transitively; circular references are
+// reported as errors. The stored data is left untouched.
+func (s *ConfigService) ResolveConfig(ctx context.Context, config *models.Config, opts ResolveOptions) (*models.Config, error) {
+	r := &resolver{
+		ctx:     ctx,
+		service: s,
+		opts:    opts,
+		loaded:  map[string]map[string]interface{}{config.Name: s.referencedData(config, opts)},
+		active:  make(map[string]bool),
+	}
+
+	data, err := r.value(r.loaded[config.Name])
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *config
+	resolved.Data = data.(map[string]interface{})
+	return &resolved, nil
+}
+
+// resolver resolves the references of a single read
+type resolver struct {
+	ctx     context.Context
+	service *ConfigService
+	opts    ResolveOptions
+	// loaded caches the data of referenced configs for the duration of the read
+	loaded map[string]map[string]interface{}
+	// active holds the references currently being resolved, for cycle detection
+	active map[string]bool
+	stack  []string
+}
+
+// value resolves the references within a data value, returning a copy
+func (r *resolver) value(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			item, err := r.value(item)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = item
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, item := range v {
+			item, err := r.value(item)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = item
+		}
+		return resolved, nil
+	case string:
+		return r.string(v)
+	default:
+		return value, nil
+	}
+}
+
+// string substitutes the references within a string. A string consisting of
+// a single reference takes the referenced value as is, keeping its type;
+// references embedded in surrounding text must point at scalar values.
+func (r *resolver) string(s string) (interface{}, error) {
+	matches := templatePattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s) {
+		return r.reference(s[matches[0][2]:matches[0][3]])
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		ref := s[m[2]:m[3]]
+		value, err := r.reference(ref)
+		if err != nil {
+			return nil, err
+		}
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, &models.ReferenceError{Reference: ref, Reason: "objects and arrays cannot be embedded in a string"}
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(fmt.Sprint(value))
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
+
+// reference resolves a single config.field reference
+func (r *resolver) reference(ref string) (interface{}, error) {
+	name, path, ok := strings.Cut(ref, ".")
+	if !ok || name == "" || path == "" {
+		return nil, &models.ReferenceError{Reference: ref, Reason: "reference must have the form config.field"}
+	}
+	name = r.service.normalizeName(name)
+
+	key := name + "." + path
+	if r.active[key] {
+		cycle := append(append([]string{}, r.stack...), key)
+		return nil, &models.ReferenceError{Reference: ref, Reason: "circular reference: " + strings.Join(cycle, " -> ")}
+	}
+
+	data, err := r.load(name)
+	if err != nil {
+		return nil, &models.ReferenceError{Reference: ref, Reason: "configuration not found"}
+	}
+	value, exists := models.Lookup(data, path)
+	if !exists {
+		return nil, &models.ReferenceError{Reference: ref, Reason: "field not found"}
+	}
+
+	r.active[key] = true
+	r.stack = append(r.stack, key)
+	defer func() {
+		delete(r.active, key)
+		r.stack = r.stack[:len(r.stack)-1]
+	}()
+
+	return r.value(value)
+}
+
+// load returns the latest data of a config, reading each config at most once
+func (r *resolver) load(name string) (map[string]interface{}, error) {
+	if data, exists := r.loaded[name]; exists {
+		return data, nil
+	}
+
+	config, err := r.service.repo.Get(r.ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if r.opts.Allow != nil && !r.opts.Allow(config) {
+		return nil, &models.ConfigNotFoundError{Name: name}
+	}
+	data := r.service.referencedData(config, r.opts)
+	r.loaded[name] = data
+	return data, nil
+}
+
+// referencedData returns the data of a config as references may read it
+func (s *ConfigService) referencedData(config *models.Config, opts ResolveOptions) map[string]interface{} {
+	if opts.Redact {
+		return s.validator.Redact(config.Type, config.Data)
+	}
+	return config.Data
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"config-engine/internal/models"
+	"config-engine/internal/validation"
+	"context"
+	"strings"
+	"testing"
+)
+
+// setupResolveService returns a service with a schemaless type for template tests
+func setupResolveService(t *testing.T, configs map[string]map[string]interface{}) *ConfigService {
+	svc := setupService(t)
+	if err := svc.validator.RegisterSchema("service_config", map[string]interface{}{"type": "object"}); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	for name, data := range configs {
+		if _, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{Name: name, Type: "service_config", Data: data}); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	return svc
+}
+
+func TestResolveConfig(t *testing.T) {
+	svc := setupResolveService(t, map[string]map[string]interface{}{
+		"shared": {"base_url": "https://api.example.com", "timeout": 30},
+		"checkout": {
+			"endpoint": "${shared.base_url}/checkout",
+			"timeout":  "${shared.timeout}",
+			"plain":    "no templates here",
+		},
+	})
+
+	config, err := svc.GetConfig(context.Background(), "checkout", nil)
+	if err != nil {
+		t.Fatalf("Failed to get config: %v", err)
+	}
+	resolved, err := svc.ResolveConfig(context.Background(), config, ResolveOptions{})
+	if err != nil {
+		t.Fatalf("Failed to resolve config: %v", err)
+	}
+
+	if resolved.Data["endpoint"] != "https://api.example.com/checkout" {
+		t.Errorf("Expected substituted endpoint, got %v", resolved.Data["endpoint"])
+	}
+	// A whole-string reference keeps the referenced value's type
+	if resolved.Data["timeout"] != 30 {
+		t.Errorf("Expected timeout 30, got %#v", resolved.Data["timeout"])
+	}
+	if resolved.Data["plain"] != "no templates here" {
+		t.Errorf("Expected plain string unchanged, got %v", resolved.Data["plain"])
+	}
+
+	// Stored data keeps the raw templates
+	stored, _ := svc.GetConfig(context.Background(), "checkout", nil)
+	if stored.Data["endpoint"] != "${shared.base_url}/checkout" {
+		t.Errorf("Expected stored template unchanged, got %v", stored.Data["endpoint"])
+	}
+}
+
+func TestResolveConfigMissingReference(t *testing.T) {
+	svc := setupResolveService(t, map[string]map[string]interface{}{
+		"shared":   {"base_url": "https://api.example.com"},
+		"checkout": {"endpoint": "${shared.missing}"},
+		"billing":  {"endpoint": "${unknown.base_url}"},
+	})
+
+	for _, name := range []string{"checkout", "billing"} {
+		config, _ := svc.GetConfig(context.Background(), name, nil)
+		_, err := svc.ResolveConfig(context.Background(), config, ResolveOptions{})
+		if _, ok := err.(*models.ReferenceError); !ok {
+			t.Errorf("%s: expected ReferenceError, got %v", name, err)
+		}
+	}
+}
+
+func TestResolveConfigCycle(t *testing.T) {
+	svc := setupResolveService(t, map[string]map[string]interface{}{
+		"first":  {"value": "${second.value}"},
+		"second": {"value": "${first.value}"},
+	})
+
+	config, _ := svc.GetConfig(context.Background(), "first", nil)
+	_, err := svc.ResolveConfig(context.Background(), config, ResolveOptions{})
+	refErr, ok := err.(*models.ReferenceError)
+	if !ok {
+		t.Fatalf("Expected ReferenceError, got %v", err)
+	}
+	if !strings.Contains(refErr.Reason, "circular reference") {
+		t.Errorf("Expected circular reference error, got %v", err)
+	}
+}
+
+func TestResolveConfigRedacted(t *testing.T) {
+	svc := setupResolveService(t, map[string]map[string]interface{}{
+		"checkout": {"copied": "${gateway.api_key}", "own": "${checkout.token}", "token": "tok_secret"},
+	})
+	svc.validator.RegisterSchema("secret_config", map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"api_key": map[string]interface{}{"type": "string", "secret": true}},
+	})
+	svc.validator.RegisterSchema("service_config", map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"token": map[string]interface{}{"type": "string", "secret": true}},
+	})
+	if _, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{Name: "gateway", Type: "secret_config", Data: map[string]interface{}{"api_key": "sk_live_secret"}}); err != nil {
+		t.Fatalf("Failed to create gateway: %v", err)
+	}
+
+	config, _ := svc.GetConfig(context.Background(), "checkout", nil)
+	resolved, err := svc.ResolveConfig(context.Background(), config, ResolveOptions{Redact: true})
+	if err != nil {
+		t.Fatalf("Failed to resolve config: %v", err)
+	}
+	for _, field := range []string{"copied", "own", "token"} {
+		if resolved.Data[field] != validation.RedactedValue {
+			t.Errorf("Expected %s to be redacted, got %v", field, resolved.Data[field])
+		}
+	}
+
+	resolved, _ = svc.ResolveConfig(context.Background(), config, ResolveOptions{})
+	if resolved.Data["copied"] != "sk_live_secret" {
+		t.Errorf("Expected unredacted resolution to read the secret, got %v", resolved.Data["copied"])
+	}
+}
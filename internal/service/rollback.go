@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"config-engine/internal/models"
+)
+
+// BulkRollback rolls each named configuration back to the version that was
+// active at req.At. Configurations are rolled back independently so that one
+// failure does not block the rest during an incident; each gets a result
+// saying what happened. Configurations that did not exist yet at req.At are
+// skipped and ones whose data already matches their version from then are
+// left unchanged.
+func (s *ConfigService) BulkRollback(ctx context.Context, req *models.BulkRollbackRequest) (*models.BulkRollbackResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if s.opts.MaxBatchNames > 0 && len(req.Names) > s.opts.MaxBatchNames {
+		return nil, &models.ValidationError{
+			Field:   "names",
+			Message: fmt.Sprintf("%d names exceeds maximum of %d", len(req.Names), s.opts.MaxBatchNames),
+		}
+	}
+
+	resp := &models.BulkRollbackResponse{
+		At:      req.At,
+		Results: make([]models.BulkRollbackResult, 0, len(req.Names)),
+	}
+	for _, name := range req.Names {
+		result, err := s.rollbackToTime(ctx, name, req)
+		if err != nil {
+			// Stop once the request is cancelled or out of time rather than
+			// reporting every remaining config as failed
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			result.Status = models.BulkRollbackFailed
+			result.Note = err.Error()
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+// rollbackToTime rolls a single configuration back to its version at req.At
+func (s *ConfigService) rollbackToTime(ctx context.Context, name string, req *models.BulkRollbackRequest) (models.BulkRollbackResult, error) {
+	result := models.BulkRollbackResult{Name: name}
+	normalized := s.normalizeName(name)
+	if err := validateName(normalized); err != nil {
+		return result, err
+	}
+	result.Name = normalized
+
+	current, err := s.repo.Get(ctx, normalized)
+	if err != nil {
+		return result, err
+	}
+	result.FromVersion = current.Version
+
+	target, err := s.repo.VersionAt(ctx, normalized, req.At)
+	var noVersion *models.NoVersionAtError
+	if errors.As(err, &noVersion) {
+		result.Status = models.BulkRollbackSkipped
+		result.Note = err.Error()
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	result.TargetVersion = target.Version
+
+	// Rerunning a bulk rollback must not stack up copies of the same version
+	if target.Version == current.Version || len(models.DiffData(current.Data, target.Data)) == 0 {
+		result.Status = models.BulkRollbackUnchanged
+		result.Version = current.Version
+		return result, nil
+	}
+
+	config, err := s.RollbackConfig(ctx, normalized, &models.RollbackRequest{
+		Version: target.Version,
+		Actor:   req.Actor,
+		Force:   req.Force,
+	})
+	if err != nil && !WriteCommitted(err) {
+		return result, err
+	}
+	result.Status = models.BulkRollbackRolledBack
+	result.Version = config.Version
+	if err != nil {
+		result.Note = err.Error()
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"config-engine/internal/models"
+)
+
+// rolloutBuckets is the resolution of rollout percentages (0.01%)
+const rolloutBuckets = 10000
+
+// EvaluateRollout decides whether user is in the rollout of a configuration.
+// Members of a listed cohort are always included; everyone else is assigned a
+// stable bucket from a hash of the config name and user id, so a user gets the
+// same decision every time and raising the percentage never drops anyone.
+func (s *ConfigService) EvaluateRollout(ctx context.Context, name, user string, cohorts []string) (*models.RolloutEvaluation, error) {
+	if user == "" {
+		return nil, &models.ValidationError{Field: "user", Message: "user is required"}
+	}
+
+	config, err := s.GetConfig(ctx, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := &models.RolloutEvaluation{Name: config.Name, Version: config.Version, User: user}
+	rollout := config.Rollout
+	if rollout == nil {
+		evaluation.InRollout = true
+		evaluation.Reason = models.RolloutReasonNoRollout
+		return evaluation, nil
+	}
+
+	percentage := rollout.Percentage
+	evaluation.Percentage = &percentage
+	if inCohort(rollout.Cohorts, user, cohorts) {
+		evaluation.InRollout = true
+		evaluation.Reason = models.RolloutReasonCohort
+		return evaluation, nil
+	}
+
+	evaluation.InRollout = inPercentage(config.Name, user, percentage)
+	evaluation.Reason = models.RolloutReasonPercentage
+	return evaluation, nil
+}
+
+// inCohort reports whether the user, or any group they belong to, is listed
+func inCohort(listed []string, user string, groups []string) bool {
+	for _, cohort := range listed {
+		if cohort == user {
+			return true
+		}
+		for _, group := range groups {
+			if cohort == group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inPercentage reports whether the user's bucket for the config falls below
+// percentage. Salting with the config name keeps rollouts of different
+// configs independent, so the same users are not always first.
+func inPercentage(name, user string, percentage float64) bool {
+	return float64(rolloutBucket(name, user)) < percentage*rolloutBuckets/100
+}
+
+// rolloutBucket maps a user to a stable bucket in [0, rolloutBuckets)
+func rolloutBucket(name, user string) uint64 {
+	sum := sha256.Sum256([]byte(name + "\x00" + user))
+	return binary.BigEndian.Uint64(sum[:8]) % rolloutBuckets
+}
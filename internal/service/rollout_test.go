@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"config-engine/internal/models"
+)
+
+func TestRolloutMonotonic(t *testing.T) {
+	users := make([]string, 2000)
+	for i := range users {
+		users[i] = fmt.Sprintf("user-%d", i)
+	}
+
+	previous := make(map[string]bool)
+	for _, percentage := range []float64{0, 0.5, 1, 5, 10, 25, 33.3, 50, 75, 99, 100} {
+		included := 0
+		for _, user := range users {
+			in := inPercentage("new_checkout", user, percentage)
+			if in != inPercentage("new_checkout", user, percentage) {
+				t.Fatalf("Expected a stable decision for %s at %v%%", user, percentage)
+			}
+			if previous[user] && !in {
+				t.Fatalf("Raising the rollout to %v%% removed %s", percentage, user)
+			}
+			previous[user] = in
+			if in {
+				included++
+			}
+		}
+
+		// The hash should spread users roughly evenly across buckets
+		expected := percentage / 100 * float64(len(users))
+		if diff := float64(included) - expected; diff > 80 || diff < -80 {
+			t.Errorf("Expected about %.0f users at %v%%, got %d", expected, percentage, included)
+		}
+	}
+}
+
+func TestEvaluateRollout(t *testing.T) {
+	svc := setupResolveService(t, map[string]map[string]interface{}{"everyone": {"enabled": true}})
+	ctx := context.Background()
+
+	_, err := svc.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name:    "new_checkout",
+		Type:    "service_config",
+		Data:    map[string]interface{}{"enabled": true},
+		Rollout: &models.Rollout{Percentage: 0, Cohorts: []string{"alice", "beta"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		config  string
+		user    string
+		cohorts []string
+		want    bool
+		reason  string
+	}{
+		{"listed user", "new_checkout", "alice", nil, true, models.RolloutReasonCohort},
+		{"listed group", "new_checkout", "bob", []string{"beta"}, true, models.RolloutReasonCohort},
+		{"outside rollout", "new_checkout", "bob", []string{"staff"}, false, models.RolloutReasonPercentage},
+		{"no rollout", "everyone", "bob", nil, true, models.RolloutReasonNoRollout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluation, err := svc.EvaluateRollout(ctx, tt.config, tt.user, tt.cohorts)
+			if err != nil {
+				t.Fatalf("Failed to evaluate: %v", err)
+			}
+			if evaluation.InRollout != tt.want || evaluation.Reason != tt.reason {
+				t.Errorf("Expected in_rollout=%v (%s), got %v (%s)", tt.want, tt.reason, evaluation.InRollout, evaluation.Reason)
+			}
+		})
+	}
+
+	// Updates without a rollout keep it; a full rollout includes everyone
+	if _, err := svc.UpdateConfig(ctx, "new_checkout", &models.UpdateConfigRequest{Data: map[string]interface{}{"enabled": false}}); err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+	if evaluation, _ := svc.EvaluateRollout(ctx, "new_checkout", "alice", nil); evaluation.Reason != models.RolloutReasonCohort {
+		t.Error("Expected the rollout to survive an update that omits it")
+	}
+	if _, err := svc.UpdateConfig(ctx, "new_checkout", &models.UpdateConfigRequest{
+		Data:    map[string]interface{}{"enabled": true},
+		Rollout: &models.Rollout{Percentage: 100},
+	}); err != nil {
+		t.Fatalf("Failed to update rollout: %v", err)
+	}
+	if evaluation, _ := svc.EvaluateRollout(ctx, "new_checkout", "bob", nil); !evaluation.InRollout {
+		t.Error("Expected a 100% rollout to include every user")
+	}
+
+	// The built-in schema bounds the percentage
+	_, err = svc.UpdateConfig(ctx, "new_checkout", &models.UpdateConfigRequest{
+		Data:    map[string]interface{}{"enabled": true},
+		Rollout: &models.Rollout{Percentage: 150},
+	})
+	if _, ok := err.(*models.SchemaValidationError); !ok {
+		t.Errorf("Expected SchemaValidationError for a 150%% rollout, got %v", err)
+	}
+	if _, err := svc.EvaluateRollout(ctx, "new_checkout", "", nil); err == nil {
+		t.Error("Expected an error without a user")
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+
+	"config-engine/internal/models"
+	"config-engine/internal/validation"
+)
+
+// SchemaExample returns a sample data object that satisfies the type's schema
+func (s *ConfigService) SchemaExample(configType string) (*models.SchemaExampleResponse, error) {
+	example, exists := s.validator.Example(configType)
+	if !exists {
+		return nil, &models.SchemaNotFoundError{Type: configType}
+	}
+
+	return &models.SchemaExampleResponse{
+		Type: configType,
+		Data: example,
+	}, nil
+}
+
+// ReloadSchemas re-reads the validator's schema directory and reports the
+// stored configurations whose latest data the new schemas reject. With
+// dryRun the schemas are only checked, not swapped in. On failure the
+// previously loaded schemas remain in effect.
+func (s *ConfigService) ReloadSchemas(ctx context.Context, dryRun bool) (*models.SchemaReloadResponse, error) {
+	stored, _, err := s.repo.List(ctx, models.ConfigFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	types, incompatible, err := s.validator.ReloadSchemasChecked(stored, dryRun)
+	if err != nil {
+		return nil, &models.SchemaLoadError{Err: err}
+	}
+	if incompatible == nil {
+		incompatible = []models.IncompatibleConfig{}
+	}
+	return &models.SchemaReloadResponse{Types: types, DryRun: dryRun, Incompatible: incompatible}, nil
+}
+
+// RegisterSchema registers the schema of a config type, replacing any it
+// had, and reports the stored configurations of that type whose latest data
+// it rejects. With dryRun the schema is only checked, not registered.
+func (s *ConfigService) RegisterSchema(ctx context.Context, configType string, req *models.RegisterSchemaRequest, dryRun bool) (*models.SchemaRegisterResponse, error) {
+	if configType == "" || configType == validation.DefinitionsType {
+		return nil, &models.ValidationError{Field: "type", Message: "type must name a config type"}
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	stored, _, err := s.repo.List(ctx, models.ConfigFilter{Type: configType})
+	if err != nil {
+		return nil, err
+	}
+
+	incompatible, err := s.validator.RegisterSchemaChecked(configType, req.Schema, stored, dryRun)
+	if err != nil {
+		if _, ok := err.(*validation.SchemaError); ok {
+			return nil, &models.ValidationError{Field: "schema", Message: err.Error()}
+		}
+		return nil, err
+	}
+	if incompatible == nil {
+		incompatible = []models.IncompatibleConfig{}
+	}
+	return &models.SchemaRegisterResponse{Type: configType, DryRun: dryRun, Incompatible: incompatible}, nil
+}
@@ -1,7 +1,12 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
 
 	"config-engine/internal/models"
 	"config-engine/internal/repository"
@@ -12,86 +17,313 @@ import (
 type ConfigService struct {
 	repo      repository.ConfigRepository
 	validator *validation.Validator
+	opts      Options
+	logger    *log.Logger
+
+	hooksMu sync.RWMutex
+	hooks   map[string][]Hook
+
+	// typeDeprecations maps deprecated config types to their messages
+	deprecationsMu   sync.RWMutex
+	typeDeprecations map[string]string
+
+	// typeDefaults maps config types to the data gets of missing configs
+	// fall back to when the caller asks for the type's default
+	defaultsMu   sync.RWMutex
+	typeDefaults map[string]map[string]interface{}
+
+	// typePolicies maps config types to the TTL, retention and rollback
+	// policy their configurations are written under. It mirrors the
+	// repository's policies when it is a PolicyStore.
+	policiesMu   sync.RWMutex
+	typePolicies map[string]models.TypePolicy
 }
 
-// NewConfigService creates a new configuration service
+// NewConfigService creates a new configuration service with default options
 func NewConfigService(repo repository.ConfigRepository, validator *validation.Validator) *ConfigService {
-	return &ConfigService{
+	return NewConfigServiceWithOptions(repo, validator, DefaultOptions())
+}
+
+// NewConfigServiceWithOptions creates a new configuration service with the given options
+func NewConfigServiceWithOptions(repo repository.ConfigRepository, validator *validation.Validator, opts Options) *ConfigService {
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	s := &ConfigService{
 		repo:      repo,
 		validator: validator,
+		opts:      opts,
+		logger:    logger,
+		hooks:     make(map[string][]Hook),
+
+		typeDeprecations: make(map[string]string),
+		typeDefaults:     make(map[string]map[string]interface{}),
+		typePolicies:     make(map[string]models.TypePolicy),
 	}
+	s.loadTypePolicies()
+	return s
 }
 
 // CreateConfig creates a new configuration
-func (s *ConfigService) CreateConfig(req *models.CreateConfigRequest) (*models.Config, error) {
+func (s *ConfigService) CreateConfig(ctx context.Context, req *models.CreateConfigRequest) (*models.Config, error) {
+	config, err := s.createConfig(ctx, s.repo, req)
+	if err != nil {
+		return nil, err
+	}
+	return written(config, s.afterWrite(ctx, hookCreate, config))
+}
+
+// createConfig validates and creates a configuration in the given repository
+func (s *ConfigService) createConfig(ctx context.Context, repo repository.ConfigRepository, req *models.CreateConfigRequest) (*models.Config, error) {
+	// Normalize names without modifying the caller's request
+	normalized := *req
+	normalized.Name = s.normalizeName(req.Name)
+	normalized.DependsOn = s.normalizeNames(req.DependsOn)
+	req = &normalized
+
 	// Validate request
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	// Check if schema exists for this config type
-	if !s.validator.HasSchema(req.Type) {
-		return nil, &models.ValidationError{
-			Field:   "type",
-			Message: fmt.Sprintf("unknown config type: %s", req.Type),
-		}
+	if err := validateName(req.Name); err != nil {
+		return nil, err
 	}
 
-	// Validate data against schema
-	if err := s.validator.Validate(req.Type, req.Data); err != nil {
+	if err := s.checkData(req.Type, req.Data, s.validator.Validate); err != nil {
+		return nil, err
+	}
+	schemaHash, _ := s.validator.SchemaHash(req.Type)
+	if err := validation.ValidateRollout(req.Rollout); err != nil {
 		return nil, &models.SchemaValidationError{Details: err.Error()}
 	}
 
+	// Ensure referenced configs exist
+	if err := s.validateDependencies(ctx, repo, req.Name, req.DependsOn); err != nil {
+		return nil, err
+	}
+
 	// Create config
 	config := &models.Config{
-		Name: req.Name,
-		Type: req.Type,
-		Data: req.Data,
+		Name:      req.Name,
+		Type:      req.Type,
+		Data:      req.Data,
+		DependsOn: req.DependsOn,
+		UpdatedBy: req.Actor,
+		Origin:    originOrAPI(req.Origin),
+		Locked:    req.Locked,
+		Rollout:   req.Rollout,
+		Message:   req.Message,
+
+		SchemaHash: schemaHash,
 	}
+	s.applyTTL(config)
 
-	if err := s.repo.Create(config); err != nil {
+	if err := repo.Create(ctx, config); err != nil {
 		return nil, err
 	}
 
 	return config, nil
 }
 
+// originOrAPI returns origin, defaulting to OriginAPI when it is not set
+func originOrAPI(origin string) string {
+	if origin == "" {
+		return models.OriginAPI
+	}
+	return origin
+}
+
+// checkData applies the data limits and validates data against the schema of
+// configType using validate, which is Validator.Validate for writes and
+// Validator.Check for validation that stores nothing
+func (s *ConfigService) checkData(configType string, data map[string]interface{}, validate func(string, map[string]interface{}) error) error {
+	if err := s.checkDataLimits(data); err != nil {
+		return err
+	}
+
+	// Check if schema exists for this config type
+	if !s.validator.HasSchema(configType) {
+		return &models.ValidationError{
+			Field:   "type",
+			Message: fmt.Sprintf("unknown config type: %s", configType),
+		}
+	}
+
+	// Validate data against schema
+	if err := validate(configType, data); err != nil {
+		return &models.SchemaValidationError{
+			Details:     err.Error(),
+			Suggestions: validation.Suggestions(err),
+			Violations:  validation.Violations(err),
+		}
+	}
+	return nil
+}
+
 // GetConfig retrieves a configuration by name
-func (s *ConfigService) GetConfig(name string, version *int) (*models.Config, error) {
+func (s *ConfigService) GetConfig(ctx context.Context, name string, version *int) (*models.Config, error) {
+	name = s.normalizeName(name)
 	if name == "" {
 		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
 	}
 
 	// If specific version requested
 	if version != nil {
-		configVersion, err := s.repo.GetVersion(name, *version)
+		configVersion, err := s.repo.GetVersion(ctx, name, *version)
 		if err != nil {
 			return nil, err
 		}
 
 		// Get the config to retrieve type info
-		config, err := s.repo.Get(name)
+		config, err := s.repo.Get(ctx, name)
 		if err != nil {
 			return nil, err
 		}
 
 		// Return a config with the requested version's data
 		return &models.Config{
-			Name:      name,
-			Type:      config.Type,
-			Version:   configVersion.Version,
-			Data:      configVersion.Data,
-			CreatedAt: config.CreatedAt,
-			UpdatedAt: configVersion.CreatedAt,
+			Name:       name,
+			Type:       config.Type,
+			Version:    configVersion.Version,
+			Data:       configVersion.Data,
+			CreatedAt:  config.CreatedAt,
+			UpdatedAt:  configVersion.CreatedAt,
+			UpdatedBy:  configVersion.CreatedBy,
+			Origin:     configVersion.Origin,
+			Checksum:   configVersion.Checksum,
+			Message:    configVersion.Message,
+			SchemaHash: configVersion.SchemaHash,
+
+			Deprecated:         config.Deprecated,
+			DeprecationMessage: config.DeprecationMessage,
 		}, nil
 	}
 
 	// Return latest version
-	return s.repo.Get(name)
+	return s.repo.Get(ctx, name)
 }
 
-// UpdateConfig updates an existing configuration
-func (s *ConfigService) UpdateConfig(name string, req *models.UpdateConfigRequest) (*models.Config, error) {
+// ListConfigs returns a page of the configurations matching the filter along
+// with the number of matches. The name prefix is normalized like names.
+func (s *ConfigService) ListConfigs(ctx context.Context, filter models.ConfigFilter) (*models.ConfigListResponse, error) {
+	filter.NamePrefix = s.normalizeName(filter.NamePrefix)
+	configs, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ConfigListResponse{
+		Configs: configs,
+		Total:   total,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+	}, nil
+}
+
+// GetConfigValue returns the value an RFC 6901 JSON Pointer refers to within
+// the latest data of a configuration. With redact, the pointer resolves
+// against the redacted data, so a secret field reads as masked.
+func (s *ConfigService) GetConfigValue(ctx context.Context, name, pointer string, redact bool) (interface{}, error) {
+	if _, err := models.ParsePointer(pointer); err != nil {
+		return nil, &models.ValidationError{Field: "pointer", Message: err.Error()}
+	}
+
+	config, err := s.GetConfig(ctx, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	if redact {
+		config = s.RedactConfig(config)
+	}
+
+	value, ok := models.LookupPointer(config.Data, pointer)
+	if !ok {
+		return nil, &models.PointerNotFoundError{Name: config.Name, Pointer: pointer}
+	}
+	return value, nil
+}
+
+// BatchGetConfigs retrieves the latest version of several configurations from
+// one consistent snapshot. Names that are missing or invalid are reported in
+// the response errors rather than failing the whole request.
+func (s *ConfigService) BatchGetConfigs(ctx context.Context, req *models.BatchGetRequest) (*models.BatchGetResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if s.opts.MaxBatchNames > 0 && len(req.Names) > s.opts.MaxBatchNames {
+		return nil, &models.ValidationError{
+			Field:   "names",
+			Message: fmt.Sprintf("%d names exceeds maximum of %d", len(req.Names), s.opts.MaxBatchNames),
+		}
+	}
+
+	resp := &models.BatchGetResponse{
+		Configs: make(map[string]*models.Config),
+		Errors:  make(map[string]models.ErrorResponse),
+	}
+
+	// Look configs up by normalized name but report them under the requested name
+	lookup := make([]string, 0, len(req.Names))
+	for _, name := range req.Names {
+		normalized := s.normalizeName(name)
+		if err := validateName(normalized); err != nil {
+			resp.Errors[name] = models.ErrorResponse{Error: err.Error()}
+			continue
+		}
+		lookup = append(lookup, normalized)
+	}
+
+	configs, err := s.repo.GetMany(ctx, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range req.Names {
+		if _, failed := resp.Errors[name]; failed {
+			continue
+		}
+		normalized := s.normalizeName(name)
+		if config, ok := configs[normalized]; ok {
+			resp.Configs[name] = config
+			continue
+		}
+		resp.Errors[name] = models.ErrorResponse{Error: (&models.ConfigNotFoundError{Name: normalized}).Error()}
+	}
+
+	return resp, nil
+}
+
+// UpdateConfig replaces the data of an existing configuration. A field set
+// to null is cleared unless its schema accepts null as a value. Types
+// registered with a schema pair are validated against their update schema.
+// When version limits are configured the update runs in a transaction, so
+// concurrent updates cannot together write past a limit.
+func (s *ConfigService) UpdateConfig(ctx context.Context, name string, req *models.UpdateConfigRequest) (*models.Config, error) {
+	var config *models.Config
+	update := func(repo repository.ConfigRepository) error {
+		var err error
+		config, err = s.updateConfig(ctx, repo, name, req)
+		return err
+	}
+
+	var err error
+	if s.versionLimited() {
+		err = s.repo.WithTransaction(ctx, update)
+	} else {
+		err = update(s.repo)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return written(config, s.afterWrite(ctx, hookUpdate, config))
+}
+
+// updateConfig validates and updates a configuration in the given repository
+func (s *ConfigService) updateConfig(ctx context.Context, repo repository.ConfigRepository, name string, req *models.UpdateConfigRequest) (*models.Config, error) {
+	name = s.normalizeName(name)
 	if name == "" {
 		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
 	}
@@ -101,33 +333,91 @@ func (s *ConfigService) UpdateConfig(name string, req *models.UpdateConfigReques
 		return nil, err
 	}
 
+	if err := s.checkDataLimits(req.Data); err != nil {
+		return nil, err
+	}
+
 	// Get existing config to retrieve type
-	existing, err := s.repo.Get(name)
+	existing, err := repo.Get(ctx, name)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkUnlocked(existing); err != nil {
+		return nil, err
+	}
+	if err := s.checkVersionLimit(ctx, repo, existing); err != nil {
+		return nil, err
+	}
+
+	// An explicit null clears an optional field; clearing a required one fails validation
+	data := s.validator.RemoveNulls(existing.Type, req.Data)
 
 	// Validate data against schema
-	if err := s.validator.Validate(existing.Type, req.Data); err != nil {
-		return nil, &models.SchemaValidationError{Details: err.Error()}
+	if err := s.validator.ValidateUpdate(existing.Type, data); err != nil {
+		return nil, &models.SchemaValidationError{Details: err.Error(), Suggestions: validation.Suggestions(err), Violations: validation.Violations(err)}
+	}
+	schemaHash, _ := s.validator.SchemaHash(existing.Type)
+
+	// Keep the existing rollout unless a new one is provided
+	rollout := existing.Rollout
+	if req.Rollout != nil {
+		if err := validation.ValidateRollout(req.Rollout); err != nil {
+			return nil, &models.SchemaValidationError{Details: err.Error()}
+		}
+		rollout = req.Rollout
+	}
+
+	// Keep existing dependencies unless new ones are provided
+	dependsOn := existing.DependsOn
+	if req.DependsOn != nil {
+		dependsOn = s.normalizeNames(req.DependsOn)
+		if err := s.validateDependencies(ctx, repo, name, dependsOn); err != nil {
+			return nil, err
+		}
 	}
 
 	// Update config
 	config := &models.Config{
-		Name: name,
-		Type: existing.Type,
-		Data: req.Data,
+		Name:      name,
+		Type:      existing.Type,
+		Data:      data,
+		DependsOn: dependsOn,
+		UpdatedBy: req.Actor,
+		Origin:    originOrAPI(req.Origin),
+		Locked:    req.Locked,
+		Rollout:   rollout,
+		Message:   req.Message,
+
+		SchemaHash: schemaHash,
 	}
+	s.applyTTL(config)
 
-	if err := s.repo.Update(config); err != nil {
+	if err := repo.Update(ctx, config); err != nil {
 		return nil, err
 	}
 
 	return config, nil
 }
 
-// RollbackConfig rolls back a configuration to a previous version
-func (s *ConfigService) RollbackConfig(name string, req *models.RollbackRequest) (*models.Config, error) {
+// RollbackConfig rolls back a configuration to a previous version. The
+// target is resolved and written in one transaction, so a concurrent write
+// cannot land between reading the current version and rolling back from it.
+func (s *ConfigService) RollbackConfig(ctx context.Context, name string, req *models.RollbackRequest) (*models.Config, error) {
+	var config *models.Config
+	err := s.repo.WithTransaction(ctx, func(tx repository.ConfigRepository) error {
+		var err error
+		config, err = s.rollbackConfig(ctx, tx, name, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return written(config, s.afterWrite(ctx, hookUpdate, config))
+}
+
+// rollbackConfig validates and applies a rollback in the given repository
+func (s *ConfigService) rollbackConfig(ctx context.Context, repo repository.ConfigRepository, name string, req *models.RollbackRequest) (*models.Config, error) {
+	name = s.normalizeName(name)
 	if name == "" {
 		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
 	}
@@ -137,47 +427,258 @@ func (s *ConfigService) RollbackConfig(name string, req *models.RollbackRequest)
 		return nil, err
 	}
 
-	// Get the target version
-	targetVersion, err := s.repo.GetVersion(name, req.Version)
+	// Get current config to retrieve type
+	current, err := repo.Get(ctx, name)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkUnlocked(current); err != nil {
+		return nil, err
+	}
+	if err := s.checkRollbackAllowed(current); err != nil {
+		return nil, err
+	}
+	if err := s.checkVersionLimit(ctx, repo, current); err != nil {
+		return nil, err
+	}
 
-	// Get current config to retrieve type
-	current, err := s.repo.Get(name)
+	// Resolve the target version number
+	target := req.Version
+	if req.Tag != "" {
+		target, err = repo.GetTag(ctx, name, req.Tag)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.Steps > 0 {
+		target = current.Version - req.Steps
+		if target < 1 {
+			return nil, &models.ValidationError{
+				Field:   "steps",
+				Message: fmt.Sprintf("cannot roll back %d steps from version %d", req.Steps, current.Version),
+			}
+		}
+	}
+
+	// Get the target version
+	targetVersion, err := repo.GetVersion(ctx, name, target)
 	if err != nil {
 		return nil, err
 	}
 
 	// Validate the historical data against current schema
-	// (in case schema has changed since that version)
-	if err := s.validator.Validate(current.Type, targetVersion.Data); err != nil {
-		return nil, &models.SchemaValidationError{
-			Details: fmt.Sprintf("target version data is incompatible with current schema: %s", err.Error()),
+	// (in case schema has changed since that version), unless forced
+	var schemaHash string
+	if !req.Force {
+		if err := s.validator.ValidateUpdate(current.Type, targetVersion.Data); err != nil {
+			return nil, &models.SchemaValidationError{
+				Details:    fmt.Sprintf("target version data is incompatible with current schema: %s", err.Error()),
+				Violations: validation.Violations(err),
+			}
 		}
+		schemaHash, _ = s.validator.SchemaHash(current.Type)
 	}
 
 	// Create a new version with the historical data
 	config := &models.Config{
-		Name: name,
-		Type: current.Type,
-		Data: targetVersion.Data,
+		Name:      name,
+		Type:      current.Type,
+		Data:      targetVersion.Data,
+		DependsOn: current.DependsOn,
+		UpdatedBy: req.Actor,
+		Origin:    models.OriginRollback,
+		Rollout:   current.Rollout,
+		Message:   fmt.Sprintf("rolled back to v%d", target),
+
+		SchemaHash: schemaHash,
 	}
+	s.applyTTL(config)
 
-	if err := s.repo.Update(config); err != nil {
+	if err := repo.Rollback(ctx, config, target); err != nil {
 		return nil, err
 	}
 
 	return config, nil
 }
 
+// TagVersion assigns a tag to a specific version of a configuration
+func (s *ConfigService) TagVersion(ctx context.Context, name string, req *models.TagRequest) (*models.TagResponse, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetTag(ctx, name, req.Tag, req.Version); err != nil {
+		return nil, err
+	}
+
+	return &models.TagResponse{Name: name, Tag: req.Tag, Version: req.Version}, nil
+}
+
+// ResolveTag returns the version number a tag points at
+func (s *ConfigService) ResolveTag(ctx context.Context, name, tag string) (int, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return 0, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	return s.repo.GetTag(ctx, name, tag)
+}
+
+// ResolveVersionAt returns the number of the version that was current at a
+// point in time: the latest one created at or before it. It fails with
+// NoVersionAtError if the configuration did not exist yet.
+func (s *ConfigService) ResolveVersionAt(ctx context.Context, name string, at time.Time) (int, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return 0, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	version, err := s.repo.VersionAt(ctx, name, at)
+	if err != nil {
+		return 0, err
+	}
+	return version.Version, nil
+}
+
+// DeleteConfig deletes a configuration.
+// Deleting a configuration that others depend on requires force.
+func (s *ConfigService) DeleteConfig(ctx context.Context, name string, force bool) error {
+	name = s.normalizeName(name)
+	if name == "" {
+		return &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	return s.repo.WithTransaction(ctx, func(tx repository.ConfigRepository) error {
+		current, err := tx.Get(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := checkUnlocked(current); err != nil {
+			return err
+		}
+
+		dependents, err := tx.ListDependents(ctx, name)
+		if err != nil {
+			return err
+		}
+		if len(dependents) > 0 && !force {
+			return &models.ConfigInUseError{Name: name, Dependents: dependents}
+		}
+		return tx.Delete(ctx, name)
+	})
+}
+
+// ListDependents lists the configurations that depend on a configuration
+func (s *ConfigService) ListDependents(ctx context.Context, name string) (*models.DependentsResponse, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	dependents, err := s.repo.ListDependents(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DependentsResponse{
+		Name:       name,
+		Dependents: dependents,
+	}, nil
+}
+
+// validateDependencies ensures every referenced configuration exists
+func (s *ConfigService) validateDependencies(ctx context.Context, repo repository.ConfigRepository, name string, dependsOn []string) error {
+	var missing []string
+	for _, ref := range dependsOn {
+		if ref == name {
+			return &models.ValidationError{Field: "depends_on", Message: "config cannot depend on itself"}
+		}
+		if !repo.Exists(ctx, ref) {
+			missing = append(missing, ref)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &models.ValidationError{
+			Field:   "depends_on",
+			Message: fmt.Sprintf("referenced configs not found: %s", strings.Join(missing, ", ")),
+		}
+	}
+	return nil
+}
+
+// ExecuteTransaction applies a set of operations atomically.
+// Each operation is validated and applied in order; if any fails, none are kept.
+func (s *ConfigService) ExecuteTransaction(ctx context.Context, req *models.TransactionRequest) (*models.TransactionResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	response := &models.TransactionResponse{}
+	err := s.repo.WithTransaction(ctx, func(tx repository.ConfigRepository) error {
+		for i, op := range req.Operations {
+			config, err := s.applyOperation(ctx, tx, op, req.Actor)
+			if err != nil {
+				return &models.TransactionError{Index: i, Err: err}
+			}
+			response.Configs = append(response.Configs, config)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Hooks only see committed writes
+	var hookErr error
+	for i, config := range response.Configs {
+		event := hookUpdate
+		if req.Operations[i].Op == models.OperationCreate {
+			event = hookCreate
+		}
+		if err := s.afterWrite(ctx, event, config); err != nil {
+			if !WriteCommitted(err) {
+				return nil, err
+			}
+			if hookErr == nil {
+				hookErr = err
+			}
+		}
+	}
+
+	return response, hookErr
+}
+
+// applyOperation applies a single transaction operation to the given repository
+func (s *ConfigService) applyOperation(ctx context.Context, repo repository.ConfigRepository, op models.TransactionOperation, actor string) (*models.Config, error) {
+	switch op.Op {
+	case models.OperationCreate:
+		return s.createConfig(ctx, repo, &models.CreateConfigRequest{Name: op.Name, Type: op.Type, Data: op.Data, DependsOn: op.DependsOn, Message: op.Message, Actor: actor})
+	case models.OperationUpdate:
+		return s.updateConfig(ctx, repo, op.Name, &models.UpdateConfigRequest{Data: op.Data, DependsOn: op.DependsOn, Message: op.Message, Actor: actor})
+	case models.OperationRollback:
+		return s.rollbackConfig(ctx, repo, op.Name, &models.RollbackRequest{Version: op.Version, Actor: actor})
+	default:
+		return nil, &models.ValidationError{
+			Field:   "op",
+			Message: fmt.Sprintf("unknown operation: %s", op.Op),
+		}
+	}
+}
+
 // ListVersions lists all versions of a configuration
-func (s *ConfigService) ListVersions(name string) (*models.VersionsResponse, error) {
+func (s *ConfigService) ListVersions(ctx context.Context, name string) (*models.VersionsResponse, error) {
+	name = s.normalizeName(name)
 	if name == "" {
 		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
 	}
 
-	versions, err := s.repo.ListVersions(name)
+	versions, err := s.repo.ListVersions(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -186,4 +687,53 @@ func (s *ConfigService) ListVersions(name string) (*models.VersionsResponse, err
 		Name:     name,
 		Versions: versions,
 	}, nil
-}
\ No newline at end of file
+}
+
+// RedactConfig returns a copy of the config with secret fields masked
+func (s *ConfigService) RedactConfig(config *models.Config) *models.Config {
+	redacted := *config
+	redacted.Data = s.validator.Redact(config.Type, config.Data)
+	return &redacted
+}
+
+// RedactVersions returns a copy of the versions response with secret fields masked
+func (s *ConfigService) RedactVersions(ctx context.Context, resp *models.VersionsResponse) (*models.VersionsResponse, error) {
+	config, err := s.repo.Get(ctx, resp.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	redacted := &models.VersionsResponse{
+		Name:     resp.Name,
+		Versions: make([]models.ConfigVersion, len(resp.Versions)),
+	}
+	for i, version := range resp.Versions {
+		version.Data = s.validator.Redact(config.Type, version.Data)
+		redacted.Versions[i] = version
+	}
+	return redacted, nil
+}
+
+// Health checks the status of the service dependencies. Process metadata
+// such as uptime and build info is left for the caller to fill in.
+func (s *ConfigService) Health(ctx context.Context) *models.HealthResponse {
+	health := &models.HealthResponse{
+		Status:     "running",
+		Components: make(map[string]models.ComponentHealth),
+	}
+
+	checks := map[string]func() error{
+		"repository": func() error { return s.repo.Ping(ctx) },
+		"validator":  s.validator.Ready,
+	}
+	for component, check := range checks {
+		if err := check(); err != nil {
+			health.Status = "unavailable"
+			health.Components[component] = models.ComponentHealth{Status: "down", Error: err.Error()}
+			continue
+		}
+		health.Components[component] = models.ComponentHealth{Status: "up"}
+	}
+
+	return health
+}
@@ -1,10 +1,19 @@
 package service
 
 import (
+	"config-engine/internal/audit"
 	"config-engine/internal/models"
 	"config-engine/internal/repository"
 	"config-engine/internal/validation"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func setupService(t *testing.T) *ConfigService {
@@ -28,7 +37,7 @@ func TestCreateConfig(t *testing.T) {
 		},
 	}
 
-	config, err := svc.CreateConfig(req)
+	config, err := svc.CreateConfig(context.Background(), req)
 	if err != nil {
 		t.Fatalf("Failed to create config: %v", err)
 	}
@@ -108,7 +117,7 @@ func TestCreateConfigValidation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := svc.CreateConfig(tt.req)
+			_, err := svc.CreateConfig(context.Background(), tt.req)
 			if tt.expectError && err == nil {
 				t.Error("Expected error but got none")
 			}
@@ -128,10 +137,10 @@ func TestGetConfig(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	svc.CreateConfig(createReq)
+	svc.CreateConfig(context.Background(), createReq)
 
 	// Get config
-	config, err := svc.GetConfig("test_config", nil)
+	config, err := svc.GetConfig(context.Background(), "test_config", nil)
 	if err != nil {
 		t.Fatalf("Failed to get config: %v", err)
 	}
@@ -148,7 +157,7 @@ func TestGetConfig(t *testing.T) {
 func TestGetConfigNotFound(t *testing.T) {
 	svc := setupService(t)
 
-	_, err := svc.GetConfig("nonexistent", nil)
+	_, err := svc.GetConfig(context.Background(), "nonexistent", nil)
 	if _, ok := err.(*models.ConfigNotFoundError); !ok {
 		t.Errorf("Expected ConfigNotFoundError, got %v", err)
 	}
@@ -163,17 +172,17 @@ func TestGetConfigSpecificVersion(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	svc.CreateConfig(createReq)
+	svc.CreateConfig(context.Background(), createReq)
 
 	// Update config
 	updateReq := &models.UpdateConfigRequest{
 		Data: map[string]interface{}{"max_limit": 2000, "enabled": false},
 	}
-	svc.UpdateConfig("test_config", updateReq)
+	svc.UpdateConfig(context.Background(), "test_config", updateReq)
 
 	// Get version 1
 	version := 1
-	config, err := svc.GetConfig("test_config", &version)
+	config, err := svc.GetConfig(context.Background(), "test_config", &version)
 	if err != nil {
 		t.Fatalf("Failed to get version 1: %v", err)
 	}
@@ -187,6 +196,51 @@ func TestGetConfigSpecificVersion(t *testing.T) {
 	}
 }
 
+func TestBatchGetConfigs(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	opts := DefaultOptions()
+	opts.MaxBatchNames = 4
+	svc := NewConfigServiceWithOptions(repository.NewInMemoryRepository(), validator, opts)
+
+	for _, name := range []string{"alpha", "beta"} {
+		svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+		})
+	}
+
+	resp, err := svc.BatchGetConfigs(context.Background(), &models.BatchGetRequest{Names: []string{"alpha", "Beta", "missing", "bad name"}})
+	if err != nil {
+		t.Fatalf("Failed to batch get: %v", err)
+	}
+	if len(resp.Configs) != 2 || resp.Configs["alpha"] == nil || resp.Configs["Beta"] == nil {
+		t.Fatalf("Expected configs for alpha and Beta, got %v", resp.Configs)
+	}
+	if resp.Configs["Beta"].Name != "beta" {
+		t.Errorf("Expected Beta to resolve to beta, got %s", resp.Configs["Beta"].Name)
+	}
+	if len(resp.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", resp.Errors)
+	}
+	if resp.Errors["missing"].Error != "configuration not found: missing" {
+		t.Errorf("Unexpected error for missing: %q", resp.Errors["missing"].Error)
+	}
+	if _, ok := resp.Errors["bad name"]; !ok {
+		t.Error("Expected an error for an invalid name")
+	}
+
+	_, err = svc.BatchGetConfigs(context.Background(), &models.BatchGetRequest{Names: []string{"a", "b", "c", "d", "e"}})
+	if _, ok := err.(*models.ValidationError); !ok {
+		t.Errorf("Expected ValidationError above the name cap, got %v", err)
+	}
+
+	_, err = svc.BatchGetConfigs(context.Background(), &models.BatchGetRequest{})
+	if _, ok := err.(*models.ValidationError); !ok {
+		t.Errorf("Expected ValidationError for no names, got %v", err)
+	}
+}
+
 func TestUpdateConfig(t *testing.T) {
 	svc := setupService(t)
 
@@ -196,14 +250,14 @@ func TestUpdateConfig(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	svc.CreateConfig(createReq)
+	svc.CreateConfig(context.Background(), createReq)
 
 	// Update config
 	updateReq := &models.UpdateConfigRequest{
 		Data: map[string]interface{}{"max_limit": 2000, "enabled": false},
 	}
 
-	config, err := svc.UpdateConfig("test_config", updateReq)
+	config, err := svc.UpdateConfig(context.Background(), "test_config", updateReq)
 	if err != nil {
 		t.Fatalf("Failed to update config: %v", err)
 	}
@@ -226,14 +280,14 @@ func TestUpdateConfigValidation(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	svc.CreateConfig(createReq)
+	svc.CreateConfig(context.Background(), createReq)
 
 	// Try to update with invalid data
 	updateReq := &models.UpdateConfigRequest{
 		Data: map[string]interface{}{"max_limit": "invalid"},
 	}
 
-	_, err := svc.UpdateConfig("test_config", updateReq)
+	_, err := svc.UpdateConfig(context.Background(), "test_config", updateReq)
 	if _, ok := err.(*models.SchemaValidationError); !ok {
 		t.Errorf("Expected SchemaValidationError, got %v", err)
 	}
@@ -246,12 +300,209 @@ func TestUpdateConfigNotFound(t *testing.T) {
 		Data: map[string]interface{}{"max_limit": 2000, "enabled": false},
 	}
 
-	_, err := svc.UpdateConfig("nonexistent", updateReq)
+	_, err := svc.UpdateConfig(context.Background(), "nonexistent", updateReq)
+	if _, ok := err.(*models.ConfigNotFoundError); !ok {
+		t.Errorf("Expected ConfigNotFoundError, got %v", err)
+	}
+}
+
+func TestPatchConfig(t *testing.T) {
+	svc := setupService(t)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "test_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+
+	// The patch omits the required enabled field, which the merged document still has
+	patch := &models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": 2000}}
+	config, err := svc.PatchConfig(context.Background(), "test_config", patch)
+	if err != nil {
+		t.Fatalf("Expected patch to succeed: %v", err)
+	}
+	if config.Version != 2 || config.Data["max_limit"] != 2000 || config.Data["enabled"] != true {
+		t.Errorf("Expected version 2 with merged data, got version %d %v", config.Version, config.Data)
+	}
+
+	// A full update still requires every field
+	_, err = svc.UpdateConfig(context.Background(), "test_config", patch)
+	if _, ok := err.(*models.SchemaValidationError); !ok {
+		t.Errorf("Expected SchemaValidationError for a partial PUT, got %v", err)
+	}
+
+	// Removing a required field with null fails validation of the merged document
+	_, err = svc.PatchConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"enabled": nil},
+	})
+	if _, ok := err.(*models.SchemaValidationError); !ok {
+		t.Errorf("Expected SchemaValidationError when removing a required field, got %v", err)
+	}
+
+	_, err = svc.PatchConfig(context.Background(), "missing", patch)
 	if _, ok := err.(*models.ConfigNotFoundError); !ok {
 		t.Errorf("Expected ConfigNotFoundError, got %v", err)
 	}
 }
 
+func TestUpdateClearsOptionalFieldsWithNull(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	validator.RegisterSchema("limits_config", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"max_limit": map[string]interface{}{"type": "integer"},
+			"note":      map[string]interface{}{"type": "string"},
+			"owner":     map[string]interface{}{"type": []interface{}{"string", "null"}},
+			"limits": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"daily": map[string]interface{}{"type": "integer"}},
+			},
+		},
+		"required":             []interface{}{"max_limit"},
+		"additionalProperties": false,
+	})
+	svc := NewConfigService(repository.NewInMemoryRepository(), validator)
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "limits",
+		Type: "limits_config",
+		Data: map[string]interface{}{"max_limit": 1000, "note": "launch", "owner": "payments", "limits": map[string]interface{}{"daily": 10}},
+	})
+
+	// A full update clears optional fields set to null, but keeps nulls the schema allows
+	config, err := svc.UpdateConfig(context.Background(), "limits", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 1000, "note": nil, "owner": nil, "limits": map[string]interface{}{"daily": nil}},
+	})
+	if err != nil {
+		t.Fatalf("Expected clearing optional fields to succeed: %v", err)
+	}
+	if _, exists := config.Data["note"]; exists {
+		t.Errorf("Expected note to be cleared, got %v", config.Data)
+	}
+	if owner, exists := config.Data["owner"]; !exists || owner != nil {
+		t.Errorf("Expected owner to be stored as null, got %v", config.Data)
+	}
+	if limits := config.Data["limits"].(map[string]interface{}); len(limits) != 0 {
+		t.Errorf("Expected limits.daily to be cleared, got %v", limits)
+	}
+
+	// A merge patch clears an optional field the same way
+	config, err = svc.PatchConfig(context.Background(), "limits", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"limits": nil},
+	})
+	if err != nil {
+		t.Fatalf("Expected patch to succeed: %v", err)
+	}
+	if _, exists := config.Data["limits"]; exists {
+		t.Errorf("Expected limits to be cleared, got %v", config.Data)
+	}
+
+	// Clearing a required field is rejected as missing, not as a type error
+	for _, clear := range []func() error{
+		func() error {
+			_, err := svc.UpdateConfig(context.Background(), "limits", &models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": nil}})
+			return err
+		},
+		func() error {
+			_, err := svc.PatchConfig(context.Background(), "limits", &models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": nil}})
+			return err
+		},
+	} {
+		err := clear()
+		schemaErr, ok := err.(*models.SchemaValidationError)
+		if !ok || !strings.Contains(schemaErr.Details, "max_limit is required") {
+			t.Errorf("Expected max_limit to be reported as required, got %v", err)
+		}
+	}
+}
+
+func TestCreateAndUpdateSchemas(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	properties := map[string]interface{}{
+		"owner":     map[string]interface{}{"type": "string"},
+		"max_limit": map[string]interface{}{"type": "integer"},
+	}
+	if err := validator.RegisterSchemaPair("team_config",
+		map[string]interface{}{"type": "object", "properties": properties, "required": []interface{}{"owner", "max_limit"}},
+		map[string]interface{}{"type": "object", "properties": properties, "required": []interface{}{"max_limit"}},
+	); err != nil {
+		t.Fatalf("Failed to register schema pair: %v", err)
+	}
+	svc := NewConfigService(repository.NewInMemoryRepository(), validator)
+
+	// Create requires owner
+	_, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "team",
+		Type: "team_config",
+		Data: map[string]interface{}{"max_limit": 100},
+	})
+	if schemaErr, ok := err.(*models.SchemaValidationError); !ok || !strings.Contains(schemaErr.Details, "owner is required") {
+		t.Fatalf("Expected owner to be required on create, got %v", err)
+	}
+	if _, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "team",
+		Type: "team_config",
+		Data: map[string]interface{}{"owner": "payments", "max_limit": 100},
+	}); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	// Update may omit owner but still needs max_limit
+	config, err := svc.UpdateConfig(context.Background(), "team", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 200},
+	})
+	if err != nil {
+		t.Fatalf("Expected update without owner to succeed: %v", err)
+	}
+	if config.Version != 2 {
+		t.Errorf("Expected version 2, got %d", config.Version)
+	}
+	_, err = svc.UpdateConfig(context.Background(), "team", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"owner": "payments"},
+	})
+	if schemaErr, ok := err.(*models.SchemaValidationError); !ok || !strings.Contains(schemaErr.Details, "max_limit is required") {
+		t.Errorf("Expected max_limit to be required on update, got %v", err)
+	}
+
+	// Types registered with a single schema use it for both
+	_, err = svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	_, err = svc.UpdateConfig(context.Background(), "payments", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 100},
+	})
+	if _, ok := err.(*models.SchemaValidationError); !ok {
+		t.Errorf("Expected the single schema to require enabled on update, got %v", err)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	target := map[string]interface{}{
+		"a": 1,
+		"b": map[string]interface{}{"c": 2, "d": 3},
+		"e": []interface{}{1, 2},
+	}
+	patch := map[string]interface{}{
+		"a": nil,
+		"b": map[string]interface{}{"c": nil, "f": 4},
+		"e": []interface{}{3},
+		"g": map[string]interface{}{"h": 5},
+	}
+
+	merged := mergePatch(target, patch)
+	want := `map[b:map[d:3 f:4] e:[3] g:map[h:5]]`
+	if got := fmt.Sprint(merged); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if _, ok := target["a"]; !ok {
+		t.Error("Expected the target to be left unchanged")
+	}
+}
+
 func TestRollbackConfig(t *testing.T) {
 	svc := setupService(t)
 
@@ -261,20 +512,20 @@ func TestRollbackConfig(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	svc.CreateConfig(createReq)
+	svc.CreateConfig(context.Background(), createReq)
 
 	// Update config multiple times
-	svc.UpdateConfig("test_config", &models.UpdateConfigRequest{
+	svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
 		Data: map[string]interface{}{"max_limit": 2000, "enabled": false},
 	})
 
-	svc.UpdateConfig("test_config", &models.UpdateConfigRequest{
+	svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
 		Data: map[string]interface{}{"max_limit": 3000, "enabled": true},
 	})
 
 	// Rollback to version 1
 	rollbackReq := &models.RollbackRequest{Version: 1}
-	config, err := svc.RollbackConfig("test_config", rollbackReq)
+	config, err := svc.RollbackConfig(context.Background(), "test_config", rollbackReq)
 	if err != nil {
 		t.Fatalf("Failed to rollback: %v", err)
 	}
@@ -293,6 +544,105 @@ func TestRollbackConfig(t *testing.T) {
 	}
 }
 
+func TestConfigOrigin(t *testing.T) {
+	svc := setupService(t)
+
+	config, _ := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "test_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	if config.Origin != models.OriginAPI {
+		t.Errorf("Expected origin %q after create, got %q", models.OriginAPI, config.Origin)
+	}
+
+	svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 2000, "enabled": false},
+	})
+	config, err := svc.RollbackConfig(context.Background(), "test_config", &models.RollbackRequest{Version: 1})
+	if err != nil {
+		t.Fatalf("Failed to rollback: %v", err)
+	}
+	if config.Origin != models.OriginRollback {
+		t.Errorf("Expected origin %q after rollback, got %q", models.OriginRollback, config.Origin)
+	}
+
+	versions, err := svc.ListVersions(context.Background(), "test_config")
+	if err != nil {
+		t.Fatalf("Failed to list versions: %v", err)
+	}
+	want := []string{models.OriginAPI, models.OriginAPI, models.OriginRollback}
+	if len(versions.Versions) != len(want) {
+		t.Fatalf("Expected %d versions, got %d", len(want), len(versions.Versions))
+	}
+	for i, v := range versions.Versions {
+		if v.Origin != want[i] {
+			t.Errorf("Expected version %d origin %q, got %q", v.Version, want[i], v.Origin)
+		}
+	}
+
+	version := 2
+	old, err := svc.GetConfig(context.Background(), "test_config", &version)
+	if err != nil {
+		t.Fatalf("Failed to get version 2: %v", err)
+	}
+	if old.Origin != models.OriginAPI {
+		t.Errorf("Expected version 2 origin %q, got %q", models.OriginAPI, old.Origin)
+	}
+}
+
+func TestLockedConfig(t *testing.T) {
+	svc := setupService(t)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name:   "test_config",
+		Type:   "payment_config",
+		Data:   map[string]interface{}{"max_limit": 1000, "enabled": true},
+		Locked: true,
+	})
+
+	_, err := svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 2000, "enabled": true},
+	})
+	if _, ok := err.(*models.ConfigLockedError); !ok {
+		t.Errorf("Expected ConfigLockedError on update, got %v", err)
+	}
+	_, err = svc.RollbackConfig(context.Background(), "test_config", &models.RollbackRequest{Version: 1})
+	if _, ok := err.(*models.ConfigLockedError); !ok {
+		t.Errorf("Expected ConfigLockedError on rollback, got %v", err)
+	}
+	err = svc.DeleteConfig(context.Background(), "test_config", true)
+	if _, ok := err.(*models.ConfigLockedError); !ok {
+		t.Errorf("Expected ConfigLockedError on delete, got %v", err)
+	}
+
+	config, err := svc.UnlockConfig(context.Background(), "test_config")
+	if err != nil {
+		t.Fatalf("Failed to unlock: %v", err)
+	}
+	if config.Locked || config.Version != 1 {
+		t.Errorf("Expected unlocked version 1, got locked=%v version %d", config.Locked, config.Version)
+	}
+
+	// Updating after unlock succeeds and may lock the config again
+	config, err = svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data:   map[string]interface{}{"max_limit": 2000, "enabled": true},
+		Locked: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected update after unlock to succeed: %v", err)
+	}
+	if !config.Locked {
+		t.Error("Expected update with locked set to lock the config again")
+	}
+	_, err = svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 3000, "enabled": true},
+	})
+	if _, ok := err.(*models.ConfigLockedError); !ok {
+		t.Errorf("Expected ConfigLockedError after relocking, got %v", err)
+	}
+}
+
 func TestRollbackConfigInvalidVersion(t *testing.T) {
 	svc := setupService(t)
 
@@ -302,11 +652,11 @@ func TestRollbackConfigInvalidVersion(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	svc.CreateConfig(createReq)
+	svc.CreateConfig(context.Background(), createReq)
 
 	// Try to rollback to non-existent version
 	rollbackReq := &models.RollbackRequest{Version: 10}
-	_, err := svc.RollbackConfig("test_config", rollbackReq)
+	_, err := svc.RollbackConfig(context.Background(), "test_config", rollbackReq)
 
 	if _, ok := err.(*models.VersionNotFoundError); !ok {
 		t.Errorf("Expected VersionNotFoundError, got %v", err)
@@ -344,11 +694,11 @@ func TestRollbackConfigValidation(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	svc.CreateConfig(createReq)
+	svc.CreateConfig(context.Background(), createReq)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := svc.RollbackConfig("test_config", tt.req)
+			_, err := svc.RollbackConfig(context.Background(), "test_config", tt.req)
 			if tt.expectError && err == nil {
 				t.Error("Expected error but got none")
 			}
@@ -365,19 +715,19 @@ func TestListVersions(t *testing.T) {
 		Type: "payment_config",
 		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
 	}
-	svc.CreateConfig(createReq)
+	svc.CreateConfig(context.Background(), createReq)
 
 	// Update multiple times
-	svc.UpdateConfig("test_config", &models.UpdateConfigRequest{
+	svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
 		Data: map[string]interface{}{"max_limit": 2000, "enabled": false},
 	})
 
-	svc.UpdateConfig("test_config", &models.UpdateConfigRequest{
+	svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
 		Data: map[string]interface{}{"max_limit": 3000, "enabled": true},
 	})
 
 	// List versions
-	response, err := svc.ListVersions("test_config")
+	response, err := svc.ListVersions(context.Background(), "test_config")
 	if err != nil {
 		t.Fatalf("Failed to list versions: %v", err)
 	}
@@ -402,11 +752,1198 @@ func TestListVersions(t *testing.T) {
 	}
 }
 
+func TestListVersionsMetadata(t *testing.T) {
+	svc := setupService(t)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name:  "test_config",
+		Type:  "payment_config",
+		Data:  map[string]interface{}{"max_limit": 1000, "enabled": true},
+		Actor: "alice",
+	})
+	svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data:  map[string]interface{}{"max_limit": 2000, "enabled": true},
+		Actor: "bob",
+	})
+	svc.RollbackConfig(context.Background(), "test_config", &models.RollbackRequest{Version: 1, Actor: "carol"})
+
+	response, err := svc.ListVersions(context.Background(), "test_config")
+	if err != nil {
+		t.Fatalf("Failed to list versions: %v", err)
+	}
+
+	current := 0
+	for _, version := range response.Versions {
+		if version.IsCurrent {
+			current++
+			if version.Version != 3 {
+				t.Errorf("Expected version 3 to be current, got %d", version.Version)
+			}
+		}
+		if version.CreatedAt.IsZero() {
+			t.Errorf("Expected created_at on version %d", version.Version)
+		}
+	}
+	if current != 1 {
+		t.Errorf("Expected exactly one current version, got %d", current)
+	}
+
+	expectedActors := []string{"alice", "bob", "carol"}
+	for i, actor := range expectedActors {
+		if response.Versions[i].CreatedBy != actor {
+			t.Errorf("Expected version %d created by %s, got '%s'", i+1, actor, response.Versions[i].CreatedBy)
+		}
+	}
+}
+
 func TestListVersionsNotFound(t *testing.T) {
 	svc := setupService(t)
 
-	_, err := svc.ListVersions("nonexistent")
+	_, err := svc.ListVersions(context.Background(), "nonexistent")
 	if _, ok := err.(*models.ConfigNotFoundError); !ok {
 		t.Errorf("Expected ConfigNotFoundError, got %v", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestCustomRuleAppliedOnCreateAndUpdate(t *testing.T) {
+	svc := setupService(t)
+
+	_, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "test_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 0, "enabled": true},
+	})
+	if _, ok := err.(*models.SchemaValidationError); !ok {
+		t.Errorf("Expected SchemaValidationError on create, got %v", err)
+	}
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "test_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+
+	_, err = svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": -1, "enabled": true},
+	})
+	if _, ok := err.(*models.SchemaValidationError); !ok {
+		t.Errorf("Expected SchemaValidationError on update, got %v", err)
+	}
+}
+
+type unreachableRepository struct {
+	*repository.InMemoryRepository
+}
+
+func (r *unreachableRepository) Ping(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestHealth(t *testing.T) {
+	svc := setupService(t)
+
+	health := svc.Health(context.Background())
+	if health.Status != "running" {
+		t.Errorf("Expected status 'running', got '%s'", health.Status)
+	}
+	if health.Components["repository"].Status != "up" {
+		t.Errorf("Expected repository up, got %+v", health.Components["repository"])
+	}
+	if health.Components["validator"].Status != "up" {
+		t.Errorf("Expected validator up, got %+v", health.Components["validator"])
+	}
+
+	validator, _ := validation.NewValidator()
+	svc = NewConfigService(&unreachableRepository{repository.NewInMemoryRepository()}, validator)
+
+	health = svc.Health(context.Background())
+	if health.Status != "unavailable" {
+		t.Errorf("Expected status 'unavailable', got '%s'", health.Status)
+	}
+	if health.Components["repository"].Error != "connection refused" {
+		t.Errorf("Expected repository error, got %+v", health.Components["repository"])
+	}
+}
+
+func TestExecuteTransaction(t *testing.T) {
+	svc := setupService(t)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "existing",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+
+	resp, err := svc.ExecuteTransaction(context.Background(), &models.TransactionRequest{
+		Operations: []models.TransactionOperation{
+			{Op: models.OperationCreate, Name: "new", Type: "payment_config", Data: map[string]interface{}{"max_limit": 10, "enabled": true}},
+			{Op: models.OperationUpdate, Name: "existing", Data: map[string]interface{}{"max_limit": 2000, "enabled": false}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+	if len(resp.Configs) != 2 {
+		t.Errorf("Expected 2 configs, got %d", len(resp.Configs))
+	}
+
+	existing, _ := svc.GetConfig(context.Background(), "existing", nil)
+	if existing.Version != 2 {
+		t.Errorf("Expected version 2, got %d", existing.Version)
+	}
+}
+
+func TestExecuteTransactionAtomicity(t *testing.T) {
+	svc := setupService(t)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "existing",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+
+	_, err := svc.ExecuteTransaction(context.Background(), &models.TransactionRequest{
+		Operations: []models.TransactionOperation{
+			{Op: models.OperationUpdate, Name: "existing", Data: map[string]interface{}{"max_limit": 2000, "enabled": false}},
+			{Op: models.OperationCreate, Name: "new", Type: "payment_config", Data: map[string]interface{}{"max_limit": 10, "enabled": true}},
+			{Op: models.OperationUpdate, Name: "missing", Data: map[string]interface{}{"max_limit": 1, "enabled": true}},
+		},
+	})
+
+	txErr, ok := err.(*models.TransactionError)
+	if !ok {
+		t.Fatalf("Expected TransactionError, got %v", err)
+	}
+	if txErr.Index != 2 {
+		t.Errorf("Expected failing index 2, got %d", txErr.Index)
+	}
+
+	existing, _ := svc.GetConfig(context.Background(), "existing", nil)
+	if existing.Version != 1 {
+		t.Errorf("Expected version 1 after failed transaction, got %d", existing.Version)
+	}
+	if _, err := svc.GetConfig(context.Background(), "new", nil); err == nil {
+		t.Error("Config from failed transaction should not exist")
+	}
+}
+
+func TestConfigDependencies(t *testing.T) {
+	svc := setupService(t)
+
+	_, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name:      "routing",
+		Type:      "payment_config",
+		Data:      map[string]interface{}{"max_limit": 1000, "enabled": true},
+		DependsOn: []string{"payments"},
+	})
+	if _, ok := err.(*models.ValidationError); !ok {
+		t.Errorf("Expected ValidationError for missing reference, got %v", err)
+	}
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	_, err = svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name:      "routing",
+		Type:      "payment_config",
+		Data:      map[string]interface{}{"max_limit": 1000, "enabled": true},
+		DependsOn: []string{"payments"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	// Updates without depends_on keep existing dependencies
+	svc.UpdateConfig(context.Background(), "routing", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 2000, "enabled": true},
+	})
+	resp, _ := svc.ListDependents(context.Background(), "payments")
+	if len(resp.Dependents) != 1 || resp.Dependents[0] != "routing" {
+		t.Errorf("Expected [routing], got %v", resp.Dependents)
+	}
+
+	err = svc.DeleteConfig(context.Background(), "payments", false)
+	if _, ok := err.(*models.ConfigInUseError); !ok {
+		t.Errorf("Expected ConfigInUseError, got %v", err)
+	}
+
+	if err := svc.DeleteConfig(context.Background(), "payments", true); err != nil {
+		t.Errorf("Forced delete failed: %v", err)
+	}
+	if _, err := svc.GetConfig(context.Background(), "payments", nil); err == nil {
+		t.Error("Config should be deleted")
+	}
+}
+
+func TestRedactVersions(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	validator.RegisterSchema("gateway_config", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"api_key": map[string]interface{}{"type": "string", "secret": true},
+		},
+	})
+	svc := NewConfigService(repository.NewInMemoryRepository(), validator)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "gateway",
+		Type: "gateway_config",
+		Data: map[string]interface{}{"api_key": "v1"},
+	})
+	svc.UpdateConfig(context.Background(), "gateway", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"api_key": "v2"},
+	})
+
+	versions, _ := svc.ListVersions(context.Background(), "gateway")
+	redacted, err := svc.RedactVersions(context.Background(), versions)
+	if err != nil {
+		t.Fatalf("Failed to redact versions: %v", err)
+	}
+	for _, v := range redacted.Versions {
+		if v.Data["api_key"] != validation.RedactedValue {
+			t.Errorf("Expected version %d to be redacted, got %v", v.Version, v.Data["api_key"])
+		}
+	}
+
+	config, _ := svc.GetConfig(context.Background(), "gateway", nil)
+	if config.Data["api_key"] != "v2" {
+		t.Errorf("Stored data should be intact, got %v", config.Data["api_key"])
+	}
+}
+
+func TestRollbackConfigForce(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	svc := NewConfigService(repository.NewInMemoryRepository(), validator)
+
+	loose := map[string]interface{}{"type": "object"}
+	if err := validator.RegisterSchema("limits_config", loose); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "limits",
+		Type: "limits_config",
+		Data: map[string]interface{}{"max": 10},
+	})
+	svc.UpdateConfig(context.Background(), "limits", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max": 20, "min": 1},
+	})
+
+	// Tighten the schema so version 1 no longer validates
+	strict := map[string]interface{}{
+		"type":     "object",
+		"required": []string{"max", "min"},
+	}
+	if err := validator.RegisterSchema("limits_config", strict); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+
+	_, err := svc.RollbackConfig(context.Background(), "limits", &models.RollbackRequest{Version: 1})
+	var schemaErr *models.SchemaValidationError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("Expected SchemaValidationError without force, got %v", err)
+	}
+
+	config, err := svc.RollbackConfig(context.Background(), "limits", &models.RollbackRequest{Version: 1, Force: true})
+	if err != nil {
+		t.Fatalf("Expected forced rollback to succeed, got %v", err)
+	}
+	if config.Version != 3 {
+		t.Errorf("Expected version 3, got %d", config.Version)
+	}
+	if _, exists := config.Data["min"]; exists || config.Data["max"] != 10 {
+		t.Errorf("Expected raw version 1 data to be restored, got %v", config.Data)
+	}
+}
+
+func TestRollbackConfigBySteps(t *testing.T) {
+	svc := setupService(t)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "test_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 2000, "enabled": true},
+	})
+	svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 3000, "enabled": true},
+	})
+
+	// Undo the last change
+	config, err := svc.RollbackConfig(context.Background(), "test_config", &models.RollbackRequest{Steps: 1})
+	if err != nil {
+		t.Fatalf("Failed to rollback: %v", err)
+	}
+	if config.Version != 4 {
+		t.Errorf("Expected version 4, got %d", config.Version)
+	}
+	if config.Data["max_limit"].(int) != 2000 {
+		t.Errorf("Expected max_limit 2000, got %v", config.Data["max_limit"])
+	}
+
+	// Rolling back past version 1 fails
+	_, err = svc.RollbackConfig(context.Background(), "test_config", &models.RollbackRequest{Steps: 4})
+	if _, ok := err.(*models.ValidationError); !ok {
+		t.Errorf("Expected ValidationError for out-of-range steps, got %v", err)
+	}
+}
+
+func TestRollbackRequestVersionAndSteps(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       *models.RollbackRequest
+		wantField string
+	}{
+		{name: "version only", req: &models.RollbackRequest{Version: 1}},
+		{name: "steps only", req: &models.RollbackRequest{Steps: 1}},
+		{name: "tag only", req: &models.RollbackRequest{Tag: "last-known-good"}},
+		{name: "version and steps", req: &models.RollbackRequest{Version: 1, Steps: 1}, wantField: "steps"},
+		{name: "version and tag", req: &models.RollbackRequest{Version: 1, Tag: "stable"}, wantField: "tag"},
+		{name: "steps and tag", req: &models.RollbackRequest{Steps: 1, Tag: "stable"}, wantField: "tag"},
+		{name: "all three", req: &models.RollbackRequest{Version: 1, Steps: 1, Tag: "stable"}, wantField: "steps"},
+		{name: "neither", req: &models.RollbackRequest{}, wantField: "version"},
+		{name: "negative version", req: &models.RollbackRequest{Version: -1}, wantField: "version"},
+		{name: "negative steps", req: &models.RollbackRequest{Steps: -1}, wantField: "steps"},
+		{name: "blank tag", req: &models.RollbackRequest{Tag: "  "}, wantField: "tag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*models.ValidationError)
+			if !ok {
+				t.Fatalf("Expected a ValidationError, got %v", err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("Expected the error on %s, got %s: %s", tt.wantField, validationErr.Field, validationErr.Message)
+			}
+		})
+	}
+}
+
+func TestTagVersion(t *testing.T) {
+	svc := setupService(t)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "test_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 2000, "enabled": true},
+	})
+
+	if _, err := svc.TagVersion(context.Background(), "test_config", &models.TagRequest{Tag: "stable", Version: 1}); err != nil {
+		t.Fatalf("Failed to tag version: %v", err)
+	}
+
+	version, err := svc.ResolveTag(context.Background(), "test_config", "stable")
+	if err != nil || version != 1 {
+		t.Errorf("Expected stable -> 1, got %d (%v)", version, err)
+	}
+
+	// Re-tagging moves the tag
+	svc.TagVersion(context.Background(), "test_config", &models.TagRequest{Tag: "stable", Version: 2})
+	version, _ = svc.ResolveTag(context.Background(), "test_config", "stable")
+	if version != 2 {
+		t.Errorf("Expected stable -> 2 after re-tag, got %d", version)
+	}
+
+	_, err = svc.TagVersion(context.Background(), "test_config", &models.TagRequest{Tag: "future", Version: 5})
+	if _, ok := err.(*models.VersionNotFoundError); !ok {
+		t.Errorf("Expected VersionNotFoundError, got %v", err)
+	}
+
+	_, err = svc.ResolveTag(context.Background(), "test_config", "missing")
+	if _, ok := err.(*models.TagNotFoundError); !ok {
+		t.Errorf("Expected TagNotFoundError, got %v", err)
+	}
+}
+
+func TestRollbackConfigByTag(t *testing.T) {
+	svc := setupService(t)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "test_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	svc.TagVersion(context.Background(), "test_config", &models.TagRequest{Tag: "last-known-good", Version: 1})
+	svc.UpdateConfig(context.Background(), "test_config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 2000, "enabled": true},
+	})
+
+	config, err := svc.RollbackConfig(context.Background(), "test_config", &models.RollbackRequest{Tag: "last-known-good"})
+	if err != nil {
+		t.Fatalf("Failed to rollback: %v", err)
+	}
+	if config.Data["max_limit"].(int) != 1000 {
+		t.Errorf("Expected max_limit 1000, got %v", config.Data["max_limit"])
+	}
+}
+
+func TestNameNormalization(t *testing.T) {
+	svc := setupService(t)
+
+	_, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "  Payment_Config ",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	config, err := svc.GetConfig(context.Background(), "PAYMENT_CONFIG", nil)
+	if err != nil {
+		t.Fatalf("Failed to get config with different case: %v", err)
+	}
+	if config.Name != "payment_config" {
+		t.Errorf("Expected normalized name 'payment_config', got '%s'", config.Name)
+	}
+
+	_, err = svc.UpdateConfig(context.Background(), "Payment_Config", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 2000, "enabled": true},
+	})
+	if err != nil {
+		t.Errorf("Failed to update config with different case: %v", err)
+	}
+
+	_, err = svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payment_CONFIG",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	if _, ok := err.(*models.ConfigExistsError); !ok {
+		t.Errorf("Expected ConfigExistsError for case-variant name, got %v", err)
+	}
+}
+
+func TestStrictNames(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	svc := NewConfigServiceWithOptions(repository.NewInMemoryRepository(), validator, Options{NormalizeNames: false})
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "Payment_Config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+
+	if _, err := svc.GetConfig(context.Background(), "payment_config", nil); err == nil {
+		t.Error("Expected strict mode to treat names case-sensitively")
+	}
+}
+
+func TestInvalidConfigName(t *testing.T) {
+	svc := setupService(t)
+
+	for _, name := range []string{"has space", "slash/name", "dot.name", "ümlaut"} {
+		_, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+		})
+		if _, ok := err.(*models.ValidationError); !ok {
+			t.Errorf("Expected ValidationError for name %q, got %v", name, err)
+		}
+	}
+}
+
+func TestDataSizeLimit(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	validator.RegisterSchema("blob_config", map[string]interface{}{"type": "object"})
+
+	// {"blob":"..."} serializes to len(blob) + 11 bytes
+	opts := DefaultOptions()
+	opts.MaxDataSize = 100
+	svc := NewConfigServiceWithOptions(repository.NewInMemoryRepository(), validator, opts)
+
+	atLimit := map[string]interface{}{"blob": strings.Repeat("x", 89)}
+	if _, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{Name: "ok", Type: "blob_config", Data: atLimit}); err != nil {
+		t.Errorf("Expected payload at the limit to be accepted: %v", err)
+	}
+
+	overLimit := map[string]interface{}{"blob": strings.Repeat("x", 90)}
+	_, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{Name: "too_big", Type: "blob_config", Data: overLimit})
+	if _, ok := err.(*models.ValidationError); !ok {
+		t.Errorf("Expected ValidationError for payload over the limit, got %v", err)
+	}
+
+	_, err = svc.UpdateConfig(context.Background(), "ok", &models.UpdateConfigRequest{Data: overLimit})
+	if _, ok := err.(*models.ValidationError); !ok {
+		t.Errorf("Expected ValidationError on update over the limit, got %v", err)
+	}
+}
+
+func TestDataDepthLimit(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	validator.RegisterSchema("nested_config", map[string]interface{}{"type": "object"})
+
+	opts := DefaultOptions()
+	opts.MaxDataDepth = 3
+	svc := NewConfigServiceWithOptions(repository.NewInMemoryRepository(), validator, opts)
+
+	nested := func(depth int) map[string]interface{} {
+		data := map[string]interface{}{"leaf": 1}
+		for i := 1; i < depth; i++ {
+			data = map[string]interface{}{"child": data}
+		}
+		return data
+	}
+
+	if _, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{Name: "shallow", Type: "nested_config", Data: nested(3)}); err != nil {
+		t.Errorf("Expected depth 3 to be accepted: %v", err)
+	}
+
+	_, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{Name: "deep", Type: "nested_config", Data: nested(4)})
+	if _, ok := err.(*models.ValidationError); !ok {
+		t.Errorf("Expected ValidationError for depth 4, got %v", err)
+	}
+}
+
+func TestRejectUnsafeKeys(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	validator.RegisterSchema("open_config", map[string]interface{}{"type": "object"})
+
+	dotted := map[string]interface{}{"a.b": 1}
+	// Off by default: the key is stored and reachable by JSON Pointer
+	svc := NewConfigServiceWithOptions(repository.NewInMemoryRepository(), validator, DefaultOptions())
+	if _, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{Name: "dotted", Type: "open_config", Data: dotted}); err != nil {
+		t.Fatalf("Expected dotted keys to be accepted by default: %v", err)
+	}
+	if value, err := svc.GetConfigValue(context.Background(), "dotted", "/a.b", false); err != nil || value != 1 {
+		t.Errorf("Expected /a.b to be 1, got %v (%v)", value, err)
+	}
+
+	opts := DefaultOptions()
+	opts.RejectUnsafeKeys = true
+	svc = NewConfigServiceWithOptions(repository.NewInMemoryRepository(), validator, opts)
+
+	tests := []struct {
+		name     string
+		data     map[string]interface{}
+		wantPath string
+	}{
+		{"dot", dotted, "/a.b"},
+		{"nested dot", map[string]interface{}{"limits": map[string]interface{}{"max.daily": 1}}, "/limits/max.daily"},
+		{"in array", map[string]interface{}{"rules": []interface{}{map[string]interface{}{"x/y.z": 1}}}, "/rules/0/x~1y.z"},
+		{"control character", map[string]interface{}{"a\nb": 1}, "/a\nb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{Name: "unsafe", Type: "open_config", Data: tt.data})
+			validationErr, ok := err.(*models.ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError, got %v", err)
+			}
+			if !strings.Contains(validationErr.Message, " at "+tt.wantPath+" ") {
+				t.Errorf("Expected the error to locate %s, got %q", tt.wantPath, validationErr.Message)
+			}
+		})
+	}
+
+	if _, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{Name: "safe", Type: "open_config", Data: map[string]interface{}{"a_b": 1}}); err != nil {
+		t.Fatalf("Expected safe keys to be accepted: %v", err)
+	}
+	_, err := svc.UpdateConfig(context.Background(), "safe", &models.UpdateConfigRequest{Data: dotted})
+	if _, ok := err.(*models.ValidationError); !ok {
+		t.Errorf("Expected ValidationError on update, got %v", err)
+	}
+}
+
+func TestSquashVersions(t *testing.T) {
+	svc := setupService(t)
+
+	svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1, "enabled": true},
+	})
+	for i := 2; i <= 4; i++ {
+		svc.UpdateConfig(context.Background(), "payments", &models.UpdateConfigRequest{
+			Data: map[string]interface{}{"max_limit": i, "enabled": true},
+		})
+	}
+
+	if _, err := svc.SquashVersions(context.Background(), "payments", &models.SquashRequest{From: 2, To: 5}); err == nil {
+		t.Error("Expected error for a range beyond the current version")
+	}
+	if _, err := svc.SquashVersions(context.Background(), "payments", &models.SquashRequest{From: 3, To: 3}); err == nil {
+		t.Error("Expected error for an empty range")
+	}
+
+	// The current version may be the end of the range
+	result, err := svc.SquashVersions(context.Background(), "payments", &models.SquashRequest{From: 2, To: 4})
+	if err != nil {
+		t.Fatalf("Failed to squash: %v", err)
+	}
+	if result.VersionsRemoved != 2 {
+		t.Errorf("Expected 2 versions removed, got %d", result.VersionsRemoved)
+	}
+
+	config, _ := svc.GetConfig(context.Background(), "payments", nil)
+	if config.Version != 4 || config.Data["max_limit"] != 4 {
+		t.Errorf("Expected current version 4 to be preserved, got %+v", config)
+	}
+
+	versions, _ := svc.ListVersions(context.Background(), "payments")
+	if len(versions.Versions) != 2 || !versions.Versions[1].IsCurrent {
+		t.Errorf("Expected versions 1 and 4 with 4 current, got %+v", versions.Versions)
+	}
+}
+
+func TestCompareConfigs(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+	svc.validator.RegisterSchema("gateway_config", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"api_key": map[string]interface{}{"type": "string", "secret": true},
+		},
+	})
+	svc.CreateConfig(ctx, &models.CreateConfigRequest{Name: "staging_payment", Type: "payment_config", Data: map[string]interface{}{"max_limit": 500, "enabled": true}})
+	svc.CreateConfig(ctx, &models.CreateConfigRequest{Name: "prod_payment", Type: "payment_config", Data: map[string]interface{}{"max_limit": 1000, "enabled": true}})
+	svc.CreateConfig(ctx, &models.CreateConfigRequest{Name: "staging_gateway", Type: "gateway_config", Data: map[string]interface{}{"api_key": "old"}})
+	svc.CreateConfig(ctx, &models.CreateConfigRequest{Name: "prod_gateway", Type: "gateway_config", Data: map[string]interface{}{"api_key": "new"}})
+
+	diff, err := svc.CompareConfigs(ctx, "staging_payment", "prod_payment", false)
+	if err != nil {
+		t.Fatalf("Failed to compare: %v", err)
+	}
+	if diff.Identical || len(diff.Changes) != 1 || diff.Changes[0].Path != "/max_limit" || diff.Changes[0].To != 1000 {
+		t.Errorf("Expected a single max_limit change, got %+v", diff)
+	}
+
+	if diff, _ := svc.CompareConfigs(ctx, "prod_payment", "prod_payment", false); !diff.Identical {
+		t.Errorf("Expected a config to be identical to itself, got %+v", diff)
+	}
+
+	// Secret drift is still reported, but without the values
+	diff, err = svc.CompareConfigs(ctx, "staging_gateway", "prod_gateway", true)
+	if err != nil || len(diff.Changes) != 1 || diff.Changes[0].From != "***" || diff.Changes[0].To != "***" {
+		t.Errorf("Expected a masked api_key change, got %+v (%v)", diff, err)
+	}
+
+	if _, err := svc.CompareConfigs(ctx, "staging_payment", "prod_gateway", false); err == nil {
+		t.Error("Expected an error comparing configs of different types")
+	} else if _, ok := err.(*models.ConfigTypeMismatchError); !ok {
+		t.Errorf("Expected ConfigTypeMismatchError, got %T", err)
+	}
+
+	_, err = svc.CompareConfigs(ctx, "staging_payment", "missing_payment", false)
+	if notFound, ok := err.(*models.ConfigNotFoundError); !ok || notFound.Name != "missing_payment" {
+		t.Errorf("Expected ConfigNotFoundError naming missing_payment, got %v", err)
+	}
+}
+
+func TestVerifyDetectsTamperedData(t *testing.T) {
+	dir := t.TempDir()
+	validator, _ := validation.NewValidator()
+	repo, err := repository.NewFileRepository(dir, nil)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	svc := NewConfigService(repo, validator)
+	ctx := context.Background()
+	for _, name := range []string{"payments", "payouts"} {
+		svc.CreateConfig(ctx, &models.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+		})
+		svc.UpdateConfig(ctx, name, &models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": 2000, "enabled": true}})
+	}
+
+	resp, err := svc.Verify(ctx)
+	if err != nil {
+		t.Fatalf("Failed to verify: %v", err)
+	}
+	if resp.ConfigsChecked != 2 || resp.VersionsChecked != 4 || resp.Unverified != 0 || len(resp.Mismatches) != 0 {
+		t.Fatalf("Expected 4 intact versions, got %+v", resp)
+	}
+
+	// Corrupt version 1 of payments on disk and load the directory again
+	path := filepath.Join(dir, "payments.json")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	tampered := strings.Replace(string(raw), `"max_limit":1000`, `"max_limit":9000`, 1)
+	if tampered == string(raw) {
+		t.Fatalf("Expected version 1 data in %s", raw)
+	}
+	if err := os.WriteFile(path, []byte(tampered), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	repo, err = repository.NewFileRepository(dir, nil)
+	if err != nil {
+		t.Fatalf("Failed to reload repository: %v", err)
+	}
+
+	resp, err = NewConfigService(repo, validator).Verify(ctx)
+	if err != nil {
+		t.Fatalf("Failed to verify: %v", err)
+	}
+	if len(resp.Mismatches) != 1 {
+		t.Fatalf("Expected one mismatch, got %+v", resp.Mismatches)
+	}
+	mismatch := resp.Mismatches[0]
+	if mismatch.Name != "payments" || mismatch.Version != 1 || mismatch.Expected == mismatch.Actual {
+		t.Errorf("Expected payments version 1 to be flagged, got %+v", mismatch)
+	}
+}
+
+func TestVersionMessages(t *testing.T) {
+	dir := t.TempDir()
+	validator, _ := validation.NewValidator()
+	repo, err := repository.NewFileRepository(dir, nil)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	svc := NewConfigService(repo, validator)
+	ctx := context.Background()
+
+	if _, err := svc.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name:    "payments",
+		Type:    "payment_config",
+		Data:    map[string]interface{}{"max_limit": 1000, "enabled": true},
+		Message: "initial limits",
+	}); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	config, err := svc.UpdateConfig(ctx, "payments", &models.UpdateConfigRequest{
+		Data:    map[string]interface{}{"max_limit": 2000, "enabled": false},
+		Message: "raise limit and pause payments",
+	})
+	if err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+	if config.Message != "raise limit and pause payments" {
+		t.Errorf("Expected the update message on the config, got %q", config.Message)
+	}
+	svc.UpdateConfig(ctx, "payments", &models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": 3000, "enabled": true}})
+	svc.RollbackConfig(ctx, "payments", &models.RollbackRequest{Version: 1})
+
+	_, err = svc.UpdateConfig(ctx, "payments", &models.UpdateConfigRequest{
+		Data:    map[string]interface{}{"max_limit": 1, "enabled": true},
+		Message: strings.Repeat("x", models.MaxMessageLength+1),
+	})
+	var validationErr *models.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "message" {
+		t.Errorf("Expected a message validation error, got %v", err)
+	}
+
+	// Messages are persisted with the versions
+	repo, err = repository.NewFileRepository(dir, nil)
+	if err != nil {
+		t.Fatalf("Failed to reload repository: %v", err)
+	}
+	resp, err := NewConfigService(repo, validator).ListVersions(ctx, "payments")
+	if err != nil {
+		t.Fatalf("Failed to list versions: %v", err)
+	}
+	want := []string{"initial limits", "raise limit and pause payments", "", "rolled back to v1"}
+	if len(resp.Versions) != len(want) {
+		t.Fatalf("Expected %d versions, got %d", len(want), len(resp.Versions))
+	}
+	for i, version := range resp.Versions {
+		if version.Message != want[i] {
+			t.Errorf("Version %d: expected message %q, got %q", version.Version, want[i], version.Message)
+		}
+	}
+}
+
+func TestVersionSchemaHashes(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	svc := NewConfigService(repository.NewInMemoryRepository(), validator)
+	ctx := context.Background()
+
+	if err := validator.RegisterSchema("limits_config", map[string]interface{}{"type": "object"}); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	svc.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name: "limits",
+		Type: "limits_config",
+		Data: map[string]interface{}{"max": 10},
+	})
+	svc.UpdateConfig(ctx, "limits", &models.UpdateConfigRequest{Data: map[string]interface{}{"max": 20}})
+
+	// Re-register the schema with a new property
+	if err := validator.RegisterSchema("limits_config", map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"max": map[string]interface{}{"type": "integer"}},
+	}); err != nil {
+		t.Fatalf("Failed to re-register schema: %v", err)
+	}
+	config, err := svc.UpdateConfig(ctx, "limits", &models.UpdateConfigRequest{Data: map[string]interface{}{"max": 30}})
+	if err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+	current, _ := validator.SchemaHash("limits_config")
+	if config.SchemaHash != current {
+		t.Errorf("Expected the config to carry the current schema hash %s, got %s", current, config.SchemaHash)
+	}
+
+	resp, err := svc.ListVersions(ctx, "limits")
+	if err != nil {
+		t.Fatalf("Failed to list versions: %v", err)
+	}
+	if len(resp.Versions) != 3 {
+		t.Fatalf("Expected 3 versions, got %d", len(resp.Versions))
+	}
+	v1, v2, v3 := resp.Versions[0].SchemaHash, resp.Versions[1].SchemaHash, resp.Versions[2].SchemaHash
+	if v1 == "" || v1 != v2 {
+		t.Errorf("Expected versions 1 and 2 to share a schema hash, got %q and %q", v1, v2)
+	}
+	if v3 != current || v3 == v2 {
+		t.Errorf("Expected version 3 to carry the new schema hash %s, got %s", current, v3)
+	}
+}
+
+func TestRegisterSchemaReportsIncompatibleConfigs(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+
+	for name, limit := range map[string]int{"payments_small": 1000, "payments_large": 9000} {
+		if _, err := svc.CreateConfig(ctx, &models.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": limit, "enabled": true},
+		}); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	// Tighten the limit so the large config no longer validates
+	req := &models.RegisterSchemaRequest{Schema: map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"max_limit": map[string]interface{}{"type": "integer", "maximum": 5000},
+			"enabled":   map[string]interface{}{"type": "boolean"},
+		},
+		"required": []interface{}{"max_limit", "enabled"},
+	}}
+	resp, err := svc.RegisterSchema(ctx, "payment_config", req, true)
+	if err != nil {
+		t.Fatalf("Failed to check schema: %v", err)
+	}
+	if !resp.DryRun || len(resp.Incompatible) != 1 || resp.Incompatible[0].Name != "payments_large" {
+		t.Fatalf("Expected payments_large to be flagged, got %+v", resp)
+	}
+	if resp.Incompatible[0].Errors[0].Field != "max_limit" {
+		t.Errorf("Expected a max_limit violation, got %+v", resp.Incompatible[0].Errors)
+	}
+
+	// The dry run left the old schema in place
+	if _, err := svc.UpdateConfig(ctx, "payments_small", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 8000, "enabled": true},
+	}); err != nil {
+		t.Fatalf("Expected the old schema to accept 8000, got %v", err)
+	}
+
+	resp, err = svc.RegisterSchema(ctx, "payment_config", req, false)
+	if err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	if resp.DryRun || len(resp.Incompatible) != 2 {
+		t.Fatalf("Expected both configs to be flagged, got %+v", resp)
+	}
+	if _, err := svc.UpdateConfig(ctx, "payments_small", &models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 6000, "enabled": true},
+	}); err == nil {
+		t.Error("Expected the new schema to reject 6000")
+	}
+
+	var validationErr *models.ValidationError
+	if _, err := svc.RegisterSchema(ctx, "payment_config", &models.RegisterSchemaRequest{
+		Schema: map[string]interface{}{"type": "no-such-type"},
+	}, false); !errors.As(err, &validationErr) {
+		t.Errorf("Expected a ValidationError for an invalid schema, got %v", err)
+	}
+}
+
+func TestSetTypeDefault(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+
+	var schemaErr *models.SchemaValidationError
+	if err := svc.SetTypeDefault("payment_config", map[string]interface{}{"max_limit": "high"}); !errors.As(err, &schemaErr) {
+		t.Errorf("Expected a default failing the schema to be rejected, got %v", err)
+	}
+	var notFound *models.SchemaNotFoundError
+	if err := svc.SetTypeDefault("unknown_type", map[string]interface{}{}); !errors.As(err, &notFound) {
+		t.Errorf("Expected a SchemaNotFoundError, got %v", err)
+	}
+
+	defaults := map[string]interface{}{"max_limit": 100, "enabled": false}
+	if err := svc.SetTypeDefault("payment_config", defaults); err != nil {
+		t.Fatalf("Failed to set default: %v", err)
+	}
+	defaults["max_limit"] = 999
+
+	config, err := svc.GetConfigOrDefault(ctx, "payments", "payment_config")
+	if err != nil {
+		t.Fatalf("Expected the default, got %v", err)
+	}
+	if config.Version != 0 || config.Data["max_limit"] != 100 {
+		t.Errorf("Expected a copy of the default as version 0, got %+v", config)
+	}
+	config.Data["max_limit"] = 1
+	if config, _ := svc.GetConfigOrDefault(ctx, "payments", "payment_config"); config.Data["max_limit"] != 100 {
+		t.Errorf("Expected the registered default to be unchanged, got %v", config.Data)
+	}
+
+	// Removing the default restores the 404
+	svc.SetTypeDefault("payment_config", nil)
+	var configNotFound *models.ConfigNotFoundError
+	if _, err := svc.GetConfigOrDefault(ctx, "payments", "payment_config"); !errors.As(err, &configNotFound) {
+		t.Errorf("Expected ConfigNotFoundError without a default, got %v", err)
+	}
+}
+
+func TestExpireConfigs(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+
+	if _, err := svc.SetTypePolicy(context.Background(), "payment_config", &models.TypePolicy{DefaultTTL: "1h"}); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+	for _, req := range []*models.CreateConfigRequest{
+		{Name: "base", Type: "payment_config", Data: map[string]interface{}{"max_limit": 100, "enabled": true}},
+		{Name: "temporary", Type: "payment_config", Data: map[string]interface{}{"max_limit": 100, "enabled": true}},
+		{Name: "dependent", Type: "payment_config", Data: map[string]interface{}{"max_limit": 100, "enabled": true}, DependsOn: []string{"base"}},
+	} {
+		if _, err := svc.CreateConfig(ctx, req); err != nil {
+			t.Fatalf("Failed to create %s: %v", req.Name, err)
+		}
+	}
+
+	auditLog := audit.NewInMemoryAuditLogger()
+	if expired, err := svc.ExpireConfigs(ctx, time.Now(), auditLog); err != nil || len(expired) != 0 {
+		t.Fatalf("Expected nothing to expire yet, got %v, %v", expired, err)
+	}
+
+	// base outlives the others only because dependent still depends on it
+	expired, err := svc.ExpireConfigs(ctx, time.Now().Add(2*time.Hour), auditLog)
+	if err != nil {
+		t.Fatalf("ExpireConfigs failed: %v", err)
+	}
+	if strings.Join(expired, ",") != "dependent,temporary" {
+		t.Errorf("Expected dependent and temporary to expire, got %v", expired)
+	}
+	if _, err := svc.GetConfig(ctx, "base", nil); err != nil {
+		t.Errorf("Expected base to be kept while depended on, got %v", err)
+	}
+	if expired, _ := svc.ExpireConfigs(ctx, time.Now().Add(2*time.Hour), auditLog); len(expired) != 1 || expired[0] != "base" {
+		t.Errorf("Expected base to expire once nothing depends on it, got %v", expired)
+	}
+
+	events, _, _ := auditLog.Query(models.AuditFilter{Action: models.AuditActionDelete, Actor: ExpiryActor})
+	if len(events) != 3 {
+		t.Fatalf("Expected an audit event per expiry, got %+v", events)
+	}
+	for _, event := range events {
+		if event.Version != 1 {
+			t.Errorf("Expected the expired version to be recorded, got %+v", event)
+		}
+	}
+
+	// Removing the policy stops later writes from expiring, and configurations
+	// that already carry an expiry are no longer scanned
+	svc.CreateConfig(ctx, &models.CreateConfigRequest{Name: "written_under_policy", Type: "payment_config", Data: map[string]interface{}{"max_limit": 100, "enabled": true}})
+	svc.SetTypePolicy(context.Background(), "payment_config", &models.TypePolicy{})
+	config, _ := svc.CreateConfig(ctx, &models.CreateConfigRequest{Name: "kept", Type: "payment_config", Data: map[string]interface{}{"max_limit": 100, "enabled": true}})
+	if config.ExpiresAt != nil {
+		t.Errorf("Expected no expiry without a policy, got %v", config.ExpiresAt)
+	}
+	if expired, _ := svc.ExpireConfigs(ctx, time.Now().Add(2*time.Hour), nil); len(expired) != 0 {
+		t.Errorf("Expected types without a TTL not to be scanned, got %v", expired)
+	}
+}
+
+func TestTypePolicySurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	validator, _ := validation.NewValidator()
+	repo, err := repository.NewFileRepository(dir, []byte("policy key"))
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	policy := models.TypePolicy{DefaultTTL: "1h", VersionLimit: 3}
+	if _, err := NewConfigService(repo, validator).SetTypePolicy(context.Background(), "payment_config", &policy); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+
+	// Simulate a restart with a new repository and service
+	repo, err = repository.NewFileRepository(dir, []byte("policy key"))
+	if err != nil {
+		t.Fatalf("Failed to reopen repository: %v", err)
+	}
+	svc := NewConfigService(repo, validator)
+	if got := svc.TypePolicy("payment_config"); got != policy {
+		t.Errorf("Expected policy %+v after restart, got %+v", policy, got)
+	}
+	config, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	if config.ExpiresAt == nil {
+		t.Error("Expected the restored policy to set an expiry")
+	}
+}
+
+// TestConcurrentUpdatesAndRollbacks is meant to be run with -race
+func TestConcurrentUpdatesAndRollbacks(t *testing.T) {
+	svc := setupService(t)
+	ctx := context.Background()
+
+	if _, err := svc.CreateConfig(ctx, &models.CreateConfigRequest{
+		Name: "payments",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1, "enabled": true},
+	}); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	if _, err := svc.UpdateConfig(ctx, "payments", &models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": 2, "enabled": true}}); err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+
+	const writers, rounds = 8, 25
+	results := make(chan *models.Config, 2*writers*rounds)
+	errs := make(chan error, 2*writers*rounds)
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for round := 0; round < rounds; round++ {
+				config, err := svc.UpdateConfig(ctx, "payments", &models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": 100 + i, "enabled": true}})
+				if err != nil {
+					errs <- err
+					continue
+				}
+				results <- config
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for round := 0; round < rounds; round++ {
+				config, err := svc.RollbackConfig(ctx, "payments", &models.RollbackRequest{Steps: 1})
+				if err != nil {
+					errs <- err
+					continue
+				}
+				results <- config
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Unexpected write failure: %v", err)
+	}
+
+	seen := make(map[int]bool)
+	for config := range results {
+		if seen[config.Version] {
+			t.Errorf("Version %d was returned twice", config.Version)
+		}
+		seen[config.Version] = true
+
+		// Nothing may land between reading the head a rollback starts from
+		// and writing the rolled back version right after it
+		if config.Origin == models.OriginRollback {
+			if want := fmt.Sprintf("rolled back to v%d", config.Version-2); config.Message != want {
+				t.Errorf("Expected version %d to be %q, got %q", config.Version, want, config.Message)
+			}
+		}
+	}
+
+	versions, err := svc.repo.ListVersions(ctx, "payments")
+	if err != nil {
+		t.Fatalf("Failed to list versions: %v", err)
+	}
+	if len(versions) != 2+2*writers*rounds {
+		t.Errorf("Expected %d versions, got %d", 2+2*writers*rounds, len(versions))
+	}
+	for i, version := range versions {
+		if version.Version != i+1 {
+			t.Fatalf("Expected versions to increase one by one, got %d at position %d", version.Version, i)
+		}
+	}
+}
+
+func TestVersionLimit(t *testing.T) {
+	validator, err := validation.NewValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	opts := DefaultOptions()
+	opts.MaxVersionsPerConfig = 3
+	svc := NewConfigServiceWithOptions(repository.NewInMemoryRepository(), validator, opts)
+	ctx := context.Background()
+
+	create := func(name string) {
+		if _, err := svc.CreateConfig(ctx, &models.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": 1, "enabled": true},
+		}); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	update := func(name string, limit int) error {
+		_, err := svc.UpdateConfig(ctx, name, &models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": limit, "enabled": true}})
+		return err
+	}
+
+	create("payments")
+	for limit := 2; limit <= 3; limit++ {
+		if err := update("payments", limit); err != nil {
+			t.Fatalf("Expected version %d to be allowed, got %v", limit, err)
+		}
+	}
+
+	var limitErr *models.VersionLimitError
+	if err := update("payments", 4); !errors.As(err, &limitErr) || limitErr.Limit != 3 {
+		t.Fatalf("Expected a VersionLimitError with limit 3, got %v", err)
+	}
+	if _, err := svc.RollbackConfig(ctx, "payments", &models.RollbackRequest{Version: 1}); !errors.As(err, &limitErr) {
+		t.Errorf("Expected the rollback to be rejected too, got %v", err)
+	}
+	if _, err := svc.PatchConfig(ctx, "payments", &models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": 5}}); !errors.As(err, &limitErr) {
+		t.Errorf("Expected the patch to be rejected too, got %v", err)
+	}
+	if config, _ := svc.GetConfig(ctx, "payments", nil); config.Version != 3 || config.Data["max_limit"] != 3 {
+		t.Errorf("Expected version 3 to stay current, got %+v", config)
+	}
+	versions, _ := svc.repo.ListVersions(ctx, "payments")
+	if len(versions) != 3 {
+		t.Errorf("Expected the history to be kept complete, got %d versions", len(versions))
+	}
+
+	// A type policy overrides the service-wide limit
+	if _, err := svc.SetTypePolicy(context.Background(), "payment_config", &models.TypePolicy{VersionLimit: 4}); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+	if err := update("payments", 4); err != nil {
+		t.Errorf("Expected the policy to allow version 4, got %v", err)
+	}
+	if err := update("payments", 5); !errors.As(err, &limitErr) || limitErr.Limit != 4 {
+		t.Errorf("Expected a VersionLimitError with limit 4, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+)
+
+// SquashVersions collapses a contiguous range of versions into its last version.
+// The current version may only appear as the end of the range, so it is never discarded.
+func (s *ConfigService) SquashVersions(ctx context.Context, name string, req *models.SquashRequest) (*models.SquashResponse, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	response := &models.SquashResponse{Name: name, From: req.From, To: req.To}
+	err := s.repo.WithTransaction(ctx, func(tx repository.ConfigRepository) error {
+		current, err := tx.Get(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := checkUnlocked(current); err != nil {
+			return err
+		}
+		if req.To > current.Version {
+			return &models.ValidationError{
+				Field:   "to",
+				Message: fmt.Sprintf("to cannot be beyond the current version %d", current.Version),
+			}
+		}
+
+		response.VersionsRemoved, err = tx.SquashVersions(ctx, name, req.From, req.To)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+)
+
+// ConfigStats returns the read statistics of a configuration. Reads made by
+// the service itself, such as the lookup before an update, are counted too.
+func (s *ConfigService) ConfigStats(ctx context.Context, name string) (*models.ConfigStatsResponse, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	reporter, ok := s.repo.(repository.UsageReporter)
+	if !ok {
+		return nil, errors.New("repository does not track usage")
+	}
+	return reporter.Usage(ctx, name)
+}
+
+// GetConfigMeta returns the metadata of the latest version of a
+// configuration, including the size of its data, without the data itself
+func (s *ConfigService) GetConfigMeta(ctx context.Context, name string) (*models.ConfigMetaResponse, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	config, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serialized, err := json.Marshal(config.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize data: %w", err)
+	}
+
+	return &models.ConfigMetaResponse{
+		Name:       config.Name,
+		Type:       config.Type,
+		Version:    config.Version,
+		CreatedAt:  config.CreatedAt,
+		UpdatedAt:  config.UpdatedAt,
+		SizeBytes:  len(serialized),
+		FieldCount: dataFieldCount(config.Data),
+	}, nil
+}
+
+// dataFieldCount counts the top-level fields of data, or the items of array data
+func dataFieldCount(data map[string]interface{}) int {
+	if items, ok := models.DataItems(data); ok {
+		return len(items)
+	}
+	return len(data)
+}
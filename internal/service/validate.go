@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"config-engine/internal/models"
+)
+
+// BatchValidate validates each item against its type's schema, applying the
+// same checks as a create, without storing anything. Items are validated
+// concurrently by a bounded pool of workers; results keep the request order.
+// Validation failures are reported per item rather than failing the request.
+func (s *ConfigService) BatchValidate(ctx context.Context, req *models.BatchValidateRequest) (*models.BatchValidateResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if s.opts.MaxBatchNames > 0 && len(req.Items) > s.opts.MaxBatchNames {
+		return nil, &models.ValidationError{
+			Field:   "items",
+			Message: fmt.Sprintf("%d items exceeds maximum of %d", len(req.Items), s.opts.MaxBatchNames),
+		}
+	}
+
+	results := make([]models.BatchValidateResult, len(req.Items))
+	indexes := make(chan int)
+	workers := min(runtime.GOMAXPROCS(0), len(req.Items))
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = s.validateItem(i, req.Items[i])
+			}
+		}()
+	}
+
+	var err error
+	for i := range req.Items {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.BatchValidateResponse{Valid: true, Results: results}
+	for _, result := range results {
+		resp.Valid = resp.Valid && result.Valid
+	}
+	return resp, nil
+}
+
+// validateItem validates a single batch item. Validation only reads, so
+// failures are not counted in the validation stats.
+func (s *ConfigService) validateItem(index int, item models.BatchValidateItem) models.BatchValidateResult {
+	result := models.BatchValidateResult{Index: index, Type: item.Type, Errors: []models.FieldViolation{}}
+
+	var err error
+	if item.Type == "" {
+		err = &models.ValidationError{Field: "type", Message: "type is required"}
+	} else {
+		err = s.checkData(item.Type, item.Data, s.validator.Check)
+	}
+
+	switch e := err.(type) {
+	case nil:
+		result.Valid = true
+	case *models.ValidationError:
+		result.Errors = append(result.Errors, models.FieldViolation{Field: e.Field, Message: e.Message})
+	case *models.SchemaValidationError:
+		if len(e.Violations) > 0 {
+			result.Errors = e.Violations
+		} else {
+			result.Errors = append(result.Errors, models.FieldViolation{Field: "data", Message: e.Details})
+		}
+	default:
+		result.Errors = append(result.Errors, models.FieldViolation{Field: "data", Message: err.Error()})
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+)
+
+// DefaultMaxWaitTimeout is the default upper bound on how long WaitForChange blocks
+const DefaultMaxWaitTimeout = 60 * time.Second
+
+// WaitForChange blocks until the config's version differs from sinceVersion,
+// the timeout elapses or ctx is cancelled. It returns the latest config and
+// true on a change, or the current config and false on timeout. A version
+// already different from sinceVersion returns immediately; with the branching
+// version strategy a rollback moves the version backwards. The timeout is
+// capped at the configured MaxWaitTimeout.
+func (s *ConfigService) WaitForChange(ctx context.Context, name string, sinceVersion int, timeout time.Duration) (*models.Config, bool, error) {
+	name = s.normalizeName(name)
+	if name == "" {
+		return nil, false, &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+	if sinceVersion < 0 {
+		return nil, false, &models.ValidationError{Field: "since_version", Message: "since_version must not be negative"}
+	}
+	if timeout <= 0 {
+		return nil, false, &models.ValidationError{Field: "timeout", Message: "timeout must be positive"}
+	}
+	if s.opts.MaxWaitTimeout > 0 && timeout > s.opts.MaxWaitTimeout {
+		timeout = s.opts.MaxWaitTimeout
+	}
+
+	subscriber, ok := s.repo.(repository.Subscriber)
+	if !ok {
+		return nil, false, errors.New("repository does not support change notifications")
+	}
+
+	// Subscribe before reading so a change between the two is not missed
+	events, unsubscribe := subscriber.Subscribe()
+	defer unsubscribe()
+
+	config, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, false, err
+	}
+	if config.Version != sinceVersion {
+		return config, true, nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			if event.Name != name {
+				continue
+			}
+			if event.Type == models.ChangeDeleted {
+				return nil, false, &models.ConfigNotFoundError{Name: name}
+			}
+			if event.Version != sinceVersion {
+				config, err := s.repo.Get(ctx, name)
+				if err != nil {
+					return nil, false, err
+				}
+				return config, true, nil
+			}
+		case <-timer.C:
+			// Events may be dropped under load, so check the stored version once more
+			config, err := s.repo.Get(ctx, name)
+			if err != nil {
+				return nil, false, err
+			}
+			return config, config.Version != sinceVersion, nil
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+}
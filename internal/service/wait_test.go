@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"config-engine/internal/models"
+	"config-engine/internal/repository"
+	"config-engine/internal/validation"
+)
+
+func createPaymentConfig(t *testing.T, svc *ConfigService, name string) {
+	_, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: name,
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 100, "enabled": true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+}
+
+func TestWaitForChangeAlreadyNewer(t *testing.T) {
+	svc := setupService(t)
+	createPaymentConfig(t, svc, "payments")
+
+	start := time.Now()
+	config, changed, err := svc.WaitForChange(context.Background(), "payments", 0, time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to wait: %v", err)
+	}
+	if !changed || config.Version != 1 {
+		t.Errorf("Expected version 1 immediately, got changed=%v config=%+v", changed, config)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Expected an already-newer version to return immediately")
+	}
+}
+
+func TestWaitForChangeWakesOnUpdate(t *testing.T) {
+	svc := setupService(t)
+	createPaymentConfig(t, svc, "payments")
+	createPaymentConfig(t, svc, "other")
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		// Changes to other configs do not wake the waiter
+		svc.UpdateConfig(context.Background(), "other", &models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": 1, "enabled": true}})
+		svc.UpdateConfig(context.Background(), "payments", &models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": 200, "enabled": true}})
+	}()
+
+	config, changed, err := svc.WaitForChange(context.Background(), "payments", 1, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to wait: %v", err)
+	}
+	if !changed || config.Version != 2 || config.Data["max_limit"] != 200 {
+		t.Errorf("Expected version 2 with the new data, got changed=%v config=%+v", changed, config)
+	}
+}
+
+func TestWaitForChangeTimeout(t *testing.T) {
+	validator, _ := validation.NewValidator()
+	opts := DefaultOptions()
+	opts.MaxWaitTimeout = 50 * time.Millisecond
+	svc := NewConfigServiceWithOptions(repository.NewInMemoryRepository(), validator, opts)
+	createPaymentConfig(t, svc, "payments")
+
+	// The requested hour is capped at MaxWaitTimeout
+	start := time.Now()
+	config, changed, err := svc.WaitForChange(context.Background(), "payments", 1, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to wait: %v", err)
+	}
+	if changed || config.Version != 1 {
+		t.Errorf("Expected no change at version 1, got changed=%v config=%+v", changed, config)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the timeout to be capped, waited %v", elapsed)
+	}
+}
+
+func TestWaitForChangeCancelledAndDeleted(t *testing.T) {
+	svc := setupService(t)
+	createPaymentConfig(t, svc, "payments")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	if _, _, err := svc.WaitForChange(ctx, "payments", 1, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		svc.DeleteConfig(context.Background(), "payments", false)
+	}()
+	_, _, err := svc.WaitForChange(context.Background(), "payments", 1, 5*time.Second)
+	if _, ok := err.(*models.ConfigNotFoundError); !ok {
+		t.Errorf("Expected ConfigNotFoundError after delete, got %v", err)
+	}
+
+	if _, _, err := svc.WaitForChange(context.Background(), "missing", 0, time.Second); err == nil {
+		t.Error("Expected an error for a missing config")
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"config-engine/internal/models"
+)
+
+// DefaultWebhookTimeout bounds a single webhook delivery
+const DefaultWebhookTimeout = 5 * time.Second
+
+// Webhook subscribes a URL to the writes of a config type. Secret signs
+// every delivery so the receiver can verify it came from this service.
+type Webhook struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// validate checks the subscription before it is registered
+func (w Webhook) validate() error {
+	target, err := url.Parse(w.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return &models.ValidationError{Field: "url", Message: fmt.Sprintf("webhook URL %q must be an absolute http or https URL", w.URL)}
+	}
+	if w.Secret == "" {
+		return &models.ValidationError{Field: "secret", Message: "a webhook secret is required to sign deliveries"}
+	}
+	return nil
+}
+
+// RegisterWebhook subscribes webhook to the writes of configType. After each
+// create or update a models.WebhookPayload is POSTed to its URL, signed
+// with its secret in the X-Signature header (see models.SignWebhookPayload).
+// Deliveries run as a hook, so a failed delivery, including a response
+// outside 2xx, is handled like any other hook failure.
+func (s *ConfigService) RegisterWebhook(configType string, webhook Webhook) error {
+	if err := webhook.validate(); err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: DefaultWebhookTimeout}
+	s.RegisterHook(configType, Hook{
+		OnCreate: func(config *models.Config) error {
+			return deliverWebhook(client, webhook, models.WebhookEventCreate, config)
+		},
+		OnUpdate: func(config *models.Config) error {
+			return deliverWebhook(client, webhook, models.WebhookEventUpdate, config)
+		},
+	})
+	return nil
+}
+
+// LoadWebhooks registers the webhooks in a JSON file mapping config types to
+// their subscriptions, e.g. {"payment_config": [{"url": "https://...",
+// "secret": "..."}]}, returning the types subscribed to. Nothing is
+// registered if any subscription is invalid.
+func (s *ConfigService) LoadWebhooks(path string) ([]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks map[string][]Webhook
+	if err := json.Unmarshal(raw, &webhooks); err != nil {
+		return nil, fmt.Errorf("invalid webhooks file %s: %v", path, err)
+	}
+
+	types := make([]string, 0, len(webhooks))
+	for configType, subscriptions := range webhooks {
+		for _, webhook := range subscriptions {
+			if err := webhook.validate(); err != nil {
+				return nil, fmt.Errorf("webhook for %s: %w", configType, err)
+			}
+		}
+		types = append(types, configType)
+	}
+	sort.Strings(types)
+
+	for _, configType := range types {
+		for _, webhook := range webhooks[configType] {
+			s.RegisterWebhook(configType, webhook)
+		}
+	}
+	return types, nil
+}
+
+// deliverWebhook POSTs the signed payload of a write to webhook
+func deliverWebhook(client *http.Client, webhook Webhook, event string, config *models.Config) error {
+	body, err := json.Marshal(models.WebhookPayload{Event: event, Config: config, SentAt: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(models.WebhookSignatureHeader, models.SignWebhookPayload(webhook.Secret, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook delivery to %s failed: %w", req.URL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s responded with status %d", req.URL.Redacted(), resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package validation
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+)
+
+// defaultCacheSize is the number of validation results kept by a new Validator
+const defaultCacheSize = 1024
+
+// cacheKey identifies a validation result by config type, whether its update
+// schema was used, and data content hash
+type cacheKey struct {
+	configType string
+	update     bool
+	hash       [sha256.Size]byte
+}
+
+// cacheEntry is a cached validation result (nil err means valid)
+type cacheEntry struct {
+	key cacheKey
+	err error
+}
+
+// validationCache is a bounded, concurrency-safe LRU cache of validation results
+type validationCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	items    map[cacheKey]*list.Element
+	// generations count the invalidations of each config type so a validation
+	// racing a schema change does not cache a result of the old schema
+	generations map[string]uint64
+}
+
+// newValidationCache creates a cache holding at most capacity results
+func newValidationCache(capacity int) *validationCache {
+	return &validationCache{
+		capacity:    capacity,
+		order:       list.New(),
+		items:       make(map[cacheKey]*list.Element),
+		generations: make(map[string]uint64),
+	}
+}
+
+// newCacheKey builds a cache key from the serialized data
+func newCacheKey(configType string, update bool, dataJSON []byte) cacheKey {
+	return cacheKey{configType: configType, update: update, hash: sha256.Sum256(dataJSON)}
+}
+
+// get returns the cached result for key, if present
+func (c *validationCache) get(key cacheKey) (error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).err, true
+}
+
+// generation returns the invalidation count of a config type, to be read
+// along with the schema a result is computed from
+func (c *validationCache) generation(configType string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generations[configType]
+}
+
+// put stores a result computed at generation, evicting the least recently
+// used entry when full. Nothing is stored if its type was invalidated since.
+func (c *validationCache) put(key cacheKey, err error, generation uint64) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[key.configType] != generation {
+		return
+	}
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*cacheEntry).err = err
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&cacheEntry{key: key, err: err})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// invalidate drops every cached result for a config type
+func (c *validationCache) invalidate(configType string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[configType]++
+	for key, elem := range c.items {
+		if key.configType == configType {
+			c.order.Remove(elem)
+			delete(c.items, key)
+		}
+	}
+}
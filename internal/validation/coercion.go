@@ -0,0 +1,154 @@
+package validation
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"config-engine/internal/models"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// SetIntegerCoercion enables or disables whole-number float coercion for a
+// config type. When enabled, values such as 1000.0 are converted to integers
+// before schema validation and custom rules run, so they satisfy "integer"
+// schemas regardless of how the client encoded them. Fractional values like
+// 1000.5 are left untouched and still fail. The stored data is not modified.
+func (v *Validator) SetIntegerCoercion(configType string, enabled bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if enabled {
+		v.coerceIntegers[configType] = true
+	} else {
+		delete(v.coerceIntegers, configType)
+	}
+	v.cache.invalidate(configType)
+}
+
+// coerceIntegers returns a copy of data with whole-number floats converted to int64
+func coerceIntegers(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+
+	coerced := make(map[string]interface{}, len(data))
+	for k, value := range data {
+		coerced[k] = coerceValue(value)
+	}
+	return coerced
+}
+
+// coerceValue converts a single value, recursing into objects and arrays
+func coerceValue(value interface{}) interface{} {
+	switch n := value.(type) {
+	case map[string]interface{}:
+		return coerceIntegers(n)
+	case []interface{}:
+		items := make([]interface{}, len(n))
+		for i, item := range n {
+			items[i] = coerceValue(item)
+		}
+		return items
+	case float64:
+		if i, ok := wholeNumber(n); ok {
+			return i
+		}
+	case float32:
+		if i, ok := wholeNumber(float64(n)); ok {
+			return i
+		}
+	case json.Number:
+		if i, ok := wholeNumberLiteral(n); ok {
+			return i
+		}
+	}
+	return value
+}
+
+// wholeNumber converts f to int64 if it has no fractional part and fits
+func wholeNumber(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// wholeNumberLiteral converts a number such as 1000.0 or 1e3 to int64 if it
+// has no fractional part and fits. Unlike wholeNumber it is exact, so large
+// integers written with a fraction or exponent keep every digit.
+func wholeNumberLiteral(n json.Number) (int64, bool) {
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok || !r.IsInt() || !r.Num().IsInt64() {
+		return 0, false
+	}
+	return r.Num().Int64(), true
+}
+
+// coercionSuggestion proposes a conversion for a value that failed a type
+// check, trying each expected type in order. It returns nil when no
+// conversion is lossless, e.g. "10.5" for an integer or "yes" for a boolean.
+func coercionSuggestion(desc gojsonschema.ResultError) *models.CoercionSuggestion {
+	given, _ := desc.Details()["given"].(string)
+	expected, _ := desc.Details()["expected"].(string)
+	// Several types are listed as [integer,null]
+	for _, to := range strings.Split(strings.Trim(expected, "[]"), ",") {
+		if value, ok := convertValue(desc.Value(), to); ok {
+			return &models.CoercionSuggestion{Field: desc.Field(), From: given, To: to, Value: value}
+		}
+	}
+	return nil
+}
+
+// convertValue converts a decoded JSON value to a JSON Schema type without
+// loss. Numbers arrive as json.Number from the schema validator.
+func convertValue(value interface{}, to string) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		v = strings.TrimSpace(v)
+		switch to {
+		case "integer":
+			n, err := strconv.ParseInt(v, 10, 64)
+			return n, err == nil
+		case "number":
+			f, err := strconv.ParseFloat(v, 64)
+			return f, err == nil && !math.IsInf(f, 0) && !math.IsNaN(f)
+		case "boolean":
+			// Only the JSON spellings, not strconv's "1" or "T"
+			if v == "true" || v == "false" {
+				return v == "true", true
+			}
+		}
+	case json.Number:
+		switch to {
+		case "integer":
+			return wholeNumberLiteral(v)
+		case "string":
+			return v.String(), true
+		}
+	case bool:
+		if to == "string" {
+			return strconv.FormatBool(v), true
+		}
+	}
+	return nil, false
+}
+
+// Suggestions collects the coercion suggestions of a Validate error
+func Suggestions(err error) []models.CoercionSuggestion {
+	errs, ok := err.(FieldErrors)
+	if !ok {
+		return nil
+	}
+
+	var suggestions []models.CoercionSuggestion
+	for _, fieldErr := range errs {
+		if fieldErr.Suggestion != nil {
+			suggestions = append(suggestions, *fieldErr.Suggestion)
+		}
+	}
+	return suggestions
+}
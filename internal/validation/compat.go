@@ -0,0 +1,89 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"config-engine/internal/models"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// RegisterSchemaChecked registers schema like RegisterSchema after
+// validating stored, the latest stored configurations, against it and the
+// type's custom rules, and returns those of configType it rejects. With
+// dryRun the schema is only checked and not registered. Shared definitions
+// cannot be checked this way.
+func (v *Validator) RegisterSchemaChecked(configType string, schema map[string]interface{}, stored []*models.Config, dryRun bool) ([]models.IncompatibleConfig, error) {
+	if configType == DefinitionsType {
+		return nil, &SchemaError{Type: configType, Err: errors.New("shared definitions are registered with RegisterDefinitions")}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	compiledSchema, err := compileSchema(schema, v.definitions)
+	if err != nil {
+		return nil, &SchemaError{Type: configType, Err: fmt.Errorf("failed to compile schema: %w", err)}
+	}
+
+	incompatible, err := v.incompatibleConfigs(configType, compiledSchema, schema, stored)
+	if err != nil || dryRun {
+		return incompatible, err
+	}
+
+	v.schemas[configType] = compiledSchema
+	v.rawSchemas[configType] = schema
+	delete(v.updateSchemas, configType)
+	delete(v.rawUpdateSchemas, configType)
+	v.cache.invalidate(configType)
+	return incompatible, nil
+}
+
+// incompatibleConfigs validates the configs of configType in stored against
+// a candidate schema and the type's custom rules, returning those it
+// rejects. The results bypass the cache, which holds the current schema's.
+// The caller holds v.mu.
+func (v *Validator) incompatibleConfigs(configType string, schema *gojsonschema.Schema, rawSchema map[string]interface{}, stored []*models.Config) ([]models.IncompatibleConfig, error) {
+	var incompatible []models.IncompatibleConfig
+	for _, config := range stored {
+		if config.Type != configType {
+			continue
+		}
+
+		data := config.Data
+		if v.coerceIntegers[configType] {
+			data = coerceIntegers(data)
+		}
+		dataJSON, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal data of %s: %w", config.Name, err)
+		}
+		result, err := schema.Validate(gojsonschema.NewBytesLoader(dataJSON))
+		if err != nil {
+			return nil, fmt.Errorf("validation error: %w", err)
+		}
+
+		if violations := Violations(checkResult(v.rules[configType], rawSchema, data, result)); len(violations) > 0 {
+			incompatible = append(incompatible, models.IncompatibleConfig{
+				Name:    config.Name,
+				Type:    config.Type,
+				Version: config.Version,
+				Errors:  violations,
+			})
+		}
+	}
+	return incompatible, nil
+}
+
+// sortIncompatible orders incompatible configs by type, then name
+func sortIncompatible(incompatible []models.IncompatibleConfig) {
+	sort.Slice(incompatible, func(i, j int) bool {
+		if incompatible[i].Type != incompatible[j].Type {
+			return incompatible[i].Type < incompatible[j].Type
+		}
+		return incompatible[i].Name < incompatible[j].Name
+	})
+}
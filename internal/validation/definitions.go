@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	// DefinitionsType is the reserved type shared definitions are registered
+	// under. It is not a config type: no config can be created with it.
+	DefinitionsType = "_definitions"
+
+	// DefinitionsURI is the URI schemas use to reference shared definitions,
+	// e.g. {"$ref": "config-engine:definitions#/definitions/money"}
+	DefinitionsURI = "config-engine:definitions"
+)
+
+// RegisterDefinitions replaces the shared definitions document that schemas
+// reference through DefinitionsURI. Every registered schema is recompiled
+// against the new document so none keeps resolving to the old one; if any
+// fails to compile the previous definitions and schemas are kept.
+func (v *Validator) RegisterDefinitions(definitions map[string]interface{}) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	compiled, err := compileAll(v.rawSchemas, definitions, nil, false)
+	compiledUpdates, updateErr := compileAll(v.rawUpdateSchemas, definitions, nil, true)
+	if err := errors.Join(err, updateErr); err != nil {
+		return err
+	}
+
+	v.definitions = definitions
+	for configType, schema := range compiled {
+		v.schemas[configType] = schema
+		v.cache.invalidate(configType)
+	}
+	for configType, schema := range compiledUpdates {
+		v.updateSchemas[configType] = schema
+	}
+	return nil
+}
+
+// compileAll compiles raw schemas against definitions. Every failure is
+// returned as a SchemaError naming the type and its file in files, if any,
+// alongside the schemas that did compile.
+func compileAll(raw map[string]map[string]interface{}, definitions map[string]interface{}, files map[string]string, update bool) (map[string]*gojsonschema.Schema, error) {
+	kind := "schema"
+	if update {
+		kind = "update schema"
+	}
+
+	compiled := make(map[string]*gojsonschema.Schema, len(raw))
+	var errs []error
+	for configType, schema := range raw {
+		c, err := compileSchema(schema, definitions)
+		if err != nil {
+			errs = append(errs, &SchemaError{
+				Type: configType,
+				File: files[configType],
+				Err:  fmt.Errorf("failed to compile %s: %w", kind, err),
+			})
+			continue
+		}
+		compiled[configType] = c
+	}
+	return compiled, errors.Join(errs...)
+}
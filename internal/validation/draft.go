@@ -0,0 +1,55 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// supportedDrafts maps the "$schema" URIs of the drafts gojsonschema implements
+var supportedDrafts = map[string]gojsonschema.Draft{
+	"http://json-schema.org/draft-04/schema": gojsonschema.Draft4,
+	"http://json-schema.org/draft-06/schema": gojsonschema.Draft6,
+	"http://json-schema.org/draft-07/schema": gojsonschema.Draft7,
+}
+
+// compileSchema compiles a schema under the draft its "$schema" declares, so
+// a draft-07 schema gets if/then/else and a draft-04 schema gets boolean
+// exclusiveMinimum. Without a declaration the hybrid draft accepts keywords
+// from every supported draft. Any other "$schema" is rejected rather than
+// silently validated under different rules. Non-nil definitions are made
+// available to "$ref" under DefinitionsURI.
+func compileSchema(schema, definitions map[string]interface{}) (*gojsonschema.Schema, error) {
+	loader := gojsonschema.NewSchemaLoader()
+	if definitions != nil {
+		if err := loader.AddSchema(DefinitionsURI, gojsonschema.NewGoLoader(definitions)); err != nil {
+			return nil, fmt.Errorf("invalid shared definitions: %w", err)
+		}
+	}
+	if declared, exists := schema["$schema"]; exists {
+		draft, err := schemaDraft(declared)
+		if err != nil {
+			return nil, err
+		}
+		loader.AutoDetect = false
+		loader.Draft = draft
+	}
+	return loader.Compile(gojsonschema.NewGoLoader(schema))
+}
+
+// schemaDraft resolves a "$schema" value, ignoring an empty fragment and the URI scheme
+func schemaDraft(declared interface{}) (gojsonschema.Draft, error) {
+	uri, ok := declared.(string)
+	if !ok {
+		return 0, fmt.Errorf("$schema must be a string")
+	}
+
+	normalized := strings.TrimSuffix(uri, "#")
+	normalized = "http://" + strings.TrimPrefix(strings.TrimPrefix(normalized, "https://"), "http://")
+	draft, supported := supportedDrafts[normalized]
+	if !supported {
+		return 0, fmt.Errorf("unsupported JSON Schema draft %q: supported drafts are draft-04, draft-06 and draft-07", uri)
+	}
+	return draft, nil
+}
@@ -0,0 +1,205 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"config-engine/internal/models"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// FieldError is a single failed schema check or custom rule on one field
+type FieldError struct {
+	Field   string
+	Message string
+	// Value and Allowed are set when the value is not one of the schema's enum values
+	Value   interface{}
+	Allowed []interface{}
+	// Suggestion is set when a wrongly typed value converts cleanly to the expected type
+	Suggestion *models.CoercionSuggestion
+	// Description is the field's schema description, guidance for fixing the value
+	Description string
+	// statsField is the key failure stats count the error under when it is not
+	// Field, so client-chosen property names do not grow the stats
+	statsField string
+}
+
+func (e FieldError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s; %s", e.Field, e.Description, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// FieldErrors is returned by Validate when data fails its schema or a custom rule
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Violations lists the failed fields of a Validate error, or returns nil if
+// the error does not come from failed checks
+func Violations(err error) []models.FieldViolation {
+	errs, ok := err.(FieldErrors)
+	if !ok {
+		return nil
+	}
+
+	violations := make([]models.FieldViolation, len(errs))
+	for i, fieldErr := range errs {
+		violations[i] = models.FieldViolation{Field: fieldErr.Field, Message: fieldErr.Message, Description: fieldErr.Description}
+	}
+	return violations
+}
+
+// SchemaError reports a schema that could not be read or compiled
+type SchemaError struct {
+	// Type is the config type the schema is for
+	Type string
+	// File is the schema file it was read from; empty for schemas registered in code
+	File string
+	Err  error
+}
+
+func (e *SchemaError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("%s (config type %s): %v", e.File, e.Type, e.Err)
+	}
+	return fmt.Sprintf("config type %s: %v", e.Type, e.Err)
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
+
+// schemaErrors lists the SchemaErrors in err, unpacking joined errors
+func schemaErrors(err error) []*SchemaError {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []*SchemaError
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, schemaErrors(e)...)
+		}
+		return errs
+	}
+
+	var schemaErr *SchemaError
+	if errors.As(err, &schemaErr) {
+		return []*SchemaError{schemaErr}
+	}
+	return nil
+}
+
+// schemaFieldError converts a schema result error, attributing errors about a
+// named property (missing or not allowed) to that property rather than its
+// parent. Enum errors are reported with the offending value and the allowed
+// values read from the raw schema, and type errors with a coercion suggestion
+// when one exists, so clients can correct the data. The field's schema
+// description, if any, is attached as guidance.
+func schemaFieldError(desc gojsonschema.ResultError, rawSchema map[string]interface{}) FieldError {
+	fieldErr := resultFieldError(desc, rawSchema)
+	fieldErr.Description = fieldDescription(rawSchema, fieldErr.Field)
+	return fieldErr
+}
+
+// resultFieldError builds the FieldError of a schema result error
+func resultFieldError(desc gojsonschema.ResultError, rawSchema map[string]interface{}) FieldError {
+	field := desc.Field()
+	if desc.Type() == "enum" {
+		if fieldErr, ok := enumFieldError(field, desc.Value(), rawSchema); ok {
+			return fieldErr
+		}
+	}
+
+	switch desc.Type() {
+	case "invalid_type":
+		return FieldError{Field: field, Message: desc.Description(), Suggestion: coercionSuggestion(desc)}
+	case "required", "additional_property_not_allowed":
+		if property, ok := desc.Details()["property"].(string); ok {
+			parent := field
+			field = childField(parent, property)
+			if desc.Type() == "additional_property_not_allowed" {
+				return FieldError{Field: field, Message: desc.Description(), statsField: childField(parent, additionalPropertiesField)}
+			}
+		}
+	}
+	return FieldError{Field: field, Message: desc.Description()}
+}
+
+// childField returns the path of property below the parent field
+func childField(parent, property string) string {
+	if parent == gojsonschema.STRING_CONTEXT_ROOT {
+		return property
+	}
+	return parent + "." + property
+}
+
+// fieldDescription returns the description the raw schema gives the field
+// at a gojsonschema context path, or "" if it has none
+func fieldDescription(rawSchema map[string]interface{}, field string) string {
+	if field == gojsonschema.STRING_CONTEXT_ROOT {
+		return ""
+	}
+	description, _ := subSchema(rawSchema, field)["description"].(string)
+	return description
+}
+
+// enumFieldError builds the error for a value outside the field's enum
+func enumFieldError(field string, value interface{}, rawSchema map[string]interface{}) (FieldError, bool) {
+	schema := subSchema(rawSchema, field)
+	if schema == nil {
+		return FieldError{}, false
+	}
+	encoded, err := json.Marshal(schema["enum"])
+	if err != nil {
+		return FieldError{}, false
+	}
+	var allowed []interface{}
+	if err := json.Unmarshal(encoded, &allowed); err != nil || len(allowed) == 0 {
+		return FieldError{}, false
+	}
+	got, err := json.Marshal(value)
+	if err != nil {
+		return FieldError{}, false
+	}
+
+	return FieldError{
+		Field:   field,
+		Message: fmt.Sprintf("%s must be one of %s, got %s", field, encoded, got),
+		Value:   value,
+		Allowed: allowed,
+	}, true
+}
+
+// subSchema returns the raw schema describing the field at a gojsonschema
+// context path such as "limits.modes.0", or nil if it cannot be found
+func subSchema(schema map[string]interface{}, field string) map[string]interface{} {
+	if field == gojsonschema.STRING_CONTEXT_ROOT {
+		return schema
+	}
+
+	for _, segment := range strings.Split(field, ".") {
+		if properties, ok := schema["properties"].(map[string]interface{}); ok {
+			if property, ok := properties[segment].(map[string]interface{}); ok {
+				schema = property
+				continue
+			}
+		}
+		if _, err := strconv.Atoi(segment); err == nil {
+			if items, ok := schema["items"].(map[string]interface{}); ok {
+				schema = items
+				continue
+			}
+		}
+		return nil
+	}
+	return schema
+}
@@ -0,0 +1,108 @@
+package validation
+
+import "config-engine/internal/models"
+
+// Example returns minimal data satisfying the type's schema, wrapped with
+// models.ArrayData when the schema declares an array.
+// Required properties are filled from the default, examples, const or enum
+// keywords when present, and otherwise with the zero value of their type.
+// The second return value is false if no schema is registered for the type.
+func (v *Validator) Example(configType string) (map[string]interface{}, bool) {
+	v.mu.RLock()
+	schema, exists := v.rawSchemas[configType]
+	v.mu.RUnlock()
+	if !exists {
+		return nil, false
+	}
+
+	switch example := exampleValue(schema).(type) {
+	case map[string]interface{}:
+		return example, true
+	case []interface{}:
+		return models.ArrayData(example), true
+	}
+	return map[string]interface{}{}, true
+}
+
+// exampleValue generates a sample value for a (sub)schema
+func exampleValue(schema map[string]interface{}) interface{} {
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+	if examples, ok := schema["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	if value, ok := schema["const"]; ok {
+		return value
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		return exampleObject(schema)
+	case "array":
+		return []interface{}{}
+	case "string":
+		return ""
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	default:
+		if _, ok := schema["properties"]; ok {
+			return exampleObject(schema)
+		}
+		return nil
+	}
+}
+
+// exampleObject generates an object containing only the required properties
+func exampleObject(schema map[string]interface{}) map[string]interface{} {
+	properties, _ := schema["properties"].(map[string]interface{})
+	example := make(map[string]interface{})
+	for _, name := range requiredProperties(schema) {
+		property, _ := properties[name].(map[string]interface{})
+		example[name] = exampleValue(property)
+	}
+	return example
+}
+
+// schemaType returns the schema's type, taking the first entry of a type list
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, entry := range t {
+			if s, ok := entry.(string); ok && s != "null" {
+				return s
+			}
+		}
+	case []string:
+		for _, s := range t {
+			if s != "null" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// requiredProperties returns the required property names of an object schema
+func requiredProperties(schema map[string]interface{}) []string {
+	switch required := schema["required"].(type) {
+	case []string:
+		return required
+	case []interface{}:
+		names := make([]string, 0, len(required))
+		for _, entry := range required {
+			if name, ok := entry.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
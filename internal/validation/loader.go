@@ -0,0 +1,255 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"config-engine/internal/models"
+)
+
+const (
+	// schemaFileExtension is the extension of schema files in a schema directory
+	schemaFileExtension = ".json"
+	// updateSchemaSuffix marks a file as the update schema of the type it names
+	updateSchemaSuffix = ".update"
+)
+
+// NewValidatorWithSchemaDir creates a validator with the predefined schemas
+// plus every *.json schema in dir, named by file (payment_config.json
+// registers type payment_config). The directory can be re-read later with
+// ReloadSchemas. It fails if any schema fails to load; the error joins a
+// SchemaError for each of them.
+func NewValidatorWithSchemaDir(dir string) (*Validator, error) {
+	v, err := NewValidator()
+	if err != nil {
+		return nil, err
+	}
+
+	v.schemaDir = dir
+	if _, err := v.ReloadSchemas(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// NewValidatorWithSchemaDirLenient is like NewValidatorWithSchemaDir but
+// skips the schemas that fail to load, returning them alongside the
+// validator, so a single bad file does not keep the other types
+// unavailable. The error is only set when the directory cannot be read.
+func NewValidatorWithSchemaDirLenient(dir string) (*Validator, []*SchemaError, error) {
+	v, err := NewValidator()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v.schemaDir = dir
+	_, _, skipped, err := v.reloadSchemas(true, nil, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v, skipped, nil
+}
+
+// ReloadSchemas re-reads the schema directory and swaps in its schemas,
+// returning the types loaded. Reloading is all-or-nothing: every file is
+// compiled first and if any fails the current schemas are kept and the
+// failures are returned as joined SchemaErrors. Removing a file does not
+// unregister its type, since stored configs of that type still need a schema
+// to be updated.
+//
+// Two file names are special: <type>.update.json is the update schema paired
+// with <type>.json (see RegisterSchemaPair), and _definitions.json replaces
+// the shared definitions, which recompiles the schemas registered outside
+// the directory as well.
+func (v *Validator) ReloadSchemas() ([]string, error) {
+	types, _, err := v.ReloadSchemasChecked(nil, false)
+	return types, err
+}
+
+// ReloadSchemasChecked reloads like ReloadSchemas after validating stored,
+// the latest stored configurations, against the new schemas and their
+// types' custom rules, and returns those the new schemas reject. With
+// dryRun the schemas are only checked and not swapped in.
+func (v *Validator) ReloadSchemasChecked(stored []*models.Config, dryRun bool) ([]string, []models.IncompatibleConfig, error) {
+	types, incompatible, skipped, err := v.reloadSchemas(false, stored, dryRun)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(skipped) > 0 {
+		errs := make([]error, len(skipped))
+		for i, schemaErr := range skipped {
+			errs[i] = schemaErr
+		}
+		return nil, nil, errors.Join(errs...)
+	}
+	return types, incompatible, nil
+}
+
+// reloadSchemas loads the schema directory, returning the types loaded, the
+// configs in stored the new schemas reject and the schemas that failed.
+// Unless partial is set nothing is swapped in when any schema fails; with
+// partial set the failed schemas are skipped. With dryRun nothing is swapped
+// in at all. The error is only set when the directory cannot be read or
+// stored data cannot be validated.
+func (v *Validator) reloadSchemas(partial bool, stored []*models.Config, dryRun bool) ([]string, []models.IncompatibleConfig, []*SchemaError, error) {
+	v.mu.RLock()
+	dir := v.schemaDir
+	v.mu.RUnlock()
+	if dir == "" {
+		return nil, nil, nil, errors.New("no schema directory configured")
+	}
+
+	loaded, readErr := readSchemaDir(dir)
+	if loaded == nil {
+		return nil, nil, nil, readErr
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	definitions, replaced := loaded[DefinitionsType]
+	delete(loaded, DefinitionsType)
+	if !replaced {
+		definitions = v.definitions
+	}
+
+	errs := []error{readErr}
+	updates := make(map[string]map[string]interface{})
+	files := make(map[string]string, len(loaded))
+	updateFiles := make(map[string]string)
+	for name, schema := range loaded {
+		configType, isUpdate := strings.CutSuffix(name, updateSchemaSuffix)
+		if !isUpdate {
+			files[name] = name + schemaFileExtension
+			continue
+		}
+		delete(loaded, name)
+		updates[configType] = schema
+		updateFiles[configType] = name + schemaFileExtension
+	}
+
+	toCompile, toCompileUpdates := loaded, updates
+	if replaced {
+		toCompile = mergeSchemas(v.rawSchemas, loaded)
+		toCompileUpdates = mergeSchemas(v.rawUpdateSchemas, updates)
+	}
+	compiled, createErr := compileAll(toCompile, definitions, files, false)
+	compiledUpdates, updateErr := compileAll(toCompileUpdates, definitions, updateFiles, true)
+	errs = append(errs, createErr, updateErr)
+
+	// An update schema is only usable paired with a create schema
+	unreadable := make(map[string]bool)
+	for _, schemaErr := range schemaErrors(readErr) {
+		unreadable[schemaErr.File] = true
+	}
+	for configType, file := range updateFiles {
+		if compiled[configType] == nil && v.rawSchemas[configType] == nil {
+			if loaded[configType] == nil && !unreadable[configType+schemaFileExtension] {
+				errs = append(errs, &SchemaError{
+					Type: configType,
+					File: file,
+					Err:  fmt.Errorf("no %s%s schema to pair with", configType, schemaFileExtension),
+				})
+			}
+			delete(compiledUpdates, configType)
+		}
+	}
+
+	skipped := schemaErrors(errors.Join(errs...))
+	if len(skipped) > 0 && !partial {
+		return nil, nil, skipped, nil
+	}
+
+	types := make([]string, 0, len(loaded))
+	for configType := range loaded {
+		if compiled[configType] != nil {
+			types = append(types, configType)
+		}
+	}
+	sort.Strings(types)
+
+	var incompatible []models.IncompatibleConfig
+	for configType, schema := range compiled {
+		found, err := v.incompatibleConfigs(configType, schema, toCompile[configType], stored)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		incompatible = append(incompatible, found...)
+	}
+	sortIncompatible(incompatible)
+	if dryRun {
+		return types, incompatible, skipped, nil
+	}
+
+	v.definitions = definitions
+	for configType, schema := range compiled {
+		v.schemas[configType] = schema
+		v.rawSchemas[configType] = toCompile[configType]
+		v.cache.invalidate(configType)
+	}
+	for configType, schema := range compiledUpdates {
+		v.updateSchemas[configType] = schema
+		v.rawUpdateSchemas[configType] = toCompileUpdates[configType]
+		v.cache.invalidate(configType)
+	}
+
+	return types, incompatible, skipped, nil
+}
+
+// mergeSchemas returns a copy of base with the schemas in overrides replacing its own
+func mergeSchemas(base, overrides map[string]map[string]interface{}) map[string]map[string]interface{} {
+	merged := make(map[string]map[string]interface{}, len(base)+len(overrides))
+	for configType, schema := range base {
+		merged[configType] = schema
+	}
+	for configType, schema := range overrides {
+		merged[configType] = schema
+	}
+	return merged
+}
+
+// readSchemaDir reads and parses every schema file in dir, returning the
+// files that parsed alongside a SchemaError for each that did not
+func readSchemaDir(dir string) (map[string]map[string]interface{}, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema directory: %w", err)
+	}
+
+	loaded := make(map[string]map[string]interface{})
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), schemaFileExtension) {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), schemaFileExtension)
+
+		schema, err := readSchemaFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			configType := strings.TrimSuffix(name, updateSchemaSuffix)
+			errs = append(errs, &SchemaError{Type: configType, File: entry.Name(), Err: err})
+			continue
+		}
+		loaded[name] = schema
+	}
+	return loaded, errors.Join(errs...)
+}
+
+// readSchemaFile reads and parses a single schema file
+func readSchemaFile(path string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	return raw, nil
+}
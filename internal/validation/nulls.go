@@ -0,0 +1,66 @@
+package validation
+
+// RemoveNulls returns a copy of data without the properties set to null
+// whose schema does not accept null, so an explicit null clears an optional
+// field instead of failing its type check. A required field set to null is
+// removed too and then reported as missing. Properties the schema does not
+// declare, or declares without a type, keep their null. Nested objects are
+// handled recursively; the caller's data is never modified. Since nulls
+// only clear fields on update, a type's update schema is used if it has one.
+func (v *Validator) RemoveNulls(configType string, data map[string]interface{}) map[string]interface{} {
+	v.mu.RLock()
+	schema, exists := v.rawSchemas[configType]
+	if update, paired := v.rawUpdateSchemas[configType]; paired {
+		schema = update
+	}
+	v.mu.RUnlock()
+	if !exists {
+		return data
+	}
+	return removeNulls(schema, data)
+}
+
+// removeNulls removes nulls according to the properties of an object schema
+func removeNulls(schema map[string]interface{}, data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	cleaned := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		property, _ := properties[key].(map[string]interface{})
+		if value == nil && property != nil && !acceptsNull(property) {
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok && property != nil {
+			cleaned[key] = removeNulls(property, nested)
+			continue
+		}
+		cleaned[key] = value
+	}
+	return cleaned
+}
+
+// acceptsNull reports whether a property schema allows a null value
+func acceptsNull(property map[string]interface{}) bool {
+	switch t := property["type"].(type) {
+	case nil:
+		return true
+	case string:
+		return t == "null"
+	case []interface{}:
+		for _, name := range t {
+			if name == "null" {
+				return true
+			}
+		}
+	case []string:
+		for _, name := range t {
+			if name == "null" {
+				return true
+			}
+		}
+	}
+	return false
+}
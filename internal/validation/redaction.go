@@ -0,0 +1,120 @@
+package validation
+
+import (
+	"strings"
+
+	"config-engine/internal/models"
+)
+
+// RedactedValue replaces secret field values in redacted responses
+const RedactedValue = "***"
+
+// maxRefChain bounds how many "$ref"s in a row are followed, so a schema
+// whose references form a cycle cannot loop redaction forever
+const maxRefChain = 32
+
+// Redact returns a copy of data with every value annotated as
+// "secret": true in the type's schema replaced by RedactedValue.
+// Nested objects, array items and array data are redacted recursively, and
+// "$ref"s are followed within the schema and into the shared definitions;
+// stored data is never modified.
+func (v *Validator) Redact(configType string, data map[string]interface{}) map[string]interface{} {
+	v.mu.RLock()
+	schema, exists := v.rawSchemas[configType]
+	definitions := v.definitions
+	v.mu.RUnlock()
+	if !exists || data == nil {
+		return data
+	}
+
+	r := redactor{definitions: definitions}
+	if items, ok := models.DataItems(data); ok {
+		return models.ArrayData(r.redact(schema, schema, items).([]interface{}))
+	}
+	return r.redact(schema, schema, data).(map[string]interface{})
+}
+
+// redactor redacts data against a schema, resolving references to the
+// shared definitions
+type redactor struct {
+	definitions map[string]interface{}
+}
+
+// redact redacts a value according to its schema. doc is the document
+// holding the schema, which local "#/..." references resolve against.
+func (r redactor) redact(schema, doc map[string]interface{}, value interface{}) interface{} {
+	if isSecret(schema) {
+		return RedactedValue
+	}
+	schema, doc = r.resolve(schema, doc)
+	if schema == nil {
+		return value
+	}
+	if isSecret(schema) {
+		return RedactedValue
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				redacted[key] = r.redact(property, doc, item)
+				continue
+			}
+			redacted[key] = item
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = item
+			switch items := schema["items"].(type) {
+			case map[string]interface{}:
+				redacted[i] = r.redact(items, doc, item)
+			case []interface{}:
+				// A tuple schema describes each position separately
+				if i < len(items) {
+					if itemSchema, ok := items[i].(map[string]interface{}); ok {
+						redacted[i] = r.redact(itemSchema, doc, item)
+					}
+				}
+			}
+		}
+		return redacted
+	}
+	return value
+}
+
+// resolve follows a schema's "$ref"s to the schema they point at and the
+// document holding it. A reference that does not resolve yields nil.
+func (r redactor) resolve(schema, doc map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	for i := 0; i < maxRefChain; i++ {
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			return schema, doc
+		}
+
+		switch {
+		case strings.HasPrefix(ref, DefinitionsURI):
+			doc, ref = r.definitions, strings.TrimPrefix(ref, DefinitionsURI)
+		case !strings.HasPrefix(ref, "#"):
+			return nil, nil
+		}
+		target, found := models.LookupPointer(doc, strings.TrimPrefix(ref, "#"))
+		if schema, ok = target.(map[string]interface{}); !found || !ok {
+			return nil, nil
+		}
+		if isSecret(schema) {
+			return schema, doc
+		}
+	}
+	return nil, nil
+}
+
+// isSecret reports whether a schema annotates its value as secret
+func isSecret(schema map[string]interface{}) bool {
+	secret, _ := schema["secret"].(bool)
+	return secret
+}
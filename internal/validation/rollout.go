@@ -0,0 +1,71 @@
+package validation
+
+import (
+	"fmt"
+
+	"config-engine/internal/models"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// rolloutSchema is the built-in schema every rollout block must satisfy,
+// whatever the type of the configuration it belongs to
+var rolloutSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"percentage": map[string]interface{}{
+			"type":    "number",
+			"minimum": 0,
+			"maximum": 100,
+		},
+		"cohorts": map[string]interface{}{
+			"type":        "array",
+			"uniqueItems": true,
+			"items": map[string]interface{}{
+				"type":      "string",
+				"minLength": 1,
+			},
+		},
+	},
+	"required":             []string{"percentage"},
+	"additionalProperties": false,
+}
+
+var compiledRolloutSchema = mustCompileSchema(rolloutSchema)
+
+// mustCompileSchema compiles a built-in schema, panicking if it is invalid
+func mustCompileSchema(schema map[string]interface{}) *gojsonschema.Schema {
+	compiled, err := compileSchema(schema, nil)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in schema: %v", err))
+	}
+	return compiled
+}
+
+// ValidateRollout checks a rollout block against the built-in rollout schema.
+// Failures are FieldErrors with fields prefixed by "rollout".
+func ValidateRollout(rollout *models.Rollout) error {
+	if rollout == nil {
+		return nil
+	}
+
+	result, err := compiledRolloutSchema.Validate(gojsonschema.NewGoLoader(rollout))
+	if err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	errs := make(FieldErrors, 0, len(result.Errors()))
+	for _, desc := range result.Errors() {
+		fieldErr := schemaFieldError(desc, rolloutSchema)
+		if fieldErr.Field == gojsonschema.STRING_CONTEXT_ROOT {
+			fieldErr.Field = "rollout"
+		} else {
+			fieldErr.Field = "rollout." + fieldErr.Field
+		}
+		errs = append(errs, fieldErr)
+	}
+	return errs
+}
@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"fmt"
+
+	"config-engine/internal/models"
+)
+
+// RuleError represents a single violation reported by a custom rule
+type RuleError struct {
+	Field   string
+	Message string
+}
+
+func (e RuleError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// CustomRule is a Go validation function invoked after schema validation.
+// It is used for cross-field and conditional checks that JSON Schema cannot express.
+type CustomRule func(data map[string]interface{}) []RuleError
+
+// RegisterRule registers a custom rule for a configuration type
+func (v *Validator) RegisterRule(configType string, rule CustomRule) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.rules[configType] = append(v.rules[configType], rule)
+	v.cache.invalidate(configType)
+}
+
+// applyRules runs the custom rules of a config type against data
+func applyRules(rules []CustomRule, data map[string]interface{}) []RuleError {
+	var violations []RuleError
+	for _, rule := range rules {
+		violations = append(violations, rule(data)...)
+	}
+	return violations
+}
+
+// paymentLimitRule requires a positive max_limit when payments are enabled
+func paymentLimitRule(data map[string]interface{}) []RuleError {
+	enabled, _ := models.GetBool(data, "enabled")
+	if !enabled {
+		return nil
+	}
+
+	limit, ok := models.GetFloat(data, "max_limit")
+	if !ok || limit <= 0 {
+		return []RuleError{{
+			Field:   "max_limit",
+			Message: "max_limit must be greater than 0 when enabled is true",
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"sync"
+
+	"config-engine/internal/models"
+)
+
+const (
+	// additionalPropertiesField counts the properties a schema does not allow,
+	// whatever their names
+	additionalPropertiesField = "(additional properties)"
+	// otherFieldsField counts failures on fields beyond maxStatsFields
+	otherFieldsField = "(other)"
+	// maxStatsFields caps the distinct fields counted per config type
+	maxStatsFields = 100
+)
+
+// validationStats counts validation failures by config type and failing field
+type validationStats struct {
+	mu     sync.Mutex
+	types  map[string]int            // config type -> failed validations
+	fields map[string]map[string]int // config type -> field -> failures
+}
+
+// newValidationStats creates empty failure counters
+func newValidationStats() *validationStats {
+	return &validationStats{
+		types:  make(map[string]int),
+		fields: make(map[string]map[string]int),
+	}
+}
+
+// record counts one failed validation and each field that failed in it
+func (s *validationStats) record(configType string, errs FieldErrors) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.types[configType]++
+	if s.fields[configType] == nil {
+		s.fields[configType] = make(map[string]int)
+	}
+	fields := s.fields[configType]
+	for _, fieldErr := range errs {
+		field := fieldErr.Field
+		if fieldErr.statsField != "" {
+			field = fieldErr.statsField
+		}
+		// Array indices make paths like items.0, items.1; past the cap new
+		// fields share one counter so the map stays bounded
+		if _, ok := fields[field]; !ok && len(fields) >= maxStatsFields {
+			field = otherFieldsField
+		}
+		fields[field]++
+	}
+}
+
+// snapshot returns a copy of the counters
+func (s *validationStats) snapshot() map[string]models.ValidationTypeStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make(map[string]models.ValidationTypeStats, len(s.types))
+	for configType, failures := range s.types {
+		fields := make(map[string]int, len(s.fields[configType]))
+		for field, count := range s.fields[configType] {
+			fields[field] = count
+		}
+		stats[configType] = models.ValidationTypeStats{Failures: failures, Fields: fields}
+	}
+	return stats
+}
+
+// FailureStats returns how often validation failed per config type and field
+// since the validator was created
+func (v *Validator) FailureStats() map[string]models.ValidationTypeStats {
+	return v.stats.snapshot()
+}
@@ -0,0 +1,38 @@
+package validation
+
+import "fmt"
+
+// RegisterSchemaPair registers distinct create and update schemas for a
+// configuration type, for types whose fields are required on create but may
+// be omitted on update (such as an immutable owner). The create schema also
+// serves metadata lookups such as secret annotations and examples.
+// RegisterSchema replaces the pair with a single schema again.
+func (v *Validator) RegisterSchemaPair(configType string, create, update map[string]interface{}) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	compiledCreate, err := compileSchema(create, v.definitions)
+	if err != nil {
+		return &SchemaError{Type: configType, Err: fmt.Errorf("failed to compile create schema: %w", err)}
+	}
+	compiledUpdate, err := compileSchema(update, v.definitions)
+	if err != nil {
+		return &SchemaError{Type: configType, Err: fmt.Errorf("failed to compile update schema: %w", err)}
+	}
+
+	v.schemas[configType] = compiledCreate
+	v.rawSchemas[configType] = create
+	v.updateSchemas[configType] = compiledUpdate
+	v.rawUpdateSchemas[configType] = update
+	v.cache.invalidate(configType)
+	return nil
+}
+
+// ValidateUpdate validates the data replacing an existing config against its
+// type's update schema, falling back to the single schema for types
+// registered without a pair
+func (v *Validator) ValidateUpdate(configType string, data map[string]interface{}) error {
+	err := v.validate(configType, data, true)
+	v.recordFailure(configType, err)
+	return err
+}
@@ -3,19 +3,46 @@ package validation
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"sync"
+
+	"config-engine/internal/models"
 
 	"github.com/xeipuuv/gojsonschema"
 )
 
 // Validator handles configuration validation against schemas
 type Validator struct {
+	// mu guards the per-type maps so types can be registered at runtime
+	mu      sync.RWMutex
 	schemas map[string]*gojsonschema.Schema
+	// rawSchemas keeps the uncompiled schemas for metadata such as secret annotations
+	rawSchemas map[string]map[string]interface{}
+	// updateSchemas hold the types registered with a distinct update schema
+	updateSchemas    map[string]*gojsonschema.Schema
+	rawUpdateSchemas map[string]map[string]interface{}
+	rules            map[string][]CustomRule
+	// coerceIntegers holds the types whose whole-number floats are validated as integers
+	coerceIntegers map[string]bool
+	cache          *validationCache
+	stats          *validationStats
+	// schemaDir is the directory ReloadSchemas reads schemas from (empty if none)
+	schemaDir string
+	// definitions is the shared document "$ref"s to DefinitionsURI resolve against
+	definitions map[string]interface{}
 }
 
 // NewValidator creates a new validator with predefined schemas
 func NewValidator() (*Validator, error) {
 	v := &Validator{
-		schemas: make(map[string]*gojsonschema.Schema),
+		schemas:          make(map[string]*gojsonschema.Schema),
+		rawSchemas:       make(map[string]map[string]interface{}),
+		updateSchemas:    make(map[string]*gojsonschema.Schema),
+		rawUpdateSchemas: make(map[string]map[string]interface{}),
+		rules:            make(map[string][]CustomRule),
+		coerceIntegers:   make(map[string]bool),
+		cache:            newValidationCache(defaultCacheSize),
+		stats:            newValidationStats(),
 	}
 
 	// Register payment_config schema
@@ -34,63 +61,183 @@ func NewValidator() (*Validator, error) {
 	}
 
 	if err := v.RegisterSchema("payment_config", paymentSchema); err != nil {
-		return nil, fmt.Errorf("failed to register payment_config schema: %w", err)
+		return nil, fmt.Errorf("failed to register built-in schema: %w", err)
+	}
+	v.RegisterRule("payment_config", paymentLimitRule)
+
+	// Register ip_allowlist, whose data is an array rather than an object
+	allowlistSchema := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":   "string",
+			"format": "ipv4",
+		},
+		"uniqueItems": true,
+	}
+
+	if err := v.RegisterSchema("ip_allowlist", allowlistSchema); err != nil {
+		return nil, fmt.Errorf("failed to register built-in schema: %w", err)
 	}
 
 	return v, nil
 }
 
-// RegisterSchema registers a new schema for a configuration type
+// RegisterSchema registers a new schema for a configuration type, used for
+// both creates and updates. "$ref"s to DefinitionsURI resolve against the
+// shared definitions; registering under DefinitionsType replaces those
+// definitions instead.
 func (v *Validator) RegisterSchema(configType string, schema map[string]interface{}) error {
-	schemaJSON, err := json.Marshal(schema)
-	if err != nil {
-		return fmt.Errorf("failed to marshal schema: %w", err)
+	if configType == DefinitionsType {
+		return v.RegisterDefinitions(schema)
 	}
 
-	schemaLoader := gojsonschema.NewBytesLoader(schemaJSON)
-	compiledSchema, err := gojsonschema.NewSchema(schemaLoader)
-	if err != nil {
-		return fmt.Errorf("failed to compile schema: %w", err)
-	}
-
-	v.schemas[configType] = compiledSchema
-	return nil
+	_, err := v.RegisterSchemaChecked(configType, schema, nil, false)
+	return err
 }
 
-// Validate validates configuration data against its type's schema
+// Validate validates configuration data against its type's schema.
+// Data that fails the schema or a custom rule yields FieldErrors; any other
+// error means validation could not be performed.
 func (v *Validator) Validate(configType string, data map[string]interface{}) error {
+	err := v.validate(configType, data, false)
+	v.recordFailure(configType, err)
+	return err
+}
+
+// Check validates data like Validate without counting failures in the
+// validation stats, which describe rejected writes. It is for re-checking
+// data that is already stored.
+func (v *Validator) Check(configType string, data map[string]interface{}) error {
+	return v.validate(configType, data, false)
+}
+
+// validate validates data against the create schema of configType, or its
+// update schema when update is set and the type has one
+func (v *Validator) validate(configType string, data map[string]interface{}, update bool) error {
+	v.mu.RLock()
 	schema, exists := v.schemas[configType]
+	rawSchema := v.rawSchemas[configType]
+	if updateSchema, paired := v.updateSchemas[configType]; update && paired {
+		schema, rawSchema = updateSchema, v.rawUpdateSchemas[configType]
+	} else {
+		update = false
+	}
+	coerce := v.coerceIntegers[configType]
+	rules := v.rules[configType]
+	generation := v.cache.generation(configType)
+	v.mu.RUnlock()
 	if !exists {
 		return fmt.Errorf("no schema found for config type: %s", configType)
 	}
 
-	dataJSON, err := json.Marshal(data)
+	if coerce {
+		data = coerceIntegers(data)
+	}
+
+	// Array data is validated as the array it represents
+	dataJSON, err := json.Marshal(models.ConfigData(data))
 	if err != nil {
 		return fmt.Errorf("failed to marshal data: %w", err)
 	}
 
+	// Reuse the result of validating identical content against the same type
+	key := newCacheKey(configType, update, dataJSON)
+	if cached, ok := v.cache.get(key); ok {
+		return cached
+	}
+
 	documentLoader := gojsonschema.NewBytesLoader(dataJSON)
 	result, err := schema.Validate(documentLoader)
 	if err != nil {
 		return fmt.Errorf("validation error: %w", err)
 	}
 
+	err = checkResult(rules, rawSchema, data, result)
+	v.cache.put(key, err, generation)
+	return err
+}
+
+// checkResult converts a schema result and custom rule violations into FieldErrors
+func checkResult(rules []CustomRule, rawSchema, data map[string]interface{}, result *gojsonschema.Result) error {
 	if !result.Valid() {
-		errors := ""
-		for i, desc := range result.Errors() {
-			if i > 0 {
-				errors += "; "
-			}
-			errors += fmt.Sprintf("%s: %s", desc.Field(), desc.Description())
+		errs := make(FieldErrors, 0, len(result.Errors()))
+		for _, desc := range result.Errors() {
+			errs = append(errs, schemaFieldError(desc, rawSchema))
+		}
+		return errs
+	}
+
+	// Run custom rules only once the data is structurally valid
+	if violations := applyRules(rules, data); len(violations) > 0 {
+		errs := make(FieldErrors, 0, len(violations))
+		for _, violation := range violations {
+			errs = append(errs, FieldError{
+				Field:       violation.Field,
+				Message:     violation.Message,
+				Description: fieldDescription(rawSchema, violation.Field),
+			})
 		}
-		return fmt.Errorf("%s", errors)
+		return errs
 	}
 
 	return nil
 }
 
+// recordFailure counts a validation result in the failure stats
+func (v *Validator) recordFailure(configType string, err error) {
+	if errs, ok := err.(FieldErrors); ok {
+		v.stats.record(configType, errs)
+	}
+}
+
 // HasSchema checks if a schema exists for the given config type
 func (v *Validator) HasSchema(configType string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
 	_, exists := v.schemas[configType]
 	return exists
-}
\ No newline at end of file
+}
+
+// SchemaHash returns a content hash of the schema configType is currently
+// validated against, or false if the type has no schema. For types
+// registered with a schema pair it covers both schemas. Shared definitions
+// are not included.
+func (v *Validator) SchemaHash(configType string) (string, bool) {
+	v.mu.RLock()
+	rawSchema, exists := v.rawSchemas[configType]
+	rawUpdate, paired := v.rawUpdateSchemas[configType]
+	v.mu.RUnlock()
+	if !exists {
+		return "", false
+	}
+
+	if paired {
+		return models.DataChecksum(map[string]interface{}{"create": rawSchema, "update": rawUpdate}), true
+	}
+	return models.DataChecksum(rawSchema), true
+}
+
+// ListSchemas returns the registered config types in sorted order
+func (v *Validator) ListSchemas() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	types := make([]string, 0, len(v.schemas))
+	for configType := range v.schemas {
+		types = append(types, configType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Ready reports whether the validator has at least one schema registered
+func (v *Validator) Ready() error {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if len(v.schemas) == 0 {
+		return fmt.Errorf("no schemas registered")
+	}
+	return nil
+}
@@ -1,7 +1,17 @@
 package validation
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+
+	"config-engine/internal/models"
 )
 
 func TestNewValidator(t *testing.T) {
@@ -93,6 +103,39 @@ func TestValidatePaymentConfig(t *testing.T) {
 	}
 }
 
+func TestValidateIPAllowlist(t *testing.T) {
+	validator, _ := NewValidator()
+
+	tests := []struct {
+		name        string
+		data        map[string]interface{}
+		expectError bool
+	}{
+		{name: "valid addresses", data: models.ArrayData([]interface{}{"10.0.0.1", "192.168.1.10"})},
+		{name: "empty array", data: models.ArrayData(nil)},
+		{name: "invalid address", data: models.ArrayData([]interface{}{"10.0.0.300"}), expectError: true},
+		{name: "duplicate address", data: models.ArrayData([]interface{}{"10.0.0.1", "10.0.0.1"}), expectError: true},
+		{name: "object data", data: map[string]interface{}{"ips": []interface{}{"10.0.0.1"}}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate("ip_allowlist", tt.data)
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+
+	example, _ := validator.Example("ip_allowlist")
+	if items, ok := models.DataItems(example); !ok || len(items) != 0 {
+		t.Errorf("Expected an empty array example, got %v", example)
+	}
+}
+
 func TestValidateUnknownType(t *testing.T) {
 	validator, _ := NewValidator()
 
@@ -159,4 +202,1064 @@ func TestRegisterSchema(t *testing.T) {
 	if err == nil {
 		t.Error("Expected validation error")
 	}
-}
\ No newline at end of file
+}
+
+func TestRegisterSchemaDrafts(t *testing.T) {
+	validator, _ := NewValidator()
+
+	// if/then/else is a draft-07 keyword: card payments must carry a card token
+	conditional := func(draft string) map[string]interface{} {
+		return map[string]interface{}{
+			"$schema": draft,
+			"type":    "object",
+			"properties": map[string]interface{}{
+				"method":     map[string]interface{}{"type": "string"},
+				"card_token": map[string]interface{}{"type": "string"},
+			},
+			"if":   map[string]interface{}{"properties": map[string]interface{}{"method": map[string]interface{}{"const": "card"}}},
+			"then": map[string]interface{}{"required": []string{"card_token"}},
+			"else": map[string]interface{}{"not": map[string]interface{}{"required": []string{"card_token"}}},
+		}
+	}
+
+	if err := validator.RegisterSchema("checkout_v7", conditional("http://json-schema.org/draft-07/schema#")); err != nil {
+		t.Fatalf("Failed to register draft-07 schema: %v", err)
+	}
+	tests := []struct {
+		name  string
+		data  map[string]interface{}
+		valid bool
+	}{
+		{"card with token", map[string]interface{}{"method": "card", "card_token": "tok_1"}, true},
+		{"card without token", map[string]interface{}{"method": "card"}, false},
+		{"invoice without token", map[string]interface{}{"method": "invoice"}, true},
+		{"invoice with token", map[string]interface{}{"method": "invoice", "card_token": "tok_1"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validator.Validate("checkout_v7", tt.data); (err == nil) != tt.valid {
+				t.Errorf("Expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+
+	// A draft-04 schema is validated under draft-04 rules, which ignore if/then/else
+	if err := validator.RegisterSchema("checkout_v4", conditional("http://json-schema.org/draft-04/schema#")); err != nil {
+		t.Fatalf("Failed to register draft-04 schema: %v", err)
+	}
+	if err := validator.Validate("checkout_v4", map[string]interface{}{"method": "card"}); err != nil {
+		t.Errorf("Expected draft-04 to ignore if/then/else, got %v", err)
+	}
+
+	unsupported := map[interface{}]string{
+		"https://json-schema.org/draft/2020-12/schema": "unsupported JSON Schema draft",
+		7: "$schema must be a string",
+	}
+	for draft, want := range unsupported {
+		err := validator.RegisterSchema("checkout_next", map[string]interface{}{"$schema": draft, "type": "object"})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected an error containing %q for $schema %v, got %v", want, draft, err)
+		}
+	}
+	if validator.HasSchema("checkout_next") {
+		t.Error("Expected a schema with an unsupported draft not to be registered")
+	}
+}
+
+func TestListSchemas(t *testing.T) {
+	validator, _ := NewValidator()
+	validator.RegisterSchema("alpha_config", map[string]interface{}{"type": "object"})
+
+	types := validator.ListSchemas()
+	if len(types) != 3 || types[0] != "alpha_config" || types[1] != "ip_allowlist" || types[2] != "payment_config" {
+		t.Errorf("Expected [alpha_config ip_allowlist payment_config], got %v", types)
+	}
+}
+
+// TestConcurrentRegisterAndValidate is meant to be run with -race
+func TestConcurrentRegisterAndValidate(t *testing.T) {
+	validator, _ := NewValidator()
+	schema := map[string]interface{}{"type": "object"}
+	data := map[string]interface{}{"max_limit": 100, "enabled": true}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			configType := fmt.Sprintf("dynamic_%d", i%5)
+			if err := validator.RegisterSchema(configType, schema); err != nil {
+				t.Errorf("Failed to register schema: %v", err)
+			}
+			validator.RegisterRule(configType, func(map[string]interface{}) []RuleError { return nil })
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			if err := validator.Validate("payment_config", data); err != nil {
+				t.Errorf("Unexpected validation error: %v", err)
+			}
+			validator.HasSchema(fmt.Sprintf("dynamic_%d", i%5))
+			validator.ListSchemas()
+			validator.Redact("payment_config", data)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestExample(t *testing.T) {
+	validator, _ := NewValidator()
+
+	example, ok := validator.Example("payment_config")
+	if !ok {
+		t.Fatal("Expected example for payment_config")
+	}
+	if len(example) != 2 || example["max_limit"] != 0 || example["enabled"] != false {
+		t.Errorf("Expected {max_limit: 0, enabled: false}, got %v", example)
+	}
+	if err := validator.Validate("payment_config", example); err != nil {
+		t.Errorf("Expected example to be valid, got %v", err)
+	}
+
+	validator.RegisterSchema("feature_config", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"mode":     map[string]interface{}{"type": "string", "enum": []interface{}{"on", "off"}},
+			"retries":  map[string]interface{}{"type": "integer", "default": 3},
+			"region":   map[string]interface{}{"type": "string", "examples": []interface{}{"eu-west-1"}},
+			"optional": map[string]interface{}{"type": "string"},
+			"limits": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"max": map[string]interface{}{"type": "number"}},
+				"required":   []interface{}{"max"},
+			},
+		},
+		"required": []string{"mode", "retries", "region", "limits"},
+	})
+
+	example, _ = validator.Example("feature_config")
+	if example["mode"] != "on" || example["retries"] != 3 || example["region"] != "eu-west-1" {
+		t.Errorf("Expected enum, default and examples keywords to be used, got %v", example)
+	}
+	if _, exists := example["optional"]; exists {
+		t.Error("Expected optional properties to be omitted")
+	}
+	if limits, _ := example["limits"].(map[string]interface{}); limits["max"] != 0 {
+		t.Errorf("Expected nested object example, got %v", example["limits"])
+	}
+	if err := validator.Validate("feature_config", example); err != nil {
+		t.Errorf("Expected example to be valid, got %v", err)
+	}
+
+	if _, ok := validator.Example("unknown_type"); ok {
+		t.Error("Expected no example for unknown type")
+	}
+}
+
+func TestIntegerCoercion(t *testing.T) {
+	validator, _ := NewValidator()
+
+	validator.SetIntegerCoercion("payment_config", true)
+
+	tests := []struct {
+		name        string
+		maxLimit    interface{}
+		expectError bool
+	}{
+		{name: "whole float", maxLimit: 1000.0, expectError: false},
+		{name: "whole json number", maxLimit: json.Number("1000.0"), expectError: false},
+		{name: "fractional float", maxLimit: 1000.5, expectError: true},
+		{name: "fractional json number", maxLimit: json.Number("1000.5"), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]interface{}{"max_limit": tt.maxLimit, "enabled": true}
+			err := validator.Validate("payment_config", data)
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+			if data["max_limit"] != tt.maxLimit {
+				t.Error("Expected caller data to be left unmodified")
+			}
+		})
+	}
+}
+
+func TestIntegerCoercionReachesRules(t *testing.T) {
+	// The schema already accepts 1000.0 as an integer; coercion is what hands
+	// rules an int64 instead of the literal the client sent
+	wantInt := func(data map[string]interface{}) []RuleError {
+		if _, ok := data["max_limit"].(int64); !ok {
+			return []RuleError{{Field: "max_limit", Message: "max_limit must be an integer"}}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name        string
+		coerce      bool
+		expectError bool
+	}{
+		{name: "without coercion", coerce: false, expectError: true},
+		{name: "with coercion", coerce: true, expectError: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, _ := NewValidator()
+			validator.RegisterRule("payment_config", wantInt)
+			validator.SetIntegerCoercion("payment_config", tt.coerce)
+
+			data := map[string]interface{}{"max_limit": json.Number("1000.0"), "enabled": true}
+			err := validator.Validate("payment_config", data)
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestCoerceIntegers(t *testing.T) {
+	data := map[string]interface{}{
+		"whole":    2.0,
+		"fraction": 2.5,
+		"nested":   map[string]interface{}{"items": []interface{}{1.0, 1.5, "x"}},
+	}
+
+	coerced := coerceIntegers(data)
+	if coerced["whole"] != int64(2) || coerced["fraction"] != 2.5 {
+		t.Errorf("Unexpected top-level coercion: %v", coerced)
+	}
+	items := coerced["nested"].(map[string]interface{})["items"].([]interface{})
+	if items[0] != int64(1) || items[1] != 1.5 || items[2] != "x" {
+		t.Errorf("Unexpected nested coercion: %v", items)
+	}
+}
+
+func TestPaymentLimitRule(t *testing.T) {
+	validator, _ := NewValidator()
+
+	tests := []struct {
+		name        string
+		data        map[string]interface{}
+		expectError bool
+	}{
+		{
+			name:        "enabled with positive limit",
+			data:        map[string]interface{}{"max_limit": 1000, "enabled": true},
+			expectError: false,
+		},
+		{
+			name:        "enabled with zero limit",
+			data:        map[string]interface{}{"max_limit": 0, "enabled": true},
+			expectError: true,
+		},
+		{
+			name:        "enabled with negative limit",
+			data:        map[string]interface{}{"max_limit": -5, "enabled": true},
+			expectError: true,
+		},
+		{
+			name:        "disabled with zero limit",
+			data:        map[string]interface{}{"max_limit": 0, "enabled": false},
+			expectError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate("payment_config", tt.data)
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	validator, _ := NewValidator()
+
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"min": map[string]interface{}{"type": "integer"},
+			"max": map[string]interface{}{"type": "integer"},
+		},
+	}
+	if err := validator.RegisterSchema("range_config", schema); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+
+	validator.RegisterRule("range_config", func(data map[string]interface{}) []RuleError {
+		min, _ := models.GetFloat(data, "min")
+		max, _ := models.GetFloat(data, "max")
+		if min > max {
+			return []RuleError{{Field: "min", Message: "min must not exceed max"}}
+		}
+		return nil
+	})
+
+	if err := validator.Validate("range_config", map[string]interface{}{"min": 1, "max": 5}); err != nil {
+		t.Errorf("Validation should succeed: %v", err)
+	}
+
+	err := validator.Validate("range_config", map[string]interface{}{"min": 10, "max": 5})
+	if err == nil {
+		t.Fatal("Expected rule violation")
+	}
+	if err.Error() != "min: min must not exceed max" {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	validator, _ := NewValidator()
+
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"api_key": map[string]interface{}{"type": "string", "secret": true},
+			"url":     map[string]interface{}{"type": "string"},
+			"db": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"host":     map[string]interface{}{"type": "string"},
+					"password": map[string]interface{}{"type": "string", "secret": true},
+				},
+			},
+		},
+	}
+	if err := validator.RegisterSchema("gateway_config", schema); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+
+	data := map[string]interface{}{
+		"api_key": "sk_live_123",
+		"url":     "https://example.com",
+		"db": map[string]interface{}{
+			"host":     "localhost",
+			"password": "hunter2",
+		},
+	}
+	if err := validator.Validate("gateway_config", data); err != nil {
+		t.Fatalf("Secret annotation should not affect validation: %v", err)
+	}
+
+	redacted := validator.Redact("gateway_config", data)
+	if redacted["api_key"] != RedactedValue {
+		t.Errorf("Expected api_key to be redacted, got %v", redacted["api_key"])
+	}
+	if redacted["url"] != "https://example.com" {
+		t.Errorf("Expected url to be untouched, got %v", redacted["url"])
+	}
+	db := redacted["db"].(map[string]interface{})
+	if db["password"] != RedactedValue {
+		t.Errorf("Expected nested password to be redacted, got %v", db["password"])
+	}
+	if db["host"] != "localhost" {
+		t.Errorf("Expected nested host to be untouched, got %v", db["host"])
+	}
+
+	// Original data must remain intact
+	if data["api_key"] != "sk_live_123" || data["db"].(map[string]interface{})["password"] != "hunter2" {
+		t.Error("Redact should not modify the original data")
+	}
+}
+
+func TestRedactArraysAndReferences(t *testing.T) {
+	validator, _ := NewValidator()
+
+	secret := map[string]interface{}{"type": "string", "secret": true}
+	credential := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"user":     map[string]interface{}{"type": "string"},
+			"password": secret,
+		},
+	}
+	if err := validator.RegisterDefinitions(map[string]interface{}{
+		"definitions": map[string]interface{}{"credential": credential},
+	}); err != nil {
+		t.Fatalf("Failed to register definitions: %v", err)
+	}
+	schemas := map[string]map[string]interface{}{
+		"items_config": {
+			"type": "object",
+			"properties": map[string]interface{}{
+				"tokens":   map[string]interface{}{"type": "array", "items": secret},
+				"backends": map[string]interface{}{"type": "array", "items": credential},
+			},
+		},
+		"list_config": {"type": "array", "items": credential},
+		"shared_config": {
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db": map[string]interface{}{"$ref": DefinitionsURI + "#/definitions/credential"},
+			},
+		},
+		"local_config": {
+			"type":        "object",
+			"definitions": map[string]interface{}{"token": secret},
+			"properties": map[string]interface{}{
+				"token": map[string]interface{}{"$ref": "#/definitions/token"},
+			},
+		},
+	}
+	for configType, schema := range schemas {
+		if err := validator.RegisterSchema(configType, schema); err != nil {
+			t.Fatalf("Failed to register %s: %v", configType, err)
+		}
+	}
+
+	backend := func() map[string]interface{} {
+		return map[string]interface{}{"user": "svc", "password": "hunter2"}
+	}
+	tests := []struct {
+		configType string
+		data       map[string]interface{}
+		pointer    string
+		want       interface{}
+	}{
+		{"items_config", map[string]interface{}{"tokens": []interface{}{"t1", "t2"}}, "/tokens/1", RedactedValue},
+		{"items_config", map[string]interface{}{"backends": []interface{}{backend()}}, "/backends/0/password", RedactedValue},
+		{"items_config", map[string]interface{}{"backends": []interface{}{backend()}}, "/backends/0/user", "svc"},
+		{"list_config", models.ArrayData([]interface{}{backend()}), "/0/password", RedactedValue},
+		{"list_config", models.ArrayData([]interface{}{backend()}), "/0/user", "svc"},
+		{"shared_config", map[string]interface{}{"db": backend()}, "/db/password", RedactedValue},
+		{"shared_config", map[string]interface{}{"db": backend()}, "/db/user", "svc"},
+		{"local_config", map[string]interface{}{"token": "t1"}, "/token", RedactedValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.configType+tt.pointer, func(t *testing.T) {
+			if err := validator.Validate(tt.configType, tt.data); err != nil {
+				t.Fatalf("Expected valid data, got %v", err)
+			}
+			redacted := validator.Redact(tt.configType, tt.data)
+			if got, _ := models.LookupPointer(redacted, tt.pointer); got != tt.want {
+				t.Errorf("Expected %v at %s, got %v", tt.want, tt.pointer, got)
+			}
+			if got, _ := models.LookupPointer(tt.data, tt.pointer); got == RedactedValue {
+				t.Error("Redact should not modify the original data")
+			}
+		})
+	}
+}
+
+func TestValidateEnumErrors(t *testing.T) {
+	validator, _ := NewValidator()
+	validator.RegisterSchema("routing_config", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"mode": map[string]interface{}{"type": "string", "enum": []string{"card", "bank"}},
+			"limits": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"tiers": map[string]interface{}{
+						"type":  "array",
+						"items": map[string]interface{}{"enum": []interface{}{1, 2, 3}},
+					},
+				},
+			},
+		},
+	})
+
+	err := validator.Validate("routing_config", map[string]interface{}{
+		"mode":   "cash",
+		"limits": map[string]interface{}{"tiers": []interface{}{1, 5}},
+	})
+	errs, ok := err.(FieldErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("Expected 2 field errors, got %v", err)
+	}
+
+	byField := map[string]FieldError{}
+	for _, fieldErr := range errs {
+		byField[fieldErr.Field] = fieldErr
+	}
+	mode := byField["mode"]
+	if mode.Value != "cash" || fmt.Sprint(mode.Allowed) != "[card bank]" {
+		t.Errorf("Expected value cash and allowed [card bank], got %+v", mode)
+	}
+	tier := byField["limits.tiers.1"]
+	if fmt.Sprint(tier.Value) != "5" || fmt.Sprint(tier.Allowed) != "[1 2 3]" {
+		t.Errorf("Expected value 5 and allowed [1 2 3], got %+v", tier)
+	}
+
+	// The message returned to clients names the allowed set and the offending value
+	for _, want := range []string{`mode must be one of ["card","bank"], got "cash"`, `limits.tiers.1 must be one of [1,2,3], got 5`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got %q", want, err.Error())
+		}
+	}
+}
+
+func TestCoercionSuggestions(t *testing.T) {
+	validator, _ := NewValidator()
+	validator.RegisterSchema("limits_config", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"max_limit": map[string]interface{}{"type": "integer"},
+			"ratio":     map[string]interface{}{"type": "number"},
+			"enabled":   map[string]interface{}{"type": "boolean"},
+			"label":     map[string]interface{}{"type": []string{"string", "null"}},
+		},
+	})
+
+	tests := []struct {
+		name  string
+		field string
+		value interface{}
+		want  *models.CoercionSuggestion
+	}{
+		{"stringified integer", "max_limit", "1000", &models.CoercionSuggestion{Field: "max_limit", From: "string", To: "integer", Value: int64(1000)}},
+		{"fractional string for integer", "max_limit", "10.5", nil},
+		{"fractional number for integer", "max_limit", 10.5, nil},
+		{"stringified number", "ratio", "0.25", &models.CoercionSuggestion{Field: "ratio", From: "string", To: "number", Value: 0.25}},
+		{"stringified boolean", "enabled", "true", &models.CoercionSuggestion{Field: "enabled", From: "string", To: "boolean", Value: true}},
+		{"ambiguous boolean", "enabled", "yes", nil},
+		{"number for string", "label", 42, &models.CoercionSuggestion{Field: "label", From: "integer", To: "string", Value: "42"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]interface{}{tt.field: tt.value}
+			err := validator.Validate("limits_config", data)
+			if err == nil {
+				t.Fatal("Expected a type error")
+			}
+			suggestions := Suggestions(err)
+			if tt.want == nil {
+				if len(suggestions) != 0 {
+					t.Errorf("Expected no suggestions, got %+v", suggestions)
+				}
+				return
+			}
+			if len(suggestions) != 1 || suggestions[0] != *tt.want {
+				t.Errorf("Expected %+v, got %+v", *tt.want, suggestions)
+			}
+			// Advisory only: the data is left as sent
+			if data[tt.field] != tt.value {
+				t.Errorf("Expected data to be unchanged, got %v", data[tt.field])
+			}
+		})
+	}
+}
+
+func TestValidationFailureStats(t *testing.T) {
+	validator, _ := NewValidator()
+
+	invalid := []map[string]interface{}{
+		{"max_limit": 100},                                // enabled is required
+		{"max_limit": "high", "enabled": true},            // max_limit has the wrong type
+		{"max_limit": 0, "enabled": true},                 // custom rule on max_limit
+		{"max_limit": 0, "enabled": true},                 // cached result still counts
+		{"max_limit": 1, "enabled": true, "extra": "yes"}, // extra is not allowed
+	}
+	for _, data := range invalid {
+		err := validator.Validate("payment_config", data)
+		if _, ok := err.(FieldErrors); !ok {
+			t.Fatalf("Expected FieldErrors for %v, got %v", data, err)
+		}
+	}
+	validator.Validate("payment_config", map[string]interface{}{"max_limit": 1, "enabled": true})
+	validator.Validate("unknown_type", map[string]interface{}{})
+
+	stats := validator.FailureStats()
+	if len(stats) != 1 {
+		t.Fatalf("Expected stats for payment_config only, got %v", stats)
+	}
+	payment := stats["payment_config"]
+	if payment.Failures != len(invalid) {
+		t.Errorf("Expected %d failures, got %d", len(invalid), payment.Failures)
+	}
+	expected := map[string]int{"enabled": 1, "max_limit": 3, additionalPropertiesField: 1}
+	if len(payment.Fields) != len(expected) {
+		t.Errorf("Expected fields %v, got %v", expected, payment.Fields)
+	}
+	for field, count := range expected {
+		if payment.Fields[field] != count {
+			t.Errorf("Expected %d failures on %s, got %d", count, field, payment.Fields[field])
+		}
+	}
+}
+
+func TestValidationFailureStatsBounded(t *testing.T) {
+	validator, _ := NewValidator()
+
+	// Clients choose unknown property names, so they share one counter
+	for i := 0; i < 2*maxStatsFields; i++ {
+		data := map[string]interface{}{"max_limit": 1, "enabled": true, fmt.Sprintf("extra_%d", i): true}
+		if _, ok := validator.Validate("payment_config", data).(FieldErrors); !ok {
+			t.Fatalf("Expected FieldErrors for %v", data)
+		}
+	}
+	fields := validator.FailureStats()["payment_config"].Fields
+	if len(fields) != 1 || fields[additionalPropertiesField] != 2*maxStatsFields {
+		t.Errorf("Expected all unknown properties under %q, got %v", additionalPropertiesField, fields)
+	}
+
+	// Fields past the cap share the overflow counter
+	stats := newValidationStats()
+	for i := 0; i < 2*maxStatsFields; i++ {
+		stats.record("list_config", FieldErrors{{Field: fmt.Sprintf("items.%d", i)}})
+	}
+	fields = stats.snapshot()["list_config"].Fields
+	if len(fields) != maxStatsFields+1 {
+		t.Errorf("Expected %d fields, got %d", maxStatsFields+1, len(fields))
+	}
+	if fields[otherFieldsField] != maxStatsFields {
+		t.Errorf("Expected %d overflow failures, got %d", maxStatsFields, fields[otherFieldsField])
+	}
+}
+
+func TestValidateCacheInvalidatedOnSchemaChange(t *testing.T) {
+	validator, _ := NewValidator()
+
+	data := map[string]interface{}{"name": "x"}
+	loose := map[string]interface{}{"type": "object"}
+	strict := map[string]interface{}{
+		"type":     "object",
+		"required": []string{"id"},
+	}
+
+	if err := validator.RegisterSchema("cached_config", loose); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	if err := validator.Validate("cached_config", data); err != nil {
+		t.Fatalf("Expected valid data, got: %v", err)
+	}
+
+	if err := validator.RegisterSchema("cached_config", strict); err != nil {
+		t.Fatalf("Failed to re-register schema: %v", err)
+	}
+	if err := validator.Validate("cached_config", data); err == nil {
+		t.Error("Expected stale cached result to be invalidated after schema change")
+	}
+}
+
+func TestValidationCacheEviction(t *testing.T) {
+	cache := newValidationCache(2)
+
+	first := newCacheKey("a", false, []byte("1"))
+	second := newCacheKey("a", false, []byte("2"))
+	third := newCacheKey("b", false, []byte("3"))
+
+	cache.put(first, nil, 0)
+	cache.put(second, nil, 0)
+	cache.get(first) // first becomes most recently used
+	cache.put(third, nil, 0)
+
+	if _, ok := cache.get(second); ok {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	if _, ok := cache.get(first); !ok {
+		t.Error("Expected recently used entry to be retained")
+	}
+
+	cache.invalidate("b")
+	if _, ok := cache.get(third); ok {
+		t.Error("Expected invalidated entry to be removed")
+	}
+
+	// A result computed before an invalidation of its type is stale
+	generation := cache.generation("a")
+	cache.invalidate("a")
+	cache.put(first, nil, generation)
+	if _, ok := cache.get(first); ok {
+		t.Error("Expected a result from before an invalidation not to be cached")
+	}
+	cache.put(first, nil, cache.generation("a"))
+	if _, ok := cache.get(first); !ok {
+		t.Error("Expected a result from the current generation to be cached")
+	}
+}
+
+// largeConfigData builds a config payload with many entries for benchmarks
+func largeConfigData(entries int) map[string]interface{} {
+	data := make(map[string]interface{}, entries)
+	for i := 0; i < entries; i++ {
+		data[fmt.Sprintf("key_%d", i)] = map[string]interface{}{
+			"enabled": i%2 == 0,
+			"limit":   i,
+			"label":   fmt.Sprintf("entry %d", i),
+		}
+	}
+	return data
+}
+
+// BenchmarkValidateLargeConfig compares repeated validation of a large payload
+// with and without the result cache. With 2000 entries the cached path measured
+// about 3.5ms/op against 16.7ms/op uncached (~5x), since it only serializes and
+// hashes the data instead of walking it against the schema.
+func BenchmarkValidateLargeConfig(b *testing.B) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"additionalProperties": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"enabled": map[string]interface{}{"type": "boolean"},
+				"limit":   map[string]interface{}{"type": "integer", "minimum": 0},
+				"label":   map[string]interface{}{"type": "string"},
+			},
+			"required": []string{"enabled", "limit"},
+		},
+	}
+	data := largeConfigData(2000)
+
+	run := func(b *testing.B, capacity int) {
+		validator, _ := NewValidator()
+		validator.cache = newValidationCache(capacity)
+		if err := validator.RegisterSchema("large_config", schema); err != nil {
+			b.Fatalf("Failed to register schema: %v", err)
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := validator.Validate("large_config", data); err != nil {
+				b.Fatalf("Unexpected validation error: %v", err)
+			}
+		}
+	}
+
+	b.Run("uncached", func(b *testing.B) { run(b, 0) })
+	b.Run("cached", func(b *testing.B) { run(b, defaultCacheSize) })
+}
+
+func TestReloadSchemas(t *testing.T) {
+	dir := t.TempDir()
+	writeSchema := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+	}
+	writeSchema("feature_flags.json", `{"type": "object", "properties": {"enabled": {"type": "boolean"}}}`)
+	writeSchema("notes.txt", `not a schema`)
+
+	validator, err := NewValidatorWithSchemaDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	if !validator.HasSchema("feature_flags") || !validator.HasSchema("payment_config") {
+		t.Fatalf("Expected directory and predefined schemas, got %v", validator.ListSchemas())
+	}
+	data := map[string]interface{}{"enabled": true}
+	if err := validator.Validate("feature_flags", data); err != nil {
+		t.Fatalf("Expected valid data, got %v", err)
+	}
+
+	// A broken file fails the whole reload and keeps the previous schemas
+	writeSchema("feature_flags.json", `{"type": "object", "required": ["owner"]}`)
+	writeSchema("broken.json", `{"type": "no-such-type"}`)
+	if _, err := validator.ReloadSchemas(); err == nil || !strings.Contains(err.Error(), "broken.json") {
+		t.Fatalf("Expected reload to fail on broken.json, got %v", err)
+	}
+	if validator.HasSchema("broken") {
+		t.Error("Expected broken schema not to be registered")
+	}
+	if err := validator.Validate("feature_flags", data); err != nil {
+		t.Errorf("Expected the previous feature_flags schema to stay active, got %v", err)
+	}
+
+	// Once fixed, the reload swaps in the new schemas
+	writeSchema("broken.json", `{"type": "object"}`)
+	types, err := validator.ReloadSchemas()
+	if err != nil {
+		t.Fatalf("Failed to reload schemas: %v", err)
+	}
+	if fmt.Sprint(types) != "[broken feature_flags]" {
+		t.Errorf("Expected [broken feature_flags], got %v", types)
+	}
+	if err := validator.Validate("feature_flags", data); err == nil {
+		t.Error("Expected the reloaded feature_flags schema to require owner")
+	}
+
+	// Validators without a directory cannot reload
+	plain, _ := NewValidator()
+	if _, err := plain.ReloadSchemas(); err == nil {
+		t.Error("Expected an error without a schema directory")
+	}
+}
+
+func TestReloadSchemasChecked(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "feature_flags.json")
+	if err := os.WriteFile(schemaFile, []byte(`{"type": "object"}`), 0o644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	validator, err := NewValidatorWithSchemaDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	stored := []*models.Config{
+		{Name: "checkout", Type: "feature_flags", Version: 3, Data: map[string]interface{}{"owner": "payments"}},
+		{Name: "search", Type: "feature_flags", Version: 1, Data: map[string]interface{}{}},
+		{Name: "payments", Type: "payment_config", Version: 1, Data: map[string]interface{}{"max_limit": 100, "enabled": true}},
+	}
+	if err := os.WriteFile(schemaFile, []byte(`{"type": "object", "required": ["owner"]}`), 0o644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	// A dry run reports the incompatible config but keeps the old schema
+	_, incompatible, err := validator.ReloadSchemasChecked(stored, true)
+	if err != nil {
+		t.Fatalf("Failed to check schemas: %v", err)
+	}
+	if len(incompatible) != 1 || incompatible[0].Name != "search" || incompatible[0].Version != 1 || len(incompatible[0].Errors) == 0 {
+		t.Fatalf("Expected search to be incompatible, got %+v", incompatible)
+	}
+	if err := validator.Check("feature_flags", map[string]interface{}{}); err != nil {
+		t.Errorf("Expected a dry run to keep the old schema, got %v", err)
+	}
+
+	if _, incompatible, err = validator.ReloadSchemasChecked(stored, false); err != nil || len(incompatible) != 1 {
+		t.Fatalf("Expected the reload to report search, got %+v (%v)", incompatible, err)
+	}
+	if err := validator.Check("feature_flags", map[string]interface{}{}); err == nil {
+		t.Error("Expected the reloaded schema to require owner")
+	}
+}
+
+func TestSchemaDirWithBadSchema(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"feature_flags.json": `{"type": "object"}`,
+		"broken.json":        `{"type": "no-such-type"}`,
+		"garbled.json":       `{"type": `,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := NewValidatorWithSchemaDir(dir)
+		var schemaErr *SchemaError
+		if !errors.As(err, &schemaErr) {
+			t.Fatalf("Expected a SchemaError, got %v", err)
+		}
+		// Every failure is reported, not just the first
+		for _, want := range []string{"broken.json (config type broken)", "garbled.json (config type garbled)"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("Expected the error to name %q, got %v", want, err)
+			}
+		}
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		validator, skipped, err := NewValidatorWithSchemaDirLenient(dir)
+		if err != nil {
+			t.Fatalf("Failed to create validator: %v", err)
+		}
+		failed := make([]string, len(skipped))
+		for i, schemaErr := range skipped {
+			failed[i] = schemaErr.Type
+		}
+		sort.Strings(failed)
+		if fmt.Sprint(failed) != "[broken garbled]" {
+			t.Errorf("Expected broken and garbled to be skipped, got %v", skipped)
+		}
+		if !validator.HasSchema("feature_flags") || !validator.HasSchema("payment_config") {
+			t.Errorf("Expected the valid schemas to load, got %v", validator.ListSchemas())
+		}
+		if validator.HasSchema("broken") || validator.HasSchema("garbled") {
+			t.Errorf("Expected the bad schemas to be skipped, got %v", validator.ListSchemas())
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		if _, _, err := NewValidatorWithSchemaDirLenient(filepath.Join(dir, "missing")); err == nil {
+			t.Error("Expected an error for a missing directory")
+		}
+	})
+}
+
+func TestSharedDefinitions(t *testing.T) {
+	validator, err := NewValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	moneyRef := map[string]interface{}{"$ref": DefinitionsURI + "#/definitions/money"}
+	invoiceSchema := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"total": moneyRef},
+		"required":   []interface{}{"total"},
+	}
+	// Compiling a reference fails until the definitions exist
+	if err := validator.RegisterSchema("invoice_config", invoiceSchema); err == nil {
+		t.Fatal("Expected an unresolvable $ref to fail")
+	}
+
+	if err := validator.RegisterSchema(DefinitionsType, map[string]interface{}{
+		"definitions": map[string]interface{}{
+			"money": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"amount":   map[string]interface{}{"type": "integer", "minimum": 0},
+					"currency": map[string]interface{}{"type": "string", "pattern": "^[A-Z]{3}$"},
+				},
+				"required":             []interface{}{"amount", "currency"},
+				"additionalProperties": false,
+			},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to register definitions: %v", err)
+	}
+	if validator.HasSchema(DefinitionsType) {
+		t.Error("Expected the definitions not to be a config type")
+	}
+
+	if err := validator.RegisterSchema("invoice_config", invoiceSchema); err != nil {
+		t.Fatalf("Failed to register invoice_config: %v", err)
+	}
+	if err := validator.RegisterSchema("refund_config", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"limit":  moneyRef,
+			"reason": map[string]interface{}{"type": "string"},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to register refund_config: %v", err)
+	}
+
+	tests := []struct {
+		configType string
+		data       map[string]interface{}
+		wantField  string
+	}{
+		{"invoice_config", map[string]interface{}{"total": map[string]interface{}{"amount": 1200, "currency": "EUR"}}, ""},
+		{"invoice_config", map[string]interface{}{"total": map[string]interface{}{"amount": -1, "currency": "EUR"}}, "total.amount"},
+		{"refund_config", map[string]interface{}{"limit": map[string]interface{}{"amount": 50, "currency": "USD"}, "reason": "damaged"}, ""},
+		{"refund_config", map[string]interface{}{"limit": map[string]interface{}{"amount": 50, "currency": "usd"}}, "limit.currency"},
+	}
+	for _, tt := range tests {
+		err := validator.Validate(tt.configType, tt.data)
+		if tt.wantField == "" {
+			if err != nil {
+				t.Errorf("%s: expected valid data, got %v", tt.configType, err)
+			}
+			continue
+		}
+		errs, ok := err.(FieldErrors)
+		if !ok || len(errs) != 1 || errs[0].Field != tt.wantField {
+			t.Errorf("%s: expected an error on %s, got %v", tt.configType, tt.wantField, err)
+		}
+	}
+
+	// Replacing the definitions recompiles the schemas that reference them
+	if err := validator.RegisterDefinitions(map[string]interface{}{
+		"definitions": map[string]interface{}{"money": map[string]interface{}{"type": "integer"}},
+	}); err != nil {
+		t.Fatalf("Failed to replace definitions: %v", err)
+	}
+	if err := validator.Validate("invoice_config", map[string]interface{}{"total": 1200}); err != nil {
+		t.Errorf("Expected the replaced money definition to apply, got %v", err)
+	}
+}
+
+func TestSchemaPairFromDir(t *testing.T) {
+	dir := t.TempDir()
+	writeSchema := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+	}
+	writeSchema("team_config.json", `{"type": "object", "required": ["owner", "max_limit"]}`)
+	writeSchema("team_config.update.json", `{"type": "object", "required": ["max_limit"]}`)
+
+	validator, err := NewValidatorWithSchemaDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	if validator.HasSchema("team_config.update") {
+		t.Error("Expected the update schema not to be a config type")
+	}
+
+	// The same data gets distinct results from the create and update schemas,
+	// including when the first result is served from the cache
+	data := map[string]interface{}{"max_limit": 100}
+	for i := 0; i < 2; i++ {
+		if err := validator.Validate("team_config", data); err == nil {
+			t.Error("Expected owner to be required on create")
+		}
+		if err := validator.ValidateUpdate("team_config", data); err != nil {
+			t.Errorf("Expected owner to be optional on update, got %v", err)
+		}
+	}
+
+	// An update schema needs a create schema to pair with
+	writeSchema("orphan.update.json", `{"type": "object"}`)
+	if _, err := validator.ReloadSchemas(); err == nil || !strings.Contains(err.Error(), "orphan.update.json") {
+		t.Errorf("Expected reload to fail on orphan.update.json, got %v", err)
+	}
+}
+
+func TestFieldErrorsIncludeSchemaDescriptions(t *testing.T) {
+	validator, _ := NewValidator()
+	validator.RegisterSchema("limits_config", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"max_limit": map[string]interface{}{
+				"type":        "integer",
+				"minimum":     1,
+				"description": "Maximum transaction amount in cents",
+			},
+			"region": map[string]interface{}{
+				"type":        "object",
+				"description": "Where the limit applies",
+				"properties": map[string]interface{}{
+					"code": map[string]interface{}{"type": "string", "description": "ISO 3166 country code"},
+				},
+				"required": []interface{}{"code"},
+			},
+			"note": map[string]interface{}{"type": "string"},
+		},
+	})
+
+	err := validator.Validate("limits_config", map[string]interface{}{
+		"max_limit": 0,
+		"region":    map[string]interface{}{},
+		"note":      5,
+	})
+	errs, ok := err.(FieldErrors)
+	if !ok {
+		t.Fatalf("Expected FieldErrors, got %v", err)
+	}
+
+	descriptions := make(map[string]string)
+	for _, fieldErr := range errs {
+		descriptions[fieldErr.Field] = fieldErr.Description
+	}
+	want := map[string]string{
+		"max_limit":   "Maximum transaction amount in cents",
+		"region.code": "ISO 3166 country code",
+		"note":        "",
+	}
+	for field, description := range want {
+		got, exists := descriptions[field]
+		if !exists || got != description {
+			t.Errorf("Expected %s to have description %q, got %q (present: %v)", field, description, got, exists)
+		}
+	}
+
+	if !strings.Contains(err.Error(), "max_limit: Maximum transaction amount in cents; ") {
+		t.Errorf("Expected the message to carry the description, got %q", err.Error())
+	}
+	if !strings.Contains(err.Error(), "note: Invalid type") {
+		t.Errorf("Expected the plain message for a field without a description, got %q", err.Error())
+	}
+	for _, violation := range Violations(err) {
+		if violation.Description != want[violation.Field] {
+			t.Errorf("Expected violation of %s to have description %q, got %q", violation.Field, want[violation.Field], violation.Description)
+		}
+	}
+}
@@ -5,55 +5,213 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"config-engine/internal/audit"
+	"config-engine/internal/grpcapi"
 	"config-engine/internal/handlers"
 	"config-engine/internal/repository"
 	"config-engine/internal/service"
 	"config-engine/internal/validation"
+
+	"google.golang.org/grpc"
 )
 
 const (
-	defaultPort            = "8080"
-	shutdownTimeout        = 15 * time.Second
-	readTimeout            = 10 * time.Second
-	writeTimeout           = 10 * time.Second
-	idleTimeout            = 60 * time.Second
-	readHeaderTimeout      = 5 * time.Second
+	defaultPort       = "8080"
+	defaultGRPCPort   = "9090"
+	shutdownTimeout   = 15 * time.Second
+	readTimeout       = 10 * time.Second
+	writeTimeout      = 10 * time.Second
+	idleTimeout       = 60 * time.Second
+	readHeaderTimeout = 5 * time.Second
+)
+
+// Build metadata, injected at build time with
+// -ldflags "-X main.version=... -X main.commit=... -X main.buildTime=..."
+var (
+	version   string
+	commit    string
+	buildTime string
 )
 
 func main() {
+	startedAt := time.Now()
+
 	// Parse command-line flags
 	port := flag.String("port", defaultPort, "Server port")
+	grpcPort := flag.String("grpc-port", defaultGRPCPort, "gRPC server port")
+	normalizeNames := flag.Bool("normalize-names", true, "Lowercase and trim config names (set false for strict, case-sensitive names)")
+	maxDataSize := flag.Int("max-data-size", service.DefaultMaxDataSize, "Maximum serialized config data size in bytes (0 disables)")
+	maxDataDepth := flag.Int("max-data-depth", service.DefaultMaxDataDepth, "Maximum config data nesting depth (0 disables)")
+	maxBatchNames := flag.Int("max-batch-names", service.DefaultMaxBatchNames, "Maximum number of names or items in a batch request (0 disables)")
+	maxInFlight := flag.Int("max-in-flight", handlers.DefaultMaxInFlight, "Maximum concurrent API requests before returning 503 (0 disables)")
+	idempotencyTTL := flag.Duration("idempotency-ttl", handlers.DefaultIdempotencyTTL, "How long create requests are remembered by Idempotency-Key (0 disables replay)")
+	sortedJSON := flag.Bool("sorted-json", false, "Encode config responses with sorted keys for byte-identical bodies")
+	strictJSON := flag.Bool("strict-json", false, "Reject request bodies that repeat a key within an object")
+	disallowUnknownFields := flag.Bool("disallow-unknown-fields", false, "Reject request bodies with fields the request type does not define")
+	maxWaitTimeout := flag.Duration("max-wait-timeout", service.DefaultMaxWaitTimeout, "Maximum time a long-poll wait request may block (0 disables the cap)")
+	versionStrategy := flag.String("version-strategy", string(repository.VersionStrategyAppend), "How rollbacks number versions: append (new version) or branching (move head back)")
+	storage := flag.String("storage", string(repository.StorageMemory), "Storage backend: memory or file")
+	storageDir := flag.String("storage-dir", "", "Data directory of the file storage backend")
+	storageKey := flag.String("storage-key", os.Getenv("STORAGE_KEY"), "Key encrypting files of the file storage backend at rest (empty stores plaintext)")
+	storageMigrate := flag.Bool("storage-migrate", false, "Rewrite plaintext and legacy encrypted files in -storage-dir with -storage-key at startup instead of refusing to load them; use once when turning on encryption or after upgrading")
+	maxConfigs := flag.Int("max-configs", 0, "Maximum number of configs stored; creates beyond it return 507 (0 is unlimited)")
+	rejectUnsafeKeys := flag.Bool("reject-unsafe-keys", false, "Reject config data keys containing '.' or control characters")
+	retainVersions := flag.Int("retain-versions", 0, "Versions per config kept by compaction (0 keeps all history)")
+	maxVersionsPerConfig := flag.Int("max-versions-per-config", 0, "Maximum number of versions a config may ever have; further updates and rollbacks return 409 instead of pruning history (0 is unlimited)")
+	adminToken := flag.String("admin-token", os.Getenv("ADMIN_TOKEN"), "Bearer token for admin endpoints (empty disables them)")
+	apiKeysFile := flag.String("api-keys", "", "JSON file mapping API keys to scopes ({\"<key>\": {\"read_only\": bool, \"namespaces\": [...], \"types\": [...]}}); when set every API request needs a valid X-API-Key header, or x-api-key metadata over gRPC (empty leaves the API open)")
+	prettyJSON := flag.Bool("pretty-json", false, "Indent JSON responses by default (clients can override with ?pretty=false)")
+	requestTimeout := flag.Duration("request-timeout", 0, "Deadline for serving an API request before returning 503; long-polls and streams are exempt (0 disables)")
+	typeRateLimits := flag.String("type-rate-limits", "", "Comma-separated per config type write limits as type=rate[:burst], rate in writes per second (e.g. payment_config=5:10)")
+	healthScoreWeights := flag.String("health-score-weights", "error_rate=0.6,capacity=0.2,memory=0.2", "Comma-separated signal=weight list for the admin health score (signals: error_rate, capacity, memory)")
+	trustedProxies := flag.String("trusted-proxies", "", "Comma-separated proxy IPs/CIDRs whose client IP header is trusted (empty trusts none; clients can forge the header, so list only proxies that overwrite it)")
+	clientIPHeader := flag.String("client-ip-header", handlers.DefaultClientIPHeader, "Header a trusted proxy sets to the client IP: X-Forwarded-For or X-Real-IP")
+	envelope := flag.Bool("envelope", false, "Wrap all JSON responses in {\"data\": ..., \"error\": ...}")
+	schemaDir := flag.String("schema-dir", "", "Directory of <config type>.json schemas loaded at startup and on reload; <config type>.update.json is an optional update schema and _definitions.json holds definitions shared via $ref")
+	strictSchemas := flag.Bool("strict-schemas", true, "Exit at startup if any schema in -schema-dir fails to load; when false the failures are logged and those types skipped")
+	expiryInterval := flag.Duration("expiry-interval", time.Minute, "How often configs past the default TTL of their type's policy are deleted (0 disables expiry)")
+	typeDefaults := flag.String("type-defaults", "", "JSON file mapping config types to default data; a get with ?default=<type> of a missing config returns it as version 0 instead of 404")
+	webhooksFile := flag.String("webhooks", "", "JSON file mapping config types to webhook subscriptions ({\"<type>\": [{\"url\": ..., \"secret\": ...}]}); each write is POSTed to the URLs, signed with HMAC-SHA256 of the secret in the X-Signature header")
+	coerceIntegerTypes := flag.String("coerce-integer-types", "", "Comma-separated config types whose whole-number floats (e.g. 1000.0) validate as integers")
 	flag.Parse()
 
 	// Setup logger
 	logger := log.New(os.Stdout, "[config-engine] ", log.LstdFlags|log.Lshortfile)
 
 	// Initialize validator
-	validator, err := validation.NewValidator()
+	var validator *validation.Validator
+	var err error
+	switch {
+	case *schemaDir != "" && *strictSchemas:
+		validator, err = validation.NewValidatorWithSchemaDir(*schemaDir)
+	case *schemaDir != "":
+		var skipped []*validation.SchemaError
+		validator, skipped, err = validation.NewValidatorWithSchemaDirLenient(*schemaDir)
+		for _, schemaErr := range skipped {
+			logger.Printf("Skipping schema: %v", schemaErr)
+		}
+	default:
+		validator, err = validation.NewValidator()
+	}
 	if err != nil {
 		logger.Fatalf("Failed to initialize validator: %v", err)
 	}
+	for _, configType := range strings.Split(*coerceIntegerTypes, ",") {
+		if configType = strings.TrimSpace(configType); configType != "" {
+			validator.SetIntegerCoercion(configType, true)
+		}
+	}
 	logger.Println("Validator initialized successfully")
 
 	// Initialize repository
-	repo := repository.NewInMemoryRepository()
-	logger.Println("Repository initialized successfully")
+	strategy, err := repository.ParseVersionStrategy(*versionStrategy)
+	if err != nil {
+		logger.Fatalf("Invalid -version-strategy: %v", err)
+	}
+	repo, err := repository.NewRepository(repository.StorageConfig{
+		Backend:      repository.StorageBackend(*storage),
+		Dir:          *storageDir,
+		Key:          []byte(*storageKey),
+		MigrateFiles: *storageMigrate,
+		Strategy:     strategy,
+		MaxConfigs:   *maxConfigs,
+	})
+	if err != nil {
+		logger.Fatalf("Invalid storage configuration: %v", err)
+	}
+	logger.Printf("Repository initialized successfully (%s storage)", *storage)
 
 	// Initialize service
-	svc := service.NewConfigService(repo, validator)
+	opts := service.DefaultOptions()
+	opts.NormalizeNames = *normalizeNames
+	opts.MaxDataSize = *maxDataSize
+	opts.MaxDataDepth = *maxDataDepth
+	opts.MaxBatchNames = *maxBatchNames
+	opts.RetainVersions = *retainVersions
+	opts.MaxVersionsPerConfig = *maxVersionsPerConfig
+	opts.RejectUnsafeKeys = *rejectUnsafeKeys
+	opts.MaxWaitTimeout = *maxWaitTimeout
+	opts.Logger = logger
+	svc := service.NewConfigServiceWithOptions(repo, validator, opts)
 	logger.Println("Service initialized successfully")
+	if *typeDefaults != "" {
+		types, err := svc.LoadTypeDefaults(*typeDefaults)
+		if err != nil {
+			logger.Fatalf("Invalid -type-defaults: %v", err)
+		}
+		logger.Printf("Loaded defaults for %d config types", len(types))
+	}
+	if *webhooksFile != "" {
+		types, err := svc.LoadWebhooks(*webhooksFile)
+		if err != nil {
+			logger.Fatalf("Invalid -webhooks: %v", err)
+		}
+		logger.Printf("Loaded webhooks for %d config types", len(types))
+	}
+	// The audit log is shared so writes over gRPC, and expiries, are recorded
+	// alongside HTTP ones
+	auditLog := audit.NewInMemoryAuditLogger()
+	expiryCtx, stopExpiry := context.WithCancel(context.Background())
+	defer stopExpiry()
+	if *expiryInterval > 0 {
+		go svc.RunExpiry(expiryCtx, *expiryInterval, auditLog)
+	}
+
+	proxies, err := handlers.ParseTrustedProxies(*trustedProxies)
+	if err != nil {
+		logger.Fatalf("Invalid -trusted-proxies: %v", err)
+	}
+	ipHeader, err := handlers.ParseClientIPHeader(*clientIPHeader)
+	if err != nil {
+		logger.Fatalf("Invalid -client-ip-header: %v", err)
+	}
+	rateLimits, err := handlers.ParseTypeRateLimits(*typeRateLimits)
+	if err != nil {
+		logger.Fatalf("Invalid -type-rate-limits: %v", err)
+	}
+	weights, err := handlers.ParseHealthScoreWeights(*healthScoreWeights)
+	if err != nil {
+		logger.Fatalf("Invalid -health-score-weights: %v", err)
+	}
+	var apiKeys map[string]handlers.APIKeyScope
+	if *apiKeysFile != "" {
+		if apiKeys, err = handlers.LoadAPIKeys(*apiKeysFile); err != nil {
+			logger.Fatalf("Invalid -api-keys: %v", err)
+		}
+		logger.Printf("Loaded %d API keys", len(apiKeys))
+	}
 
 	// Initialize handler
-	handler := handlers.NewConfigHandler(svc, logger)
+	handler := handlers.NewConfigHandlerWithAudit(svc, auditLog, logger)
 
 	// Setup router (Gin engine)
-	router := handlers.SetupRouter(handler, logger)
+	routerOpts := handlers.DefaultRouterOptions()
+	routerOpts.MaxInFlight = *maxInFlight
+	routerOpts.IdempotencyTTL = *idempotencyTTL
+	routerOpts.SortedJSON = *sortedJSON
+	routerOpts.Envelope = *envelope
+	routerOpts.PrettyJSON = *prettyJSON
+	routerOpts.StrictJSON = *strictJSON
+	routerOpts.DisallowUnknownFields = *disallowUnknownFields
+	routerOpts.AdminToken = *adminToken
+	routerOpts.APIKeys = apiKeys
+	routerOpts.TrustedProxies = proxies
+	routerOpts.ClientIPHeader = ipHeader
+	routerOpts.RequestTimeout = *requestTimeout
+	routerOpts.TypeRateLimits = rateLimits
+	routerOpts.HealthScoreWeights = weights
+	routerOpts.StartedAt = startedAt
+	routerOpts.Build = handlers.NewBuildInfo(version, commit, buildTime)
+	router := handlers.SetupRouterWithOptions(handler, logger, routerOpts)
 
 	// Configure server
 	addr := fmt.Sprintf(":%s", *port)
@@ -75,6 +233,24 @@ func main() {
 		}
 	}()
 
+	// Start gRPC server in a goroutine
+	grpcAddr := fmt.Sprintf(":%s", *grpcPort)
+	var grpcOpts []grpc.ServerOption
+	if len(apiKeys) > 0 {
+		grpcOpts = append(grpcOpts, grpc.UnaryInterceptor(grpcapi.APIKeyInterceptor(svc, apiKeys)))
+	}
+	grpcServer := grpcapi.Register(svc, auditLog, logger, grpcOpts...)
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		logger.Fatalf("Failed to listen on %s: %v", grpcAddr, err)
+	}
+	go func() {
+		logger.Printf("Starting gRPC server on %s", grpcAddr)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logger.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
 	logger.Printf("Configuration Management Service is running on http://localhost%s", addr)
 	logger.Println("Press Ctrl+C to stop the server")
 
@@ -89,10 +265,36 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
-	// Attempt graceful shutdown
-	if err := server.Shutdown(ctx); err != nil {
-		logger.Printf("Server forced to shutdown: %v", err)
-	}
+	// Attempt graceful shutdown of both servers concurrently
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Printf("Server forced to shutdown: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		shutdownGRPC(ctx, grpcServer, logger)
+	}()
+	wg.Wait()
 
 	logger.Println("Server stopped")
-}
\ No newline at end of file
+}
+
+// shutdownGRPC drains in-flight RPCs, forcing a stop once ctx expires
+func shutdownGRPC(ctx context.Context, server *grpc.Server, logger *log.Logger) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		logger.Println("gRPC server forced to shutdown")
+		server.Stop()
+	}
+}
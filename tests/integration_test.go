@@ -2,14 +2,18 @@ package tests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"config-engine/internal/handlers"
 	"config-engine/internal/models"
@@ -99,8 +103,8 @@ func TestCreateConfigValidationError(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", resp.StatusCode)
 	}
 }
 
@@ -124,7 +128,10 @@ func TestCreateConfigDuplicate(t *testing.T) {
 
 	// Try to create again
 	body, _ = json.Marshal(reqBody)
-	resp, _ := http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+	resp, err := http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusConflict {
@@ -171,7 +178,10 @@ func TestGetConfigNotFound(t *testing.T) {
 	server, _ := setupTestServer(t)
 	defer server.Close()
 
-	resp, _ := http.Get(server.URL + "/api/v1/configs/nonexistent")
+	resp, err := http.Get(server.URL + "/api/v1/configs/nonexistent")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNotFound {
@@ -209,7 +219,10 @@ func TestGetConfigWithVersion(t *testing.T) {
 	client.Do(req)
 
 	// Get version 1
-	resp, _ := http.Get(server.URL + "/api/v1/configs/payment_config?version=1")
+	resp, err := http.Get(server.URL + "/api/v1/configs/payment_config?version=1")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
@@ -223,7 +236,7 @@ func TestGetConfigWithVersion(t *testing.T) {
 		t.Errorf("Expected version 1, got %d", config.Version)
 	}
 
-	if config.Data["max_limit"].(float64) != 1000 {
+	if config.Data["max_limit"] != json.Number("1000") {
 		t.Errorf("Expected max_limit 1000, got %v", config.Data["max_limit"])
 	}
 }
@@ -273,11 +286,55 @@ func TestUpdateConfigEndpoint(t *testing.T) {
 		t.Errorf("Expected version 2, got %d", config.Version)
 	}
 
-	if config.Data["max_limit"].(float64) != 2000 {
+	if config.Data["max_limit"] != json.Number("2000") {
 		t.Errorf("Expected max_limit 2000, got %v", config.Data["max_limit"])
 	}
 }
 
+func TestPatchConfigEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	createReq := models.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	}
+	body, _ := json.Marshal(createReq)
+	http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+
+	// Only max_limit changes; enabled is required but already present
+	for _, tt := range []struct {
+		method string
+		status int
+	}{
+		{"PUT", http.StatusUnprocessableEntity},
+		{"PATCH", http.StatusOK},
+	} {
+		req, _ := http.NewRequest(tt.method, server.URL+"/api/v1/configs/payment_config",
+			bytes.NewBufferString(`{"data": {"max_limit": 2000}}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != tt.status {
+			t.Fatalf("Expected status %d for %s, got %d", tt.status, tt.method, resp.StatusCode)
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var config models.Config
+		json.NewDecoder(resp.Body).Decode(&config)
+		if config.Data["max_limit"] != json.Number("2000") || config.Data["enabled"] != true {
+			t.Errorf("Expected merged data, got %v", config.Data)
+		}
+	}
+}
+
 func TestListVersionsEndpoint(t *testing.T) {
 	server, _ := setupTestServer(t)
 	defer server.Close()
@@ -379,7 +436,7 @@ func TestRollbackConfigEndpoint(t *testing.T) {
 		t.Errorf("Expected version 3, got %d", config.Version)
 	}
 
-	if config.Data["max_limit"].(float64) != 1000 {
+	if config.Data["max_limit"] != json.Number("1000") {
 		t.Errorf("Expected max_limit 1000, got %v", config.Data["max_limit"])
 	}
 }
@@ -480,7 +537,7 @@ func TestFullWorkflow(t *testing.T) {
 		t.Errorf("Expected version 6 after rollback, got %d", rolledBackConfig.Version)
 	}
 
-	if rolledBackConfig.Data["max_limit"].(float64) != 1000 {
+	if rolledBackConfig.Data["max_limit"] != json.Number("1000") {
 		t.Errorf("Expected rolled back max_limit 1000, got %v", rolledBackConfig.Data["max_limit"])
 	}
 
@@ -496,3 +553,1233 @@ func TestFullWorkflow(t *testing.T) {
 
 	fmt.Println("Full workflow test completed successfully")
 }
+
+func TestTransactionEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	txReq := models.TransactionRequest{
+		Operations: []models.TransactionOperation{
+			{Op: "create", Name: "flag_a", Type: "payment_config", Data: map[string]interface{}{"max_limit": 100, "enabled": true}},
+			{Op: "create", Name: "flag_a", Type: "payment_config", Data: map[string]interface{}{"max_limit": 200, "enabled": true}},
+		},
+	}
+	body, _ := json.Marshal(txReq)
+	resp, err := http.Post(server.URL+"/api/v1/transactions", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", resp.StatusCode)
+	}
+
+	var errResp models.TransactionErrorResponse
+	json.NewDecoder(resp.Body).Decode(&errResp)
+	if errResp.OperationIndex != 1 {
+		t.Errorf("Expected operation index 1, got %d", errResp.OperationIndex)
+	}
+
+	getResp, err := http.Get(server.URL + "/api/v1/configs/flag_a")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer getResp.Body.Close()
+
+	if getResp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404 after rolled back transaction, got %d", getResp.StatusCode)
+	}
+}
+
+func TestGetConfigByTag(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	createReq := models.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	}
+	body, _ := json.Marshal(createReq)
+	http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+
+	tagReq := models.TagRequest{Tag: "stable", Version: 1}
+	body, _ = json.Marshal(tagReq)
+	resp, err := http.Post(server.URL+"/api/v1/configs/payment_config/tags", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(server.URL + "/api/v1/configs/payment_config?tag=stable")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	var config models.Config
+	json.NewDecoder(resp.Body).Decode(&config)
+	if config.Version != 1 {
+		t.Errorf("Expected version 1, got %d", config.Version)
+	}
+
+	missing, err := http.Get(server.URL + "/api/v1/configs/payment_config?tag=unknown")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer missing.Body.Close()
+	if missing.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", missing.StatusCode)
+	}
+}
+
+func TestAuditEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	createReq := models.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	}
+	body, _ := json.Marshal(createReq)
+	req, _ := http.NewRequest("POST", server.URL+"/api/v1/configs", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Actor", "alice")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+
+	resp, err = http.Get(server.URL + "/api/v1/audit?actor=alice&action=create&limit=10")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	var auditResp models.AuditResponse
+	json.NewDecoder(resp.Body).Decode(&auditResp)
+	if auditResp.Total != 1 || len(auditResp.Events) != 1 {
+		t.Fatalf("Expected 1 event, got total %d", auditResp.Total)
+	}
+	if auditResp.Events[0].Name != "payment_config" {
+		t.Errorf("Expected event for payment_config, got %s", auditResp.Events[0].Name)
+	}
+	if auditResp.Events[0].Origin != models.OriginAPI {
+		t.Errorf("Expected origin %q, got %q", models.OriginAPI, auditResp.Events[0].Origin)
+	}
+
+	bad, err := http.Get(server.URL + "/api/v1/audit?since=yesterday")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer bad.Body.Close()
+	if bad.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for malformed since, got %d", bad.StatusCode)
+	}
+}
+
+func TestEmptyBodyOnWriteEndpoints(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{"POST", "/api/v1/configs"},
+		{"PUT", "/api/v1/configs/payment_config"},
+		{"POST", "/api/v1/configs/payment_config/rollback"},
+	}
+
+	for _, r := range requests {
+		req, _ := http.NewRequest(r.method, server.URL+r.path, bytes.NewBufferString("   "))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+
+		var errResp models.ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s %s: expected status 400, got %d", r.method, r.path, resp.StatusCode)
+		}
+		if errResp.Error != "request body is required" {
+			t.Errorf("%s %s: unexpected error '%s'", r.method, r.path, errResp.Error)
+		}
+	}
+}
+
+func TestProposalWorkflowEndpoints(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	createReq := models.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	}
+	body, _ := json.Marshal(createReq)
+	http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+
+	proposeReq := models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 5000, "enabled": true},
+	}
+	body, _ = json.Marshal(proposeReq)
+	resp, err := http.Post(server.URL+"/api/v1/configs/payment_config/proposals", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	var proposal models.Proposal
+	json.NewDecoder(resp.Body).Decode(&proposal)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest("POST", server.URL+"/api/v1/configs/payment_config/proposals/"+proposal.ID+"/approve", nil)
+	req.Header.Set("X-Actor", "bob")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	var config models.Config
+	json.NewDecoder(resp.Body).Decode(&config)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if config.Version != 2 {
+		t.Errorf("Expected version 2, got %d", config.Version)
+	}
+
+	// The approver is recorded in the audit trail
+	resp, err = http.Get(server.URL + "/api/v1/audit?action=approve")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	var auditResp models.AuditResponse
+	json.NewDecoder(resp.Body).Decode(&auditResp)
+	if len(auditResp.Events) != 1 || auditResp.Events[0].Actor != "bob" {
+		t.Errorf("Expected approval by bob in audit trail, got %+v", auditResp.Events)
+	}
+}
+
+func TestSchemaExampleEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/schemas/payment_config/example")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	var example models.SchemaExampleResponse
+	json.NewDecoder(resp.Body).Decode(&example)
+	if example.Data["max_limit"] != json.Number("0") || example.Data["enabled"] != false {
+		t.Errorf("Expected {max_limit: 0, enabled: false}, got %v", example.Data)
+	}
+
+	missing, err := http.Get(server.URL + "/api/v1/schemas/unknown_config/example")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer missing.Body.Close()
+	if missing.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown type, got %d", missing.StatusCode)
+	}
+}
+
+func TestSquashVersionsEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	createReq := models.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1, "enabled": true},
+	}
+	body, _ := json.Marshal(createReq)
+	resp, _ := http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+	resp.Body.Close()
+	for i := 2; i <= 3; i++ {
+		body, _ = json.Marshal(models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": i, "enabled": true}})
+		req, _ := http.NewRequest("PUT", server.URL+"/api/v1/configs/payment_config", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ = http.DefaultClient.Do(req)
+		resp.Body.Close()
+	}
+
+	body, _ = json.Marshal(models.SquashRequest{From: 1, To: 2})
+	resp, err := http.Post(server.URL+"/api/v1/configs/payment_config/versions/squash", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	var result models.SquashResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	if result.VersionsRemoved != 1 {
+		t.Errorf("Expected 1 version removed, got %d", result.VersionsRemoved)
+	}
+
+	versionResp, err := http.Get(server.URL + "/api/v1/configs/payment_config?version=1")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer versionResp.Body.Close()
+	if versionResp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected squashed version to be gone, got %d", versionResp.StatusCode)
+	}
+}
+
+func TestBatchGetEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	for _, name := range []string{"alpha", "beta"} {
+		body, _ := json.Marshal(models.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+		})
+		resp, err := http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	body, _ := json.Marshal(models.BatchGetRequest{Names: []string{"alpha", "missing", "beta"}})
+	resp, err := http.Post(server.URL+"/api/v1/configs:batchGet", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	var batch models.BatchGetResponse
+	json.NewDecoder(resp.Body).Decode(&batch)
+	if len(batch.Configs) != 2 || batch.Configs["alpha"] == nil || batch.Configs["beta"] == nil {
+		t.Errorf("Expected configs for alpha and beta, got %v", batch.Configs)
+	}
+	if len(batch.Errors) != 1 || batch.Errors["missing"].Error == "" {
+		t.Errorf("Expected an error for missing, got %v", batch.Errors)
+	}
+
+	names := make([]string, service.DefaultMaxBatchNames+1)
+	for i := range names {
+		names[i] = fmt.Sprintf("config_%d", i)
+	}
+	body, _ = json.Marshal(models.BatchGetRequest{Names: names})
+	tooMany, err := http.Post(server.URL+"/api/v1/configs:batchGet", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	tooMany.Body.Close()
+	if tooMany.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400 above the name cap, got %d", tooMany.StatusCode)
+	}
+
+	unknown, err := http.Post(server.URL+"/api/v1/configs:batchDelete", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	unknown.Body.Close()
+	if unknown.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown method, got %d", unknown.StatusCode)
+	}
+}
+
+func TestGetConfigValueEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	createReq := models.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	}
+	body, _ := json.Marshal(createReq)
+	http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/data/max_limit", http.StatusOK, "1000"},
+		{"/data/enabled", http.StatusOK, "true"},
+		{"/data/missing", http.StatusNotFound, ""},
+		{"/data/max_limit/deeper", http.StatusNotFound, ""},
+		{"/data/bad~escape", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		resp, err := http.Get(server.URL + "/api/v1/configs/payment_config" + tt.path)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.status, resp.StatusCode)
+			continue
+		}
+		if tt.body != "" && string(raw) != tt.body {
+			t.Errorf("%s: expected body %s, got %s", tt.path, tt.body, raw)
+		}
+	}
+}
+
+func TestNonObjectDataRejected(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	for _, data := range []string{`"{}"`, `5`} {
+		body := `{"name": "payment_config", "type": "payment_config", "data": ` + data + `}`
+		resp, err := http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for data %s, got %d", data, resp.StatusCode)
+		}
+		var errResp models.ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		if errResp.Error != "data must be an object or array" {
+			t.Errorf("Expected 'data must be an object or array' for data %s, got %q", data, errResp.Error)
+		}
+	}
+}
+
+func TestArrayConfigEndpoints(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	send := func(method, path, body string) *http.Response {
+		req, _ := http.NewRequest(method, server.URL+path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		return resp
+	}
+
+	for _, tt := range []struct {
+		body string
+		want int
+	}{
+		{`{"name": "office_ips", "type": "ip_allowlist", "data": ["10.0.0.300"]}`, http.StatusUnprocessableEntity},
+		{`{"name": "office_ips", "type": "ip_allowlist", "data": {"ips": ["10.0.0.1"]}}`, http.StatusUnprocessableEntity},
+		{`{"name": "office_ips", "type": "payment_config", "data": ["10.0.0.1"]}`, http.StatusUnprocessableEntity},
+		{`{"name": "office_ips", "type": "ip_allowlist", "data": ["10.0.0.1"]}`, http.StatusCreated},
+	} {
+		resp := send(http.MethodPost, "/api/v1/configs", tt.body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("Expected status %d for %s, got %d", tt.want, tt.body, resp.StatusCode)
+		}
+	}
+
+	resp := send(http.MethodPut, "/api/v1/configs/office_ips", `{"data": ["10.0.0.1", "10.0.0.2"]}`)
+	var raw map[string]json.RawMessage
+	json.NewDecoder(resp.Body).Decode(&raw)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if string(raw["data"]) != `["10.0.0.1","10.0.0.2"]` {
+		t.Errorf("Expected the data as an array, got %s", raw["data"])
+	}
+
+	resp = send(http.MethodGet, "/api/v1/configs/office_ips/data/1", "")
+	value, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(value) != `"10.0.0.2"` {
+		t.Errorf("Expected the second item, got %d: %s", resp.StatusCode, value)
+	}
+
+	resp = send(http.MethodGet, "/api/v1/configs/office_ips/diff?from=1&to=2", "")
+	var diff models.VersionDiff
+	json.NewDecoder(resp.Body).Decode(&diff)
+	resp.Body.Close()
+	if len(diff.Changes) != 1 || diff.Changes[0].Path != "" || diff.Changes[0].Op != models.DiffChanged {
+		t.Errorf("Expected the whole array to change, got %+v", diff)
+	}
+
+	resp = send(http.MethodPost, "/api/v1/configs/office_ips/rollback", `{"version": 1}`)
+	var config models.Config
+	json.NewDecoder(resp.Body).Decode(&config)
+	resp.Body.Close()
+	if items, ok := models.DataItems(config.Data); !ok || len(items) != 1 || items[0] != "10.0.0.1" {
+		t.Errorf("Expected rollback to restore the first array, got %v", config.Data)
+	}
+}
+
+func TestBindingErrorsAreFieldViolations(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	body, _ := json.Marshal(models.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	resp, err := http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+
+	tests := []struct {
+		name  string
+		body  string
+		field string
+		error string
+	}{
+		{"version as a string", `{"version": "1"}`, "version", "version must be an integer"},
+		{"body as an array", `[1]`, "body", "body must be an object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+"/api/v1/configs/payment_config/rollback", "application/json", bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("Expected status 400, got %d", resp.StatusCode)
+			}
+			var errResp models.ErrorResponse
+			json.NewDecoder(resp.Body).Decode(&errResp)
+			if errResp.Error != tt.error {
+				t.Errorf("Expected error %q, got %q", tt.error, errResp.Error)
+			}
+			if len(errResp.Violations) != 1 || errResp.Violations[0].Field != tt.field || errResp.Violations[0].Message != tt.error {
+				t.Errorf("Expected a single violation of %s, got %+v", tt.field, errResp.Violations)
+			}
+			if strings.Contains(errResp.Error+errResp.Details, "Go ") || strings.Contains(errResp.Error+errResp.Details, "models.") {
+				t.Errorf("Expected no Go type details, got %+v", errResp)
+			}
+		})
+	}
+
+	// Schema failures list their violations in the same shape
+	body, _ = json.Marshal(models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": "high", "enabled": true}})
+	req, _ := http.NewRequest(http.MethodPut, server.URL+"/api/v1/configs/payment_config", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	var errResp models.ErrorResponse
+	json.NewDecoder(resp.Body).Decode(&errResp)
+	if resp.StatusCode != http.StatusUnprocessableEntity || len(errResp.Violations) == 0 || errResp.Violations[0].Field != "max_limit" {
+		t.Errorf("Expected a 422 with a max_limit violation, got %d %+v", resp.StatusCode, errResp)
+	}
+}
+
+func TestCompareEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	for name, limit := range map[string]int{"staging_payment": 500, "prod_payment": 1000} {
+		body, _ := json.Marshal(models.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": limit, "enabled": true},
+		})
+		http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+	}
+
+	resp, err := http.Get(server.URL + "/api/v1/compare?a=staging_payment&b=prod_payment")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	var diff models.VersionDiff
+	json.NewDecoder(resp.Body).Decode(&diff)
+	if len(diff.Changes) != 1 || diff.Changes[0].Path != "/max_limit" || diff.Changes[0].From != float64(500) || diff.Changes[0].To != float64(1000) {
+		t.Errorf("Expected max_limit to change from 500 to 1000, got %+v", diff.Changes)
+	}
+
+	resp, err = http.Get(server.URL + "/api/v1/compare?a=staging_payment&b=dev_payment")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("Expected status 404, got %d", resp.StatusCode)
+	}
+	var errResp models.ErrorResponse
+	json.NewDecoder(resp.Body).Decode(&errResp)
+	if !strings.Contains(errResp.Error, "dev_payment") {
+		t.Errorf("Expected the error to name dev_payment, got %q", errResp.Error)
+	}
+}
+
+func TestDiffVersionsEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	body, _ := json.Marshal(models.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+	body, _ = json.Marshal(models.UpdateConfigRequest{
+		Data: map[string]interface{}{"max_limit": 2000, "enabled": true},
+	})
+	req, _ := http.NewRequest(http.MethodPut, server.URL+"/api/v1/configs/payment_config", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	if resp, err := http.DefaultClient.Do(req); err == nil {
+		resp.Body.Close()
+	}
+
+	resp, err := http.Get(server.URL + "/api/v1/configs/payment_config/diff?from=1&to=2&format=text")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Expected text/plain, got %q", ct)
+	}
+	text, _ := io.ReadAll(resp.Body)
+	for _, line := range []string{
+		"--- payment_config@1",
+		"+++ payment_config@2",
+		`     "enabled": true,`,
+		`-    "max_limit": 1000`,
+		`+    "max_limit": 2000`,
+	} {
+		if !strings.Contains(string(text), line+"\n") {
+			t.Errorf("Expected line %q in diff:\n%s", line, text)
+		}
+	}
+
+	resp, err = http.Get(server.URL + "/api/v1/configs/payment_config/diff?from=1&to=2")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	var diff models.VersionDiff
+	json.NewDecoder(resp.Body).Decode(&diff)
+	if diff.From != "payment_config@1" || len(diff.Changes) != 1 || diff.Changes[0].Path != "/max_limit" {
+		t.Errorf("Expected a max_limit change, got %+v", diff)
+	}
+
+	for query, want := range map[string]int{
+		"from=1":                  http.StatusBadRequest,
+		"from=1&to=2&format=html": http.StatusBadRequest,
+		"from=1&to=9":             http.StatusNotFound,
+	} {
+		resp, err = http.Get(server.URL + "/api/v1/configs/payment_config/diff?" + query)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%s: expected status %d, got %d", query, want, resp.StatusCode)
+		}
+	}
+}
+
+func TestCoercionSuggestionsEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	body := []byte(`{"name":"payment_config","type":"payment_config","data":{"max_limit":"1000","enabled":true}}`)
+	resp, err := http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got %d", resp.StatusCode)
+	}
+
+	var errResp models.ErrorResponse
+	json.NewDecoder(resp.Body).Decode(&errResp)
+	if len(errResp.Suggestions) != 1 {
+		t.Fatalf("Expected one suggestion, got %+v", errResp)
+	}
+	suggestion := errResp.Suggestions[0]
+	if suggestion.Field != "max_limit" || suggestion.From != "string" || suggestion.To != "integer" || suggestion.Value != float64(1000) {
+		t.Errorf("Expected max_limit string to integer 1000, got %+v", suggestion)
+	}
+}
+
+func TestConfigStatsEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	body, _ := json.Marshal(models.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+
+	for i := 0; i < 3; i++ {
+		resp, err := http.Get(server.URL + "/api/v1/configs/payment_config")
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	resp, err := http.Get(server.URL + "/api/v1/configs/payment_config/stats")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	var stats models.ConfigStatsResponse
+	json.NewDecoder(resp.Body).Decode(&stats)
+	if stats.Reads != 3 || stats.LastReadAt == nil || stats.VersionCount != 1 {
+		t.Errorf("Expected 3 reads of 1 version, got %+v", stats)
+	}
+}
+
+func TestConfigMetaEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	body, _ := json.Marshal(models.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+	body, _ = json.Marshal(models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": 25000, "enabled": false}})
+	req, _ := http.NewRequest(http.MethodPut, server.URL+"/api/v1/configs/payment_config", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+
+	resp, err = http.Get(server.URL + "/api/v1/configs/payment_config/meta")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	var raw map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&raw)
+	if _, hasData := raw["data"]; hasData {
+		t.Error("Expected the metadata to leave out the data")
+	}
+	encoded, _ := json.Marshal(raw)
+	var meta models.ConfigMetaResponse
+	json.Unmarshal(encoded, &meta)
+
+	wantSize := len(`{"enabled":false,"max_limit":25000}`)
+	if meta.Name != "payment_config" || meta.Type != "payment_config" || meta.Version != 2 {
+		t.Errorf("Expected payment_config version 2, got %+v", meta)
+	}
+	if meta.SizeBytes != wantSize || meta.FieldCount != 2 {
+		t.Errorf("Expected %d bytes in 2 fields, got %d bytes in %d fields", wantSize, meta.SizeBytes, meta.FieldCount)
+	}
+	if meta.CreatedAt.IsZero() || meta.UpdatedAt.Before(meta.CreatedAt) {
+		t.Errorf("Expected creation and update times, got %v and %v", meta.CreatedAt, meta.UpdatedAt)
+	}
+
+	resp, err = http.Get(server.URL + "/api/v1/configs/missing/meta")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestJSONPatchEndpoint(t *testing.T) {
+	validator, err := validation.NewValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	validator.RegisterSchema("gateway_config", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"timeout": map[string]interface{}{"type": "integer"},
+			"debug":   map[string]interface{}{"type": "boolean"},
+		},
+		"required": []string{"timeout"},
+	})
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	server := httptest.NewServer(handlers.SetupRouter(handlers.NewConfigHandler(svc, logger), logger))
+	defer server.Close()
+
+	body, _ := json.Marshal(models.CreateConfigRequest{
+		Name: "gateway",
+		Type: "gateway_config",
+		Data: map[string]interface{}{"timeout": 30, "debug": true},
+	})
+	http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+
+	patch := func(ops string) (*http.Response, models.Config) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPatch, server.URL+"/api/v1/configs/gateway", bytes.NewBufferString(ops))
+		req.Header.Set("Content-Type", models.JSONPatchContentType)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		var config models.Config
+		json.NewDecoder(resp.Body).Decode(&config)
+		return resp, config
+	}
+
+	resp, config := patch(`[{"op": "test", "path": "/timeout", "value": 30}, {"op": "replace", "path": "/timeout", "value": 60}]`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200 for replace, got %d", resp.StatusCode)
+	}
+	if config.Version != 2 || fmt.Sprint(config.Data["timeout"]) != "60" {
+		t.Errorf("Expected version 2 with timeout 60, got version %d with %v", config.Version, config.Data["timeout"])
+	}
+
+	resp, config = patch(`[{"op": "remove", "path": "/debug"}]`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200 for remove, got %d", resp.StatusCode)
+	}
+	if _, exists := config.Data["debug"]; exists || config.Version != 3 {
+		t.Errorf("Expected version 3 without debug, got version %d with %v", config.Version, config.Data)
+	}
+
+	// A stale test rejects the whole patch
+	resp, _ = patch(`[{"op": "add", "path": "/debug", "value": false}, {"op": "test", "path": "/timeout", "value": 30}]`)
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected status 409 for a failing test, got %d", resp.StatusCode)
+	}
+	// Removing a required field fails the schema
+	resp, _ = patch(`[{"op": "remove", "path": "/timeout"}]`)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for an invalid result, got %d", resp.StatusCode)
+	}
+
+	current, err := svc.GetConfig(context.Background(), "gateway", nil)
+	if err != nil {
+		t.Fatalf("Failed to get config: %v", err)
+	}
+	if current.Version != 3 {
+		t.Errorf("Expected rejected patches to leave version 3, got %d", current.Version)
+	}
+}
+
+func TestValidateStoredConfigEndpoint(t *testing.T) {
+	validator, err := validation.NewValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	logger := log.New(io.Discard, "", 0)
+	server := httptest.NewServer(handlers.SetupRouter(handlers.NewConfigHandler(svc, logger), logger))
+	defer server.Close()
+
+	body, _ := json.Marshal(models.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+
+	check := func() models.ConfigValidationResponse {
+		t.Helper()
+		resp, err := http.Get(server.URL + "/api/v1/configs/payment_config/validate")
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		var result models.ConfigValidationResponse
+		json.NewDecoder(resp.Body).Decode(&result)
+		return result
+	}
+
+	if result := check(); !result.Valid || len(result.Errors) != 0 || result.Version != 1 {
+		t.Fatalf("Expected version 1 to be valid, got %+v", result)
+	}
+
+	// Tighten the schema so the stored max_limit is now too large
+	err = validator.RegisterSchema("payment_config", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"max_limit": map[string]interface{}{"type": "integer", "maximum": 500},
+			"enabled":   map[string]interface{}{"type": "boolean"},
+		},
+		"required":             []string{"max_limit", "enabled"},
+		"additionalProperties": false,
+	})
+	if err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+
+	result := check()
+	if result.Valid || len(result.Errors) != 1 || result.Errors[0].Field != "max_limit" {
+		t.Errorf("Expected max_limit to fail the tightened schema, got %+v", result)
+	}
+
+	// Checking neither changes the config nor counts as a failed write
+	config, _ := svc.GetConfig(context.Background(), "payment_config", nil)
+	if config.Version != 1 {
+		t.Errorf("Expected the config to stay at version 1, got %d", config.Version)
+	}
+	if stats := validator.FailureStats(); len(stats) != 0 {
+		t.Errorf("Expected no validation failures recorded, got %+v", stats)
+	}
+}
+
+func TestListConfigsEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	for _, name := range []string{"payments_eu", "payments_us", "payouts"} {
+		body, _ := json.Marshal(models.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+		})
+		http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+	}
+
+	list := func(query string) (int, models.ConfigListResponse) {
+		resp, err := http.Get(server.URL + "/api/v1/configs?" + query)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		var page models.ConfigListResponse
+		json.NewDecoder(resp.Body).Decode(&page)
+		return resp.StatusCode, page
+	}
+
+	code, page := list("type=payment_config&name_prefix=PAYMENTS&limit=1&offset=1")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+	if page.Total != 2 || len(page.Configs) != 1 || page.Configs[0].Name != "payments_us" {
+		t.Errorf("Expected payments_us of 2 matches, got %+v", page)
+	}
+	if page.Limit != 1 || page.Offset != 1 {
+		t.Errorf("Expected limit 1 and offset 1 echoed, got %d and %d", page.Limit, page.Offset)
+	}
+
+	if _, page := list("type=feature_flag"); page.Total != 0 || len(page.Configs) != 0 {
+		t.Errorf("Expected no feature flags, got %+v", page)
+	}
+
+	if code, _ := list("limit=0"); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for limit=0, got %d", code)
+	}
+
+	// A limit past the end of the list must not overflow
+	code, page = list("limit=9223372036854775807&offset=1")
+	if code != http.StatusOK || page.Total != 3 || len(page.Configs) != 2 || page.Limit != 1000 {
+		t.Errorf("Expected the capped limit and the remaining configs, got %d: %+v", code, page)
+	}
+}
+
+func TestBatchValidateEndpoint(t *testing.T) {
+	server, repo := setupTestServer(t)
+	defer server.Close()
+
+	body := []byte(`{"items": [
+		{"type": "payment_config", "data": {"max_limit": 1000, "enabled": true}},
+		{"type": "payment_config", "data": {"max_limit": "1000", "enabled": true}},
+		{"type": "unknown_config", "data": {}},
+		{"type": "payment_config", "data": {"max_limit": 0, "enabled": true}},
+		{"type": "payment_config", "data": {"max_limit": 5, "enabled": false}}
+	]}`)
+	resp, err := http.Post(server.URL+"/api/v1/validate:batch", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	var result models.BatchValidateResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	if result.Valid {
+		t.Error("Expected the batch to be invalid")
+	}
+	wantValid := []bool{true, false, false, false, true}
+	wantField := []string{"", "max_limit", "type", "max_limit", ""}
+	if len(result.Results) != len(wantValid) {
+		t.Fatalf("Expected %d results, got %+v", len(wantValid), result.Results)
+	}
+	for i, r := range result.Results {
+		if r.Index != i || r.Valid != wantValid[i] {
+			t.Errorf("Result %d: expected index %d valid=%v, got %+v", i, i, wantValid[i], r)
+		}
+		if wantField[i] != "" && (len(r.Errors) == 0 || r.Errors[0].Field != wantField[i]) {
+			t.Errorf("Result %d: expected an error on %s, got %+v", i, wantField[i], r.Errors)
+		}
+	}
+
+	if names, _ := repo.ListNames(context.Background()); len(names) != 0 {
+		t.Errorf("Expected nothing stored, got %v", names)
+	}
+}
+
+func TestGetConfigAsOf(t *testing.T) {
+	server, repo := setupTestServer(t)
+	defer server.Close()
+
+	body, _ := json.Marshal(models.CreateConfigRequest{
+		Name: "payment_config",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+	})
+	http.Post(server.URL+"/api/v1/configs", "application/json", bytes.NewBuffer(body))
+	for _, limit := range []int{2000, 3000} {
+		time.Sleep(2 * time.Millisecond)
+		body, _ := json.Marshal(models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": limit, "enabled": true}})
+		req, _ := http.NewRequest(http.MethodPut, server.URL+"/api/v1/configs/payment_config", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	versions, err := repo.ListVersions(context.Background(), "payment_config")
+	if err != nil || len(versions) != 3 {
+		t.Fatalf("Expected 3 versions, got %d (%v)", len(versions), err)
+	}
+
+	tests := []struct {
+		name        string
+		at          time.Time
+		wantStatus  int
+		wantVersion int
+	}{
+		{"before creation", versions[0].CreatedAt.Add(-time.Nanosecond), http.StatusNotFound, 0},
+		{"at creation", versions[0].CreatedAt, http.StatusOK, 1},
+		{"just before v2", versions[1].CreatedAt.Add(-time.Nanosecond), http.StatusOK, 1},
+		{"at v2", versions[1].CreatedAt, http.StatusOK, 2},
+		{"just after v2", versions[1].CreatedAt.Add(time.Nanosecond), http.StatusOK, 2},
+		{"after latest", versions[2].CreatedAt.Add(time.Hour), http.StatusOK, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := url.Values{"as_of": {tt.at.UTC().Format(time.RFC3339Nano)}}
+			resp, err := http.Get(server.URL + "/api/v1/configs/payment_config?" + query.Encode())
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var config models.Config
+			json.NewDecoder(resp.Body).Decode(&config)
+			if config.Version != tt.wantVersion {
+				t.Errorf("Expected version %d, got %d", tt.wantVersion, config.Version)
+			}
+		})
+	}
+
+	for query, want := range map[string]int{
+		"as_of=yesterday":                       http.StatusBadRequest,
+		"as_of=2024-01-15T10:00:00Z&version=1":  http.StatusBadRequest,
+		"as_of=2024-01-15T10:00:00Z&tag=stable": http.StatusBadRequest,
+	} {
+		resp, err := http.Get(server.URL + "/api/v1/configs/payment_config?" + query)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%s: expected status %d, got %d", query, want, resp.StatusCode)
+		}
+	}
+}
+
+func TestGetConfigDefault(t *testing.T) {
+	validator, err := validation.NewValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	validator.RegisterSchema("feature_flags", map[string]interface{}{"type": "object"})
+	svc := service.NewConfigService(repository.NewInMemoryRepository(), validator)
+	if err := svc.SetTypeDefault("payment_config", map[string]interface{}{"max_limit": 100, "enabled": false}); err != nil {
+		t.Fatalf("Failed to set default: %v", err)
+	}
+	logger := log.New(os.Stdout, "[test] ", log.LstdFlags)
+	server := httptest.NewServer(handlers.SetupRouter(handlers.NewConfigHandler(svc, logger), logger))
+	defer server.Close()
+
+	get := func(path string) (int, models.Config) {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		var config models.Config
+		json.NewDecoder(resp.Body).Decode(&config)
+		return resp.StatusCode, config
+	}
+
+	// A registered default stands in for the missing config as version 0
+	status, config := get("/api/v1/configs/payments_eu?default=payment_config")
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200 with a default, got %d", status)
+	}
+	if config.Name != "payments_eu" || config.Type != "payment_config" || config.Version != 0 {
+		t.Errorf("Expected version 0 of payments_eu, got %+v", config)
+	}
+	if fmt.Sprint(config.Data["max_limit"]) != "100" || config.Data["enabled"] != false {
+		t.Errorf("Expected the default data, got %v", config.Data)
+	}
+
+	// Without a default, or without asking for one, a missing config is a 404
+	if status, _ := get("/api/v1/configs/flags_eu?default=feature_flags"); status != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a type without a default, got %d", status)
+	}
+	if status, _ := get("/api/v1/configs/payments_eu"); status != http.StatusNotFound {
+		t.Errorf("Expected status 404 without default, got %d", status)
+	}
+	if status, _ := get("/api/v1/configs/payments_eu?default=payment_config&version=1"); status != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for default with version, got %d", status)
+	}
+
+	// Once the config exists it is returned instead of the default
+	if _, err := svc.CreateConfig(context.Background(), &models.CreateConfigRequest{
+		Name: "payments_eu",
+		Type: "payment_config",
+		Data: map[string]interface{}{"max_limit": 5000, "enabled": true},
+	}); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	status, config = get("/api/v1/configs/payments_eu?default=payment_config")
+	if status != http.StatusOK || config.Version != 1 || fmt.Sprint(config.Data["max_limit"]) != "5000" {
+		t.Errorf("Expected the stored version 1, got %d %+v", status, config)
+	}
+}
+
+func TestRenameConfigEndpoint(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	post := func(path string, body interface{}) *http.Response {
+		encoded, _ := json.Marshal(body)
+		resp, err := http.Post(server.URL+path, "application/json", bytes.NewBuffer(encoded))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		return resp
+	}
+	for _, name := range []string{"payments", "payments_us"} {
+		post("/api/v1/configs", models.CreateConfigRequest{
+			Name: name,
+			Type: "payment_config",
+			Data: map[string]interface{}{"max_limit": 1000, "enabled": true},
+		}).Body.Close()
+	}
+	body, _ := json.Marshal(models.UpdateConfigRequest{Data: map[string]interface{}{"max_limit": 2000, "enabled": true}})
+	req, _ := http.NewRequest(http.MethodPut, server.URL+"/api/v1/configs/payments", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+
+	// The target name is taken
+	resp = post("/api/v1/configs/payments/rename", models.RenameRequest{NewName: "payments_us"})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("Expected status 409 for a taken name, got %d", resp.StatusCode)
+	}
+
+	resp = post("/api/v1/configs/payments/rename", models.RenameRequest{NewName: "payments_eu"})
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if location := resp.Header.Get("Location"); location != "/api/v1/configs/payments_eu" {
+		t.Errorf("Expected the Location of the new name, got %q", location)
+	}
+	var config models.Config
+	json.NewDecoder(resp.Body).Decode(&config)
+	if config.Name != "payments_eu" || config.Version != 2 {
+		t.Errorf("Expected version 2 of payments_eu, got %+v", config)
+	}
+
+	old, err := http.Get(server.URL + "/api/v1/configs/payments")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	old.Body.Close()
+	if old.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected the old name to return 404, got %d", old.StatusCode)
+	}
+
+	versionsResp, err := http.Get(server.URL + "/api/v1/configs/payments_eu/versions")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer versionsResp.Body.Close()
+	var versions models.VersionsResponse
+	json.NewDecoder(versionsResp.Body).Decode(&versions)
+	if len(versions.Versions) != 2 {
+		t.Errorf("Expected both versions to survive the rename, got %+v", versions)
+	}
+}